@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"library-system/internal/config"
+	"library-system/internal/db/migrations"
+	"library-system/internal/db/postgres"
+	"library-system/internal/models"
+	"library-system/internal/services"
+	"library-system/internal/storage"
+	"library-system/internal/validation"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/joho/godotenv"
+	"gorm.io/gorm"
+)
+
+// app is the setup shared by the commands that work on the library
+type app struct {
+	cfg      *config.Config
+	db       *gorm.DB
+	service  services.Service
+	store    storage.Store
+	validate *validator.Validate
+}
+
+// loadEnv reads the .env file outside of deployed environments
+func loadEnv() {
+	if os.Getenv("ENV") == "" {
+		if err := godotenv.Load(); err != nil {
+			log.Fatalln("Error loading env file", err)
+		}
+	}
+}
+
+// newApp loads the configuration, connects to a database that is fully
+// migrated and builds the model and service layers
+func newApp() *app {
+	loadEnv()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalln("Error loading config", err)
+	}
+
+	db := postgres.Connect()
+	requireMigrated(db)
+
+	model := models.New(db)
+	fmt.Println("Model layer initialized")
+
+	store, err := newStore(cfg)
+	if err != nil {
+		log.Fatalln("Error opening storage", err)
+	}
+
+	service := services.New(model, cfg, store)
+	fmt.Println("Service layer initialized")
+
+	return &app{cfg: cfg, db: db, service: service, store: store, validate: validation.New()}
+}
+
+// newStore opens the configured storage backend for cover images
+func newStore(cfg *config.Config) (storage.Store, error) {
+	if cfg.StorageBackend == "s3" {
+		return storage.NewS3(storage.S3Config{
+			Endpoint:        cfg.S3.Endpoint,
+			Bucket:          cfg.S3.Bucket,
+			Region:          cfg.S3.Region,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+		})
+	}
+	return storage.NewLocal(cfg.StorageDir)
+}
+
+func newMigrator(db *gorm.DB) *migrations.Migrator {
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalln("Error getting database instance", err)
+	}
+
+	migrator, err := migrations.New(sqlDB)
+	if err != nil {
+		log.Fatalln("Error loading migrations", err)
+	}
+	return migrator
+}
+
+// requireMigrated stops when the schema is behind this build
+func requireMigrated(db *gorm.DB) {
+	pending, err := newMigrator(db).Pending(context.Background())
+	if err != nil {
+		log.Fatalln("Error checking migrations", err)
+	}
+	if len(pending) > 0 {
+		log.Fatalf("Database has %d pending migrations, run `server migrate up` first\n", len(pending))
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: server <command> [arguments]
+
+commands:
+  serve                 run the HTTP API (the default)
+  migrate               apply, roll back or list schema migrations
+  seed                  load a fixture set into an empty catalogue
+  books import          create or update books from a CSV, NDJSON or MARC file
+  books export          write the catalogue as CSV, NDJSON, MARC, MARCXML or Dublin Core
+  publishers merge      fold duplicate publishers into one
+  users create-admin    register an administrator account
+
+Run "server <command> -h" for the arguments of a command.`
+
+func main() {
+	args := os.Args[1:]
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	switch args[0] {
+	case "serve":
+		runServe(args[1:])
+	case "migrate":
+		runMigrate(args[1:])
+	case "seed":
+		runSeed(args[1:])
+	case "books":
+		runBooks(args[1:])
+	case "publishers":
+		runPublishers(args[1:])
+	case "users":
+		runUsers(args[1:])
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s\n", args[0], usage)
+		os.Exit(2)
+	}
+}
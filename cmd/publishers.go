@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"library-system/internal/entities"
+
+	"github.com/gofrs/uuid"
+)
+
+const publishersUsage = `usage: server publishers <command>
+
+commands:
+  merge -into ID DUPLICATE_ID...   fold duplicate publishers into one`
+
+// runPublishers implements the publishers commands
+func runPublishers(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, publishersUsage)
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "merge":
+		mergePublishers(args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, publishersUsage)
+		os.Exit(2)
+	}
+}
+
+// mergePublishers moves the books and imprints of the duplicates to the
+// publisher given with -into, keeps their names as its aliases and removes
+// them
+func mergePublishers(args []string) {
+	fs := flag.NewFlagSet("publishers merge", flag.ExitOnError)
+	into := fs.String("into", "", "ID of the publisher to keep")
+	fs.Parse(args)
+
+	id, err := uuid.FromString(*into)
+	if err != nil {
+		log.Fatalf("Invalid -into publisher ID %q\n", *into)
+	}
+	if fs.NArg() == 0 {
+		log.Fatalln("publishers merge needs at least one duplicate")
+	}
+
+	req := &entities.PublisherMergeRequest{}
+	for _, arg := range fs.Args() {
+		dup, err := uuid.FromString(arg)
+		if err != nil {
+			log.Fatalf("Invalid publisher ID %q\n", arg)
+		}
+		req.From = append(req.From, dup)
+	}
+
+	a := newApp()
+	if err := a.validate.Struct(req); err != nil {
+		log.Fatalln("Invalid merge", err)
+	}
+
+	publisher, err := a.service.MergePublishers(context.Background(), id, req)
+	if err != nil {
+		log.Fatalln("Error merging publishers", err)
+	}
+	fmt.Printf("merged %d publishers into %s, which now has %d books\n", len(req.From), publisher.Name, publisher.BookCount)
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"strings"
+
+	"library-system/internal/db/postgres"
+)
+
+// runSeed implements the seed command
+func runSeed(args []string) {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	set := fs.String("set", postgres.DefaultFixtures, "fixture set to load: "+strings.Join(postgres.FixtureSets(), ", "))
+	force := fs.Bool("force", false, "wipe the catalogue, its items, loans and holds before seeding")
+	fs.Parse(args)
+
+	loadEnv()
+	db := postgres.Connect()
+	requireMigrated(db)
+
+	if err := postgres.SeedData(db, *set, *force); err != nil {
+		log.Fatalln("Error seeding database", err)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	_ "library-system/docs"
+	"library-system/internal/handlers"
+	"library-system/internal/services"
+	"library-system/internal/storage"
+	"library-system/internal/web/rest"
+
+	"github.com/rs/cors"
+	httpSwagger "github.com/swaggo/http-swagger/v2"
+)
+
+// runServe implements the serve command: the HTTP API with its background
+// jobs
+func runServe(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	fs.Parse(args)
+
+	a := newApp()
+
+	go expireHolds(a.service, a.cfg.HoldExpiryInterval)
+	go purgeTrash(a.service, a.cfg.TrashPurgeInterval)
+	go failStaleImports(a.service, a.cfg.ImportLease)
+
+	handler := handlers.New(a.service, a.validate)
+	fmt.Println("Handler layer initialized")
+
+	r := rest.NewRouter(handler)
+	fmt.Println("Routers loaded")
+
+	r.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
+		httpSwagger.URL("/swagger/doc.json"),
+		httpSwagger.DeepLinking(true),
+		httpSwagger.DocExpansion("none"),
+		httpSwagger.DomID("swagger-ui"),
+	))
+	fmt.Println("Swagger documentation available at /swagger/index.html")
+
+	// Covers kept on disk are served by the API itself when the media
+	// base URL is a path on it
+	if local, ok := a.store.(*storage.Local); ok && strings.HasPrefix(a.cfg.MediaBaseURL, "/") {
+		r.PathPrefix(a.cfg.MediaBaseURL + "/").Handler(http.StripPrefix(a.cfg.MediaBaseURL, local.Handler()))
+		fmt.Printf("Covers served at %s\n", a.cfg.MediaBaseURL)
+	}
+
+	allowedOrigins := []string{"http://localhost:3000"}
+	if os.Getenv("ENV") == "prod" {
+		allowedOrigins = []string{"https://library-system-frontend.vercel.app"}
+	}
+
+	c := cors.New(cors.Options{
+		AllowedOrigins:   allowedOrigins,
+		AllowCredentials: true,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "If-Match", "If-None-Match"},
+		ExposedHeaders:   []string{"ETag", "Accept-Patch"},
+	})
+
+	corsHandler := c.Handler(r)
+
+	fmt.Printf("Server listening on %s...\n", *addr)
+	if err := http.ListenAndServe(*addr, corsHandler); err != nil {
+		log.Fatalln("Error serving", err)
+	}
+}
+
+// expireHolds periodically releases copies whose holds were not picked up
+func expireHolds(service services.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := service.ExpireHolds(context.Background()); err != nil {
+			log.Println("Error expiring holds", err)
+		}
+	}
+}
+
+// purgeTrash periodically removes books past the trash retention period
+func purgeTrash(service services.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := service.PurgeTrash(context.Background()); err != nil {
+			log.Println("Error purging trash", err)
+		}
+	}
+}
+
+// failStaleImports periodically fails import jobs whose server stopped
+// running them, such as this one before a restart
+func failStaleImports(service services.Service, lease time.Duration) {
+	ticker := time.NewTicker(lease)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := service.FailStaleImports(context.Background()); err != nil {
+			log.Println("Error failing stale imports", err)
+		}
+	}
+}
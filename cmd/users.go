@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"library-system/internal/entities"
+)
+
+const usersUsage = `usage: server users <command>
+
+commands:
+  create-admin -name NAME -email EMAIL   register an administrator; the
+                                         password is read from ADMIN_PASSWORD
+                                         or standard input`
+
+// runUsers implements the users commands
+func runUsers(args []string) {
+	if len(args) == 0 || args[0] != "create-admin" {
+		fmt.Fprintln(os.Stderr, usersUsage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("users create-admin", flag.ExitOnError)
+	name := fs.String("name", "", "display name")
+	email := fs.String("email", "", "login email")
+	fs.Parse(args[1:])
+
+	// Kept off the command line so it does not end up in shell history
+	password := os.Getenv("ADMIN_PASSWORD")
+	if password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			log.Fatalln("Error reading password", err)
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	req := &entities.RegisterRequest{Name: *name, Email: *email, Password: password}
+
+	a := newApp()
+	if err := a.validate.Struct(req); err != nil {
+		log.Fatalln("Invalid admin account", err)
+	}
+
+	user, err := a.service.CreateAdmin(context.Background(), req)
+	if err != nil {
+		log.Fatalln("Error creating admin", err)
+	}
+
+	fmt.Printf("created admin %s <%s>\n", user.ID, user.Email)
+}
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+
+	"library-system/internal/entities"
+)
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated principal
+func WithPrincipal(ctx context.Context, p *entities.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the authenticated principal, if any
+func PrincipalFromContext(ctx context.Context) (*entities.Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*entities.Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+)
+
+// Permission names an action guarded by role-based access control
+type Permission string
+
+const (
+	PermBooksCreate Permission = "books:create"
+	PermBooksUpdate Permission = "books:update"
+	// PermBooksDelete moves books to the trash and restores them
+	PermBooksDelete Permission = "books:delete"
+	// PermBooksPurge removes books from the trash for good
+	PermBooksPurge Permission = "books:purge"
+	// PermBooksImport covers bulk imports and their reports
+	PermBooksImport Permission = "books:import"
+
+	// PermAuthorsManage covers the author records books are linked to
+	PermAuthorsManage Permission = "authors:manage"
+	// PermPublishersManage covers the publisher registry, merges included
+	PermPublishersManage Permission = "publishers:manage"
+	// PermSubjectsManage covers the subject headings books are filed under
+	PermSubjectsManage Permission = "subjects:manage"
+	// PermWorksManage covers grouping editions into works and series
+	PermWorksManage Permission = "works:manage"
+
+	// PermItemsManage covers cataloguing the physical copies of books
+	PermItemsManage Permission = "items:manage"
+
+	// PermLoansBorrow lets a member borrow and return their own books
+	PermLoansBorrow Permission = "loans:borrow"
+	// PermLoansManage covers loans of any member
+	PermLoansManage Permission = "loans:manage"
+
+	// PermFinesView lets a member see their own balance and transactions
+	PermFinesView Permission = "fines:view"
+	// PermFinesManage covers every member's account: payments, waivers
+	// and charges
+	PermFinesManage Permission = "fines:manage"
+
+	// PermHoldsPlace lets a member queue for books with no copies left
+	PermHoldsPlace Permission = "holds:place"
+	// PermHoldsManage covers holds of any member and queue priorities
+	PermHoldsManage Permission = "holds:manage"
+
+	// PermReservationsBook lets a member reserve reading-room seats
+	PermReservationsBook Permission = "reservations:book"
+	// PermReservationsManage covers reservations of any member
+	PermReservationsManage Permission = "reservations:manage"
+
+	PermRolesManage Permission = "roles:manage"
+)
+
+// matrix lists what each role may do. Roles are not hierarchical, an
+// admin's permissions are spelled out in full.
+var matrix = map[enums.Role][]Permission{
+	enums.RoleMember: {
+		PermLoansBorrow,
+		PermFinesView,
+		PermHoldsPlace,
+		PermReservationsBook,
+	},
+	enums.RoleLibrarian: {
+		PermBooksCreate,
+		PermBooksUpdate,
+		PermBooksDelete,
+		PermBooksImport,
+		PermAuthorsManage,
+		PermPublishersManage,
+		PermSubjectsManage,
+		PermWorksManage,
+		PermItemsManage,
+		PermLoansBorrow,
+		PermLoansManage,
+		PermFinesView,
+		PermFinesManage,
+		PermHoldsPlace,
+		PermHoldsManage,
+		PermReservationsBook,
+		PermReservationsManage,
+	},
+	enums.RoleAdmin: {
+		PermBooksCreate,
+		PermBooksUpdate,
+		PermBooksDelete,
+		PermBooksPurge,
+		PermBooksImport,
+		PermAuthorsManage,
+		PermPublishersManage,
+		PermSubjectsManage,
+		PermWorksManage,
+		PermItemsManage,
+		PermLoansBorrow,
+		PermLoansManage,
+		PermFinesView,
+		PermFinesManage,
+		PermHoldsPlace,
+		PermHoldsManage,
+		PermReservationsBook,
+		PermReservationsManage,
+		PermRolesManage,
+	},
+}
+
+// Can reports whether any of the principal's roles grants the permission
+func Can(p *entities.Principal, perm Permission) bool {
+	if p == nil {
+		return false
+	}
+	for _, role := range p.Roles {
+		for _, granted := range matrix[role] {
+			if granted == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"testing"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+)
+
+func TestCan(t *testing.T) {
+	principal := func(roles ...enums.Role) *entities.Principal {
+		return &entities.Principal{Roles: roles}
+	}
+
+	tests := []struct {
+		name      string
+		principal *entities.Principal
+		perm      Permission
+		want      bool
+	}{
+		{name: "anonymous", principal: nil, perm: PermBooksCreate, want: false},
+		{name: "member cannot create books", principal: principal(enums.RoleMember), perm: PermBooksCreate, want: false},
+		{name: "librarian creates books", principal: principal(enums.RoleLibrarian), perm: PermBooksCreate, want: true},
+		{name: "librarian deletes books", principal: principal(enums.RoleLibrarian), perm: PermBooksDelete, want: true},
+		{name: "librarian cannot purge books", principal: principal(enums.RoleLibrarian), perm: PermBooksPurge, want: false},
+		{name: "admin purges books", principal: principal(enums.RoleAdmin), perm: PermBooksPurge, want: true},
+		{name: "librarian cannot manage roles", principal: principal(enums.RoleLibrarian), perm: PermRolesManage, want: false},
+		{name: "admin manages roles", principal: principal(enums.RoleAdmin), perm: PermRolesManage, want: true},
+		{name: "member cannot catalogue items", principal: principal(enums.RoleMember), perm: PermItemsManage, want: false},
+		{name: "librarian manages authors", principal: principal(enums.RoleLibrarian), perm: PermAuthorsManage, want: true},
+		{name: "member cannot manage authors", principal: principal(enums.RoleMember), perm: PermAuthorsManage, want: false},
+		{name: "librarian manages publishers", principal: principal(enums.RoleLibrarian), perm: PermPublishersManage, want: true},
+		{name: "member cannot manage subjects", principal: principal(enums.RoleMember), perm: PermSubjectsManage, want: false},
+		{name: "librarian groups works", principal: principal(enums.RoleLibrarian), perm: PermWorksManage, want: true},
+		{name: "librarian catalogues items", principal: principal(enums.RoleLibrarian), perm: PermItemsManage, want: true},
+		{name: "member borrows books", principal: principal(enums.RoleMember), perm: PermLoansBorrow, want: true},
+		{name: "member cannot manage loans", principal: principal(enums.RoleMember), perm: PermLoansManage, want: false},
+		{name: "librarian manages loans", principal: principal(enums.RoleLibrarian), perm: PermLoansManage, want: true},
+		{name: "member views own fines", principal: principal(enums.RoleMember), perm: PermFinesView, want: true},
+		{name: "member cannot waive fines", principal: principal(enums.RoleMember), perm: PermFinesManage, want: false},
+		{name: "librarian waives fines", principal: principal(enums.RoleLibrarian), perm: PermFinesManage, want: true},
+		{name: "any role is enough", principal: principal(enums.RoleMember, enums.RoleLibrarian), perm: PermBooksUpdate, want: true},
+		{name: "unknown role", principal: principal("superuser"), perm: PermBooksUpdate, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Can(tt.principal, tt.perm); got != tt.want {
+				t.Errorf("Can() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
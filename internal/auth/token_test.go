@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestTokenManager(t *testing.T) {
+	principal := &entities.Principal{
+		UserID: uuid.Must(uuid.NewV4()),
+		Email:  "reader@example.com",
+		Roles:  []enums.Role{enums.RoleMember, enums.RoleLibrarian},
+	}
+
+	m := NewTokenManager("secret", time.Minute, time.Hour)
+	tokens, err := m.Issue(principal)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if tokens.TokenType != "Bearer" || tokens.ExpiresIn != 60 {
+		t.Errorf("Issue() = %+v", tokens)
+	}
+
+	expired, _ := NewTokenManager("secret", -time.Minute, -time.Minute).Issue(principal)
+	forged, _ := NewTokenManager("other", time.Minute, time.Hour).Issue(principal)
+
+	tests := []struct {
+		name    string
+		parse   func(string) (*entities.Principal, error)
+		token   string
+		wantErr bool
+	}{
+		{name: "access token", parse: m.ParseAccess, token: tokens.AccessToken},
+		{name: "refresh token", parse: m.ParseRefresh, token: tokens.RefreshToken},
+		{name: "refresh token used as access", parse: m.ParseAccess, token: tokens.RefreshToken, wantErr: true},
+		{name: "access token used as refresh", parse: m.ParseRefresh, token: tokens.AccessToken, wantErr: true},
+		{name: "expired", parse: m.ParseAccess, token: expired.AccessToken, wantErr: true},
+		{name: "wrong secret", parse: m.ParseAccess, token: forged.AccessToken, wantErr: true},
+		{name: "garbage", parse: m.ParseAccess, token: "not.a.jwt", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parse error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if err != entities.ErrInvalidToken {
+					t.Errorf("parse error = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, principal) {
+				t.Errorf("parse = %+v, want %+v", got, principal)
+			}
+		})
+	}
+}
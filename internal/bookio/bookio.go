@@ -0,0 +1,283 @@
+// Package bookio reads and writes books in the bulk interchange formats used
+// by import and export: CSV with a header row, newline-delimited JSON and
+// MARC 21 records. Exports can also be written as MARCXML and OAI Dublin
+// Core.
+package bookio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/marc"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	// FormatMARC is binary MARC 21 (ISO 2709)
+	FormatMARC = "marc"
+	// FormatMARCXML is a MARC 21 XML collection, written only
+	FormatMARCXML = "marcxml"
+	// FormatDC is OAI Dublin Core records in a collection element, written
+	// only
+	FormatDC = "oai_dc"
+)
+
+var ErrUnknownFormat = errors.New("unknown format")
+
+// Columns are the CSV header fields, in the order they are written
+var Columns = []string{"isbn", "title", "author", "publisher", "publish_date", "description", "copies", "item_type", "dewey", "lcc"}
+
+// dateLayout is how publish dates are written; RFC 3339 timestamps are
+// accepted on input too
+const dateLayout = "2006-01-02"
+
+// RowError is a row that could not be decoded. Reading may continue with
+// the next row.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// Reader decodes one book request per row. Rows are numbered from 1,
+// not counting the CSV header.
+type Reader interface {
+	// Read returns the next row and its number, io.EOF after the last one,
+	// or a *RowError for a row that is malformed
+	Read() (int, *entities.BookRequest, error)
+}
+
+// Writer encodes one book per row
+type Writer interface {
+	Write(book *entities.BookResponse) error
+	// Flush writes any buffered rows and ends the document. It is called
+	// once, after the last row.
+	Flush() error
+}
+
+// ContentType is the media type of a format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatMARC:
+		return "application/marc"
+	case FormatMARCXML:
+		return "application/marcxml+xml"
+	case FormatDC:
+		return "application/xml"
+	}
+	return ""
+}
+
+// Readable reports whether books can be imported from a format
+func Readable(format string) bool {
+	return format == FormatCSV || format == FormatNDJSON || format == FormatMARC
+}
+
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVReader(r)
+	case FormatNDJSON:
+		s := bufio.NewScanner(r)
+		s.Buffer(make([]byte, 64*1024), 1024*1024)
+		return &ndjsonReader{scanner: s}, nil
+	case FormatMARC:
+		return &marcReader{r: marc.NewReader(r)}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatNDJSON:
+		bw := bufio.NewWriter(w)
+		return &ndjsonWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+	case FormatMARC:
+		return newMARCWriter(w), nil
+	case FormatMARCXML:
+		return newXMLWriter(w, marcHeader, "</collection>\n", func(b *entities.BookResponse) any { return toMARC(b) })
+	case FormatDC:
+		return newXMLWriter(w, xml.Header+"<collection>\n", "</collection>\n", func(b *entities.BookResponse) any { return toDC(b) })
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv has no header row")
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"isbn", "title"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv header has no %s column", name)
+		}
+	}
+
+	return &csvReader{r: cr, columns: columns}, nil
+}
+
+func (c *csvReader) Read() (int, *entities.BookRequest, error) {
+	record, err := c.r.Read()
+	if errors.Is(err, io.EOF) {
+		return 0, nil, io.EOF
+	}
+	c.row++
+	if err != nil {
+		return c.row, nil, &RowError{Row: c.row, Err: err}
+	}
+
+	field := func(name string) string {
+		i, ok := c.columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	req := &entities.BookRequest{
+		ISBN:        field("isbn"),
+		Title:       field("title"),
+		Author:      field("author"),
+		Publisher:   field("publisher"),
+		Description: field("description"),
+		ItemType:    enums.ItemType(field("item_type")),
+		Dewey:       field("dewey"),
+		LCC:         field("lcc"),
+	}
+	if v := field("publish_date"); v != "" {
+		if req.PublishDate, err = parseDate(v); err != nil {
+			return c.row, nil, &RowError{Row: c.row, Err: err}
+		}
+	}
+	if v := field("copies"); v != "" {
+		if req.Copies, err = strconv.Atoi(v); err != nil {
+			return c.row, nil, &RowError{Row: c.row, Err: fmt.Errorf("invalid copies %q", v)}
+		}
+	}
+
+	return c.row, req, nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	row     int
+}
+
+func (n *ndjsonReader) Read() (int, *entities.BookRequest, error) {
+	for n.scanner.Scan() {
+		line := bytes.TrimSpace(n.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		n.row++
+
+		var req entities.BookRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			return n.row, nil, &RowError{Row: n.row, Err: err}
+		}
+		return n.row, &req, nil
+	}
+	if err := n.scanner.Err(); err != nil {
+		return 0, nil, err
+	}
+	return 0, nil, io.EOF
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(Columns); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: cw}, nil
+}
+
+func (c *csvWriter) Write(book *entities.BookResponse) error {
+	return c.w.Write([]string{
+		book.ISBN,
+		book.Title,
+		book.Author,
+		book.Publisher,
+		book.PublishDate.Format(dateLayout),
+		book.Description,
+		strconv.Itoa(book.Copies),
+		string(book.ItemType),
+		book.Dewey,
+		book.LCC,
+	})
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(book *entities.BookResponse) error {
+	return n.enc.Encode(book)
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return n.w.Flush()
+}
+
+func parseDate(v string) (time.Time, error) {
+	if t, err := time.Parse(dateLayout, v); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid publish_date %q", v)
+	}
+	return t, nil
+}
@@ -0,0 +1,273 @@
+package bookio
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+)
+
+func readAll(t *testing.T, r Reader) ([]*entities.BookRequest, []int) {
+	t.Helper()
+
+	var reqs []*entities.BookRequest
+	var bad []int
+	for {
+		row, req, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return reqs, bad
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			bad = append(bad, row)
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		reqs = append(reqs, req)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	in := "Title,ISBN,Copies,publish_date\n" +
+		"Dune,9780441013593,3,1965-08-01\n" +
+		"Bad copies,9780441013593,many,\n" +
+		"\"Comma, in title\",0441013597,,1965-08-01T00:00:00Z\n"
+
+	r, err := NewReader(FormatCSV, strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	reqs, bad := readAll(t, r)
+
+	if len(reqs) != 2 || len(bad) != 1 || bad[0] != 2 {
+		t.Fatalf("read %d rows, bad rows %v", len(reqs), bad)
+	}
+	if reqs[0].Title != "Dune" || reqs[0].Copies != 3 || reqs[0].PublishDate.Year() != 1965 {
+		t.Errorf("row 1 = %+v", reqs[0])
+	}
+	if reqs[1].Title != "Comma, in title" || reqs[1].ISBN != "0441013597" {
+		t.Errorf("row 3 = %+v", reqs[1])
+	}
+}
+
+func TestCSVReader_missingColumn(t *testing.T) {
+	if _, err := NewReader(FormatCSV, strings.NewReader("title,author\n")); err == nil {
+		t.Error("NewReader() error = nil, want missing isbn column")
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	in := `{"title":"Dune","isbn":"9780441013593","copies":2}` + "\n\n" +
+		`{"title":` + "\n" +
+		`{"title":"Emma","isbn":"9780141439587"}` + "\n"
+
+	r, err := NewReader(FormatNDJSON, strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	reqs, bad := readAll(t, r)
+
+	if len(reqs) != 2 || len(bad) != 1 || bad[0] != 2 {
+		t.Fatalf("read %d rows, bad rows %v", len(reqs), bad)
+	}
+	if reqs[1].Title != "Emma" {
+		t.Errorf("row 3 = %+v", reqs[1])
+	}
+}
+
+func TestWriter_roundTrip(t *testing.T) {
+	book := &entities.BookResponse{
+		Title:       "Dune",
+		Author:      "Frank Herbert",
+		ISBN:        "9780441013593",
+		Publisher:   "Ace",
+		PublishDate: time.Date(1965, time.August, 1, 0, 0, 0, 0, time.UTC),
+		Copies:      4,
+		ItemType:    "book",
+	}
+
+	for _, format := range []string{FormatCSV, FormatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(format, &buf)
+			if err != nil {
+				t.Fatalf("NewWriter() error = %v", err)
+			}
+			if err := w.Write(book); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatalf("Flush() error = %v", err)
+			}
+
+			r, err := NewReader(format, &buf)
+			if err != nil {
+				t.Fatalf("NewReader() error = %v", err)
+			}
+			reqs, bad := readAll(t, r)
+			if len(reqs) != 1 || len(bad) != 0 {
+				t.Fatalf("read %d rows, bad rows %v", len(reqs), bad)
+			}
+			got := reqs[0]
+			if got.Title != book.Title || got.ISBN != book.ISBN || got.Copies != book.Copies ||
+				!got.PublishDate.Equal(book.PublishDate) || got.ItemType != book.ItemType {
+				t.Errorf("round trip = %+v", got)
+			}
+		})
+	}
+}
+
+func TestNewReader_unknownFormat(t *testing.T) {
+	if _, err := NewReader("xml", strings.NewReader("")); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("NewReader() error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+func TestMARCXMLWriter(t *testing.T) {
+	books := []*entities.BookResponse{
+		{Title: "Dune & Sons", Author: "Frank Herbert", ISBN: "9780441013593", Publisher: "Ace",
+			PublishDate: time.Date(1965, time.August, 1, 0, 0, 0, 0, time.UTC), ItemType: "book"},
+		{Title: "Analog", ISBN: "9771059211002", ItemType: "periodical"},
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatMARCXML, &buf)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	for _, b := range books {
+		if err := w.Write(b); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	var got struct {
+		Records []marcRecord `xml:"record"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not XML: %v\n%s", err, buf.String())
+	}
+	if len(got.Records) != 2 {
+		t.Fatalf("wrote %d records, want 2", len(got.Records))
+	}
+
+	subfield := func(r marcRecord, tag, code string) string {
+		for _, f := range r.DataFields {
+			for _, sf := range f.Subfields {
+				if f.Tag == tag && sf.Code == code {
+					return sf.Value
+				}
+			}
+		}
+		return ""
+	}
+
+	dune := got.Records[0]
+	if len(dune.Leader) != 24 || dune.Leader[6:8] != "am" {
+		t.Errorf("leader = %q", dune.Leader)
+	}
+	for _, want := range []struct{ tag, code, value string }{
+		{"020", "a", "9780441013593"},
+		{"100", "a", "Frank Herbert"},
+		{"245", "a", "Dune & Sons"},
+		{"264", "b", "Ace"},
+		{"264", "c", "1965"},
+	} {
+		if v := subfield(dune, want.tag, want.code); v != want.value {
+			t.Errorf("%s $%s = %q, want %q", want.tag, want.code, v, want.value)
+		}
+	}
+
+	analog := got.Records[1]
+	if analog.Leader[7] != 's' {
+		t.Errorf("periodical leader = %q, want serial level", analog.Leader)
+	}
+	if v := subfield(analog, "100", "a"); v != "" {
+		t.Errorf("100 $a = %q, want no author field", v)
+	}
+}
+
+func TestDCWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatDC, &buf)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	if err := w.Write(&entities.BookResponse{Title: "Dune", Author: "Frank Herbert", ISBN: "9780441013593",
+		PublishDate: time.Date(1965, time.August, 1, 0, 0, 0, 0, time.UTC), ItemType: "book"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	var got struct {
+		Records []struct {
+			Title      string `xml:"http://purl.org/dc/elements/1.1/ title"`
+			Creator    string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+			Date       string `xml:"http://purl.org/dc/elements/1.1/ date"`
+			Identifier string `xml:"http://purl.org/dc/elements/1.1/ identifier"`
+		} `xml:"http://www.openarchives.org/OAI/2.0/oai_dc/ dc"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not XML: %v\n%s", err, buf.String())
+	}
+	if len(got.Records) != 1 {
+		t.Fatalf("wrote %d records, want 1", len(got.Records))
+	}
+	r := got.Records[0]
+	if r.Title != "Dune" || r.Creator != "Frank Herbert" || r.Date != "1965-08-01" || r.Identifier != "urn:isbn:9780441013593" {
+		t.Errorf("record = %+v", r)
+	}
+}
+
+func TestMARC_roundTrip(t *testing.T) {
+	book := &entities.BookResponse{
+		Title:       "Dune",
+		Author:      "Frank Herbert",
+		ISBN:        "9780441013593",
+		Publisher:   "Ace",
+		PublishDate: time.Date(1965, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Copies:      4,
+		ItemType:    "book",
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(FormatMARC, &buf)
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	if err := w.Write(book); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	// A record cut short at the end of the file
+	buf.WriteString("00100nam")
+
+	r, err := NewReader(FormatMARC, &buf)
+	if err != nil {
+		t.Fatalf("NewReader() error = %v", err)
+	}
+	reqs, bad := readAll(t, r)
+	if len(reqs) != 1 || len(bad) != 1 || bad[0] != 2 {
+		t.Fatalf("read %d rows, bad rows %v", len(reqs), bad)
+	}
+
+	got := reqs[0]
+	if got.Title != book.Title || got.Author != book.Author || got.ISBN != book.ISBN ||
+		!got.PublishDate.Equal(book.PublishDate) || got.Copies != 0 || len(got.MARC) == 0 {
+		t.Errorf("round trip = %+v", got)
+	}
+}
@@ -0,0 +1,88 @@
+package bookio
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	"library-system/internal/entities"
+	"library-system/internal/marc"
+)
+
+type marcReader struct {
+	r   *marc.Reader
+	row int
+}
+
+// Read maps the next MARC record onto a book request that keeps the raw
+// record. Records say nothing about copies, so Copies is left at zero.
+func (m *marcReader) Read() (int, *entities.BookRequest, error) {
+	raw, err := m.r.Read()
+	if errors.Is(err, io.EOF) {
+		return 0, nil, io.EOF
+	}
+	m.row++
+	if err != nil {
+		// A truncated record can only be the last one
+		if errors.Is(err, marc.ErrMalformed) {
+			return m.row, nil, &RowError{Row: m.row, Err: err}
+		}
+		return 0, nil, err
+	}
+
+	book, err := marc.ToBook(raw)
+	if err != nil {
+		return m.row, nil, &RowError{Row: m.row, Err: err}
+	}
+
+	return m.row, &entities.BookRequest{
+		Title:       book.Title,
+		Author:      book.Author,
+		ISBN:        book.ISBN,
+		Publisher:   book.Publisher,
+		PublishDate: book.PublishDate,
+		Description: book.Description,
+		ItemType:    book.ItemType,
+		Dewey:       book.Dewey,
+		LCC:         book.LCC,
+		MARC:        book.MARC,
+	}, nil
+}
+
+type marcWriter struct {
+	w  *bufio.Writer
+	mw *marc.Writer
+}
+
+func newMARCWriter(w io.Writer) *marcWriter {
+	bw := bufio.NewWriter(w)
+	return &marcWriter{w: bw, mw: marc.NewWriter(bw)}
+}
+
+func (m *marcWriter) Write(book *entities.BookResponse) error {
+	return m.mw.Write(marc.FromBook(fromResponse(book)))
+}
+
+func (m *marcWriter) Flush() error {
+	return m.w.Flush()
+}
+
+// fromResponse recovers the stored fields of a book for the MARC mapping
+func fromResponse(book *entities.BookResponse) *entities.Book {
+	return &entities.Book{
+		ID:          book.ID,
+		CreatedAt:   book.CreatedAt,
+		UpdatedAt:   book.UpdatedAt,
+		Title:       book.Title,
+		Author:      book.Author,
+		ISBN:        book.ISBN,
+		Publisher:   book.Publisher,
+		PublishDate: book.PublishDate,
+		Description: book.Description,
+		Copies:      book.Copies,
+		ItemType:    book.ItemType,
+		Dewey:       book.Dewey,
+		LCC:         book.LCC,
+		MARC:        book.MARC,
+	}
+}
@@ -0,0 +1,137 @@
+package bookio
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/marc"
+)
+
+const marcHeader = xml.Header + `<collection xmlns="http://www.loc.gov/MARC21/slim">` + "\n"
+
+// marcRecord is a bibliographic record in the MARC 21 XML schema
+type marcRecord struct {
+	XMLName       xml.Name           `xml:"record"`
+	Leader        string             `xml:"leader"`
+	ControlFields []marcControlField `xml:"controlfield"`
+	DataFields    []marcDataField    `xml:"datafield"`
+}
+
+type marcControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type marcDataField struct {
+	Tag       string         `xml:"tag,attr"`
+	Ind1      string         `xml:"ind1,attr"`
+	Ind2      string         `xml:"ind2,attr"`
+	Subfields []marcSubfield `xml:"subfield"`
+}
+
+type marcSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// toMARC writes the MARC record of a book, the same one a MARC 21 export
+// carries, in the XML schema
+func toMARC(book *entities.BookResponse) *marcRecord {
+	record := marc.FromBook(fromResponse(book))
+
+	out := &marcRecord{Leader: record.Leader}
+	for _, f := range record.Fields {
+		if f.IsControl() {
+			out.ControlFields = append(out.ControlFields, marcControlField{Tag: f.Tag, Value: f.Value})
+			continue
+		}
+		df := marcDataField{Tag: f.Tag, Ind1: string(indicator(f.Ind1)), Ind2: string(indicator(f.Ind2))}
+		for _, sf := range f.Subfields {
+			df.Subfields = append(df.Subfields, marcSubfield{Code: string(sf.Code), Value: sf.Value})
+		}
+		out.DataFields = append(out.DataFields, df)
+	}
+	return out
+}
+
+func indicator(b byte) byte {
+	if b == 0 {
+		return ' '
+	}
+	return b
+}
+
+// dcRecord is an unqualified Dublin Core record as served by OAI-PMH
+type dcRecord struct {
+	XMLName        xml.Name `xml:"oai_dc:dc"`
+	NSOAI          string   `xml:"xmlns:oai_dc,attr"`
+	NSDC           string   `xml:"xmlns:dc,attr"`
+	NSXSI          string   `xml:"xmlns:xsi,attr"`
+	SchemaLocation string   `xml:"xsi:schemaLocation,attr"`
+	Title          string   `xml:"dc:title,omitempty"`
+	Creator        string   `xml:"dc:creator,omitempty"`
+	Publisher      string   `xml:"dc:publisher,omitempty"`
+	Date           string   `xml:"dc:date,omitempty"`
+	Description    string   `xml:"dc:description,omitempty"`
+	Identifier     string   `xml:"dc:identifier,omitempty"`
+	Type           string   `xml:"dc:type,omitempty"`
+}
+
+func toDC(book *entities.BookResponse) *dcRecord {
+	record := &dcRecord{
+		NSOAI:          "http://www.openarchives.org/OAI/2.0/oai_dc/",
+		NSDC:           "http://purl.org/dc/elements/1.1/",
+		NSXSI:          "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: "http://www.openarchives.org/OAI/2.0/oai_dc/ http://www.openarchives.org/OAI/2.0/oai_dc.xsd",
+		Title:          book.Title,
+		Creator:        book.Author,
+		Publisher:      book.Publisher,
+		Description:    book.Description,
+	}
+	if !book.PublishDate.IsZero() {
+		record.Date = book.PublishDate.Format(dateLayout)
+	}
+	if book.ISBN != "" {
+		record.Identifier = "urn:isbn:" + book.ISBN
+	}
+	// DCMI type vocabulary; media covers too many kinds to name one
+	if book.ItemType != enums.ItemMedia {
+		record.Type = "Text"
+	}
+	return record
+}
+
+// xmlWriter writes one element per book between a header and a footer
+type xmlWriter struct {
+	w      *bufio.Writer
+	enc    *xml.Encoder
+	record func(*entities.BookResponse) any
+	footer string
+}
+
+func newXMLWriter(w io.Writer, header, footer string, record func(*entities.BookResponse) any) (*xmlWriter, error) {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(header); err != nil {
+		return nil, err
+	}
+	enc := xml.NewEncoder(bw)
+	enc.Indent("  ", "  ")
+	return &xmlWriter{w: bw, enc: enc, record: record, footer: footer}, nil
+}
+
+func (x *xmlWriter) Write(book *entities.BookResponse) error {
+	if err := x.enc.Encode(x.record(book)); err != nil {
+		return err
+	}
+	return x.enc.Flush()
+}
+
+func (x *xmlWriter) Flush() error {
+	if _, err := x.w.WriteString("\n" + x.footer); err != nil {
+		return err
+	}
+	return x.w.Flush()
+}
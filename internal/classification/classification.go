@@ -0,0 +1,169 @@
+// Package classification reads Dewey Decimal and Library of Congress call
+// numbers: it validates them, finds the class a call number files under
+// and places classes in the outline of their scheme.
+package classification
+
+import (
+	"regexp"
+	"strings"
+
+	"library-system/internal/entities/enums"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	// A Dewey number has three digits and an optional decimal part, and
+	// may be followed by a Cutter number, a work mark or a date
+	deweyPattern = regexp.MustCompile(`^(\d{3})(\.\d+)?( .+)?$`)
+	// An LC call number has one to three class letters, a class number
+	// and optional Cutter numbers and date
+	lccPattern = regexp.MustCompile(`^([A-Z]{1,3}) ?\d{1,4}(\.\d+)?([ .].+)?$`)
+)
+
+// Normalize tidies a call number as typed: surrounding and repeated spaces
+// are dropped and LC class letters are upper-cased
+func Normalize(scheme enums.ClassScheme, callNumber string) string {
+	callNumber = strings.Join(strings.Fields(callNumber), " ")
+	if scheme == enums.SchemeLCC {
+		callNumber = strings.ToUpper(callNumber)
+	}
+	return callNumber
+}
+
+// Valid reports whether a normalized call number belongs to the scheme
+func Valid(scheme enums.ClassScheme, callNumber string) bool {
+	switch scheme {
+	case enums.SchemeDewey:
+		return deweyPattern.MatchString(callNumber)
+	case enums.SchemeLCC:
+		return lccPattern.MatchString(callNumber)
+	}
+	return false
+}
+
+// ValidateDewey is a validator.Func for string fields holding a Dewey call
+// number. Empty fields pass; pair it with required when needed.
+func ValidateDewey(fl validator.FieldLevel) bool {
+	v := fl.Field().String()
+	return v == "" || Valid(enums.SchemeDewey, Normalize(enums.SchemeDewey, v))
+}
+
+// ValidateLCC is a validator.Func for string fields holding a Library of
+// Congress call number. Empty fields pass.
+func ValidateLCC(fl validator.FieldLevel) bool {
+	v := fl.Field().String()
+	return v == "" || Valid(enums.SchemeLCC, Normalize(enums.SchemeLCC, v))
+}
+
+// Class is the class a call number files under: the three digit section
+// of a Dewey number ("813" for "813.54 L478t"), the class letters of an
+// LC call number ("PS" for "PS3545.E6 G7"). It is empty for call numbers
+// that are not valid.
+func Class(scheme enums.ClassScheme, callNumber string) string {
+	var m []string
+	switch scheme {
+	case enums.SchemeDewey:
+		m = deweyPattern.FindStringSubmatch(callNumber)
+	case enums.SchemeLCC:
+		m = lccPattern.FindStringSubmatch(callNumber)
+	}
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
+
+// Path lists a class and the broader classes above it, broadest first.
+// Dewey sections sit under a division and a main class ("800", "810",
+// "813"); LC subclasses sit under their main class letter ("P", "PS").
+func Path(scheme enums.ClassScheme, class string) []string {
+	switch scheme {
+	case enums.SchemeDewey:
+		if len(class) != 3 {
+			return nil
+		}
+		path := []string{class[:1] + "00"}
+		if division := class[:2] + "0"; division != path[0] {
+			path = append(path, division)
+		}
+		if class != path[len(path)-1] {
+			path = append(path, class)
+		}
+		return path
+	case enums.SchemeLCC:
+		if class == "" {
+			return nil
+		}
+		if len(class) == 1 {
+			return []string{class}
+		}
+		return []string{class[:1], class}
+	}
+	return nil
+}
+
+// Prefix is the leading part shared by every call number filed under a
+// class: the trailing zeros of a Dewey main class or division are
+// dropped, so "800" covers 800 to 899 and "810" covers 810 to 819. Other
+// values, including partial call numbers such as "813.5", are kept.
+func Prefix(scheme enums.ClassScheme, class string) string {
+	class = Normalize(scheme, class)
+	if scheme == enums.SchemeDewey && len(class) == 3 && !strings.Contains(class, ".") {
+		if class[1:] == "00" {
+			return class[:1]
+		}
+		if class[2] == '0' {
+			return class[:2]
+		}
+	}
+	return class
+}
+
+// Label names the main classes of each scheme, for browsing
+func Label(scheme enums.ClassScheme, class string) string {
+	switch scheme {
+	case enums.SchemeDewey:
+		return deweyClasses[class]
+	case enums.SchemeLCC:
+		return lccClasses[class]
+	}
+	return ""
+}
+
+var deweyClasses = map[string]string{
+	"000": "Computer science, information and general works",
+	"100": "Philosophy and psychology",
+	"200": "Religion",
+	"300": "Social sciences",
+	"400": "Language",
+	"500": "Science",
+	"600": "Technology",
+	"700": "Arts and recreation",
+	"800": "Literature",
+	"900": "History and geography",
+}
+
+var lccClasses = map[string]string{
+	"A": "General works",
+	"B": "Philosophy, psychology and religion",
+	"C": "Auxiliary sciences of history",
+	"D": "World history",
+	"E": "History of the Americas",
+	"F": "History of the Americas",
+	"G": "Geography, anthropology and recreation",
+	"H": "Social sciences",
+	"J": "Political science",
+	"K": "Law",
+	"L": "Education",
+	"M": "Music",
+	"N": "Fine arts",
+	"P": "Language and literature",
+	"Q": "Science",
+	"R": "Medicine",
+	"S": "Agriculture",
+	"T": "Technology",
+	"U": "Military science",
+	"V": "Naval science",
+	"Z": "Bibliography and library science",
+}
@@ -0,0 +1,75 @@
+package classification
+
+import (
+	"slices"
+	"testing"
+
+	"library-system/internal/entities/enums"
+)
+
+func TestClass(t *testing.T) {
+	tests := []struct {
+		scheme enums.ClassScheme
+		in     string
+		want   string
+	}{
+		{scheme: enums.SchemeDewey, in: "813.54 L478t", want: "813"},
+		{scheme: enums.SchemeDewey, in: "005.133", want: "005"},
+		{scheme: enums.SchemeDewey, in: "81.3", want: ""},
+		{scheme: enums.SchemeLCC, in: "PS3545.E6 G7 1939", want: "PS"},
+		{scheme: enums.SchemeLCC, in: "QA76.73.G63", want: "QA"},
+		{scheme: enums.SchemeLCC, in: "E 185.61", want: "E"},
+		{scheme: enums.SchemeLCC, in: "PS", want: ""},
+		{scheme: enums.SchemeLCC, in: "813.54", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := Class(tt.scheme, Normalize(tt.scheme, tt.in)); got != tt.want {
+				t.Errorf("Class(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPath(t *testing.T) {
+	tests := []struct {
+		scheme enums.ClassScheme
+		class  string
+		want   []string
+	}{
+		{scheme: enums.SchemeDewey, class: "813", want: []string{"800", "810", "813"}},
+		{scheme: enums.SchemeDewey, class: "810", want: []string{"800", "810"}},
+		{scheme: enums.SchemeDewey, class: "800", want: []string{"800"}},
+		{scheme: enums.SchemeDewey, class: "005", want: []string{"000", "005"}},
+		{scheme: enums.SchemeLCC, class: "PS", want: []string{"P", "PS"}},
+		{scheme: enums.SchemeLCC, class: "E", want: []string{"E"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.class, func(t *testing.T) {
+			if got := Path(tt.scheme, tt.class); !slices.Equal(got, tt.want) {
+				t.Errorf("Path(%q) = %v, want %v", tt.class, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	tests := []struct {
+		class string
+		want  string
+	}{
+		{class: "800", want: "8"},
+		{class: "810", want: "81"},
+		{class: "813", want: "813"},
+		{class: "813.5", want: "813.5"},
+		{class: "000", want: "0"},
+	}
+
+	for _, tt := range tests {
+		if got := Prefix(enums.SchemeDewey, tt.class); got != tt.want {
+			t.Errorf("Prefix(%q) = %q, want %q", tt.class, got, tt.want)
+		}
+	}
+}
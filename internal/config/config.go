@@ -0,0 +1,257 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"library-system/internal/entities/enums"
+)
+
+// Config holds the runtime settings read from the environment
+type Config struct {
+	JWTSecret       string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// LoanPeriodDays is how many days a checkout lasts; loans fall due
+	// at the end of the last day
+	LoanPeriodDays int
+
+	// Reading-room seats per location and how many slots a member may
+	// reserve on a single day
+	SeatsInside        int
+	SeatsOutside       int
+	ReservationsPerDay int
+
+	// HoldPickupDays is how long a copy set aside for a hold waits on the
+	// hold shelf; HoldExpiryInterval is how often lapsed holds are swept
+	HoldPickupDays     int
+	HoldExpiryInterval time.Duration
+
+	// Overdue fines in minor currency units: a daily rate per item type,
+	// days late that are not fined, and the most one loan can be fined.
+	// Members owing more than GoodStandingLimit cannot borrow.
+	FineRates         map[enums.ItemType]int64
+	FineGraceDays     int
+	FineCap           int64
+	GoodStandingLimit int64
+
+	// Deleted books stay in the trash for TrashRetention before the purge
+	// job, run every TrashPurgeInterval, removes them for good
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// Bulk imports up to ImportSyncLimit bytes are processed while the
+	// request waits, larger ones in the background, up to ImportMaxBytes.
+	// Rows are committed ImportBatchSize at a time. A job whose heartbeat
+	// is older than ImportLease is failed as abandoned.
+	ImportSyncLimit int64
+	ImportMaxBytes  int64
+	ImportBatchSize int
+	ImportLease     time.Duration
+
+	// Covers are kept in StorageBackend, "local" (under StorageDir) or
+	// "s3", and linked to below MediaBaseURL. Uploads are limited to
+	// CoverMaxBytes.
+	StorageBackend string
+	StorageDir     string
+	MediaBaseURL   string
+	S3             S3Config
+	CoverMaxBytes  int64
+}
+
+// S3Config locates the S3-compatible bucket covers are kept in
+type S3Config struct {
+	Endpoint        string
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// Load reads the configuration from environment variables, applying
+// defaults for everything but secrets
+func Load() (*Config, error) {
+	cfg := &Config{
+		JWTSecret: os.Getenv("JWT_SECRET"),
+	}
+	if cfg.JWTSecret == "" {
+		return nil, errors.New("JWT_SECRET environment variable is not set")
+	}
+
+	var err error
+	if cfg.AccessTokenTTL, err = duration("ACCESS_TOKEN_TTL", 15*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.RefreshTokenTTL, err = duration("REFRESH_TOKEN_TTL", 7*24*time.Hour); err != nil {
+		return nil, err
+	}
+
+	if cfg.LoanPeriodDays, err = integer("LOAN_PERIOD_DAYS", 14); err != nil {
+		return nil, err
+	}
+
+	if cfg.SeatsInside, err = integer("READING_ROOM_SEATS_INSIDE", 30); err != nil {
+		return nil, err
+	}
+	if cfg.SeatsOutside, err = integer("READING_ROOM_SEATS_OUTSIDE", 10); err != nil {
+		return nil, err
+	}
+	if cfg.ReservationsPerDay, err = integer("RESERVATIONS_PER_DAY", 2); err != nil {
+		return nil, err
+	}
+
+	if cfg.HoldPickupDays, err = integer("HOLD_PICKUP_DAYS", 3); err != nil {
+		return nil, err
+	}
+	if cfg.HoldExpiryInterval, err = duration("HOLD_EXPIRY_INTERVAL", 15*time.Minute); err != nil {
+		return nil, err
+	}
+
+	if cfg.FineRates, err = fineRates("FINE_RATES"); err != nil {
+		return nil, err
+	}
+	if cfg.FineGraceDays, err = nonNegative("FINE_GRACE_DAYS", 1); err != nil {
+		return nil, err
+	}
+	fineCap, err := integer("FINE_CAP", 1000)
+	if err != nil {
+		return nil, err
+	}
+	cfg.FineCap = int64(fineCap)
+	limit, err := nonNegative("GOOD_STANDING_LIMIT", 500)
+	if err != nil {
+		return nil, err
+	}
+	cfg.GoodStandingLimit = int64(limit)
+
+	if cfg.TrashRetention, err = duration("TRASH_RETENTION", 30*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.TrashPurgeInterval, err = duration("TRASH_PURGE_INTERVAL", 24*time.Hour); err != nil {
+		return nil, err
+	}
+
+	syncLimit, err := nonNegative("IMPORT_SYNC_LIMIT", 1<<20)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ImportSyncLimit = int64(syncLimit)
+	importMax, err := integer("IMPORT_MAX_BYTES", 64<<20)
+	if err != nil {
+		return nil, err
+	}
+	cfg.ImportMaxBytes = int64(importMax)
+	if cfg.ImportBatchSize, err = integer("IMPORT_BATCH_SIZE", 500); err != nil {
+		return nil, err
+	}
+	if cfg.ImportLease, err = duration("IMPORT_LEASE", 2*time.Minute); err != nil {
+		return nil, err
+	}
+
+	cfg.StorageBackend = text("STORAGE_BACKEND", "local")
+	cfg.StorageDir = text("STORAGE_DIR", "media")
+	cfg.MediaBaseURL = strings.TrimSuffix(text("MEDIA_BASE_URL", "/media"), "/")
+	cfg.S3 = S3Config{
+		Endpoint:        os.Getenv("S3_ENDPOINT"),
+		Bucket:          os.Getenv("S3_BUCKET"),
+		Region:          text("S3_REGION", "us-east-1"),
+		AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+	}
+	switch cfg.StorageBackend {
+	case "local":
+	case "s3":
+		if cfg.S3.Endpoint == "" || cfg.S3.Bucket == "" {
+			return nil, errors.New("S3_ENDPOINT and S3_BUCKET must be set for the s3 storage backend")
+		}
+	default:
+		return nil, fmt.Errorf("invalid STORAGE_BACKEND %q", cfg.StorageBackend)
+	}
+	coverMax, err := integer("COVER_MAX_BYTES", 5<<20)
+	if err != nil {
+		return nil, err
+	}
+	cfg.CoverMaxBytes = int64(coverMax)
+
+	return cfg, nil
+}
+
+// defaultFineRates are the daily fines per item type, overridable one by
+// one through FINE_RATES
+var defaultFineRates = map[enums.ItemType]int64{
+	enums.ItemBook:       25,
+	enums.ItemPeriodical: 10,
+	enums.ItemMedia:      100,
+	enums.ItemReference:  50,
+}
+
+// fineRates parses "type:rate" pairs such as "book:25,media:100" over the
+// default rates
+func fineRates(key string) (map[enums.ItemType]int64, error) {
+	rates := make(map[enums.ItemType]int64, len(defaultFineRates))
+	for t, rate := range defaultFineRates {
+		rates[t] = rate
+	}
+
+	v := os.Getenv(key)
+	if v == "" {
+		return rates, nil
+	}
+	for _, pair := range strings.Split(v, ",") {
+		t, rate, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		n, err := strconv.ParseInt(rate, 10, 64)
+		if !ok || err != nil || n < 0 || !enums.ItemType(t).IsValid() {
+			return nil, fmt.Errorf("invalid %s %q", key, v)
+		}
+		rates[enums.ItemType(t)] = n
+	}
+	return rates, nil
+}
+
+func text(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func integer(key string, def int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, v)
+	}
+	return n, nil
+}
+
+func nonNegative(key string, def int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, v)
+	}
+	return n, nil
+}
+
+func duration(key string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, v)
+	}
+	return d, nil
+}
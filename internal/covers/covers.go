@@ -0,0 +1,149 @@
+// Package covers checks uploaded cover images and renders the thumbnails
+// served alongside them.
+package covers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	// ErrUnsupported is returned for uploads that are not JPEG, PNG or
+	// WebP images
+	ErrUnsupported = errors.New("cover must be a JPEG, PNG or WebP image")
+	// ErrCorrupt is returned for images that cannot be decoded
+	ErrCorrupt = errors.New("cover image cannot be decoded")
+	// ErrDimensions is returned for images too small to show or too large
+	// to decode safely
+	ErrDimensions = errors.New("cover image dimensions are out of range")
+)
+
+// MinSide and MaxSide bound the width and height of an upload in pixels.
+// MaxSide keeps a small compressed file from decoding into a huge bitmap.
+const (
+	MinSide = 16
+	MaxSide = 8000
+)
+
+// Sizes are the widths of the thumbnails rendered for every cover
+var Sizes = []Size{
+	{Name: "small", Width: 120},
+	{Name: "medium", Width: 300},
+	{Name: "large", Width: 600},
+}
+
+type Size struct {
+	Name  string
+	Width int
+}
+
+// types maps the accepted content types to file extensions
+var types = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// Blob is a file to store under a key
+type Blob struct {
+	Key         string
+	Data        []byte
+	ContentType string
+}
+
+// Cover is a processed upload: the original as sent and its thumbnails
+type Cover struct {
+	Original   Blob
+	Thumbnails []Blob
+}
+
+// Blobs returns the original followed by the thumbnails
+func (c *Cover) Blobs() []Blob {
+	return append([]Blob{c.Original}, c.Thumbnails...)
+}
+
+// Process checks an upload for a book by its content rather than its
+// declared type and renders the thumbnails. Keys are named after the
+// content hash so a new upload never overwrites a cached one.
+func Process(bookID uuid.UUID, data []byte) (*Cover, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := types[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: got %s", ErrUnsupported, contentType)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	if cfg.Width < MinSide || cfg.Height < MinSide || cfg.Width > MaxSide || cfg.Height > MaxSide {
+		return nil, fmt.Errorf("%w: %dx%d", ErrDimensions, cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+
+	sum := sha256.Sum256(data)
+	base := "covers/" + bookID.String() + "/" + hex.EncodeToString(sum[:8])
+	cover := &Cover{Original: Blob{Key: base + ext, Data: data, ContentType: contentType}}
+	for _, size := range Sizes {
+		thumb, err := thumbnail(img, size.Width)
+		if err != nil {
+			return nil, err
+		}
+		cover.Thumbnails = append(cover.Thumbnails, Blob{Key: base + "-" + size.Name + ".jpg", Data: thumb, ContentType: "image/jpeg"})
+	}
+
+	return cover, nil
+}
+
+// Keys returns the keys of a stored cover by size name, "original"
+// included, from the key of its original
+func Keys(original string) map[string]string {
+	if original == "" {
+		return nil
+	}
+
+	base := strings.TrimSuffix(original, path.Ext(original))
+	keys := map[string]string{"original": original}
+	for _, size := range Sizes {
+		keys[size.Name] = base + "-" + size.Name + ".jpg"
+	}
+	return keys
+}
+
+// thumbnail scales the image to a width, never enlarging it, onto a white
+// background so transparent covers come out as they look on a page
+func thumbnail(img image.Image, width int) ([]byte, error) {
+	src := img.Bounds()
+	if src.Dx() < width {
+		width = src.Dx()
+	}
+	height := max(1, src.Dy()*width/src.Dx())
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, src, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
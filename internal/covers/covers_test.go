@@ -0,0 +1,89 @@
+package covers
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid"
+)
+
+func TestProcess(t *testing.T) {
+	id := uuid.Must(uuid.NewV4())
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantExt string
+		wantErr error
+	}{
+		{name: "png", data: encode(t, "png", 400, 600), wantExt: ".png"},
+		{name: "small jpeg", data: encode(t, "jpeg", 200, 300), wantExt: ".jpg"},
+		{name: "text", data: []byte("not an image at all"), wantErr: ErrUnsupported},
+		{name: "gif", data: []byte("GIF89a\x01\x00\x01\x00"), wantErr: ErrUnsupported},
+		{name: "truncated png", data: encode(t, "png", 400, 600)[:40], wantErr: ErrCorrupt},
+		{name: "tiny", data: encode(t, "png", 8, 8), wantErr: ErrDimensions},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Process(id, tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Process() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			if !strings.HasPrefix(got.Original.Key, "covers/"+id.String()+"/") || !strings.HasSuffix(got.Original.Key, tt.wantExt) {
+				t.Errorf("Process() original key = %q", got.Original.Key)
+			}
+			keys := Keys(got.Original.Key)
+			if len(got.Thumbnails) != len(Sizes) {
+				t.Fatalf("Process() thumbnails = %d, want %d", len(got.Thumbnails), len(Sizes))
+			}
+			width := 0
+			for i, thumb := range got.Thumbnails {
+				if thumb.Key != keys[Sizes[i].Name] {
+					t.Errorf("thumbnail key = %q, want %q", thumb.Key, keys[Sizes[i].Name])
+				}
+				cfg, err := jpeg.DecodeConfig(bytes.NewReader(thumb.Data))
+				if err != nil {
+					t.Fatalf("thumbnail %s: %v", Sizes[i].Name, err)
+				}
+				if cfg.Width > Sizes[i].Width || cfg.Width < width || cfg.Height != cfg.Width*3/2 {
+					t.Errorf("thumbnail %s is %dx%d", Sizes[i].Name, cfg.Width, cfg.Height)
+				}
+				width = cfg.Width
+			}
+		})
+	}
+}
+
+func encode(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 200})
+		}
+	}
+
+	var buf bytes.Buffer
+	var err error
+	if format == "png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
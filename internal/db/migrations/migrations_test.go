@@ -0,0 +1,213 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []int64
+		wantErr bool
+	}{
+		{
+			name: "sorted by version",
+			fsys: fstest.MapFS{
+				"sql/0002_b.up.sql":   {Data: []byte("B")},
+				"sql/0002_b.down.sql": {Data: []byte("-B")},
+				"sql/0001_a.up.sql":   {Data: []byte("A")},
+				"sql/0001_a.down.sql": {Data: []byte("-A")},
+			},
+			want: []int64{1, 2},
+		},
+		{
+			name: "missing down",
+			fsys: fstest.MapFS{
+				"sql/0001_a.up.sql": {Data: []byte("A")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "bad file name",
+			fsys: fstest.MapFS{
+				"sql/create_books.sql": {Data: []byte("A")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "version reused",
+			fsys: fstest.MapFS{
+				"sql/0001_a.up.sql":   {Data: []byte("A")},
+				"sql/0001_b.down.sql": {Data: []byte("-B")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Load() = %+v, want versions %v", got, tt.want)
+			}
+			for i, m := range got {
+				if m.Version != tt.want[i] {
+					t.Errorf("Load()[%d].Version = %d, want %d", i, m.Version, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_embedded(t *testing.T) {
+	migrations, err := Load(files)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			t.Errorf("migration %s has version %d, want %d", m.Name, m.Version, i+1)
+		}
+	}
+}
+
+func expectLocked(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock(hashtext($1))`)).
+		WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS schema_migrations`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlocked(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock(hashtext($1))`)).
+		WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+var testMigrations = []Migration{
+	{Version: 1, Name: "create_books", Up: "CREATE TABLE books", Down: "DROP TABLE books"},
+	{Version: 2, Name: "create_users", Up: "CREATE TABLE users", Down: "DROP TABLE users"},
+}
+
+func TestMigrator_Up(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	expectLocked(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE users").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).
+		WithArgs(int64(2), "create_users").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlocked(mock)
+
+	m := &Migrator{db: db, migrations: testMigrations}
+	applied, err := m.Up(context.Background())
+	if err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	if len(applied) != 1 || applied[0].Version != 2 {
+		t.Errorf("Up() = %+v, want version 2 only", applied)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMigrator_Up_failureRollsBack(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	expectLocked(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE books").WillReturnError(errors.New("syntax error"))
+	mock.ExpectRollback()
+	expectUnlocked(mock)
+
+	m := &Migrator{db: db, migrations: testMigrations}
+	applied, err := m.Up(context.Background())
+	if err == nil {
+		t.Fatal("Up() error = nil, want the migration's error")
+	}
+	if len(applied) != 0 {
+		t.Errorf("Up() = %+v, want nothing applied", applied)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	expectLocked(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()).AddRow(2, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP TABLE users").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM schema_migrations WHERE version = $1`)).
+		WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlocked(mock)
+
+	m := &Migrator{db: db, migrations: testMigrations}
+	rolledBack, err := m.Down(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Down() error = %v", err)
+	}
+	if len(rolledBack) != 1 || rolledBack[0].Version != 2 {
+		t.Errorf("Down() = %+v, want version 2 only", rolledBack)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMigrator_Status_unknownVersion(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	expectLocked(mock)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version, applied_at FROM schema_migrations`)).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()).AddRow(7, time.Now()))
+	expectUnlocked(mock)
+
+	m := &Migrator{db: db, migrations: testMigrations}
+	statuses, err := m.Status(context.Background())
+	if !errors.Is(err, ErrUnknownVersion) {
+		t.Errorf("Status() error = %v, want %v", err, ErrUnknownVersion)
+	}
+	if len(statuses) != 2 || statuses[0].AppliedAt == nil || statuses[1].AppliedAt != nil {
+		t.Errorf("Status() = %+v", statuses)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS books (
+    id           uuid PRIMARY KEY,
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    deleted_at   timestamptz,
+    title        text NOT NULL,
+    author       text NOT NULL,
+    isbn         text NOT NULL CONSTRAINT uni_books_isbn UNIQUE,
+    publisher    text NOT NULL,
+    publish_date timestamptz NOT NULL,
+    description  text,
+    copies       bigint NOT NULL DEFAULT 1
+);
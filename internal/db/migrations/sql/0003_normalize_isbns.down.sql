@@ -0,0 +1 @@
+-- The original spellings are not kept; normalized ISBNs stay as they are
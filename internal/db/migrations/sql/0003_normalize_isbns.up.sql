@@ -0,0 +1,47 @@
+-- Rewrite ISBNs stored before validation was introduced into their canonical
+-- ISBN-13 form. Rows that are invalid or would collide with an existing book
+-- are left untouched for manual cleanup.
+CREATE FUNCTION pg_temp.isbn13(raw text) RETURNS text AS $$
+DECLARE
+    n   text := upper(replace(replace(raw, '-', ''), ' ', ''));
+    sum int := 0;
+BEGIN
+    IF n ~ '^[0-9]{9}[0-9X]$' THEN
+        FOR i IN 1..10 LOOP
+            sum := sum + (11 - i) * CASE WHEN substr(n, i, 1) = 'X' THEN 10 ELSE substr(n, i, 1)::int END;
+        END LOOP;
+        IF sum % 11 <> 0 THEN
+            RETURN NULL;
+        END IF;
+        n := '978' || substr(n, 1, 9);
+        sum := 0;
+        FOR i IN 1..12 LOOP
+            sum := sum + CASE WHEN i % 2 = 0 THEN 3 ELSE 1 END * substr(n, i, 1)::int;
+        END LOOP;
+        RETURN n || ((10 - sum % 10) % 10)::text;
+    END IF;
+
+    IF n ~ '^[0-9]{13}$' THEN
+        FOR i IN 1..13 LOOP
+            sum := sum + CASE WHEN i % 2 = 0 THEN 3 ELSE 1 END * substr(n, i, 1)::int;
+        END LOOP;
+        IF sum % 10 = 0 THEN
+            RETURN n;
+        END IF;
+    END IF;
+
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+UPDATE books b SET isbn = c.isbn13
+FROM (
+    SELECT id, pg_temp.isbn13(isbn) AS isbn13,
+        row_number() OVER (PARTITION BY pg_temp.isbn13(isbn) ORDER BY created_at, id) AS n
+    FROM books
+    WHERE isbn !~ '^97[89][0-9]{10}$'
+) c
+WHERE b.id = c.id
+    AND c.isbn13 IS NOT NULL
+    AND c.n = 1
+    AND NOT EXISTS (SELECT 1 FROM books o WHERE o.isbn = c.isbn13);
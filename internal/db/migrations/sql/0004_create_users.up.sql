@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS users (
+    id            uuid PRIMARY KEY,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    name          text NOT NULL,
+    email         text NOT NULL,
+    password_hash text NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
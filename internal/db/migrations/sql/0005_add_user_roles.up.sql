@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id    uuid NOT NULL CONSTRAINT fk_users_roles REFERENCES users (id),
+    role       text NOT NULL,
+    granted_by uuid,
+    created_at timestamptz,
+    PRIMARY KEY (user_id, role)
+);
+
+CREATE TABLE IF NOT EXISTS role_audits (
+    id         uuid PRIMARY KEY,
+    created_at timestamptz,
+    user_id    uuid NOT NULL,
+    role       text NOT NULL,
+    action     text NOT NULL,
+    actor_id   uuid NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_role_audits_created_at ON role_audits (created_at);
+CREATE INDEX IF NOT EXISTS idx_role_audits_user_id ON role_audits (user_id);
+
+-- Accounts registered before roles existed are members
+INSERT INTO user_roles (user_id, role, created_at)
+SELECT id, 'member', now() FROM users u
+WHERE NOT EXISTS (SELECT 1 FROM user_roles r WHERE r.user_id = u.id);
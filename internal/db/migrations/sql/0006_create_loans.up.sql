@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS loans (
+    id             uuid PRIMARY KEY,
+    created_at     timestamptz,
+    updated_at     timestamptz,
+    book_id        uuid NOT NULL CONSTRAINT fk_loans_book REFERENCES books (id),
+    user_id        uuid NOT NULL CONSTRAINT fk_loans_user REFERENCES users (id),
+    checked_out_at timestamptz NOT NULL,
+    due_at         timestamptz NOT NULL,
+    returned_at    timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_loans_book_id ON loans (book_id);
+CREATE INDEX IF NOT EXISTS idx_loans_user_id ON loans (user_id);
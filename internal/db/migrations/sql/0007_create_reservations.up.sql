@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS reservations (
+    id           uuid PRIMARY KEY,
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    user_id      uuid NOT NULL CONSTRAINT fk_reservations_user REFERENCES users (id),
+    date         date NOT NULL,
+    slot         bigint NOT NULL,
+    location     varchar(16) NOT NULL,
+    status       varchar(16) NOT NULL DEFAULT 'booked',
+    cancelled_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservations_date_slot ON reservations (date, slot);
+
+-- A member holds at most one seat per slot
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reservations_member_slot ON reservations (user_id, date, slot)
+    WHERE status = 'booked';
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS holds (
+    id         uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    book_id    uuid NOT NULL CONSTRAINT fk_holds_book REFERENCES books (id),
+    user_id    uuid NOT NULL CONSTRAINT fk_holds_user REFERENCES users (id),
+    status     varchar(16) NOT NULL DEFAULT 'waiting',
+    priority   bigint NOT NULL DEFAULT 0,
+    queued_at  timestamptz NOT NULL,
+    ready_at   timestamptz,
+    expires_at timestamptz,
+    closed_at  timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_holds_queue ON holds (book_id, status);
+CREATE INDEX IF NOT EXISTS idx_holds_user_id ON holds (user_id);
+
+-- A member has at most one active hold per book
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_member_book ON holds (book_id, user_id)
+    WHERE status = 'waiting' OR status = 'ready';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_transactions;
+ALTER TABLE books DROP COLUMN IF EXISTS item_type;
@@ -0,0 +1,30 @@
+ALTER TABLE books ADD COLUMN IF NOT EXISTS item_type varchar(16) NOT NULL DEFAULT 'book';
+
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+    id          uuid PRIMARY KEY,
+    created_at  timestamptz,
+    member_id   uuid NOT NULL CONSTRAINT fk_ledger_transactions_member REFERENCES users (id),
+    kind        varchar(16) NOT NULL,
+    amount      bigint NOT NULL,
+    description text,
+    loan_id     uuid,
+    actor_id    uuid
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_transactions_created_at ON ledger_transactions (created_at);
+CREATE INDEX IF NOT EXISTS idx_ledger_transactions_member_id ON ledger_transactions (member_id);
+
+-- A loan is fined at most once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_loan_fine ON ledger_transactions (loan_id)
+    WHERE kind = 'fine';
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id             uuid PRIMARY KEY,
+    transaction_id uuid NOT NULL CONSTRAINT fk_ledger_transactions_entries REFERENCES ledger_transactions (id),
+    account        varchar(32) NOT NULL,
+    member_id      uuid,
+    amount         bigint NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_transaction_id ON ledger_entries (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_member_id ON ledger_entries (member_id);
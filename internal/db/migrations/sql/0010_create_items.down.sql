@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS items_sync_book_copies ON items;
+DROP FUNCTION IF EXISTS sync_book_copies();
+ALTER TABLE holds DROP COLUMN IF EXISTS item_id;
+ALTER TABLE loans DROP COLUMN IF EXISTS item_id;
+DROP TABLE IF EXISTS items;
@@ -0,0 +1,75 @@
+CREATE TABLE IF NOT EXISTS items (
+    id             uuid PRIMARY KEY,
+    created_at     timestamptz,
+    updated_at     timestamptz,
+    book_id        uuid NOT NULL CONSTRAINT fk_books_items REFERENCES books (id) ON DELETE CASCADE,
+    barcode        text NOT NULL,
+    condition      varchar(16) NOT NULL DEFAULT 'good',
+    shelf_location text,
+    acquired_at    date NOT NULL,
+    price          bigint NOT NULL DEFAULT 0,
+    status         varchar(16) NOT NULL DEFAULT 'available'
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_items_barcode ON items (barcode);
+CREATE INDEX IF NOT EXISTS idx_items_book_status ON items (book_id, status);
+
+ALTER TABLE loans ADD COLUMN IF NOT EXISTS item_id uuid CONSTRAINT fk_loans_item REFERENCES items (id);
+CREATE INDEX IF NOT EXISTS idx_loans_item_id ON loans (item_id);
+
+ALTER TABLE holds ADD COLUMN IF NOT EXISTS item_id uuid CONSTRAINT fk_holds_item REFERENCES items (id);
+CREATE INDEX IF NOT EXISTS idx_holds_item_id ON holds (item_id);
+
+-- Keep books.copies equal to the number of the book's items that are
+-- available, whichever code path moves an item
+CREATE OR REPLACE FUNCTION sync_book_copies() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        UPDATE books SET copies = (
+            SELECT count(*) FROM items WHERE book_id = OLD.book_id AND status = 'available'
+        ) WHERE id = OLD.book_id;
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        UPDATE books SET copies = (
+            SELECT count(*) FROM items WHERE book_id = NEW.book_id AND status = 'available'
+        ) WHERE id = NEW.book_id;
+    END IF;
+    RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS items_sync_book_copies ON items;
+CREATE TRIGGER items_sync_book_copies
+    AFTER INSERT OR DELETE OR UPDATE OF status, book_id ON items
+    FOR EACH ROW EXECUTE FUNCTION sync_book_copies();
+
+-- Catalogue items for books created before copies were tracked
+-- individually: one on the shelf per remaining copy, one per open loan and
+-- one per copy waiting on the hold shelf. Loans and holds reuse their own
+-- IDs for their item so each can be linked back.
+CREATE TEMP TABLE unstocked ON COMMIT DROP AS
+SELECT id FROM books b WHERE NOT EXISTS (SELECT 1 FROM items i WHERE i.book_id = b.id);
+
+INSERT INTO items (id, created_at, updated_at, book_id, barcode, condition, acquired_at, price, status)
+SELECT id, now(), now(), book_id, 'AUTO-' || upper(substr(replace(id::text, '-', ''), 1, 12)), 'good', current_date, 0, 'available'
+FROM (
+    SELECT gen_random_uuid() AS id, b.id AS book_id
+    FROM books b CROSS JOIN generate_series(1, b.copies)
+    WHERE b.id IN (SELECT id FROM unstocked)
+) shelf;
+
+INSERT INTO items (id, created_at, updated_at, book_id, barcode, condition, acquired_at, price, status)
+SELECT id, now(), now(), book_id, 'AUTO-' || upper(substr(replace(id::text, '-', ''), 1, 12)), 'good', current_date, 0, 'on_loan'
+FROM loans
+WHERE returned_at IS NULL AND item_id IS NULL AND book_id IN (SELECT id FROM unstocked);
+
+UPDATE loans SET item_id = id
+WHERE returned_at IS NULL AND item_id IS NULL AND book_id IN (SELECT id FROM unstocked);
+
+INSERT INTO items (id, created_at, updated_at, book_id, barcode, condition, acquired_at, price, status)
+SELECT id, now(), now(), book_id, 'AUTO-' || upper(substr(replace(id::text, '-', ''), 1, 12)), 'good', current_date, 0, 'on_hold'
+FROM holds
+WHERE status = 'ready' AND item_id IS NULL AND book_id IN (SELECT id FROM unstocked);
+
+UPDATE holds SET item_id = id
+WHERE status = 'ready' AND item_id IS NULL AND book_id IN (SELECT id FROM unstocked);
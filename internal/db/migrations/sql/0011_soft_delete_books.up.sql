@@ -0,0 +1,5 @@
+-- Books were stored with a zero deleted_at before soft delete was
+-- introduced, which would otherwise read as deleted
+UPDATE books SET deleted_at = NULL WHERE deleted_at < '0002-01-01';
+
+CREATE INDEX IF NOT EXISTS idx_books_deleted_at ON books (deleted_at);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id          uuid PRIMARY KEY,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    actor_id    uuid,
+    format      varchar(16) NOT NULL,
+    status      varchar(16) NOT NULL DEFAULT 'queued',
+    created     bigint NOT NULL DEFAULT 0,
+    updated     bigint NOT NULL DEFAULT 0,
+    rejected    bigint NOT NULL DEFAULT 0,
+    error       text,
+    finished_at timestamptz
+);
+
+-- The report of each row; book_id is informational and not a foreign key,
+-- so purging a book does not depend on old import reports
+CREATE TABLE IF NOT EXISTS import_rows (
+    job_id  uuid NOT NULL CONSTRAINT fk_import_jobs_rows REFERENCES import_jobs (id) ON DELETE CASCADE,
+    row     bigint NOT NULL,
+    isbn    text,
+    book_id uuid,
+    outcome varchar(16) NOT NULL,
+    reason  text,
+    PRIMARY KEY (job_id, row)
+);
@@ -0,0 +1,3 @@
+-- The MARC 21 record a book was catalogued from, kept as received so
+-- exports can carry the fields the catalogue does not map
+ALTER TABLE books ADD COLUMN IF NOT EXISTS marc bytea;
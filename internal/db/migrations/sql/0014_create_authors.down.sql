@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS book_contributors;
+DROP TABLE IF EXISTS author_aliases;
+DROP TABLE IF EXISTS authors;
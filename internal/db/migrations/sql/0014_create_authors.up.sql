@@ -0,0 +1,110 @@
+CREATE TABLE IF NOT EXISTS authors (
+    id         uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    name       text NOT NULL,
+    sort_name  text NOT NULL,
+    name_key   text NOT NULL,
+    birth_date date,
+    death_date date,
+    bio        text
+);
+
+-- Not unique: two people may share a name
+CREATE INDEX IF NOT EXISTS idx_authors_name_key ON authors (name_key);
+CREATE INDEX IF NOT EXISTS idx_authors_sort_name ON authors (sort_name);
+
+CREATE TABLE IF NOT EXISTS author_aliases (
+    author_id uuid NOT NULL CONSTRAINT fk_authors_aliases REFERENCES authors (id) ON DELETE CASCADE,
+    name      text NOT NULL,
+    name_key  text NOT NULL,
+    PRIMARY KEY (author_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_author_aliases_name_key ON author_aliases (name_key);
+
+-- An author credited on any book, including one in the trash, cannot be
+-- deleted until the credit is removed
+CREATE TABLE IF NOT EXISTS book_contributors (
+    book_id   uuid NOT NULL CONSTRAINT fk_books_contributors REFERENCES books (id) ON DELETE CASCADE,
+    author_id uuid NOT NULL CONSTRAINT fk_book_contributors_author REFERENCES authors (id) ON DELETE RESTRICT,
+    role      varchar(16) NOT NULL,
+    position  bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (book_id, author_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_contributors_author_id ON book_contributors (author_id);
+
+-- Split the author credit of every book into authors. These functions
+-- follow the rules of the names package.
+CREATE FUNCTION pg_temp.display_name(name text) RETURNS text AS $$
+    SELECT CASE
+        WHEN name ~ '^[^,]*[^,[:space:]][^,]*,[^,]*[^,[:space:]][^,]*$'
+            THEN btrim(split_part(name, ',', 2)) || ' ' || btrim(split_part(name, ',', 1))
+        ELSE btrim(name)
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE FUNCTION pg_temp.sort_name(name text) RETURNS text AS $$
+    SELECT CASE
+        WHEN name LIKE '%,%' OR btrim(name) NOT LIKE '% %' THEN btrim(name)
+        ELSE regexp_replace(btrim(name), '^(.*) (\S+)$', '\2, \1')
+    END
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE FUNCTION pg_temp.name_key(name text) RETURNS text AS $$
+    SELECT regexp_replace(lower(pg_temp.display_name(name)), '[^[:alnum:]]', '', 'g')
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Commas separate names only when every part has at least two words, so
+-- "Tolkien, J. R. R." stays one name
+CREATE FUNCTION pg_temp.split_names(credit text) RETURNS SETOF text AS $$
+DECLARE
+    part   text;
+    pieces text[];
+    split  boolean;
+BEGIN
+    credit := btrim(regexp_replace(credit, '\s+', ' ', 'g'));
+    FOREACH part IN ARRAY regexp_split_to_array(credit, '\s*(;|&|\mand\M)\s*', 'i') LOOP
+        part := btrim(part);
+        CONTINUE WHEN part = '';
+
+        pieces := regexp_split_to_array(part, '\s*,\s*');
+        split := array_length(pieces, 1) > 1;
+        FOR i IN 1..array_length(pieces, 1) LOOP
+            IF pieces[i] !~ '\S\s+\S' THEN
+                split := false;
+            END IF;
+        END LOOP;
+
+        IF split THEN
+            RETURN QUERY SELECT unnest(pieces);
+        ELSE
+            RETURN NEXT part;
+        END IF;
+    END LOOP;
+END
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+CREATE TEMPORARY TABLE credits ON COMMIT DROP AS
+SELECT b.id AS book_id, s.position - 1 AS position, s.name, pg_temp.name_key(s.name) AS name_key
+FROM books b, pg_temp.split_names(b.author) WITH ORDINALITY AS s(name, position);
+
+DELETE FROM credits WHERE name_key = '';
+
+INSERT INTO authors (id, created_at, updated_at, name, sort_name, name_key)
+SELECT gen_random_uuid(), now(), now(), pg_temp.display_name(n.name), pg_temp.sort_name(n.name), n.name_key
+FROM (
+    SELECT DISTINCT ON (name_key) name, name_key
+    FROM credits
+    ORDER BY name_key, book_id, position
+) n
+WHERE NOT EXISTS (SELECT 1 FROM authors a WHERE a.name_key = n.name_key);
+
+INSERT INTO book_contributors (book_id, author_id, role, position)
+SELECT c.book_id, a.id, 'author', c.position
+FROM credits c
+JOIN LATERAL (
+    SELECT id FROM authors WHERE name_key = c.name_key ORDER BY created_at, id LIMIT 1
+) a ON true
+ON CONFLICT DO NOTHING;
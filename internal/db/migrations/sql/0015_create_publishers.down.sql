@@ -0,0 +1,3 @@
+ALTER TABLE books DROP COLUMN IF EXISTS publisher_id;
+DROP TABLE IF EXISTS publisher_aliases;
+DROP TABLE IF EXISTS publishers;
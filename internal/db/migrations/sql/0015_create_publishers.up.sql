@@ -0,0 +1,63 @@
+-- A publisher with a parent is one of its imprints
+CREATE TABLE IF NOT EXISTS publishers (
+    id         uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    name       text NOT NULL,
+    name_key   text NOT NULL,
+    parent_id  uuid CONSTRAINT fk_publishers_imprints REFERENCES publishers (id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_publishers_name_key ON publishers (name_key);
+CREATE INDEX IF NOT EXISTS idx_publishers_parent_id ON publishers (parent_id);
+
+CREATE TABLE IF NOT EXISTS publisher_aliases (
+    publisher_id uuid NOT NULL CONSTRAINT fk_publishers_aliases REFERENCES publishers (id) ON DELETE CASCADE,
+    name         text NOT NULL,
+    name_key     text NOT NULL,
+    PRIMARY KEY (publisher_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_publisher_aliases_name_key ON publisher_aliases (name_key);
+
+ALTER TABLE books ADD COLUMN IF NOT EXISTS publisher_id uuid
+    CONSTRAINT fk_publishers_books REFERENCES publishers (id) ON DELETE RESTRICT;
+CREATE INDEX IF NOT EXISTS idx_books_publisher_id ON books (publisher_id);
+
+-- Register the publishers credited on existing books, one per key under
+-- the most common spelling. Follows names.OrganizationKey.
+CREATE FUNCTION pg_temp.publisher_key(name text) RETURNS text AS $$
+DECLARE
+    words text[] := regexp_split_to_array(btrim(regexp_replace(lower(name), '[^[:alnum:]]+', ' ', 'g')), ' ');
+BEGIN
+    WHILE array_length(words, 1) > 1 AND words[array_length(words, 1)] = ANY (ARRAY[
+        'co', 'company', 'corp', 'corporation', 'gmbh',
+        'inc', 'incorporated', 'limited', 'llc', 'ltd', 'plc'
+    ]) LOOP
+        words := words[1:array_length(words, 1) - 1];
+    END LOOP;
+    IF array_length(words, 1) > 1 AND words[1] = 'the' THEN
+        words := words[2:array_length(words, 1)];
+    END IF;
+    RETURN array_to_string(words, '');
+END
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+INSERT INTO publishers (id, created_at, updated_at, name, name_key)
+SELECT gen_random_uuid(), now(), now(), c.name, c.name_key
+FROM (
+    SELECT pg_temp.publisher_key(publisher) AS name_key,
+        mode() WITHIN GROUP (ORDER BY btrim(publisher)) AS name
+    FROM books
+    GROUP BY 1
+) c
+WHERE c.name_key <> ''
+    AND NOT EXISTS (SELECT 1 FROM publishers p WHERE p.name_key = c.name_key);
+
+UPDATE books b SET publisher_id = (
+    SELECT id FROM publishers p
+    WHERE p.name_key = pg_temp.publisher_key(b.publisher)
+    ORDER BY created_at, id
+    LIMIT 1
+)
+WHERE b.publisher_id IS NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS book_subjects;
+DROP TABLE IF EXISTS subjects;
+ALTER TABLE books DROP COLUMN IF EXISTS lcc;
+ALTER TABLE books DROP COLUMN IF EXISTS dewey;
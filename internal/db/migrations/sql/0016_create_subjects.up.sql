@@ -0,0 +1,33 @@
+-- Call numbers; the pattern indexes serve the class prefix filters
+ALTER TABLE books ADD COLUMN IF NOT EXISTS dewey varchar(64) NOT NULL DEFAULT '';
+ALTER TABLE books ADD COLUMN IF NOT EXISTS lcc varchar(64) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_books_dewey ON books (dewey text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_books_lcc ON books (lcc text_pattern_ops);
+
+-- A subject with a parent narrows it. A subject with narrower subjects
+-- cannot be deleted until they are moved or deleted.
+CREATE TABLE IF NOT EXISTS subjects (
+    id         uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    name       text NOT NULL,
+    kind       varchar(16) NOT NULL DEFAULT 'topic',
+    parent_id  uuid CONSTRAINT fk_subjects_children REFERENCES subjects (id) ON DELETE RESTRICT
+);
+
+CREATE INDEX IF NOT EXISTS idx_subjects_parent_id ON subjects (parent_id);
+
+-- Names are unique among the subjects under the same parent, top-level
+-- subjects included
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subjects_parent_name
+    ON subjects (COALESCE(parent_id, '00000000-0000-0000-0000-000000000000'), lower(name));
+
+-- A subject any book is filed under, including one in the trash, cannot
+-- be deleted
+CREATE TABLE IF NOT EXISTS book_subjects (
+    book_id    uuid NOT NULL CONSTRAINT fk_books_subjects REFERENCES books (id) ON DELETE CASCADE,
+    subject_id uuid NOT NULL CONSTRAINT fk_book_subjects_subject REFERENCES subjects (id) ON DELETE RESTRICT,
+    PRIMARY KEY (book_id, subject_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_subjects_subject_id ON book_subjects (subject_id);
@@ -0,0 +1,3 @@
+ALTER TABLE books DROP COLUMN IF EXISTS work_id;
+DROP TABLE IF EXISTS works;
+DROP TABLE IF EXISTS series;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS series (
+    id          uuid PRIMARY KEY,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    name        text NOT NULL,
+    description text NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_series_name ON series (lower(name));
+
+-- Deleting a series leaves its works standing alone
+CREATE TABLE IF NOT EXISTS works (
+    id            uuid PRIMARY KEY,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    title         text NOT NULL,
+    author        text NOT NULL,
+    title_key     text NOT NULL,
+    author_key    text NOT NULL,
+    series_id     uuid CONSTRAINT fk_works_series REFERENCES series (id) ON DELETE SET NULL,
+    series_volume numeric(8,2)
+);
+
+CREATE INDEX IF NOT EXISTS idx_works_keys ON works (title_key, author_key);
+CREATE INDEX IF NOT EXISTS idx_works_series_id ON works (series_id, series_volume);
+
+-- Deleting a work leaves its editions ungrouped
+ALTER TABLE books ADD COLUMN IF NOT EXISTS work_id uuid
+    CONSTRAINT fk_works_books REFERENCES works (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_books_work_id ON books (work_id);
@@ -0,0 +1 @@
+ALTER TABLE books ADD COLUMN IF NOT EXISTS cover varchar(255) NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS books_bump_version ON books;
+DROP FUNCTION IF EXISTS bump_book_version();
+ALTER TABLE books DROP COLUMN IF EXISTS version;
@@ -0,0 +1,19 @@
+ALTER TABLE books ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+
+-- Bump the version on every change to a book, whichever code path makes
+-- it, so a write based on an older read can be told apart. Updates that
+-- change nothing, such as the copies sync recounting the same number, keep
+-- the version.
+CREATE OR REPLACE FUNCTION bump_book_version() RETURNS trigger AS $$
+BEGIN
+    IF NEW IS DISTINCT FROM OLD THEN
+        NEW.version := OLD.version + 1;
+    END IF;
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS books_bump_version ON books;
+CREATE TRIGGER books_bump_version
+    BEFORE UPDATE ON books
+    FOR EACH ROW EXECUTE FUNCTION bump_book_version();
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS book_revisions (
+    id         uuid PRIMARY KEY,
+    created_at timestamptz NOT NULL,
+    book_id    uuid NOT NULL,
+    revision   bigint NOT NULL,
+    action     text NOT NULL,
+    actor_id   uuid,
+    changes    jsonb NOT NULL DEFAULT '{}',
+    snapshot   jsonb NOT NULL DEFAULT '{}'
+);
+
+-- Revisions outlive the book, so there is no foreign key: a purged book's
+-- history is kept
+CREATE UNIQUE INDEX IF NOT EXISTS idx_book_revisions_book_revision ON book_revisions (book_id, revision);
+CREATE INDEX IF NOT EXISTS idx_book_revisions_book_created_at ON book_revisions (book_id, created_at);
+
+-- Books catalogued before the history was kept start from their state now
+INSERT INTO book_revisions (id, created_at, book_id, revision, action, snapshot)
+SELECT gen_random_uuid(), now(), b.id, b.version, 'snapshot', jsonb_build_object(
+    'created_at', b.created_at,
+    'title', b.title,
+    'author', b.author,
+    'isbn', b.isbn,
+    'publisher', b.publisher,
+    'publisher_id', b.publisher_id,
+    'publish_date', b.publish_date,
+    'description', b.description,
+    'item_type', b.item_type,
+    'dewey', b.dewey,
+    'lcc', b.lcc,
+    'work_id', b.work_id,
+    'cover', b.cover
+)
+FROM books b
+WHERE NOT EXISTS (SELECT 1 FROM book_revisions r WHERE r.book_id = b.id);
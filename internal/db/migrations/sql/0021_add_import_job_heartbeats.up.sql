@@ -0,0 +1,3 @@
+-- A running import beats its heartbeat while it works. A job whose
+-- heartbeat has gone stale lost its server and is failed by another.
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS heartbeat_at timestamptz;
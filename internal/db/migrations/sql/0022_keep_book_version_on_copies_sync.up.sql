@@ -0,0 +1,17 @@
+-- The copies sync moves books.copies on every checkout and return. Those
+-- are circulation rather than changes to the catalogue record, so they keep
+-- the version and do not turn away a librarian's edit with 412. Writes made
+-- through the API always set updated_at, so a copies change made by hand
+-- still bumps it.
+CREATE OR REPLACE FUNCTION bump_book_version() RETURNS trigger AS $$
+DECLARE
+    recounted books;
+BEGIN
+    recounted := NEW;
+    recounted.copies := OLD.copies;
+    IF recounted IS DISTINCT FROM OLD THEN
+        NEW.version := OLD.version + 1;
+    END IF;
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
@@ -4,13 +4,13 @@ import (
 	"os"
 	"time"
 
-	"library-system/internal/entities"
-
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+// Connect opens the database. The schema is managed by the migrations
+// package and is not touched here.
 func Connect() *gorm.DB {
 	connectionString := os.Getenv("DATABASE_URL")
 	if connectionString == "" {
@@ -18,7 +18,8 @@ func Connect() *gorm.DB {
 	}
 
 	db, err := gorm.Open(postgres.Open(connectionString), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger:         logger.Default.LogMode(logger.Info),
+		TranslateError: true,
 	})
 	if err != nil {
 		panic("failed to connect database: " + err.Error())
@@ -37,15 +38,5 @@ func Connect() *gorm.DB {
 		panic("failed to ping database: " + err.Error())
 	}
 
-	if err := db.AutoMigrate(
-		&entities.Book{},
-	); err != nil {
-		panic("failed to auto-migrate database: " + err.Error())
-	}
-
-	if err := SeedData(db); err != nil {
-		panic("failed to seed database: " + err.Error())
-	}
-
 	return db
 }
@@ -1,17 +1,58 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models/author"
+	"library-system/internal/models/book"
+	"library-system/internal/models/item"
+	"library-system/internal/models/publisher"
+	"library-system/internal/names"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gofrs/uuid"
 	"gorm.io/gorm"
 )
 
-func SeedData(db *gorm.DB) error {
+// DefaultFixtures is the fixture set seeded when none is named
+const DefaultFixtures = "demo"
 
-	if err := seedBooks(db); err != nil {
+// fixtureSets are the named catalogues that can be seeded
+var fixtureSets = map[string]func() []entities.Book{
+	"demo":    demoBooks,
+	"minimal": func() []entities.Book { return demoBooks()[:1] },
+}
+
+// FixtureSets lists the names of the fixture sets in order
+func FixtureSets() []string {
+	names := make([]string, 0, len(fixtureSets))
+	for name := range fixtureSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SeedData loads a fixture set into an empty catalogue. With force, the
+// catalogue and everything circulating from it is wiped first.
+func SeedData(db *gorm.DB, set string, force bool) error {
+	books, ok := fixtureSets[set]
+	if !ok {
+		return fmt.Errorf("unknown fixture set %q, want one of %s", set, strings.Join(FixtureSets(), ", "))
+	}
+
+	if force {
+		// Items, loans and holds reference books and go with them
+		if err := db.Exec(`TRUNCATE books CASCADE`).Error; err != nil {
+			return fmt.Errorf("error resetting books: %w", err)
+		}
+	}
+
+	if err := seedBooks(db, books()); err != nil {
 		return fmt.Errorf("error seeding books: %w", err)
 	}
 
@@ -19,10 +60,10 @@ func SeedData(db *gorm.DB) error {
 	return nil
 }
 
-func seedBooks(db *gorm.DB) error {
+func seedBooks(db *gorm.DB, books []entities.Book) error {
 
 	var count int64
-	if err := db.Model(&entities.Book{}).Count(&count).Error; err != nil {
+	if err := db.Unscoped().Model(&entities.Book{}).Count(&count).Error; err != nil {
 		return err
 	}
 
@@ -31,7 +72,61 @@ func seedBooks(db *gorm.DB) error {
 		return nil
 	}
 
-	books := []entities.Book{
+	// Each book links to its publisher in the registry, registered as needed
+	credited := make([]string, len(books))
+	for i := range books {
+		credited[i] = books[i].Publisher
+	}
+	publishers, err := publisher.New(db).Resolve(context.Background(), credited)
+	if err != nil {
+		return err
+	}
+
+	// Stock each book with its copies; the trigger keeps the count in step.
+	// Each book is credited to its authors, who are added as needed.
+	authors := author.New(db)
+	for i := range books {
+		books[i].PublisherID = publishers[i]
+
+		credited, err := authors.Resolve(context.Background(), names.Split(books[i].Author))
+		if err != nil {
+			return err
+		}
+		for j, a := range credited {
+			books[i].Contributors = append(books[i].Contributors, entities.BookContributor{
+				AuthorID: a.ID,
+				Role:     enums.ContributorAuthor,
+				Position: j,
+			})
+		}
+
+		books[i].Items = make([]entities.Item, books[i].Copies)
+		for j := range books[i].Items {
+			books[i].Items[j] = item.NewShelfItem(books[i].ID, books[i].CreatedAt)
+		}
+	}
+
+	// Books are catalogued as through the API, at the first version and
+	// with their history started
+	err = db.Transaction(func(tx *gorm.DB) error {
+		catalogue := book.New(tx)
+		for i := range books {
+			if err := catalogue.Create(context.Background(), &books[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Seeded %d books successfully\n", len(books))
+	return nil
+}
+
+func demoBooks() []entities.Book {
+	return []entities.Book{
 		{
 			ID:          mustGenerateUUID(),
 			Title:       "To Kill a Mockingbird",
@@ -93,14 +188,6 @@ func seedBooks(db *gorm.DB) error {
 			UpdatedAt:   time.Now(),
 		},
 	}
-
-	result := db.Create(&books)
-	if result.Error != nil {
-		return result.Error
-	}
-
-	fmt.Printf("Seeded %d books successfully\n", len(books))
-	return nil
 }
 
 func mustGenerateUUID() uuid.UUID {
@@ -0,0 +1,99 @@
+package entities
+
+import (
+	"time"
+
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// Author is a person credited on books. A book keeps its author credit as
+// printed; its contributors link it to the authors behind the credit.
+type Author struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name" gorm:"not null"`
+	SortName  string    `json:"sort_name" gorm:"not null"`
+	// NameKey is the normalized name authors are matched by. It is not
+	// unique: two people may share a name.
+	NameKey   string        `json:"-" gorm:"not null;index"`
+	BirthDate *time.Time    `json:"birth_date" gorm:"type:date"`
+	DeathDate *time.Time    `json:"death_date" gorm:"type:date"`
+	Bio       string        `json:"bio" gorm:"type:text"`
+	Aliases   []AuthorAlias `json:"-" gorm:"foreignKey:AuthorID;constraint:OnDelete:CASCADE"`
+}
+
+// AuthorAlias is another name an author is credited under, such as a pen
+// name or a transliteration
+type AuthorAlias struct {
+	AuthorID uuid.UUID `json:"author_id" gorm:"primaryKey"`
+	Name     string    `json:"name" gorm:"primaryKey"`
+	NameKey  string    `json:"-" gorm:"not null;index"`
+}
+
+// BookContributor credits an author on a book in one role. An author may
+// hold several roles on the same book.
+type BookContributor struct {
+	BookID   uuid.UUID             `json:"book_id" gorm:"primaryKey"`
+	AuthorID uuid.UUID             `json:"author_id" gorm:"primaryKey;index"`
+	Role     enums.ContributorRole `json:"role" gorm:"primaryKey;type:varchar(16)"`
+	// Position orders the contributors as they are credited
+	Position int     `json:"position" gorm:"not null;default:0"`
+	Author   *Author `json:"-" gorm:"foreignKey:AuthorID;constraint:OnDelete:RESTRICT"`
+}
+
+type AuthorRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+	// SortName defaults to the name filed under its last word
+	SortName  string     `json:"sort_name" validate:"max=255"`
+	Aliases   []string   `json:"aliases" validate:"dive,required,max=255"`
+	BirthDate *time.Time `json:"birth_date"`
+	DeathDate *time.Time `json:"death_date"`
+	Bio       string     `json:"bio"`
+}
+
+type AuthorResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	SortName  string     `json:"sort_name"`
+	Aliases   []string   `json:"aliases"`
+	BirthDate *time.Time `json:"birth_date,omitempty"`
+	DeathDate *time.Time `json:"death_date,omitempty"`
+	Bio       string     `json:"bio,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// AuthorQuery lists authors whose name or alias contains Name, in sort
+// name order
+type AuthorQuery struct {
+	Name  string
+	Page  int
+	Limit int
+}
+
+type AuthorPage struct {
+	Data  []*AuthorResponse `json:"data"`
+	Total int64             `json:"total"`
+	Page  int               `json:"page"`
+	Limit int               `json:"limit"`
+	Links PageLinks         `json:"links"`
+}
+
+// ContributorRequest credits an existing author by ID, or an author found
+// or created by name
+type ContributorRequest struct {
+	AuthorID *uuid.UUID `json:"author_id"`
+	Name     string     `json:"name" validate:"required_without=AuthorID,max=255"`
+	// Role defaults to author
+	Role enums.ContributorRole `json:"role" validate:"omitempty,oneof=author editor translator illustrator"`
+}
+
+type ContributorResponse struct {
+	AuthorID uuid.UUID             `json:"author_id"`
+	Name     string                `json:"name"`
+	SortName string                `json:"sort_name"`
+	Role     enums.ContributorRole `json:"role"`
+}
@@ -1,44 +1,241 @@
 package entities
 
 import (
+	"encoding/json"
 	"time"
 
+	"library-system/internal/entities/enums"
+
 	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
 )
 
 type Book struct {
-	ID          uuid.UUID `json:"id" gorm:"primaryKey"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	DeletedAt   time.Time `json:"deleted_at"`
-	Title       string    `json:"title" gorm:"not null" validate:"required"`
-	Author      string    `json:"author" gorm:"not null" validate:"required"`
-	ISBN        string    `json:"isbn" gorm:"unique;not null" validate:"required"`
-	Publisher   string    `json:"publisher" gorm:"not null" validate:"required"`
-	PublishDate time.Time `json:"publish_date" gorm:"not null" validate:"required"`
-	Description string    `json:"description" gorm:"type:text"`
-	Copies      int       `json:"copies" gorm:"not null;default:1" validate:"required,min=0"`
+	ID          uuid.UUID      `json:"id" gorm:"primaryKey"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	Title       string         `json:"title" gorm:"not null" validate:"required"`
+	Author      string         `json:"author" gorm:"not null" validate:"required"`
+	ISBN        string         `json:"isbn" gorm:"unique;not null" validate:"required,isbn"`
+	Publisher   string         `json:"publisher" gorm:"not null" validate:"required"`
+	PublisherID *uuid.UUID     `json:"publisher_id" gorm:"index"`
+	PublishDate time.Time      `json:"publish_date" gorm:"not null" validate:"required"`
+	Description string         `json:"description" gorm:"type:text"`
+	Copies      int            `json:"copies" gorm:"not null;default:1" validate:"required,min=0"`
+	ItemType    enums.ItemType `json:"item_type" gorm:"type:varchar(16);not null;default:'book'"`
+	Dewey       string         `json:"dewey" gorm:"type:varchar(64);index"`
+	LCC         string         `json:"lcc" gorm:"column:lcc;type:varchar(64);index"`
+	WorkID      *uuid.UUID     `json:"work_id" gorm:"index"`
+	Version     int64          `json:"version" gorm:"not null"`
+	Items       []Item         `json:"-" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE"`
+	// Contributors link the author credit to authors, with their roles
+	Contributors []BookContributor `json:"-" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE"`
+	// Subjects file the book under subject headings and genres
+	Subjects []BookSubject `json:"-" gorm:"foreignKey:BookID;constraint:OnDelete:CASCADE"`
+	// Cover is the storage key of the uploaded cover image, empty when
+	// the book has none
+	Cover string `json:"-" gorm:"type:varchar(255);not null"`
+	// MARC is the MARC 21 record the book was catalogued from, as received
+	MARC []byte `json:"-" gorm:"column:marc"`
 }
 
 type BookRequest struct {
 	Title       string    `json:"title" validate:"required"`
 	Author      string    `json:"author" validate:"required"`
-	ISBN        string    `json:"isbn" validate:"required"`
+	ISBN        string    `json:"isbn" validate:"required,isbn"`
 	Publisher   string    `json:"publisher" validate:"required"`
 	PublishDate time.Time `json:"publish_date" validate:"required"`
 	Description string    `json:"description"`
 	Copies      int       `json:"copies" validate:"required,min=0"`
+	// ItemType defaults to book
+	ItemType enums.ItemType `json:"item_type" validate:"omitempty,oneof=book periodical media reference"`
+	// MARC is the raw record of a book imported from MARC 21. Such rows
+	// may leave Copies out.
+	MARC []byte `json:"-" validate:"-"`
+	// Contributors replace the book's credited authors and their roles.
+	// When left out, the authors are taken from the author credit.
+	Contributors []ContributorRequest `json:"contributors,omitempty" validate:"omitempty,dive"`
+	// PublisherID picks the publisher or imprint in the registry. When
+	// left out, it is found or added by the publisher name.
+	PublisherID *uuid.UUID `json:"publisher_id,omitempty"`
+	// Dewey and LCC are call numbers, such as "813.54 L478t" and
+	// "PS3562.E353 T6"
+	Dewey string `json:"dewey,omitempty" validate:"omitempty,max=64,dewey"`
+	LCC   string `json:"lcc,omitempty" validate:"omitempty,max=64,lcc"`
+	// SubjectIDs replace the subjects the book is filed under. When left
+	// out, an update keeps them.
+	SubjectIDs []uuid.UUID `json:"subject_ids,omitempty" validate:"omitempty,max=50"`
+	// WorkID makes the book an edition of a work. When left out, an
+	// update keeps the book's work.
+	WorkID *uuid.UUID `json:"work_id,omitempty"`
+}
+
+// BookPatch is a partial update of a book: a JSON Merge Patch or a JSON
+// Patch, told apart by its content type, of the book as a BookRequest
+type BookPatch struct {
+	ContentType string
+	Body        []byte
+}
+
+// BookChange is an update of a book saved as one: the catalogue columns
+// written from Book, which holds the version the change is based on, and
+// the links and copies it replaces. Nil links and copies are left alone;
+// an empty, non-nil list removes them all.
+type BookChange struct {
+	Book         *Book
+	Columns      []string
+	Contributors []BookContributor
+	SubjectIDs   []uuid.UUID
+	Copies       *int
+}
+
+// BookRevision is one change in the history of a book: who made it, what
+// changed and the book's catalogue details after it
+type BookRevision struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	BookID    uuid.UUID `json:"book_id" gorm:"not null"`
+	// Revision is the version of the book the change brought it to
+	Revision int64                `json:"revision" gorm:"not null"`
+	Action   enums.RevisionAction `json:"action" gorm:"not null"`
+	// ActorID is empty for changes made by background jobs
+	ActorID *uuid.UUID `json:"actor_id"`
+	// Changes hold the value of each changed field before and after
+	Changes map[string]FieldChange `json:"changes" gorm:"type:jsonb;serializer:json"`
+	// Snapshot holds every tracked field after the change, named as in
+	// BookRequest
+	Snapshot map[string]json.RawMessage `json:"-" gorm:"type:jsonb;serializer:json"`
+}
+
+// FieldChange is the value of a field before and after a change, null
+// before a book is created
+type FieldChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
 }
 
 type BookResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Title       string    `json:"title"`
-	Author      string    `json:"author"`
-	ISBN        string    `json:"isbn"`
-	Publisher   string    `json:"publisher"`
-	PublishDate time.Time `json:"publish_date"`
-	Description string    `json:"description"`
-	Copies      int       `json:"copies"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uuid.UUID      `json:"id"`
+	Title       string         `json:"title"`
+	Author      string         `json:"author"`
+	ISBN        string         `json:"isbn"`
+	ISBN10      string         `json:"isbn10,omitempty"`
+	Publisher   string         `json:"publisher"`
+	PublisherID *uuid.UUID     `json:"publisher_id,omitempty"`
+	PublishDate time.Time      `json:"publish_date"`
+	Description string         `json:"description"`
+	Copies      int            `json:"copies"`
+	ItemType    enums.ItemType `json:"item_type"`
+	Dewey       string         `json:"dewey,omitempty"`
+	LCC         string         `json:"lcc,omitempty"`
+	WorkID      *uuid.UUID     `json:"work_id,omitempty"`
+	Version     int64          `json:"version"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	// DeletedAt is set on books in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// CoverURLs link the cover image by size: original, small, medium and
+	// large
+	CoverURLs map[string]string `json:"cover_urls,omitempty"`
+	// MARC is the stored MARC 21 record, used by exports
+	MARC []byte `json:"-"`
+	// Contributors and Subjects are listed on single book lookups only
+	Contributors []ContributorResponse `json:"contributors,omitempty"`
+	Subjects     []SubjectSummary      `json:"subjects,omitempty"`
+	// WorkSuggestions are returned by book creation only, for a book not
+	// yet grouped under a work
+	WorkSuggestions []WorkSuggestion `json:"work_suggestions,omitempty"`
+}
+
+// SortField is a single column of a multi-field sort
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// BookQuery holds the filters, sort order and pagination of a book listing.
+// Cursor takes precedence over Page when both are set.
+type BookQuery struct {
+	Page          int
+	Limit         int
+	Cursor        string
+	Author        string
+	Publisher     string
+	PublishedFrom *time.Time
+	PublishedTo   *time.Time
+	Available     *bool
+	// Subject takes in the subjects below it
+	Subject *uuid.UUID
+	// Dewey and LCC match call numbers starting with a class or prefix
+	Dewey string
+	LCC   string
+	Sort  []SortField
+}
+
+// PageInfo describes where a page sits in the full result set
+type PageInfo struct {
+	Total      int64
+	NextCursor string
+	PrevCursor string
+}
+
+type PageLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+type BookPage struct {
+	Data       []*BookResponse `json:"data"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page,omitempty"`
+	Limit      int             `json:"limit"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+	PrevCursor string          `json:"prev_cursor,omitempty"`
+	Links      PageLinks       `json:"links"`
+}
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// BookSearchQuery is a full-text search over title, author, publisher and description
+type BookSearchQuery struct {
+	Q     string
+	Page  int
+	Limit int
+}
+
+// BookSearchHit is a matching book with its relevance and highlighted fragments
+type BookSearchHit struct {
+	Book           Book `gorm:"embedded"`
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
+}
+
+// BookSearchResult holds one page of hits. Fuzzy is set when nothing matched
+// the full-text query and the hits come from trigram similarity instead.
+type BookSearchResult struct {
+	Hits  []BookSearchHit
+	Total int64
+	Fuzzy bool
+}
+
+type BookSearchResponse struct {
+	BookResponse
+	Rank           float64 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+type BookSearchPage struct {
+	Data  []*BookSearchResponse `json:"data"`
+	Total int64                 `json:"total"`
+	Page  int                   `json:"page"`
+	Limit int                   `json:"limit"`
+	Fuzzy bool                  `json:"fuzzy"`
+	Links PageLinks             `json:"links"`
 }
@@ -16,6 +16,219 @@ const (
 	Inside  Location = "inside"
 )
 
+func (l Location) IsValid() bool {
+	switch l {
+	case Outside, Inside:
+		return true
+	}
+	return false
+}
+
+type Role string
+
+const (
+	RoleMember    Role = "member"
+	RoleLibrarian Role = "librarian"
+	RoleAdmin     Role = "admin"
+)
+
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleMember, RoleLibrarian, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+type RoleAction string
+
+const (
+	RoleGranted RoleAction = "grant"
+	RoleRevoked RoleAction = "revoke"
+)
+
+type RevisionAction string
+
+const (
+	RevisionCreated  RevisionAction = "create"
+	RevisionUpdated  RevisionAction = "update"
+	RevisionDeleted  RevisionAction = "delete"
+	RevisionRestored RevisionAction = "restore"
+	RevisionPurged   RevisionAction = "purge"
+	// RevisionSnapshot starts the history of books catalogued before it
+	// was kept, from their state at the time
+	RevisionSnapshot RevisionAction = "snapshot"
+)
+
+type HoldStatus string
+
+const (
+	// HoldWaiting holds are queued for the next free copy
+	HoldWaiting HoldStatus = "waiting"
+	// HoldReady holds have a copy set aside awaiting pickup
+	HoldReady     HoldStatus = "ready"
+	HoldFulfilled HoldStatus = "fulfilled"
+	HoldCancelled HoldStatus = "cancelled"
+	HoldExpired   HoldStatus = "expired"
+)
+
+// IsActive reports whether the hold is still queued or awaiting pickup
+func (s HoldStatus) IsActive() bool {
+	return s == HoldWaiting || s == HoldReady
+}
+
+// ItemType is the kind of material a book is, used to pick its fine rate
+type ItemType string
+
+const (
+	ItemBook       ItemType = "book"
+	ItemPeriodical ItemType = "periodical"
+	ItemMedia      ItemType = "media"
+	ItemReference  ItemType = "reference"
+)
+
+func (t ItemType) IsValid() bool {
+	switch t {
+	case ItemBook, ItemPeriodical, ItemMedia, ItemReference:
+		return true
+	}
+	return false
+}
+
+// ItemStatus is where a physical copy is. Only available items count
+// towards a book's copies.
+type ItemStatus string
+
+const (
+	ItemAvailable ItemStatus = "available"
+	ItemOnLoan    ItemStatus = "on_loan"
+	// ItemOnHold items are set aside on the hold shelf for a member
+	ItemOnHold    ItemStatus = "on_hold"
+	ItemInRepair  ItemStatus = "in_repair"
+	ItemLost      ItemStatus = "lost"
+	ItemWithdrawn ItemStatus = "withdrawn"
+)
+
+// InCirculation reports whether the item is lent or set aside for a
+// member; only circulation moves items out of these states
+func (s ItemStatus) InCirculation() bool {
+	return s == ItemOnLoan || s == ItemOnHold
+}
+
+type ItemCondition string
+
+const (
+	ConditionNew     ItemCondition = "new"
+	ConditionGood    ItemCondition = "good"
+	ConditionFair    ItemCondition = "fair"
+	ConditionPoor    ItemCondition = "poor"
+	ConditionDamaged ItemCondition = "damaged"
+)
+
+// ContributorRole is the part a person played in making a book
+type ContributorRole string
+
+const (
+	ContributorAuthor      ContributorRole = "author"
+	ContributorEditor      ContributorRole = "editor"
+	ContributorTranslator  ContributorRole = "translator"
+	ContributorIllustrator ContributorRole = "illustrator"
+)
+
+func (r ContributorRole) IsValid() bool {
+	switch r {
+	case ContributorAuthor, ContributorEditor, ContributorTranslator, ContributorIllustrator:
+		return true
+	}
+	return false
+}
+
+// SubjectKind tells topical subject headings from genre and form terms
+type SubjectKind string
+
+const (
+	SubjectTopic SubjectKind = "topic"
+	SubjectGenre SubjectKind = "genre"
+)
+
+func (k SubjectKind) IsValid() bool {
+	switch k {
+	case SubjectTopic, SubjectGenre:
+		return true
+	}
+	return false
+}
+
+// ClassScheme is a classification scheme call numbers are drawn from
+type ClassScheme string
+
+const (
+	SchemeDewey ClassScheme = "dewey"
+	SchemeLCC   ClassScheme = "lcc"
+)
+
+func (s ClassScheme) IsValid() bool {
+	switch s {
+	case SchemeDewey, SchemeLCC:
+		return true
+	}
+	return false
+}
+
+// ImportStatus is where a bulk import job is in its life
+type ImportStatus string
+
+const (
+	ImportQueued  ImportStatus = "queued"
+	ImportRunning ImportStatus = "running"
+	ImportDone    ImportStatus = "done"
+	// ImportFailed jobs stopped part way; rows already reported stay imported
+	ImportFailed ImportStatus = "failed"
+)
+
+// ImportOutcome is what an import did with one row
+type ImportOutcome string
+
+const (
+	ImportCreated  ImportOutcome = "created"
+	ImportUpdated  ImportOutcome = "updated"
+	ImportRejected ImportOutcome = "rejected"
+)
+
+func (o ImportOutcome) IsValid() bool {
+	switch o {
+	case ImportCreated, ImportUpdated, ImportRejected:
+		return true
+	}
+	return false
+}
+
+// LedgerKind is the business event behind a ledger transaction
+type LedgerKind string
+
+const (
+	// LedgerFine is an overdue fine assessed on return
+	LedgerFine LedgerKind = "fine"
+	// LedgerCharge bills a lost or damaged copy
+	LedgerCharge  LedgerKind = "charge"
+	LedgerPayment LedgerKind = "payment"
+	LedgerWaiver  LedgerKind = "waiver"
+)
+
+// LedgerAccount is one side of a double-entry posting. Debits are positive
+// amounts, credits negative.
+type LedgerAccount string
+
+const (
+	// AccountReceivable is what members owe; its balance per member is the
+	// member's outstanding balance
+	AccountReceivable   LedgerAccount = "member_receivable"
+	AccountFineIncome   LedgerAccount = "fine_income"
+	AccountChargeIncome LedgerAccount = "replacement_income"
+	AccountCash         LedgerAccount = "cash"
+	AccountWaived       LedgerAccount = "fines_waived"
+)
+
 type RevervationSlot int
 
 const (
@@ -33,6 +246,18 @@ const (
 	Slot12
 )
 
+// SlotDuration is the length of every reservation slot
+const SlotDuration = 2 * time.Hour
+
+func (s RevervationSlot) IsValid() bool {
+	return s >= Slot1 && s <= Slot12
+}
+
+// StartHour is the hour of the day the slot begins, the inverse of GetSlot
+func (s RevervationSlot) StartHour() int {
+	return (int(s) - 1) * 2
+}
+
 func GetSlot(datetime time.Time) RevervationSlot {
 
 	hour := datetime.Hour()
@@ -1,11 +1,226 @@
 package entities
 
-import "errors"
+// Kind classifies an error by what went wrong, which decides the status it
+// is reported to API clients with
+type Kind string
 
+const (
+	// KindMalformed requests cannot be read: a bad ID, body or parameter
+	KindMalformed Kind = "malformed"
+	// KindInvalid requests can be read but break a rule of the catalogue
+	KindInvalid              Kind = "invalid"
+	KindUnauthenticated      Kind = "unauthenticated"
+	KindForbidden            Kind = "forbidden"
+	KindNotFound             Kind = "not_found"
+	KindConflict             Kind = "conflict"
+	KindPreconditionFailed   Kind = "precondition_failed"
+	KindPreconditionRequired Kind = "precondition_required"
+	KindTooLarge             Kind = "too_large"
+	KindUnsupportedMediaType Kind = "unsupported_media_type"
+	// KindUnavailable errors pass once the database or storage is back
+	KindUnavailable Kind = "unavailable"
+	KindInternal    Kind = "internal"
+)
+
+// Error is an error that is reported to API clients: its kind, a code
+// that stays the same across releases for clients to match on, and a
+// message safe to show them. Errors wrapping one are reported as it, with
+// their own message as the detail.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+}
+
+func newError(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Errors of requests the handlers cannot read, and of failures that are
+// not the client's
 var (
-	ErrBookNotFound = errors.New("book not found")
+	ErrInvalidID = newError(KindMalformed, "invalid_id", "invalid ID")
+
+	ErrInvalidBody = newError(KindMalformed, "invalid_body", "invalid request body")
 
-	ErrUserNotFound = errors.New("user not found")
+	ErrInvalidParameter = newError(KindMalformed, "invalid_parameter", "invalid parameter")
 
-	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrUnsupportedMediaType = newError(KindUnsupportedMediaType, "unsupported_media_type", "unsupported content type")
+
+	// ErrValidation reports a request body that failed validation, with
+	// what is wrong with each field
+	ErrValidation = newError(KindInvalid, "validation_failed", "request failed validation")
+
+	ErrMalformedAuthorization = newError(KindUnauthenticated, "invalid_request", "malformed Authorization header")
+
+	ErrUnavailable = newError(KindUnavailable, "service_unavailable", "service temporarily unavailable")
+
+	ErrInternal = newError(KindInternal, "internal_error", "internal server error")
 )
+
+var (
+	ErrBookNotFound = newError(KindNotFound, "book_not_found", "book not found")
+
+	ErrInvalidBookQuery = newError(KindMalformed, "invalid_book_query", "invalid book query")
+
+	ErrInvalidISBN = newError(KindInvalid, "invalid_isbn", "invalid isbn")
+
+	ErrNoCopiesAvailable = newError(KindConflict, "no_copies_available", "no copies available")
+
+	ErrBookHasHistory = newError(KindConflict, "book_has_history", "book has loan or hold history and cannot be purged")
+
+	ErrDuplicateISBN = newError(KindConflict, "duplicate_isbn", "a book with this isbn already exists")
+
+	ErrBookInTrash = newError(KindConflict, "book_in_trash", "a book with this isbn is in the trash")
+
+	ErrBookModified = newError(KindPreconditionFailed, "book_modified", "book was changed since it was read")
+
+	ErrVersionRequired = newError(KindPreconditionRequired, "version_required", "the If-Match header is required to change a book")
+
+	ErrInvalidBook = newError(KindInvalid, "invalid_book", "invalid book")
+
+	ErrInvalidPatch = newError(KindMalformed, "invalid_patch", "invalid patch")
+
+	ErrPatchConflict = newError(KindConflict, "patch_conflict", "patch does not apply to the book")
+
+	ErrRevisionNotFound = newError(KindNotFound, "revision_not_found", "revision not found")
+
+	ErrRevisionOutdated = newError(KindConflict, "revision_outdated", "revision refers to a publisher or work that no longer exists")
+
+	ErrInvalidImport = newError(KindInvalid, "invalid_import", "invalid import")
+
+	ErrImportTooLarge = newError(KindTooLarge, "import_too_large", "import is too large")
+
+	ErrImportJobNotFound = newError(KindNotFound, "import_job_not_found", "import job not found")
+
+	ErrImportJobNotRunning = newError(KindConflict, "import_job_not_running", "import job is not running")
+
+	ErrAuthorNotFound = newError(KindNotFound, "author_not_found", "author not found")
+
+	ErrInvalidAuthor = newError(KindInvalid, "invalid_author", "invalid author")
+
+	ErrAuthorHasBooks = newError(KindConflict, "author_has_books", "author is credited on books and cannot be deleted")
+
+	ErrPublisherNotFound = newError(KindNotFound, "publisher_not_found", "publisher not found")
+
+	ErrInvalidPublisher = newError(KindInvalid, "invalid_publisher", "invalid publisher")
+
+	ErrPublisherInUse = newError(KindConflict, "publisher_in_use", "publisher has books or imprints and cannot be deleted")
+
+	ErrSubjectNotFound = newError(KindNotFound, "subject_not_found", "subject not found")
+
+	ErrInvalidSubject = newError(KindInvalid, "invalid_subject", "invalid subject")
+
+	ErrDuplicateSubject = newError(KindConflict, "duplicate_subject", "subject already exists under the same parent")
+
+	ErrSubjectInUse = newError(KindConflict, "subject_in_use", "subject has books or narrower subjects and cannot be deleted")
+
+	ErrWorkNotFound = newError(KindNotFound, "work_not_found", "work not found")
+
+	ErrInvalidWork = newError(KindInvalid, "invalid_work", "invalid work")
+
+	ErrSeriesNotFound = newError(KindNotFound, "series_not_found", "series not found")
+
+	ErrInvalidSeries = newError(KindInvalid, "invalid_series", "invalid series")
+
+	ErrDuplicateSeries = newError(KindConflict, "duplicate_series", "a series with this name already exists")
+
+	ErrNotInSeries = newError(KindNotFound, "not_in_series", "book is not part of a series")
+
+	ErrInvalidCover = newError(KindUnsupportedMediaType, "invalid_cover", "invalid cover image")
+
+	ErrCoverTooLarge = newError(KindTooLarge, "cover_too_large", "cover image is too large")
+
+	ErrNoCover = newError(KindNotFound, "no_cover", "book has no cover")
+
+	ErrItemNotFound = newError(KindNotFound, "item_not_found", "item not found")
+
+	ErrBarcodeTaken = newError(KindConflict, "barcode_taken", "barcode already in use")
+
+	ErrItemInCirculation = newError(KindConflict, "item_in_circulation", "item is on loan or on hold")
+
+	ErrItemUnavailable = newError(KindConflict, "item_unavailable", "item is not available for loan")
+
+	ErrItemHasHistory = newError(KindConflict, "item_has_history", "item has circulation history; withdraw it instead")
+
+	ErrLoanNotFound = newError(KindNotFound, "loan_not_found", "loan not found")
+
+	ErrLoanAlreadyReturned = newError(KindConflict, "loan_already_returned", "loan already returned")
+
+	ErrNotInGoodStanding = newError(KindConflict, "not_in_good_standing", "member balance exceeds the good standing limit")
+
+	ErrUnbalancedTransaction = newError(KindInternal, "unbalanced_transaction", "ledger transaction does not balance")
+
+	ErrAmountExceedsBalance = newError(KindConflict, "amount_exceeds_balance", "amount exceeds outstanding balance")
+
+	ErrHoldNotFound = newError(KindNotFound, "hold_not_found", "hold not found")
+
+	ErrHoldExists = newError(KindConflict, "hold_exists", "hold already placed on this book")
+
+	ErrCopiesAvailable = newError(KindConflict, "copies_available", "copies are available for checkout")
+
+	ErrHoldNotWaiting = newError(KindConflict, "hold_not_waiting", "hold is no longer waiting in the queue")
+
+	ErrHoldClosed = newError(KindConflict, "hold_closed", "hold already fulfilled, cancelled or expired")
+
+	ErrReservationNotFound = newError(KindNotFound, "reservation_not_found", "reservation not found")
+
+	ErrInvalidReservation = newError(KindInvalid, "invalid_reservation", "invalid reservation slot or location")
+
+	ErrReservationConflict = newError(KindConflict, "reservation_conflict", "already holding a seat in this slot")
+
+	ErrSlotFull = newError(KindConflict, "slot_full", "no seats left in this slot")
+
+	ErrReservationLimitReached = newError(KindConflict, "reservation_limit_reached", "daily reservation limit reached")
+
+	ErrReservationCancelled = newError(KindConflict, "reservation_cancelled", "reservation already cancelled")
+
+	ErrReservationStarted = newError(KindConflict, "reservation_started", "reservation slot has already started")
+
+	ErrUserNotFound = newError(KindNotFound, "user_not_found", "user not found")
+
+	ErrInvalidCredentials = newError(KindUnauthenticated, "invalid_credentials", "invalid credentials")
+
+	ErrEmailTaken = newError(KindConflict, "email_taken", "email already registered")
+
+	ErrInvalidToken = newError(KindUnauthenticated, "invalid_token", "invalid or expired token")
+
+	ErrUnauthenticated = newError(KindUnauthenticated, "unauthenticated", "authentication required")
+
+	ErrForbidden = newError(KindForbidden, "forbidden", "insufficient permissions")
+
+	ErrInvalidRole = newError(KindInvalid, "invalid_role", "invalid role")
+
+	ErrRoleAlreadyGranted = newError(KindConflict, "role_already_granted", "role already granted")
+
+	ErrRoleNotGranted = newError(KindNotFound, "role_not_granted", "role not granted")
+
+	ErrLastAdmin = newError(KindConflict, "last_admin", "cannot revoke the last admin")
+)
+
+// Problem is the RFC 7807 application/problem+json body of every error
+// response, carrying the error's code and, for a request that failed
+// validation, what is wrong with each field
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+	// Permission names the permission a request was refused for lacking
+	Permission string `json:"permission,omitempty"`
+}
+
+// FieldError is what is wrong with one field of a request, named by its
+// JSON path such as "contributors[1].role". Code is the rule it broke.
+type FieldError struct {
+	Field  string `json:"field"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
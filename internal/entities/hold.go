@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"time"
+
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// Hold is a member's place in the queue for a book with no copies on the
+// shelf. Holds are served by descending priority, then first come first
+// served. A member has at most one active hold per book. A ready hold has
+// an item set aside for pickup.
+type Hold struct {
+	ID        uuid.UUID        `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+	BookID    uuid.UUID        `json:"book_id" gorm:"not null;index:idx_holds_queue;uniqueIndex:idx_holds_member_book,where:status = 'waiting' OR status = 'ready'"`
+	UserID    uuid.UUID        `json:"user_id" gorm:"not null;index;uniqueIndex:idx_holds_member_book,where:status = 'waiting' OR status = 'ready'"`
+	Status    enums.HoldStatus `json:"status" gorm:"type:varchar(16);not null;default:'waiting';index:idx_holds_queue"`
+	Priority  int              `json:"priority" gorm:"not null;default:0"`
+	ItemID    *uuid.UUID       `json:"item_id" gorm:"index"`
+	QueuedAt  time.Time        `json:"queued_at" gorm:"not null"`
+	ReadyAt   *time.Time       `json:"ready_at"`
+	ExpiresAt *time.Time       `json:"expires_at"`
+	ClosedAt  *time.Time       `json:"closed_at"`
+	Book      *Book            `json:"-" gorm:"foreignKey:BookID"`
+	User      *User            `json:"-" gorm:"foreignKey:UserID"`
+	Item      *Item            `json:"-" gorm:"foreignKey:ItemID"`
+}
+
+type HoldRequest struct {
+	// UserID lets staff place a hold on behalf of a member
+	UserID *uuid.UUID `json:"user_id"`
+}
+
+type HoldPriorityRequest struct {
+	// Priority moves the hold ahead of every waiting hold with a lower
+	// priority; the default is 0
+	Priority int `json:"priority" validate:"min=0,max=100"`
+}
+
+// HoldQuery filters a hold listing
+type HoldQuery struct {
+	BookID     *uuid.UUID
+	UserID     *uuid.UUID
+	ActiveOnly bool
+}
+
+type HoldResponse struct {
+	ID        uuid.UUID        `json:"id"`
+	BookID    uuid.UUID        `json:"book_id"`
+	UserID    uuid.UUID        `json:"user_id"`
+	Status    enums.HoldStatus `json:"status"`
+	Priority  int              `json:"priority"`
+	ItemID    *uuid.UUID       `json:"item_id,omitempty"`
+	QueuedAt  time.Time        `json:"queued_at"`
+	ReadyAt   *time.Time       `json:"ready_at,omitempty"`
+	ExpiresAt *time.Time       `json:"expires_at,omitempty"`
+	ClosedAt  *time.Time       `json:"closed_at,omitempty"`
+	// Position is the 1-based place in the queue of a waiting hold
+	Position int `json:"position,omitempty"`
+}
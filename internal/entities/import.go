@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"io"
+	"time"
+
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// ImportJob is a bulk catalogue import. The counts are kept up to date as
+// batches are committed, so a running job can be polled for progress.
+type ImportJob struct {
+	ID        uuid.UUID          `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	ActorID   *uuid.UUID         `json:"actor_id,omitempty"`
+	Format    string             `json:"format" gorm:"type:varchar(16);not null"`
+	Status    enums.ImportStatus `json:"status" gorm:"type:varchar(16);not null;default:'queued'"`
+	Created   int                `json:"created" gorm:"not null;default:0"`
+	Updated   int                `json:"updated" gorm:"not null;default:0"`
+	Rejected  int                `json:"rejected" gorm:"not null;default:0"`
+	// Error is why a failed job stopped
+	Error      string     `json:"error,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	// HeartbeatAt is when the server running the job last showed it was
+	HeartbeatAt *time.Time  `json:"-"`
+	Rows        []ImportRow `json:"rows,omitempty" gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE"`
+}
+
+// ImportRow reports what became of one row of an import. Rows are numbered
+// from 1, not counting a CSV header.
+type ImportRow struct {
+	JobID   uuid.UUID           `json:"-" gorm:"primaryKey"`
+	Row     int                 `json:"row" gorm:"primaryKey;autoIncrement:false"`
+	ISBN    string              `json:"isbn,omitempty"`
+	BookID  *uuid.UUID          `json:"book_id,omitempty"`
+	Outcome enums.ImportOutcome `json:"outcome" gorm:"type:varchar(16);not null"`
+	Reason  string              `json:"reason,omitempty"`
+}
+
+// ImportRequest is an upload of books to create or update by ISBN
+type ImportRequest struct {
+	Format string
+	Body   io.Reader
+	// Size is the length of Body in bytes, -1 when unknown. Uploads larger
+	// than the configured limit are imported in the background unless Wait
+	// is set.
+	Size int64
+	Wait bool
+}
+
+// UpsertResult is what an upsert did with one book
+type UpsertResult struct {
+	Outcome enums.ImportOutcome
+	Err     error
+}
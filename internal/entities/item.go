@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"time"
+
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// Item is a physical copy of a book. A book's copies are the number of its
+// items that are available on the shelf.
+type Item struct {
+	ID            uuid.UUID           `json:"id" gorm:"primaryKey"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+	BookID        uuid.UUID           `json:"book_id" gorm:"not null;index:idx_items_book_status"`
+	Barcode       string              `json:"barcode" gorm:"not null;uniqueIndex"`
+	Condition     enums.ItemCondition `json:"condition" gorm:"type:varchar(16);not null;default:'good'"`
+	ShelfLocation string              `json:"shelf_location"`
+	AcquiredAt    time.Time           `json:"acquired_at" gorm:"type:date;not null"`
+	// Price is the replacement cost in minor currency units
+	Price  int64            `json:"price" gorm:"not null;default:0"`
+	Status enums.ItemStatus `json:"status" gorm:"type:varchar(16);not null;default:'available';index:idx_items_book_status"`
+}
+
+type ItemRequest struct {
+	Barcode       string              `json:"barcode" validate:"required,max=32"`
+	Condition     enums.ItemCondition `json:"condition" validate:"omitempty,oneof=new good fair poor damaged"`
+	ShelfLocation string              `json:"shelf_location" validate:"max=64"`
+	// AcquiredAt defaults to today
+	AcquiredAt *time.Time `json:"acquired_at"`
+	Price      int64      `json:"price" validate:"min=0"`
+	// Status defaults to available. On loan and on hold are set by
+	// circulation only.
+	Status enums.ItemStatus `json:"status" validate:"omitempty,oneof=available in_repair lost withdrawn"`
+}
@@ -0,0 +1,85 @@
+package entities
+
+import (
+	"time"
+
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// LedgerTransaction groups the balanced entries of one money movement: a
+// fine, a charge, a payment or a waiver. Amount is the transaction's size
+// in minor currency units and is always positive; its direction follows
+// from the entries. A loan is fined at most once.
+type LedgerTransaction struct {
+	ID          uuid.UUID        `json:"id" gorm:"primaryKey"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"index"`
+	MemberID    uuid.UUID        `json:"member_id" gorm:"not null;index"`
+	Kind        enums.LedgerKind `json:"kind" gorm:"type:varchar(16);not null"`
+	Amount      int64            `json:"amount" gorm:"not null"`
+	Description string           `json:"description"`
+	LoanID      *uuid.UUID       `json:"loan_id,omitempty" gorm:"uniqueIndex:idx_ledger_loan_fine,where:kind = 'fine'"`
+	ActorID     *uuid.UUID       `json:"actor_id,omitempty"`
+	Entries     []LedgerEntry    `json:"entries" gorm:"foreignKey:TransactionID"`
+	Member      *User            `json:"-" gorm:"foreignKey:MemberID"`
+}
+
+// LedgerEntry is one side of a double-entry posting. Debits are positive,
+// credits negative. MemberID is set on member receivable entries only.
+type LedgerEntry struct {
+	ID            uuid.UUID           `json:"id" gorm:"primaryKey"`
+	TransactionID uuid.UUID           `json:"-" gorm:"not null;index"`
+	Account       enums.LedgerAccount `json:"account" gorm:"type:varchar(32);not null"`
+	MemberID      *uuid.UUID          `json:"-" gorm:"index"`
+	Amount        int64               `json:"amount" gorm:"not null"`
+}
+
+// Balanced reports whether the entries of the transaction sum to zero
+func (t *LedgerTransaction) Balanced() bool {
+	if len(t.Entries) < 2 {
+		return false
+	}
+	var sum int64
+	for _, e := range t.Entries {
+		sum += e.Amount
+	}
+	return sum == 0
+}
+
+// ReceivableChange is how much the transaction moves the member's balance
+func (t *LedgerTransaction) ReceivableChange() int64 {
+	var change int64
+	for _, e := range t.Entries {
+		if e.Account == enums.AccountReceivable {
+			change += e.Amount
+		}
+	}
+	return change
+}
+
+type PaymentRequest struct {
+	Amount      int64  `json:"amount" validate:"required,min=1"`
+	Description string `json:"description"`
+}
+
+type WaiverRequest struct {
+	Amount int64      `json:"amount" validate:"required,min=1"`
+	Reason string     `json:"reason" validate:"required"`
+	LoanID *uuid.UUID `json:"loan_id"`
+}
+
+// ChargeRequest bills a member for a lost or damaged copy
+type ChargeRequest struct {
+	Amount      int64      `json:"amount" validate:"required,min=1"`
+	Description string     `json:"description" validate:"required"`
+	LoanID      *uuid.UUID `json:"loan_id"`
+}
+
+type MemberBalance struct {
+	MemberID uuid.UUID `json:"member_id"`
+	// Balance is what the member owes, in minor currency units
+	Balance      int64 `json:"balance"`
+	Threshold    int64 `json:"threshold"`
+	GoodStanding bool  `json:"good_standing"`
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type Loan struct {
+	ID           uuid.UUID  `json:"id" gorm:"primaryKey"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	BookID       uuid.UUID  `json:"book_id" gorm:"index;not null"`
+	ItemID       *uuid.UUID `json:"item_id" gorm:"index"`
+	UserID       uuid.UUID  `json:"user_id" gorm:"index;not null"`
+	CheckedOutAt time.Time  `json:"checked_out_at" gorm:"not null"`
+	DueAt        time.Time  `json:"due_at" gorm:"not null"`
+	ReturnedAt   *time.Time `json:"returned_at"`
+	Book         *Book      `json:"-" gorm:"foreignKey:BookID"`
+	Item         *Item      `json:"-" gorm:"foreignKey:ItemID"`
+	User         *User      `json:"-" gorm:"foreignKey:UserID"`
+}
+
+type CheckoutRequest struct {
+	// UserID lets staff check a book out on behalf of a member;
+	// members always borrow for themselves
+	UserID *uuid.UUID `json:"user_id"`
+	// Barcode checks out a specific copy, as scanned at the desk; any
+	// available copy is taken otherwise
+	Barcode string `json:"barcode"`
+}
+
+// LoanQuery filters a loan listing
+type LoanQuery struct {
+	UserID     *uuid.UUID
+	ActiveOnly bool
+}
+
+type LoanResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	BookID       uuid.UUID  `json:"book_id"`
+	ItemID       *uuid.UUID `json:"item_id,omitempty"`
+	UserID       uuid.UUID  `json:"user_id"`
+	CheckedOutAt time.Time  `json:"checked_out_at"`
+	DueAt        time.Time  `json:"due_at"`
+	ReturnedAt   *time.Time `json:"returned_at"`
+	Overdue      bool       `json:"overdue"`
+}
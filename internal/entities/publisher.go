@@ -0,0 +1,80 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Publisher is an entry in the publisher registry. A publisher with a
+// parent is one of the parent's imprints. Books keep the publisher credit
+// as printed and link to the registry by PublisherID.
+type Publisher struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Name      string    `json:"name" gorm:"not null"`
+	// NameKey is the normalized name publishers are matched by
+	NameKey  string           `json:"-" gorm:"not null;index"`
+	ParentID *uuid.UUID       `json:"parent_id" gorm:"index"`
+	Parent   *Publisher       `json:"-" gorm:"foreignKey:ParentID;constraint:OnDelete:RESTRICT"`
+	Imprints []Publisher      `json:"-" gorm:"foreignKey:ParentID"`
+	Aliases  []PublisherAlias `json:"-" gorm:"foreignKey:PublisherID;constraint:OnDelete:CASCADE"`
+	// BookCount is the number of books linked to the publisher itself,
+	// filled in by listings
+	BookCount int64 `json:"-" gorm:"->;-:migration"`
+}
+
+// PublisherAlias is another name a publisher is credited under. Merging
+// publishers keeps the names of the merged ones as aliases.
+type PublisherAlias struct {
+	PublisherID uuid.UUID `json:"publisher_id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"primaryKey"`
+	NameKey     string    `json:"-" gorm:"not null;index"`
+}
+
+type PublisherRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+	// ParentID makes the publisher an imprint of the parent
+	ParentID *uuid.UUID `json:"parent_id"`
+	Aliases  []string   `json:"aliases" validate:"dive,required,max=255"`
+}
+
+// PublisherMergeRequest names the duplicates to fold into a publisher
+type PublisherMergeRequest struct {
+	From []uuid.UUID `json:"from" validate:"required,min=1,max=100"`
+}
+
+// PublisherSummary names a related publisher in a response
+type PublisherSummary struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type PublisherResponse struct {
+	ID        uuid.UUID          `json:"id"`
+	Name      string             `json:"name"`
+	Aliases   []string           `json:"aliases"`
+	Parent    *PublisherSummary  `json:"parent,omitempty"`
+	Imprints  []PublisherSummary `json:"imprints,omitempty"`
+	BookCount int64              `json:"book_count"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// PublisherQuery lists publishers whose name or alias contains Name, in
+// name order. TopLevel leaves imprints out.
+type PublisherQuery struct {
+	Name     string
+	TopLevel bool
+	Page     int
+	Limit    int
+}
+
+type PublisherPage struct {
+	Data  []*PublisherResponse `json:"data"`
+	Total int64                `json:"total"`
+	Page  int                  `json:"page"`
+	Limit int                  `json:"limit"`
+	Links PageLinks            `json:"links"`
+}
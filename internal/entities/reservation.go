@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"time"
+
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// Reservation is a reading-room seat held by a member for one slot of a day.
+// A member holds at most one booked seat per slot.
+type Reservation struct {
+	ID          uuid.UUID               `json:"id" gorm:"primaryKey"`
+	CreatedAt   time.Time               `json:"created_at"`
+	UpdatedAt   time.Time               `json:"updated_at"`
+	UserID      uuid.UUID               `json:"user_id" gorm:"not null;uniqueIndex:idx_reservations_member_slot,where:status = 'booked'"`
+	Date        time.Time               `json:"date" gorm:"type:date;not null;index:idx_reservations_date_slot;uniqueIndex:idx_reservations_member_slot,where:status = 'booked'"`
+	Slot        enums.RevervationSlot   `json:"slot" gorm:"not null;index:idx_reservations_date_slot;uniqueIndex:idx_reservations_member_slot,where:status = 'booked'"`
+	Location    enums.Location          `json:"location" gorm:"type:varchar(16);not null"`
+	Status      enums.ReversationStatus `json:"status" gorm:"type:varchar(16);not null;default:'booked'"`
+	CancelledAt *time.Time              `json:"cancelled_at"`
+	User        *User                   `json:"-" gorm:"foreignKey:UserID"`
+}
+
+type ReservationRequest struct {
+	// StartsAt is any time within the wanted slot; it is mapped onto
+	// the slot with enums.GetSlot
+	StartsAt time.Time      `json:"starts_at" validate:"required"`
+	Location enums.Location `json:"location" validate:"required,oneof=inside outside"`
+	// UserID lets staff book on behalf of a member
+	UserID *uuid.UUID `json:"user_id"`
+}
+
+// ReservationLimits are the checks a booking must pass, evaluated by the
+// model while the day is locked against concurrent bookings
+type ReservationLimits struct {
+	// Capacity is the number of seats in the reserved location
+	Capacity int
+	// DailyLimit is how many slots a member may hold on one day
+	DailyLimit int
+}
+
+// ReservationQuery filters a reservation listing
+type ReservationQuery struct {
+	UserID *uuid.UUID
+	Date   *time.Time
+	Status enums.ReversationStatus
+}
+
+// SlotOccupancy is the number of booked seats in a slot and location
+type SlotOccupancy struct {
+	Slot     enums.RevervationSlot
+	Location enums.Location
+	Booked   int
+}
+
+type ReservationResponse struct {
+	ID          uuid.UUID               `json:"id"`
+	UserID      uuid.UUID               `json:"user_id"`
+	Date        string                  `json:"date"`
+	Slot        enums.RevervationSlot   `json:"slot"`
+	Location    enums.Location          `json:"location"`
+	Status      enums.ReversationStatus `json:"status"`
+	StartsAt    time.Time               `json:"starts_at"`
+	EndsAt      time.Time               `json:"ends_at"`
+	CreatedAt   time.Time               `json:"created_at"`
+	CancelledAt *time.Time              `json:"cancelled_at,omitempty"`
+}
+
+type SlotAvailability struct {
+	Slot      enums.RevervationSlot `json:"slot"`
+	Location  enums.Location        `json:"location"`
+	StartsAt  time.Time             `json:"starts_at"`
+	EndsAt    time.Time             `json:"ends_at"`
+	Capacity  int                   `json:"capacity"`
+	Booked    int                   `json:"booked"`
+	Available int                   `json:"available"`
+}
@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"time"
+
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// Subject is a heading in the subject hierarchy. A subject with a parent
+// narrows it, so "Science fiction" sits under "Fiction". Topics and
+// genres share the hierarchy and are told apart by Kind.
+type Subject struct {
+	ID        uuid.UUID         `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Name      string            `json:"name" gorm:"not null"`
+	Kind      enums.SubjectKind `json:"kind" gorm:"type:varchar(16);not null;default:'topic'"`
+	ParentID  *uuid.UUID        `json:"parent_id" gorm:"index"`
+	Parent    *Subject          `json:"-" gorm:"foreignKey:ParentID;constraint:OnDelete:RESTRICT"`
+	// BookCount is the number of books filed under the subject itself and
+	// TotalCount those under it or any subject below it, filled in by
+	// the tree
+	BookCount  int64 `json:"-" gorm:"->;-:migration"`
+	TotalCount int64 `json:"-" gorm:"->;-:migration"`
+}
+
+// BookSubject files a book under a subject
+type BookSubject struct {
+	BookID    uuid.UUID `json:"book_id" gorm:"primaryKey"`
+	SubjectID uuid.UUID `json:"subject_id" gorm:"primaryKey;index"`
+	Subject   *Subject  `json:"-" gorm:"constraint:OnDelete:RESTRICT"`
+}
+
+type SubjectRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+	// Kind defaults to topic
+	Kind     enums.SubjectKind `json:"kind" validate:"omitempty,oneof=topic genre"`
+	ParentID *uuid.UUID        `json:"parent_id"`
+}
+
+// SubjectSummary names a subject in a book or a browse path
+type SubjectSummary struct {
+	ID   uuid.UUID         `json:"id"`
+	Name string            `json:"name"`
+	Kind enums.SubjectKind `json:"kind"`
+}
+
+// SubjectNode is a subject in a browse tree, with the subjects below it
+type SubjectNode struct {
+	ID       uuid.UUID         `json:"id"`
+	Name     string            `json:"name"`
+	Kind     enums.SubjectKind `json:"kind"`
+	ParentID *uuid.UUID        `json:"parent_id,omitempty"`
+	// BookCount counts the books filed under the subject itself,
+	// TotalCount those under it or any subject below it
+	BookCount  int64          `json:"book_count"`
+	TotalCount int64          `json:"total_count"`
+	Children   []*SubjectNode `json:"children,omitempty"`
+}
+
+// SubjectTree is a browse page: the subjects above the one browsed,
+// broadest first, and the tree below it
+type SubjectTree struct {
+	Path     []SubjectSummary `json:"path,omitempty"`
+	Subjects []*SubjectNode   `json:"subjects"`
+}
+
+// ClassCount is the number of books filed under one class of a scheme
+type ClassCount struct {
+	Class string
+	Count int64
+}
+
+// ClassNode is a class in a classification browse tree. BookCount takes
+// in the classes below it.
+type ClassNode struct {
+	Class     string       `json:"class"`
+	Label     string       `json:"label,omitempty"`
+	BookCount int64        `json:"book_count"`
+	Children  []*ClassNode `json:"children,omitempty"`
+}
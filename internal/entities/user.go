@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"time"
+
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+type User struct {
+	ID           uuid.UUID  `json:"id" gorm:"primaryKey"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	Name         string     `json:"name" gorm:"not null"`
+	Email        string     `json:"email" gorm:"uniqueIndex;not null"`
+	PasswordHash string     `json:"-" gorm:"not null"`
+	Roles        []UserRole `json:"-" gorm:"foreignKey:UserID"`
+}
+
+// UserRole grants one role to a user; a user may hold several
+type UserRole struct {
+	UserID    uuid.UUID  `json:"user_id" gorm:"primaryKey"`
+	Role      enums.Role `json:"role" gorm:"primaryKey"`
+	GrantedBy *uuid.UUID `json:"granted_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RoleAudit records every grant and revoke with the admin who made it
+type RoleAudit struct {
+	ID        uuid.UUID        `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time        `json:"created_at" gorm:"index"`
+	UserID    uuid.UUID        `json:"user_id" gorm:"index;not null"`
+	Role      enums.Role       `json:"role" gorm:"not null"`
+	Action    enums.RoleAction `json:"action" gorm:"not null"`
+	ActorID   uuid.UUID        `json:"actor_id" gorm:"not null"`
+}
+
+type RegisterRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type RoleRequest struct {
+	Role enums.Role `json:"role" validate:"required,oneof=member librarian admin"`
+}
+
+type UserResponse struct {
+	ID        uuid.UUID    `json:"id"`
+	Name      string       `json:"name"`
+	Email     string       `json:"email"`
+	Roles     []enums.Role `json:"roles"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	UserID uuid.UUID
+	Email  string
+	Roles  []enums.Role
+}
+
+// RoleNames lists the roles held by the user
+func (u *User) RoleNames() []enums.Role {
+	roles := make([]enums.Role, len(u.Roles))
+	for i, r := range u.Roles {
+		roles[i] = r.Role
+	}
+	return roles
+}
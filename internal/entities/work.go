@@ -0,0 +1,123 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// Work is the creation that books are editions of. Printings, editions
+// and translations of "The Hobbit" are separate books of one work.
+type Work struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Title     string    `json:"title" gorm:"not null"`
+	Author    string    `json:"author" gorm:"not null"`
+	// TitleKey and AuthorKey are the normalized title and first author
+	// new books are matched against
+	TitleKey  string     `json:"-" gorm:"not null;index:idx_works_keys"`
+	AuthorKey string     `json:"-" gorm:"not null;index:idx_works_keys"`
+	SeriesID  *uuid.UUID `json:"series_id" gorm:"index"`
+	Series    *Series    `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+	// SeriesVolume is the work's number in its series, such as 1 or 2.5
+	// for a novella between the second and third volumes
+	SeriesVolume *float64 `json:"series_volume" gorm:"type:numeric(8,2)"`
+	// EditionCount is the number of books of the work, filled in by
+	// lookups and listings
+	EditionCount int64 `json:"-" gorm:"->;-:migration"`
+}
+
+// Series is a sequence of works, ordered by their volume numbers
+type Series struct {
+	ID          uuid.UUID `json:"id" gorm:"primaryKey"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Name        string    `json:"name" gorm:"not null"`
+	Description string    `json:"description" gorm:"type:text"`
+}
+
+type WorkRequest struct {
+	Title  string `json:"title" validate:"required,max=255"`
+	Author string `json:"author" validate:"required,max=255"`
+	// SeriesID places the work in a series, at SeriesVolume if given
+	SeriesID     *uuid.UUID `json:"series_id"`
+	SeriesVolume *float64   `json:"series_volume" validate:"omitempty,gt=0,lt=1000000"`
+	// BookIDs are books to group under the work as its editions, in
+	// addition to those it has
+	BookIDs []uuid.UUID `json:"book_ids" validate:"max=100"`
+}
+
+type SeriesRequest struct {
+	Name        string `json:"name" validate:"required,max=255"`
+	Description string `json:"description"`
+}
+
+// WorkSummary names a work in a series or a suggestion
+type WorkSummary struct {
+	ID           uuid.UUID `json:"id"`
+	Title        string    `json:"title"`
+	Author       string    `json:"author"`
+	SeriesVolume *float64  `json:"series_volume,omitempty"`
+}
+
+// SeriesSummary names the series of a work
+type SeriesSummary struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type WorkResponse struct {
+	ID           uuid.UUID      `json:"id"`
+	Title        string         `json:"title"`
+	Author       string         `json:"author"`
+	Series       *SeriesSummary `json:"series,omitempty"`
+	SeriesVolume *float64       `json:"series_volume,omitempty"`
+	EditionCount int64          `json:"edition_count"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+type SeriesResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	// Works are listed on single series lookups only, in volume order
+	Works     []WorkSummary `json:"works,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// SeriesPosition places a book's work in its series, between the works
+// before and after it
+type SeriesPosition struct {
+	Series   SeriesSummary `json:"series"`
+	Work     WorkSummary   `json:"work"`
+	Previous *WorkSummary  `json:"previous,omitempty"`
+	Next     *WorkSummary  `json:"next,omitempty"`
+}
+
+// WorkSuggestion proposes grouping a new book with books of the same title
+// and author: under an existing work, or under a new work with the books
+// listed
+type WorkSuggestion struct {
+	Work    *WorkSummary `json:"work,omitempty"`
+	BookIDs []uuid.UUID  `json:"book_ids,omitempty"`
+}
+
+// WorkQuery lists works whose title and author credit contain Title and
+// Author, in title order
+type WorkQuery struct {
+	Title  string
+	Author string
+	Page   int
+	Limit  int
+}
+
+type WorkPage struct {
+	Data  []*WorkResponse `json:"data"`
+	Total int64           `json:"total"`
+	Page  int             `json:"page"`
+	Limit int             `json:"limit"`
+	Links PageLinks       `json:"links"`
+}
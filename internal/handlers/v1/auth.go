@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"library-system/internal/entities"
+	"net/http"
+)
+
+func (h *handlerV1) Register(w http.ResponseWriter, r *http.Request) {
+	var req entities.RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	user, err := h.Service.Register(r.Context(), &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *handlerV1) Login(w http.ResponseWriter, r *http.Request) {
+	var req entities.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tokens, err := h.Service.Login(r.Context(), &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(tokens)
+}
+
+func (h *handlerV1) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	var req entities.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tokens, err := h.Service.RefreshToken(r.Context(), &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(tokens)
+}
+
+func (h *handlerV1) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.Service.GetCurrentUser(r.Context())
+	if err != nil {
+		if errors.Is(err, entities.ErrInvalidToken) || errors.Is(err, entities.ErrUserNotFound) {
+			err = entities.ErrUnauthenticated
+		}
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
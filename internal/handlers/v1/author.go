@@ -0,0 +1,152 @@
+package v1
+
+import (
+	"encoding/json"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListAuthors lists authors in sort name order. The name parameter matches
+// any part of an author's name or aliases.
+func (h *handlerV1) ListAuthors(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	page, err := parseInt(values, "page")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	limit, err := parseInt(values, "limit")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result, err := h.Service.ListAuthors(r.Context(), &entities.AuthorQuery{
+		Name:  values.Get("name"),
+		Page:  page,
+		Limit: limit,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	hasNext := int64(result.Page*result.Limit) < result.Total
+	result.Links = pageLinks(r.URL, result.Page, hasNext, "", "")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *handlerV1) GetAuthor(w http.ResponseWriter, r *http.Request) {
+	id, ok := authorID(w, r)
+	if !ok {
+		return
+	}
+
+	author, err := h.Service.GetAuthor(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(author)
+}
+
+func (h *handlerV1) CreateAuthor(w http.ResponseWriter, r *http.Request) {
+	var req entities.AuthorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	author, err := h.Service.CreateAuthor(r.Context(), &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(author)
+}
+
+func (h *handlerV1) UpdateAuthor(w http.ResponseWriter, r *http.Request) {
+	id, ok := authorID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.AuthorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	author, err := h.Service.UpdateAuthor(r.Context(), id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(author)
+}
+
+func (h *handlerV1) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
+	id, ok := authorID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteAuthor(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAuthorBooks lists the books an author is credited on, optionally in
+// one role only
+func (h *handlerV1) ListAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	id, ok := authorID(w, r)
+	if !ok {
+		return
+	}
+
+	role := enums.ContributorRole(r.URL.Query().Get("role"))
+	books, err := h.Service.ListAuthorBooks(r.Context(), id, role)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(books)
+}
+
+func authorID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.FromString(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -2,7 +2,10 @@ package v1
 
 import (
 	"encoding/json"
+	"io"
 	"library-system/internal/entities"
+	"library-system/internal/jsonpatch"
+	"mime"
 	"net/http"
 
 	"github.com/gofrs/uuid"
@@ -10,101 +13,257 @@ import (
 )
 
 func (h *handlerV1) GetAllBooks(w http.ResponseWriter, r *http.Request) {
-	books, err := h.Service.GetAllBooks(r.Context())
+	query, err := parseBookQuery(r.URL.Query())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
+	page, err := h.Service.GetAllBooks(r.Context(), query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page.Links = pageLinks(r.URL, page.Page, page.NextCursor != "", page.NextCursor, page.PrevCursor)
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(books)
+	json.NewEncoder(w).Encode(page)
+}
+
+func (h *handlerV1) SearchBooks(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	page, err := parseInt(values, "page")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	limit, err := parseInt(values, "limit")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result, err := h.Service.SearchBooks(r.Context(), &entities.BookSearchQuery{
+		Q:     values.Get("q"),
+		Page:  page,
+		Limit: limit,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	hasNext := int64(result.Page*result.Limit) < result.Total
+	result.Links = pageLinks(r.URL, result.Page, hasNext, "", "")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
 }
 
 func (h *handlerV1) GetBookByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.FromString(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	asOf, err := parseDate(r.URL.Query(), "as_of")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if asOf != nil {
+		h.getBookAsOf(w, r, id, *asOf)
 		return
 	}
 
 	book, err := h.Service.GetBookByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(book)
+	writeBook(w, r, book)
+}
+
+func (h *handlerV1) GetBookByISBN(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	book, err := h.Service.GetBookByISBN(r.Context(), vars["isbn"])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeBook(w, r, book)
 }
 
 func (h *handlerV1) CreateBook(w http.ResponseWriter, r *http.Request) {
 	var req entities.BookRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, r, entities.ErrInvalidBody)
 		return
 	}
 
 	if err := h.Validate.Struct(req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	err := h.Service.CreateBook(r.Context(), &req)
+	book, err := h.Service.CreateBook(r.Context(), &req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", bookETag(book.Version))
 	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(book)
 }
 
 func (h *handlerV1) UpdateBook(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.FromString(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	var req entities.BookRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeError(w, r, entities.ErrInvalidBody)
 		return
 	}
 
 	if err := h.Validate.Struct(req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	err = h.Service.UpdateBook(r.Context(), id, &req)
+	book, err := h.Service.UpdateBook(r.Context(), id, version, &req)
 	if err != nil {
-		if err == entities.ErrBookNotFound {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", bookETag(book.Version))
+	json.NewEncoder(w).Encode(book)
+}
+
+// PatchBook takes a JSON Merge Patch (application/merge-patch+json) or a
+// JSON Patch (application/json-patch+json) of the book as a PUT body
+func (h *handlerV1) PatchBook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType != jsonpatch.MergePatchType && contentType != jsonpatch.JSONPatchType {
+		w.Header().Set("Accept-Patch", acceptPatch)
+		writeError(w, r, entities.ErrUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	book, err := h.Service.PatchBook(r.Context(), id, version, &entities.BookPatch{ContentType: contentType, Body: body})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", bookETag(book.Version))
+	json.NewEncoder(w).Encode(book)
 }
 
 func (h *handlerV1) DeleteBook(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := uuid.FromString(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid book ID", http.StatusBadRequest)
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	err = h.Service.DeleteBook(r.Context(), id, version)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handlerV1) ListTrash(w http.ResponseWriter, r *http.Request) {
+	books, err := h.Service.ListTrash(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(books)
+}
+
+func (h *handlerV1) RestoreBook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	book, err := h.Service.RestoreBook(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(book)
+}
+
+func (h *handlerV1) PurgeBook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
 		return
 	}
 
-	err = h.Service.DeleteBook(r.Context(), id)
+	err = h.Service.PurgeBook(r.Context(), id)
 	if err != nil {
-		if err == entities.ErrBookNotFound {
-			http.Error(w, err.Error(), http.StatusNotFound)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -0,0 +1,86 @@
+package v1
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"library-system/internal/entities"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+// SetCover takes a multipart/form-data upload with the image in the
+// "cover" field. The part is streamed to the service, which enforces the
+// size limit and checks the image by its content.
+func (h *handlerV1) SetCover(w http.ResponseWriter, r *http.Request) {
+	id, ok := coverBookID(w, r)
+	if !ok {
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		writeError(w, r, fmt.Errorf("%w: expected a multipart/form-data upload", entities.ErrInvalidBody))
+		return
+	}
+	var part io.Reader
+	for part == nil {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			writeError(w, r, fmt.Errorf("%w: missing cover field", entities.ErrInvalidBody))
+			return
+		}
+		if err != nil {
+			writeError(w, r, fmt.Errorf("%w: invalid multipart upload", entities.ErrInvalidBody))
+			return
+		}
+		if p.FormName() == "cover" {
+			part = p
+		}
+	}
+
+	book, err := h.Service.SetCover(r.Context(), id, version, part)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeBook(w, r, book)
+}
+
+func (h *handlerV1) DeleteCover(w http.ResponseWriter, r *http.Request) {
+	id, ok := coverBookID(w, r)
+	if !ok {
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.Service.DeleteCover(r.Context(), id, version); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func coverBookID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.FromString(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return uuid.Nil, false
+	}
+	return id, true
+}
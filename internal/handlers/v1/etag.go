@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"library-system/internal/entities"
+	"library-system/internal/jsonpatch"
+)
+
+// Books are tagged with their version, which a trigger bumps on every
+// change, as a strong ETag such as "7"
+
+// acceptPatch lists the formats PATCH takes, for the Accept-Patch header
+const acceptPatch = jsonpatch.MergePatchType + ", " + jsonpatch.JSONPatchType
+
+func bookETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// writeBook answers a read of a book with its ETag, or with 304 when the
+// client's If-None-Match already names it
+func writeBook(w http.ResponseWriter, r *http.Request, book *entities.BookResponse) {
+	etag := bookETag(book.Version)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Accept-Patch", acceptPatch)
+	if noneMatch := r.Header.Get("If-None-Match"); noneMatch != "" && etagListed(noneMatch, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(book)
+}
+
+// etagListed reports whether an If-None-Match list names the tag, using
+// the weak comparison RFC 9110 asks for
+func etagListed(list, etag string) bool {
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// ifMatchVersion reads the version a change to a book is based on from
+// If-Match. "*" matches any version and comes back as zero. A missing
+// header fails with ErrVersionRequired, a tag that cannot be one of ours,
+// such as a weak one, with ErrBookModified.
+func ifMatchVersion(r *http.Request) (int64, error) {
+	tag := strings.TrimSpace(r.Header.Get("If-Match"))
+	if tag == "" {
+		return 0, entities.ErrVersionRequired
+	}
+	if tag == "*" {
+		return 0, nil
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(tag, `"`), 10, 64)
+	if err != nil || version <= 0 || tag != bookETag(version) {
+		return 0, entities.ErrBookModified
+	}
+	return version, nil
+}
@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"fmt"
+	"library-system/internal/bookio"
+	"library-system/internal/entities"
+	"net/http"
+)
+
+// exportExtensions are the file extensions suggested for each export format
+var exportExtensions = map[string]string{
+	bookio.FormatCSV:     "csv",
+	bookio.FormatNDJSON:  "ndjson",
+	bookio.FormatMARC:    "mrc",
+	bookio.FormatMARCXML: "xml",
+	bookio.FormatDC:      "xml",
+}
+
+// ExportBooks streams the catalogue as a download, filtered by the author,
+// publisher and publish date parameters of the book listing
+func (h *handlerV1) ExportBooks(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	format := values.Get("format")
+	if format == "" {
+		format = bookio.FormatCSV
+	}
+	ext, ok := exportExtensions[format]
+	if !ok {
+		writeError(w, r, fmt.Errorf("%w: format must be csv, ndjson, marc, marcxml or oai_dc", entities.ErrInvalidParameter))
+		return
+	}
+
+	query, err := parseBookQuery(values)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	sw := &startedWriter{ResponseWriter: w}
+	sw.Header().Set("Content-Type", bookio.ContentType(format))
+	sw.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="catalogue.%s"`, ext))
+
+	if _, err := h.Service.ExportBooks(r.Context(), format, query, sw); err != nil {
+		if sw.started {
+			// The status is sent; cut the response short so the client
+			// sees a truncated download rather than a complete one
+			panic(http.ErrAbortHandler)
+		}
+		writeError(w, r, err)
+	}
+}
+
+// startedWriter records whether any of the body has been written
+type startedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (s *startedWriter) Write(p []byte) (int, error) {
+	s.started = true
+	return s.ResponseWriter.Write(p)
+}
@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"encoding/json"
+	"library-system/internal/entities"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+func (h *handlerV1) GetMemberBalance(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	balance, err := h.Service.GetMemberBalance(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(balance)
+}
+
+func (h *handlerV1) ListMemberTransactions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	txns, err := h.Service.ListMemberTransactions(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(txns)
+}
+
+func (h *handlerV1) RecordPayment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	var req entities.PaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	txn, err := h.Service.RecordPayment(r.Context(), id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(txn)
+}
+
+func (h *handlerV1) WaiveFine(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	var req entities.WaiverRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	txn, err := h.Service.WaiveFine(r.Context(), id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(txn)
+}
+
+func (h *handlerV1) ChargeMember(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	var req entities.ChargeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	txn, err := h.Service.ChargeMember(r.Context(), id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(txn)
+}
@@ -3,6 +3,7 @@ package v1
 import (
 	"net/http"
 
+	"library-system/internal/auth"
 	"library-system/internal/services"
 
 	"github.com/go-playground/validator/v10"
@@ -15,10 +16,101 @@ type handlerV1 struct {
 
 type HandlerV1 interface {
 	GetBookByID(w http.ResponseWriter, r *http.Request)
+	GetBookByISBN(w http.ResponseWriter, r *http.Request)
 	GetAllBooks(w http.ResponseWriter, r *http.Request)
+	SearchBooks(w http.ResponseWriter, r *http.Request)
 	CreateBook(w http.ResponseWriter, r *http.Request)
 	UpdateBook(w http.ResponseWriter, r *http.Request)
+	PatchBook(w http.ResponseWriter, r *http.Request)
 	DeleteBook(w http.ResponseWriter, r *http.Request)
+	ListTrash(w http.ResponseWriter, r *http.Request)
+	RestoreBook(w http.ResponseWriter, r *http.Request)
+	PurgeBook(w http.ResponseWriter, r *http.Request)
+	ImportBooks(w http.ResponseWriter, r *http.Request)
+	GetImportJob(w http.ResponseWriter, r *http.Request)
+	ExportBooks(w http.ResponseWriter, r *http.Request)
+	SetCover(w http.ResponseWriter, r *http.Request)
+	DeleteCover(w http.ResponseWriter, r *http.Request)
+	ListBookHistory(w http.ResponseWriter, r *http.Request)
+	RevertBook(w http.ResponseWriter, r *http.Request)
+
+	ListAuthors(w http.ResponseWriter, r *http.Request)
+	GetAuthor(w http.ResponseWriter, r *http.Request)
+	CreateAuthor(w http.ResponseWriter, r *http.Request)
+	UpdateAuthor(w http.ResponseWriter, r *http.Request)
+	DeleteAuthor(w http.ResponseWriter, r *http.Request)
+	ListAuthorBooks(w http.ResponseWriter, r *http.Request)
+
+	ListPublishers(w http.ResponseWriter, r *http.Request)
+	GetPublisher(w http.ResponseWriter, r *http.Request)
+	CreatePublisher(w http.ResponseWriter, r *http.Request)
+	UpdatePublisher(w http.ResponseWriter, r *http.Request)
+	DeletePublisher(w http.ResponseWriter, r *http.Request)
+	ListPublisherBooks(w http.ResponseWriter, r *http.Request)
+	MergePublishers(w http.ResponseWriter, r *http.Request)
+
+	BrowseSubjects(w http.ResponseWriter, r *http.Request)
+	GetSubject(w http.ResponseWriter, r *http.Request)
+	CreateSubject(w http.ResponseWriter, r *http.Request)
+	UpdateSubject(w http.ResponseWriter, r *http.Request)
+	DeleteSubject(w http.ResponseWriter, r *http.Request)
+	BrowseClasses(w http.ResponseWriter, r *http.Request)
+
+	ListWorks(w http.ResponseWriter, r *http.Request)
+	GetWork(w http.ResponseWriter, r *http.Request)
+	CreateWork(w http.ResponseWriter, r *http.Request)
+	UpdateWork(w http.ResponseWriter, r *http.Request)
+	DeleteWork(w http.ResponseWriter, r *http.Request)
+	ListEditions(w http.ResponseWriter, r *http.Request)
+	GetSeriesPosition(w http.ResponseWriter, r *http.Request)
+	ListSeries(w http.ResponseWriter, r *http.Request)
+	GetSeries(w http.ResponseWriter, r *http.Request)
+	CreateSeries(w http.ResponseWriter, r *http.Request)
+	UpdateSeries(w http.ResponseWriter, r *http.Request)
+	DeleteSeries(w http.ResponseWriter, r *http.Request)
+
+	ListItems(w http.ResponseWriter, r *http.Request)
+	GetItem(w http.ResponseWriter, r *http.Request)
+	CreateItem(w http.ResponseWriter, r *http.Request)
+	UpdateItem(w http.ResponseWriter, r *http.Request)
+	DeleteItem(w http.ResponseWriter, r *http.Request)
+
+	Register(w http.ResponseWriter, r *http.Request)
+	Login(w http.ResponseWriter, r *http.Request)
+	RefreshToken(w http.ResponseWriter, r *http.Request)
+	GetCurrentUser(w http.ResponseWriter, r *http.Request)
+
+	CheckoutBook(w http.ResponseWriter, r *http.Request)
+	ReturnLoan(w http.ResponseWriter, r *http.Request)
+	GetLoan(w http.ResponseWriter, r *http.Request)
+	ListLoans(w http.ResponseWriter, r *http.Request)
+
+	GetMemberBalance(w http.ResponseWriter, r *http.Request)
+	ListMemberTransactions(w http.ResponseWriter, r *http.Request)
+	RecordPayment(w http.ResponseWriter, r *http.Request)
+	WaiveFine(w http.ResponseWriter, r *http.Request)
+	ChargeMember(w http.ResponseWriter, r *http.Request)
+
+	PlaceHold(w http.ResponseWriter, r *http.Request)
+	GetHoldQueue(w http.ResponseWriter, r *http.Request)
+	ListHolds(w http.ResponseWriter, r *http.Request)
+	GetHold(w http.ResponseWriter, r *http.Request)
+	CancelHold(w http.ResponseWriter, r *http.Request)
+	SetHoldPriority(w http.ResponseWriter, r *http.Request)
+
+	ReserveSeat(w http.ResponseWriter, r *http.Request)
+	CancelReservation(w http.ResponseWriter, r *http.Request)
+	GetReservation(w http.ResponseWriter, r *http.Request)
+	ListReservations(w http.ResponseWriter, r *http.Request)
+	GetSeatAvailability(w http.ResponseWriter, r *http.Request)
+
+	GetUserRoles(w http.ResponseWriter, r *http.Request)
+	GrantRole(w http.ResponseWriter, r *http.Request)
+	RevokeRole(w http.ResponseWriter, r *http.Request)
+	ListRoleAudit(w http.ResponseWriter, r *http.Request)
+
+	Authenticate(next http.Handler) http.Handler
+	Authorize(perm auth.Permission) func(http.Handler) http.Handler
 }
 
 func New(s services.Service, v *validator.Validate) HandlerV1 {
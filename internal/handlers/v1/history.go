@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"library-system/internal/entities"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+func (h *handlerV1) ListBookHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.FromString(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	revisions, err := h.Service.ListBookHistory(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(revisions)
+}
+
+// getBookAsOf answers GET /api/books/{id}?as_of=. A past state is not one
+// a change can be based on, so it goes out without an ETag.
+func (h *handlerV1) getBookAsOf(w http.ResponseWriter, r *http.Request, id uuid.UUID, at time.Time) {
+	book, err := h.Service.GetBookAsOf(r.Context(), id, at)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(book)
+}
+
+// RevertBook restores the book to a revision from its history. Like any
+// other change it needs the If-Match header.
+func (h *handlerV1) RevertBook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+	revision, err := strconv.ParseInt(vars["revision"], 10, 64)
+	if err != nil || revision < 1 {
+		writeError(w, r, fmt.Errorf("%w: revision %q", entities.ErrInvalidParameter, vars["revision"]))
+		return
+	}
+
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	book, err := h.Service.RevertBook(r.Context(), id, version, revision)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", bookETag(book.Version))
+	json.NewEncoder(w).Encode(book)
+}
@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"library-system/internal/entities"
+	"net/http"
+	"strconv"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+func (h *handlerV1) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	// The body is optional: members place holds for themselves
+	var req entities.HoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	hold, err := h.Service.PlaceHold(r.Context(), id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hold)
+}
+
+func (h *handlerV1) GetHoldQueue(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	holds, err := h.Service.GetHoldQueue(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(holds)
+}
+
+func (h *handlerV1) ListHolds(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	var query entities.HoldQuery
+	if v := values.Get("user_id"); v != "" {
+		id, err := uuid.FromString(v)
+		if err != nil {
+			writeError(w, r, entities.ErrInvalidID)
+			return
+		}
+		query.UserID = &id
+	}
+	if v := values.Get("active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("%w: active %q", entities.ErrInvalidParameter, v))
+			return
+		}
+		query.ActiveOnly = active
+	}
+
+	holds, err := h.Service.ListHolds(r.Context(), &query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(holds)
+}
+
+func (h *handlerV1) GetHold(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	hold, err := h.Service.GetHold(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hold)
+}
+
+func (h *handlerV1) CancelHold(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	hold, err := h.Service.CancelHold(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hold)
+}
+
+func (h *handlerV1) SetHoldPriority(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	var req entities.HoldPriorityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	hold, err := h.Service.SetHoldPriority(r.Context(), id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hold)
+}
@@ -0,0 +1,73 @@
+package v1
+
+import (
+	"encoding/json"
+	"library-system/internal/bookio"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"mime"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ImportBooks takes a CSV, NDJSON or MARC 21 upload, named by the format
+// parameter or the Content-Type. Small uploads are answered with the
+// finished job; large ones with 202 and the location of the job to poll.
+func (h *handlerV1) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = uploadFormat(r.Header.Get("Content-Type"))
+	}
+
+	job, err := h.Service.ImportBooks(r.Context(), &entities.ImportRequest{
+		Format: format,
+		Body:   r.Body,
+		Size:   r.ContentLength,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if job.Status == enums.ImportQueued || job.Status == enums.ImportRunning {
+		w.Header().Set("Location", "/api/books/import/"+job.ID.String())
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(job)
+}
+
+func (h *handlerV1) GetImportJob(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	outcome := enums.ImportOutcome(r.URL.Query().Get("outcome"))
+	job, err := h.Service.GetImportJob(r.Context(), id, outcome)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// uploadFormat maps a Content-Type to an import format
+func uploadFormat(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return bookio.FormatCSV
+	case "application/x-ndjson", "application/jsonl":
+		return bookio.FormatNDJSON
+	case "application/marc":
+		return bookio.FormatMARC
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"encoding/json"
+	"library-system/internal/entities"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+func (h *handlerV1) ListItems(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bookID, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	items, err := h.Service.ListItems(r.Context(), bookID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+func (h *handlerV1) GetItem(w http.ResponseWriter, r *http.Request) {
+	bookID, id, ok := itemIDs(w, r)
+	if !ok {
+		return
+	}
+
+	item, err := h.Service.GetItem(r.Context(), bookID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *handlerV1) CreateItem(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bookID, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	var req entities.ItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	item, err := h.Service.CreateItem(r.Context(), bookID, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *handlerV1) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	bookID, id, ok := itemIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.ItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	item, err := h.Service.UpdateItem(r.Context(), bookID, id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+func (h *handlerV1) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	bookID, id, ok := itemIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteItem(r.Context(), bookID, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// itemIDs parses the book and item IDs of an item route
+func itemIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	vars := mux.Vars(r)
+	bookID, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.FromString(vars["itemId"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return bookID, id, true
+}
@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"library-system/internal/entities"
+	"net/http"
+	"strconv"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+func (h *handlerV1) CheckoutBook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	// The body is optional: members borrow for themselves
+	var req entities.CheckoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	loan, err := h.Service.CheckoutBook(r.Context(), id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(loan)
+}
+
+func (h *handlerV1) ReturnLoan(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	loan, err := h.Service.ReturnLoan(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loan)
+}
+
+func (h *handlerV1) GetLoan(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	loan, err := h.Service.GetLoan(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loan)
+}
+
+func (h *handlerV1) ListLoans(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	var query entities.LoanQuery
+	if v := values.Get("user_id"); v != "" {
+		id, err := uuid.FromString(v)
+		if err != nil {
+			writeError(w, r, entities.ErrInvalidID)
+			return
+		}
+		query.UserID = &id
+	}
+	if v := values.Get("active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, r, fmt.Errorf("%w: active %q", entities.ErrInvalidParameter, v))
+			return
+		}
+		query.ActiveOnly = active
+	}
+
+	loans, err := h.Service.ListLoans(r.Context(), &query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loans)
+}
@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+
+	"library-system/internal/auth"
+	"library-system/internal/entities"
+)
+
+// Authenticate resolves a bearer access token into the request principal.
+// Requests without an Authorization header pass through anonymously; a
+// header carrying a bad or expired token is rejected.
+func (h *handlerV1) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		scheme, token, ok := strings.Cut(header, " ")
+		if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_request"`)
+			writeError(w, r, entities.ErrMalformedAuthorization)
+			return
+		}
+
+		principal, err := h.Service.Authenticate(r.Context(), token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeError(w, r, entities.ErrInvalidToken)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// Authorize only lets through authenticated callers holding a role that
+// grants perm: anonymous requests get 401, the rest 403.
+func (h *handlerV1) Authorize(perm auth.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.PrincipalFromContext(r.Context())
+			if !ok {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeRefusal(w, r, entities.ErrUnauthenticated, perm)
+				return
+			}
+
+			if !auth.Can(principal, perm) {
+				writeRefusal(w, r, entities.ErrForbidden, perm)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeRefusal answers a request refused for lacking a permission, naming
+// the permission
+func writeRefusal(w http.ResponseWriter, r *http.Request, err error, perm auth.Permission) {
+	p := problemFor(r, err)
+	p.Permission = string(perm)
+	writeProblem(w, p)
+}
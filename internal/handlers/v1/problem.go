@@ -0,0 +1,121 @@
+package v1
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"library-system/internal/entities"
+	"library-system/internal/validation"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ProblemType is the media type of error responses
+const ProblemType = "application/problem+json"
+
+// kindStatus is the HTTP status each kind of error is reported with
+var kindStatus = map[entities.Kind]int{
+	entities.KindMalformed:            http.StatusBadRequest,
+	entities.KindInvalid:              http.StatusUnprocessableEntity,
+	entities.KindUnauthenticated:      http.StatusUnauthorized,
+	entities.KindForbidden:            http.StatusForbidden,
+	entities.KindNotFound:             http.StatusNotFound,
+	entities.KindConflict:             http.StatusConflict,
+	entities.KindPreconditionFailed:   http.StatusPreconditionFailed,
+	entities.KindPreconditionRequired: http.StatusPreconditionRequired,
+	entities.KindTooLarge:             http.StatusRequestEntityTooLarge,
+	entities.KindUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	entities.KindUnavailable:          http.StatusServiceUnavailable,
+	entities.KindInternal:             http.StatusInternalServerError,
+}
+
+// writeError answers a request that failed with err as a problem. Errors
+// of the entities taxonomy are reported by their kind and code, validation
+// errors field by field, and a database that cannot be reached as 503.
+// Anything else is a 500 whose cause is logged rather than shown.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	writeProblem(w, problemFor(r, err))
+}
+
+func problemFor(r *http.Request, err error) *entities.Problem {
+	fields := validation.Fields(err)
+	detail := err.Error()
+
+	var known *entities.Error
+	switch {
+	case errors.As(err, &known):
+	case fields != nil:
+		known = entities.ErrValidation
+	case unavailable(err):
+		log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+		known, detail = entities.ErrUnavailable, entities.ErrUnavailable.Message
+	default:
+		known = entities.ErrInternal
+	}
+	if known.Kind == entities.KindInternal {
+		log.Printf("%s %s: %v", r.Method, r.URL.Path, err)
+		detail = entities.ErrInternal.Message
+	}
+
+	// Validator messages name Go types and tags, so the fields are
+	// described in the API's terms instead
+	if fields != nil {
+		detail = known.Message + ": " + validation.Reason(err)
+	}
+
+	status := kindStatus[known.Kind]
+	return &entities.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     known.Code,
+		Errors:   fields,
+	}
+}
+
+func writeProblem(w http.ResponseWriter, p *entities.Problem) {
+	w.Header().Set("Content-Type", ProblemType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if p.Status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", "5")
+	}
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// unavailable reports whether err comes from a database that is down,
+// unreachable or refusing connections, which a retry may get past
+func unavailable(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Connection exceptions, too many connections, and a server shutting
+	// down or starting up
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "53300" ||
+			pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	}
+
+	return false
+}
@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"library-system/internal/entities"
+	"library-system/internal/validation"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func Test_writeError(t *testing.T) {
+	type book struct {
+		Title string `json:"title" validate:"required"`
+	}
+	invalid := validation.New().Struct(book{})
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+		wantFields []entities.FieldError
+	}{
+		{
+			name:       "not found",
+			err:        entities.ErrBookNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   "book_not_found",
+			wantDetail: "book not found",
+		},
+		{
+			name:       "duplicate isbn",
+			err:        entities.ErrDuplicateISBN,
+			wantStatus: http.StatusConflict,
+			wantCode:   "duplicate_isbn",
+			wantDetail: "a book with this isbn already exists",
+		},
+		{
+			name:       "wrapped with a detail",
+			err:        fmt.Errorf("%w: limit %q", entities.ErrInvalidParameter, "x"),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_parameter",
+			wantDetail: `invalid parameter: limit "x"`,
+		},
+		{
+			name:       "failed validation",
+			err:        invalid,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "validation_failed",
+			wantDetail: "request failed validation: title is required",
+			wantFields: []entities.FieldError{{Field: "title", Code: "required", Detail: "is required"}},
+		},
+		{
+			name:       "failed validation in a service",
+			err:        fmt.Errorf("%w: %w", entities.ErrInvalidBook, invalid),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "invalid_book",
+			wantDetail: "invalid book: title is required",
+			wantFields: []entities.FieldError{{Field: "title", Code: "required", Detail: "is required"}},
+		},
+		{
+			name:       "connection closed",
+			err:        sql.ErrConnDone,
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   "service_unavailable",
+			wantDetail: "service temporarily unavailable",
+		},
+		{
+			name:       "server shutting down",
+			err:        fmt.Errorf("listing books: %w", &pgconn.PgError{Code: "57P01", Message: "terminating connection"}),
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   "service_unavailable",
+			wantDetail: "service temporarily unavailable",
+		},
+		{
+			name:       "anything else",
+			err:        &pgconn.PgError{Code: "42P01", Message: `relation "books" does not exist`},
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   "internal_error",
+			wantDetail: "internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			writeError(w, httptest.NewRequest(http.MethodGet, "/api/books/1", nil), tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("writeError() status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Content-Type"); got != ProblemType {
+				t.Errorf("writeError() Content-Type = %q, want %q", got, ProblemType)
+			}
+
+			var p entities.Problem
+			if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+				t.Fatalf("writeError() body: %v", err)
+			}
+			if p.Status != tt.wantStatus || p.Code != tt.wantCode || p.Detail != tt.wantDetail || p.Instance != "/api/books/1" {
+				t.Errorf("writeError() = %+v", p)
+			}
+			if len(p.Errors) != len(tt.wantFields) {
+				t.Fatalf("writeError() errors = %+v, want %+v", p.Errors, tt.wantFields)
+			}
+			for i, field := range tt.wantFields {
+				if p.Errors[i] != field {
+					t.Errorf("writeError() errors[%d] = %+v, want %+v", i, p.Errors[i], field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"library-system/internal/entities"
+	"net/http"
+	"strconv"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListPublishers lists publishers in name order. The name parameter matches
+// any part of a publisher's name or aliases, and top_level=true leaves
+// imprints out.
+func (h *handlerV1) ListPublishers(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	page, err := parseInt(values, "page")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	limit, err := parseInt(values, "limit")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	query := &entities.PublisherQuery{
+		Name:  values.Get("name"),
+		Page:  page,
+		Limit: limit,
+	}
+	if v := values.Get("top_level"); v != "" {
+		if query.TopLevel, err = strconv.ParseBool(v); err != nil {
+			writeError(w, r, fmt.Errorf("%w: top_level %q", entities.ErrInvalidParameter, v))
+			return
+		}
+	}
+
+	result, err := h.Service.ListPublishers(r.Context(), query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	hasNext := int64(result.Page*result.Limit) < result.Total
+	result.Links = pageLinks(r.URL, result.Page, hasNext, "", "")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *handlerV1) GetPublisher(w http.ResponseWriter, r *http.Request) {
+	id, ok := publisherID(w, r)
+	if !ok {
+		return
+	}
+
+	publisher, err := h.Service.GetPublisher(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(publisher)
+}
+
+func (h *handlerV1) CreatePublisher(w http.ResponseWriter, r *http.Request) {
+	var req entities.PublisherRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	publisher, err := h.Service.CreatePublisher(r.Context(), &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(publisher)
+}
+
+func (h *handlerV1) UpdatePublisher(w http.ResponseWriter, r *http.Request) {
+	id, ok := publisherID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.PublisherRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	publisher, err := h.Service.UpdatePublisher(r.Context(), id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(publisher)
+}
+
+func (h *handlerV1) DeletePublisher(w http.ResponseWriter, r *http.Request) {
+	id, ok := publisherID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeletePublisher(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListPublisherBooks lists the books linked to a publisher; imprints=true
+// takes in the books of its imprints too
+func (h *handlerV1) ListPublisherBooks(w http.ResponseWriter, r *http.Request) {
+	id, ok := publisherID(w, r)
+	if !ok {
+		return
+	}
+
+	var imprints bool
+	if v := r.URL.Query().Get("imprints"); v != "" {
+		var err error
+		if imprints, err = strconv.ParseBool(v); err != nil {
+			writeError(w, r, fmt.Errorf("%w: imprints %q", entities.ErrInvalidParameter, v))
+			return
+		}
+	}
+
+	books, err := h.Service.ListPublisherBooks(r.Context(), id, imprints)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(books)
+}
+
+// MergePublishers folds the publishers listed in the body into the one in
+// the path and returns it
+func (h *handlerV1) MergePublishers(w http.ResponseWriter, r *http.Request) {
+	id, ok := publisherID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.PublisherMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	publisher, err := h.Service.MergePublishers(r.Context(), id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(publisher)
+}
+
+func publisherID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.FromString(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return uuid.Nil, false
+	}
+	return id, true
+}
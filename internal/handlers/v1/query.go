@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"library-system/internal/classification"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// parseBookQuery reads the listing parameters of GET /api/books:
+//
+//	page, limit, cursor, author, publisher, published_from, published_to,
+//	available, subject, dewey, lcc and sort (comma separated, "-" prefix
+//	for descending)
+func parseBookQuery(values url.Values) (*entities.BookQuery, error) {
+	query := &entities.BookQuery{
+		Cursor:    values.Get("cursor"),
+		Author:    strings.TrimSpace(values.Get("author")),
+		Publisher: strings.TrimSpace(values.Get("publisher")),
+		Dewey:     classification.Prefix(enums.SchemeDewey, values.Get("dewey")),
+		LCC:       classification.Prefix(enums.SchemeLCC, values.Get("lcc")),
+	}
+
+	var err error
+	if query.Page, err = parseInt(values, "page"); err != nil {
+		return nil, err
+	}
+	if query.Limit, err = parseInt(values, "limit"); err != nil {
+		return nil, err
+	}
+	if query.PublishedFrom, err = parseDate(values, "published_from"); err != nil {
+		return nil, err
+	}
+	if query.PublishedTo, err = parseDate(values, "published_to"); err != nil {
+		return nil, err
+	}
+
+	if v := values.Get("available"); v != "" {
+		available, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: available %q", entities.ErrInvalidParameter, v)
+		}
+		query.Available = &available
+	}
+
+	if v := values.Get("subject"); v != "" {
+		subject, err := uuid.FromString(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: subject %q", entities.ErrInvalidParameter, v)
+		}
+		query.Subject = &subject
+	}
+
+	if v := values.Get("sort"); v != "" {
+		for _, field := range strings.Split(v, ",") {
+			field = strings.TrimSpace(field)
+			desc := strings.HasPrefix(field, "-")
+			query.Sort = append(query.Sort, entities.SortField{
+				Field: strings.TrimPrefix(field, "-"),
+				Desc:  desc,
+			})
+		}
+	}
+
+	return query, nil
+}
+
+func parseInt(values url.Values, key string) (int, error) {
+	v := values.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w: %s %q", entities.ErrInvalidParameter, key, v)
+	}
+	return n, nil
+}
+
+// parseDate accepts either a plain date or a full RFC 3339 timestamp
+func parseDate(values url.Values, key string) (*time.Time, error) {
+	v := values.Get(key)
+	if v == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %q", entities.ErrInvalidParameter, key, v)
+}
+
+// pageLinks builds self/next/prev links that keep every other query
+// parameter of the current request. Offset pages (page > 0) link by page
+// number, cursor pages by cursor.
+func pageLinks(u *url.URL, page int, hasNext bool, nextCursor, prevCursor string) entities.PageLinks {
+	links := entities.PageLinks{Self: u.RequestURI()}
+
+	link := func(set func(q url.Values)) string {
+		q := u.Query()
+		q.Del("page")
+		q.Del("cursor")
+		set(q)
+		return u.Path + "?" + q.Encode()
+	}
+
+	if page > 0 {
+		if hasNext {
+			links.Next = link(func(q url.Values) { q.Set("page", strconv.Itoa(page+1)) })
+		}
+		if page > 1 {
+			links.Prev = link(func(q url.Values) { q.Set("page", strconv.Itoa(page-1)) })
+		}
+		return links
+	}
+
+	if nextCursor != "" {
+		links.Next = link(func(q url.Values) { q.Set("cursor", nextCursor) })
+	}
+	if prevCursor != "" {
+		links.Prev = link(func(q url.Values) { q.Set("cursor", prevCursor) })
+	}
+	return links
+}
@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"net/http"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+func (h *handlerV1) ReserveSeat(w http.ResponseWriter, r *http.Request) {
+	var req entities.ReservationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	reservation, err := h.Service.ReserveSeat(r.Context(), &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+func (h *handlerV1) CancelReservation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	reservation, err := h.Service.CancelReservation(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reservation)
+}
+
+func (h *handlerV1) GetReservation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := uuid.FromString(vars["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	reservation, err := h.Service.GetReservation(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reservation)
+}
+
+func (h *handlerV1) ListReservations(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	var query entities.ReservationQuery
+	if v := values.Get("user_id"); v != "" {
+		id, err := uuid.FromString(v)
+		if err != nil {
+			writeError(w, r, entities.ErrInvalidID)
+			return
+		}
+		query.UserID = &id
+	}
+
+	date, err := parseDate(values, "date")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	query.Date = date
+
+	if v := values.Get("status"); v != "" {
+		status := enums.ReversationStatus(v)
+		if status != enums.Booked && status != enums.Cancelled {
+			writeError(w, r, fmt.Errorf("%w: status %q", entities.ErrInvalidParameter, v))
+			return
+		}
+		query.Status = status
+	}
+
+	reservations, err := h.Service.ListReservations(r.Context(), &query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reservations)
+}
+
+// GetSeatAvailability lists free seats per slot for ?date=, today by default
+func (h *handlerV1) GetSeatAvailability(w http.ResponseWriter, r *http.Request) {
+	date, err := parseDate(r.URL.Query(), "date")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if date == nil {
+		now := time.Now()
+		date = &now
+	}
+
+	availability, err := h.Service.GetSeatAvailability(r.Context(), *date)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(availability)
+}
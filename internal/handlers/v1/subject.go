@@ -0,0 +1,129 @@
+package v1
+
+import (
+	"encoding/json"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+// BrowseSubjects returns the subject tree with book counts; kind limits it
+// to topics or genres
+func (h *handlerV1) BrowseSubjects(w http.ResponseWriter, r *http.Request) {
+	kind := enums.SubjectKind(r.URL.Query().Get("kind"))
+	tree, err := h.Service.BrowseSubjects(r.Context(), kind)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tree)
+}
+
+// GetSubject returns a subject with the subjects above and below it
+func (h *handlerV1) GetSubject(w http.ResponseWriter, r *http.Request) {
+	id, ok := subjectID(w, r)
+	if !ok {
+		return
+	}
+
+	tree, err := h.Service.GetSubject(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tree)
+}
+
+func (h *handlerV1) CreateSubject(w http.ResponseWriter, r *http.Request) {
+	var req entities.SubjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	subject, err := h.Service.CreateSubject(r.Context(), &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(subject)
+}
+
+func (h *handlerV1) UpdateSubject(w http.ResponseWriter, r *http.Request) {
+	id, ok := subjectID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.SubjectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	subject, err := h.Service.UpdateSubject(r.Context(), id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(subject)
+}
+
+func (h *handlerV1) DeleteSubject(w http.ResponseWriter, r *http.Request) {
+	id, ok := subjectID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteSubject(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// BrowseClasses returns the outline of the dewey or lcc scheme with the
+// number of books under each class
+func (h *handlerV1) BrowseClasses(w http.ResponseWriter, r *http.Request) {
+	scheme := enums.ClassScheme(mux.Vars(r)["scheme"])
+	classes, err := h.Service.BrowseClasses(r.Context(), scheme)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(classes)
+}
+
+func subjectID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.FromString(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return uuid.Nil, false
+	}
+	return id, true
+}
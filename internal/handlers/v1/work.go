@@ -0,0 +1,272 @@
+package v1
+
+import (
+	"encoding/json"
+	"library-system/internal/entities"
+	"net/http"
+
+	"github.com/gofrs/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ListWorks lists works in title order. The title and author parameters
+// match any part of a work's title and author credit.
+func (h *handlerV1) ListWorks(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+
+	page, err := parseInt(values, "page")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	limit, err := parseInt(values, "limit")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	result, err := h.Service.ListWorks(r.Context(), &entities.WorkQuery{
+		Title:  values.Get("title"),
+		Author: values.Get("author"),
+		Page:   page,
+		Limit:  limit,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	hasNext := int64(result.Page*result.Limit) < result.Total
+	result.Links = pageLinks(r.URL, result.Page, hasNext, "", "")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (h *handlerV1) GetWork(w http.ResponseWriter, r *http.Request) {
+	id, ok := workID(w, r)
+	if !ok {
+		return
+	}
+
+	work, err := h.Service.GetWork(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(work)
+}
+
+func (h *handlerV1) CreateWork(w http.ResponseWriter, r *http.Request) {
+	var req entities.WorkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	work, err := h.Service.CreateWork(r.Context(), &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(work)
+}
+
+func (h *handlerV1) UpdateWork(w http.ResponseWriter, r *http.Request) {
+	id, ok := workID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.WorkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	work, err := h.Service.UpdateWork(r.Context(), id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(work)
+}
+
+func (h *handlerV1) DeleteWork(w http.ResponseWriter, r *http.Request) {
+	id, ok := workID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteWork(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListEditions lists the books of a work, oldest first
+func (h *handlerV1) ListEditions(w http.ResponseWriter, r *http.Request) {
+	id, ok := workID(w, r)
+	if !ok {
+		return
+	}
+
+	books, err := h.Service.ListEditions(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(books)
+}
+
+// GetSeriesPosition returns the series of a book's work with the works
+// before and after it
+func (h *handlerV1) GetSeriesPosition(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.FromString(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return
+	}
+
+	position, err := h.Service.GetSeriesPosition(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(position)
+}
+
+// ListSeries lists the series in name order; name matches any part of the
+// name
+func (h *handlerV1) ListSeries(w http.ResponseWriter, r *http.Request) {
+	series, err := h.Service.ListSeries(r.Context(), r.URL.Query().Get("name"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(series)
+}
+
+// GetSeries returns a series with its works in volume order
+func (h *handlerV1) GetSeries(w http.ResponseWriter, r *http.Request) {
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	series, err := h.Service.GetSeries(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(series)
+}
+
+func (h *handlerV1) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	var req entities.SeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	series, err := h.Service.CreateSeries(r.Context(), &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(series)
+}
+
+func (h *handlerV1) UpdateSeries(w http.ResponseWriter, r *http.Request) {
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.SeriesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, entities.ErrInvalidBody)
+		return
+	}
+
+	if err := h.Validate.Struct(req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	series, err := h.Service.UpdateSeries(r.Context(), id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(series)
+}
+
+func (h *handlerV1) DeleteSeries(w http.ResponseWriter, r *http.Request) {
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Service.DeleteSeries(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func workID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.FromString(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func seriesID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.FromString(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, r, entities.ErrInvalidID)
+		return uuid.Nil, false
+	}
+	return id, true
+}
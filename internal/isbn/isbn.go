@@ -0,0 +1,145 @@
+// Package isbn validates, normalizes and converts ISBN-10 and ISBN-13 numbers.
+package isbn
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	ErrInvalidLength   = errors.New("isbn must have 10 or 13 digits")
+	ErrInvalidChars    = errors.New("isbn contains invalid characters")
+	ErrInvalidChecksum = errors.New("isbn check digit does not match")
+	ErrNoISBN10        = errors.New("isbn-13 with a 979 prefix has no isbn-10 form")
+)
+
+// Normalize removes hyphen and space separators and upper-cases an
+// ISBN-10 "x" check digit. It does not validate.
+func Normalize(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case r == '-' || r == ' ':
+			continue
+		case r == 'x':
+			b.WriteRune('X')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Check validates s in either form, ignoring separators
+func Check(s string) error {
+	n := Normalize(s)
+	switch len(n) {
+	case 10:
+		return check10(n)
+	case 13:
+		return check13(n)
+	default:
+		return ErrInvalidLength
+	}
+}
+
+// Valid reports whether s is a well-formed ISBN-10 or ISBN-13
+func Valid(s string) bool {
+	return Check(s) == nil
+}
+
+// To13 returns the canonical ISBN-13 form of s without separators
+func To13(s string) (string, error) {
+	n := Normalize(s)
+	if err := Check(n); err != nil {
+		return "", err
+	}
+	if len(n) == 13 {
+		return n, nil
+	}
+
+	body := "978" + n[:9]
+	return body + string(checkDigit13(body)), nil
+}
+
+// To10 returns the ISBN-10 form of s. Only 978-prefixed ISBN-13s have one.
+func To10(s string) (string, error) {
+	n := Normalize(s)
+	if err := Check(n); err != nil {
+		return "", err
+	}
+	if len(n) == 10 {
+		return n, nil
+	}
+	if !strings.HasPrefix(n, "978") {
+		return "", ErrNoISBN10
+	}
+
+	body := n[3:12]
+	return body + string(checkDigit10(body)), nil
+}
+
+// ValidateField is a validator.Func for string fields holding an ISBN
+func ValidateField(fl validator.FieldLevel) bool {
+	return Valid(fl.Field().String())
+}
+
+func check10(n string) error {
+	for i := 0; i < 9; i++ {
+		if !isDigit(n[i]) {
+			return ErrInvalidChars
+		}
+	}
+	if !isDigit(n[9]) && n[9] != 'X' {
+		return ErrInvalidChars
+	}
+	if checkDigit10(n[:9]) != n[9] {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+func check13(n string) error {
+	for i := 0; i < 13; i++ {
+		if !isDigit(n[i]) {
+			return ErrInvalidChars
+		}
+	}
+	if checkDigit13(n[:12]) != n[12] {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// checkDigit10 computes the mod 11 check digit of a 9 digit body
+func checkDigit10(body string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+	d := (11 - sum%11) % 11
+	if d == 10 {
+		return 'X'
+	}
+	return byte('0' + d)
+}
+
+// checkDigit13 computes the alternating 1/3 weighted mod 10 check digit of a 12 digit body
+func checkDigit13(body string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		w := 1
+		if i%2 == 1 {
+			w = 3
+		}
+		sum += int(body[i]-'0') * w
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,84 @@
+package isbn
+
+import "testing"
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr error
+	}{
+		{name: "isbn-13", in: "9780061120084"},
+		{name: "hyphenated isbn-13", in: "978-0-06-112008-4"},
+		{name: "spaced isbn-13", in: "978 0 06 112008 4"},
+		{name: "isbn-10", in: "0061120081"},
+		{name: "isbn-10 with x check digit", in: "0-8044-2957-x"},
+		{name: "979 prefix", in: "979-10-90636-07-1"},
+		{name: "bad isbn-13 checksum", in: "9780061120085", wantErr: ErrInvalidChecksum},
+		{name: "bad isbn-10 checksum", in: "0061120082", wantErr: ErrInvalidChecksum},
+		{name: "x inside isbn-10", in: "00611X0081", wantErr: ErrInvalidChars},
+		{name: "x in isbn-13", in: "978006112008X", wantErr: ErrInvalidChars},
+		{name: "too short", in: "12345", wantErr: ErrInvalidLength},
+		{name: "empty", in: "", wantErr: ErrInvalidLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Check(tt.in); err != tt.wantErr {
+				t.Errorf("Check(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTo13(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "978-0-06-112008-4", want: "9780061120084"},
+		{in: "0-06-112008-1", want: "9780061120084"},
+		{in: "080442957X", want: "9780804429573"},
+		{in: "979-10-90636-07-1", want: "9791090636071"},
+		{in: "1234567890", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := To13(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("To13(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("To13(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTo10(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr error
+	}{
+		{in: "9780061120084", want: "0061120081"},
+		{in: "978-0-8044-2957-3", want: "080442957X"},
+		{in: "0061120081", want: "0061120081"},
+		{in: "9791090636071", wantErr: ErrNoISBN10},
+		{in: "9780061120085", wantErr: ErrInvalidChecksum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := To10(tt.in)
+			if err != tt.wantErr {
+				t.Errorf("To10(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("To10(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
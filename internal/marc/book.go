@@ -0,0 +1,321 @@
+package marc
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/isbn"
+)
+
+// mapped is what a record says about a book, field by field
+type mapped struct {
+	ISBN        string
+	Author      string
+	Title       string
+	Publisher   string
+	Year        int
+	Description string
+	Dewey       string
+	LCC         string
+}
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+func mapRecord(r *Record) mapped {
+	var m mapped
+
+	// The first 020 that carries an ISBN; $a may be followed by a
+	// qualifier such as "(pbk.)"
+	for i := range r.Fields {
+		if r.Fields[i].Tag != "020" {
+			continue
+		}
+		if fields := strings.Fields(r.Fields[i].Subfield('a')); len(fields) > 0 {
+			m.ISBN = fields[0]
+			break
+		}
+	}
+
+	if f := r.Field("100"); f != nil {
+		m.Author = trimISBD(f.Subfield('a'))
+	}
+	if f := r.Field("245"); f != nil {
+		m.Title = trimISBD(f.Subfield('a'))
+		if sub := trimISBD(f.Subfield('b')); sub != "" {
+			m.Title += ": " + sub
+		}
+	}
+	if f := publication(r); f != nil {
+		m.Publisher = trimISBD(f.Subfield('b'))
+		if year := yearPattern.FindString(f.Subfield('c')); year != "" {
+			m.Year, _ = strconv.Atoi(year)
+		}
+	}
+	if f := r.Field("520"); f != nil {
+		m.Description = strings.TrimSpace(f.Subfield('a'))
+	}
+
+	// Call numbers are the class number in $a and the item number in $b.
+	// Dewey numbers may carry the prime marks that show where they can
+	// be cut short, as in "813/.54".
+	if f := r.Field("082"); f != nil {
+		class := strings.NewReplacer("/", "", "'", "").Replace(f.Subfield('a'))
+		m.Dewey = strings.Join(strings.Fields(class+" "+f.Subfield('b')), " ")
+	}
+	if f := r.Field("050"); f != nil {
+		m.LCC = strings.Join(strings.Fields(f.Subfield('a')+" "+f.Subfield('b')), " ")
+	}
+
+	return m
+}
+
+// publication is the field naming the publisher: a 264 publication
+// statement (second indicator 1) in current records, 260 in older ones
+func publication(r *Record) *Field {
+	for i := range r.Fields {
+		if r.Fields[i].Tag == "264" && r.Fields[i].Ind2 == '1' {
+			return &r.Fields[i]
+		}
+	}
+	return r.Field("260")
+}
+
+// trimISBD drops the punctuation cataloguers put between elements, such
+// as the " /" ending a title or the "," ending a name. A closing full stop
+// is dropped too, unless it ends an initial.
+func trimISBD(s string) string {
+	s = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), " /:;,="))
+	if strings.HasSuffix(s, ".") {
+		words := strings.Fields(s)
+		if last := words[len(words)-1]; utf8.RuneCountInString(last) > 2 {
+			s = strings.TrimSuffix(s, ".")
+		}
+	}
+	return s
+}
+
+// ToBook decodes a record and maps it onto a book: the ISBN from 020, the
+// author from 100, the title from 245 ($a and $b), the publisher and year
+// from 264 or 260, the description from 520 and the call numbers from 082
+// and 050. The book keeps the raw record. Copies are left at zero, records do not describe holdings.
+func ToBook(raw []byte) (*entities.Book, error) {
+	record, err := Decode(raw)
+	if err != nil {
+		return nil, err
+	}
+	m := mapRecord(record)
+
+	book := &entities.Book{
+		ISBN:        m.ISBN,
+		Author:      m.Author,
+		Title:       m.Title,
+		Publisher:   m.Publisher,
+		Description: m.Description,
+		Dewey:       m.Dewey,
+		LCC:         m.LCC,
+		ItemType:    itemType(record.Leader),
+		MARC:        raw,
+	}
+	if m.Year > 0 {
+		book.PublishDate = time.Date(m.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return book, nil
+}
+
+// itemType reads the type of record (leader 06) and bibliographic level
+// (leader 07)
+func itemType(leader string) enums.ItemType {
+	switch {
+	case leader[7] == 's':
+		return enums.ItemPeriodical
+	case strings.IndexByte("gijkmo", leader[6]) >= 0:
+		return enums.ItemMedia
+	}
+	return enums.ItemBook
+}
+
+// FromBook builds the record for a book. A book catalogued from a MARC
+// record starts from that record: fields whose mapped value was edited
+// since are rewritten and every other field is kept as it was. Other
+// books get a new record with the mapped fields.
+func FromBook(book *entities.Book) *Record {
+	record := &Record{}
+	if book.MARC != nil {
+		if stored, err := Decode(book.MARC); err == nil {
+			record = stored
+		}
+	}
+	if record.Leader == "" {
+		record.Leader = newLeader(book.ItemType)
+		record.Fields = []Field{
+			{Tag: "001", Value: book.ID.String()},
+			{Tag: "008", Value: fixedData(book)},
+		}
+	}
+	was := mapRecord(record)
+
+	if itemType(record.Leader) != book.ItemType {
+		leader := []byte(record.Leader)
+		copy(leader[6:8], newLeader(book.ItemType)[6:8])
+		record.Leader = string(leader)
+	}
+	record.setControl("005", book.UpdatedAt.UTC().Format("20060102150405")+".0")
+
+	if isbn13, err := isbn.To13(was.ISBN); err != nil || isbn13 != book.ISBN {
+		record.setSubfield("020", ' ', ' ', 'a', book.ISBN)
+	}
+	if book.Author != was.Author {
+		// The other subfields, such as dates, describe the old name
+		record.remove("100")
+		if book.Author != "" {
+			record.insert(Field{Tag: "100", Ind1: '1', Ind2: ' ', Subfields: []Subfield{{'a', book.Author}}})
+		}
+	}
+	if book.Title != was.Title {
+		title := record.setSubfield("245", '0', '0', 'a', book.Title)
+		title.dropSubfield('b')
+	}
+	if f := record.Field("245"); f != nil {
+		// First indicator: the title is an added entry when there is an author
+		f.Ind1 = '0'
+		if record.Field("100") != nil {
+			f.Ind1 = '1'
+		}
+	}
+
+	year := 0
+	if !book.PublishDate.IsZero() {
+		year = book.PublishDate.Year()
+	}
+	if book.Publisher != was.Publisher || year != was.Year {
+		pub := publication(record)
+		if pub == nil {
+			pub = record.insert(Field{Tag: "264", Ind1: ' ', Ind2: '1'})
+		}
+		pub.setSubfield('b', book.Publisher)
+		if year > 0 {
+			pub.setSubfield('c', strconv.Itoa(year))
+		} else {
+			pub.dropSubfield('c')
+		}
+	}
+
+	if book.Description != was.Description {
+		record.remove("520")
+		if book.Description != "" {
+			record.insert(Field{Tag: "520", Ind1: ' ', Ind2: ' ', Subfields: []Subfield{{'a', book.Description}}})
+		}
+	}
+
+	// Call numbers assigned here are marked as not from the Library of
+	// Congress (second indicator 4)
+	if book.Dewey != was.Dewey {
+		record.remove("082")
+		if book.Dewey != "" {
+			record.insert(Field{Tag: "082", Ind1: '0', Ind2: '4', Subfields: []Subfield{{'a', book.Dewey}}})
+		}
+	}
+	if book.LCC != was.LCC {
+		record.remove("050")
+		if book.LCC != "" {
+			record.insert(Field{Tag: "050", Ind1: ' ', Ind2: '4', Subfields: []Subfield{{'a', book.LCC}}})
+		}
+	}
+
+	return record
+}
+
+// newLeader is the leader of a new record: status new, the type and level
+// of the item, Unicode and RDA description
+func newLeader(t enums.ItemType) string {
+	recordType, level := "a", "m"
+	switch t {
+	case enums.ItemPeriodical:
+		level = "s"
+	case enums.ItemMedia:
+		recordType = "g"
+	}
+	return "00000n" + recordType + level + " a2200000 i 4500"
+}
+
+// fixedData is the 008 field: date entered, a single known date, and
+// unknown place and language
+func fixedData(book *entities.Book) string {
+	year := "    "
+	if !book.PublishDate.IsZero() {
+		year = book.PublishDate.Format("2006")
+	}
+	return book.CreatedAt.UTC().Format("060102") + "s" + year + "    xx                  und d"
+}
+
+func (r *Record) setControl(tag, value string) {
+	if f := r.Field(tag); f != nil {
+		f.Value = value
+		return
+	}
+	r.insert(Field{Tag: tag, Value: value})
+}
+
+// setSubfield sets a subfield of the first field with the tag, adding the
+// field with the given indicators if there is none
+func (r *Record) setSubfield(tag string, ind1, ind2, code byte, value string) *Field {
+	f := r.Field(tag)
+	if f == nil {
+		f = r.insert(Field{Tag: tag, Ind1: ind1, Ind2: ind2})
+	}
+	f.setSubfield(code, value)
+	return f
+}
+
+// insert adds a field in tag order and returns it
+func (r *Record) insert(f Field) *Field {
+	i := 0
+	for i < len(r.Fields) && r.Fields[i].Tag <= f.Tag {
+		i++
+	}
+	r.Fields = append(r.Fields, Field{})
+	copy(r.Fields[i+1:], r.Fields[i:])
+	r.Fields[i] = f
+	return &r.Fields[i]
+}
+
+func (r *Record) remove(tag string) {
+	kept := r.Fields[:0]
+	for _, f := range r.Fields {
+		if f.Tag != tag {
+			kept = append(kept, f)
+		}
+	}
+	r.Fields = kept
+}
+
+// setSubfield sets the first subfield with the code, or removes it when
+// the value is empty
+func (f *Field) setSubfield(code byte, value string) {
+	if value == "" {
+		f.dropSubfield(code)
+		return
+	}
+	for i := range f.Subfields {
+		if f.Subfields[i].Code == code {
+			f.Subfields[i].Value = value
+			return
+		}
+	}
+	f.Subfields = append(f.Subfields, Subfield{Code: code, Value: value})
+}
+
+func (f *Field) dropSubfield(code byte) {
+	kept := f.Subfields[:0]
+	for _, sf := range f.Subfields {
+		if sf.Code != code {
+			kept = append(kept, sf)
+		}
+	}
+	f.Subfields = kept
+}
@@ -0,0 +1,276 @@
+// Package marc reads and writes MARC 21 bibliographic records in the ISO
+// 2709 exchange format, and maps them onto books.
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	fieldTerminator  = 0x1E
+	recordTerminator = 0x1D
+	subfieldDelim    = 0x1F
+
+	leaderLen   = 24
+	dirEntryLen = 12
+)
+
+var ErrMalformed = errors.New("malformed MARC record")
+
+// Record is a MARC record: a leader followed by control and data fields,
+// in the order they appear
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+// Field is a control field (tags 001 to 009), which has only a Value, or a
+// data field with two indicators and subfields
+type Field struct {
+	Tag       string
+	Value     string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// IsControl reports whether the field is a control field
+func (f *Field) IsControl() bool {
+	return f.Tag < "010"
+}
+
+// Subfield returns the value of the first subfield with the code
+func (f *Field) Subfield(code byte) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
+
+// Field returns the first field with the tag, or nil
+func (r *Record) Field(tag string) *Field {
+	for i := range r.Fields {
+		if r.Fields[i].Tag == tag {
+			return &r.Fields[i]
+		}
+	}
+	return nil
+}
+
+// Decode parses one ISO 2709 record, including its record terminator.
+// Records are expected in UTF-8; MARC-8 records (leader position 09 blank)
+// are accepted only when they are plain ASCII.
+func Decode(data []byte) (*Record, error) {
+	if len(data) < leaderLen+1 || data[len(data)-1] != recordTerminator {
+		return nil, fmt.Errorf("%w: record is truncated", ErrMalformed)
+	}
+
+	leader := string(data[:leaderLen])
+	length, ok := number(leader[0:5])
+	if !ok || length != len(data) {
+		return nil, fmt.Errorf("%w: leader gives length %q for %d bytes", ErrMalformed, leader[0:5], len(data))
+	}
+	base, ok := number(leader[12:17])
+	if !ok || base <= leaderLen || base > len(data) || data[base-1] != fieldTerminator {
+		return nil, fmt.Errorf("%w: invalid base address %q", ErrMalformed, leader[12:17])
+	}
+	if leader[9] != 'a' && !isASCII(data) {
+		return nil, fmt.Errorf("%w: MARC-8 encoded records are not supported", ErrMalformed)
+	}
+	if !utf8.Valid(data) {
+		return nil, fmt.Errorf("%w: record is not valid UTF-8", ErrMalformed)
+	}
+
+	directory := data[leaderLen : base-1]
+	if len(directory)%dirEntryLen != 0 {
+		return nil, fmt.Errorf("%w: directory length %d", ErrMalformed, len(directory))
+	}
+
+	record := &Record{Leader: leader}
+	for i := 0; i < len(directory); i += dirEntryLen {
+		entry := directory[i : i+dirEntryLen]
+		tag := string(entry[0:3])
+		flen, ok1 := number(string(entry[3:7]))
+		start, ok2 := number(string(entry[7:12]))
+		if !ok1 || !ok2 || flen < 1 || start < 0 || base+start < leaderLen || base+start+flen > len(data)-1 {
+			return nil, fmt.Errorf("%w: directory entry %q", ErrMalformed, entry)
+		}
+		if data[base+start+flen-1] != fieldTerminator {
+			return nil, fmt.Errorf("%w: field %s does not end where its directory entry says", ErrMalformed, tag)
+		}
+
+		// The field's data without its terminator
+		raw := data[base+start : base+start+flen-1]
+		field, err := decodeField(tag, raw)
+		if err != nil {
+			return nil, err
+		}
+		record.Fields = append(record.Fields, field)
+	}
+
+	return record, nil
+}
+
+// number reads a fixed-width numeric field of the leader or directory,
+// which holds ASCII digits only: no sign, space or other digit
+func number(s string) (int, bool) {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		n = n*10 + int(s[i]-'0')
+	}
+	return n, len(s) > 0
+}
+
+func decodeField(tag string, raw []byte) (Field, error) {
+	field := Field{Tag: tag}
+	if field.IsControl() {
+		field.Value = string(raw)
+		return field, nil
+	}
+
+	if len(raw) < 2 {
+		return field, fmt.Errorf("%w: field %s has no indicators", ErrMalformed, tag)
+	}
+	field.Ind1, field.Ind2 = raw[0], raw[1]
+
+	for _, part := range bytes.Split(raw[2:], []byte{subfieldDelim})[1:] {
+		if len(part) == 0 {
+			continue
+		}
+		field.Subfields = append(field.Subfields, Subfield{Code: part[0], Value: string(part[1:])})
+	}
+	return field, nil
+}
+
+// Encode writes the record in ISO 2709, in UTF-8. The record length, base
+// address and encoding positions of the leader are filled in.
+func (r *Record) Encode() ([]byte, error) {
+	leader := []byte(r.Leader)
+	if len(leader) != leaderLen {
+		return nil, fmt.Errorf("%w: leader is %d bytes", ErrMalformed, len(leader))
+	}
+
+	var directory, body bytes.Buffer
+	for i := range r.Fields {
+		f := &r.Fields[i]
+		if len(f.Tag) != 3 {
+			return nil, fmt.Errorf("%w: tag %q", ErrMalformed, f.Tag)
+		}
+
+		start := body.Len()
+		if f.IsControl() {
+			body.WriteString(f.Value)
+		} else {
+			body.WriteByte(indicator(f.Ind1))
+			body.WriteByte(indicator(f.Ind2))
+			for _, sf := range f.Subfields {
+				body.WriteByte(subfieldDelim)
+				body.WriteByte(sf.Code)
+				body.WriteString(sf.Value)
+			}
+		}
+		body.WriteByte(fieldTerminator)
+
+		flen := body.Len() - start
+		if flen > 9999 || start > 99999 {
+			return nil, fmt.Errorf("%w: field %s is too long", ErrMalformed, f.Tag)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", f.Tag, flen, start)
+	}
+	directory.WriteByte(fieldTerminator)
+
+	base := leaderLen + directory.Len()
+	length := base + body.Len() + 1
+	if length > 99999 {
+		return nil, fmt.Errorf("%w: record is longer than 99999 bytes", ErrMalformed)
+	}
+
+	copy(leader[0:5], fmt.Sprintf("%05d", length))
+	leader[9] = 'a'
+	copy(leader[10:12], "22")
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+	copy(leader[20:24], "4500")
+
+	out := make([]byte, 0, length)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, body.Bytes()...)
+	return append(out, recordTerminator), nil
+}
+
+func indicator(b byte) byte {
+	if b == 0 {
+		return ' '
+	}
+	return b
+}
+
+func isASCII(data []byte) bool {
+	for _, b := range data {
+		if b >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Reader splits an ISO 2709 file into records
+type Reader struct {
+	r *bufio.Reader
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read returns the raw bytes of the next record, up to and including its
+// terminator, or io.EOF after the last one. Line breaks some tools put
+// between records are skipped.
+func (r *Reader) Read() ([]byte, error) {
+	for {
+		data, err := r.r.ReadBytes(recordTerminator)
+		data = bytes.TrimLeft(data, "\r\n")
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(bytes.TrimSpace(data)) > 0 {
+				return nil, fmt.Errorf("%w: record is truncated", ErrMalformed)
+			}
+			return nil, err
+		}
+		if len(data) > 1 {
+			return data, nil
+		}
+	}
+}
+
+// Writer writes records one after another
+type Writer struct {
+	w io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (w *Writer) Write(record *Record) error {
+	data, err := record.Encode()
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(data)
+	return err
+}
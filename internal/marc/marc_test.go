@@ -0,0 +1,276 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+)
+
+// vendorRecord is a record as a supplier would send it, with ISBD
+// punctuation, a 260 publication field, call numbers and a subject
+// heading the catalogue does not map
+func vendorRecord(t *testing.T) []byte {
+	t.Helper()
+
+	record := &Record{
+		Leader: "00000cam  2200000 a 4500",
+		Fields: []Field{
+			{Tag: "001", Value: "ocm12345"},
+			{Tag: "020", Ind1: ' ', Ind2: ' ', Subfields: []Subfield{{'a', "0441013597 (pbk.)"}}},
+			{Tag: "050", Ind1: '0', Ind2: '0', Subfields: []Subfield{{'a', "PS3558.E63"}, {'b', "D8 1965"}}},
+			{Tag: "082", Ind1: '0', Ind2: '4', Subfields: []Subfield{{'a', "813/.54"}}},
+			{Tag: "100", Ind1: '1', Ind2: ' ', Subfields: []Subfield{{'a', "Herbert, Frank,"}, {'d', "1920-1986."}}},
+			{Tag: "245", Ind1: '1', Ind2: '0', Subfields: []Subfield{{'a', "Dune :"}, {'b', "a novel /"}, {'c', "Frank Herbert."}}},
+			{Tag: "260", Ind1: ' ', Ind2: ' ', Subfields: []Subfield{{'a', "New York :"}, {'b', "Ace Books,"}, {'c', "c1965."}}},
+			{Tag: "520", Ind1: ' ', Ind2: ' ', Subfields: []Subfield{{'a', "Paul Atreides comes of age on Arrakis."}}},
+			{Tag: "650", Ind1: ' ', Ind2: '0', Subfields: []Subfield{{'a', "Science fiction."}}},
+		},
+	}
+	raw, err := record.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	return raw
+}
+
+func TestDecode_roundTrip(t *testing.T) {
+	raw := vendorRecord(t)
+
+	record, err := Decode(raw)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(record.Fields) != 9 || record.Field("001").Value != "ocm12345" {
+		t.Fatalf("Decode() fields = %+v", record.Fields)
+	}
+	if f := record.Field("245"); f.Ind1 != '1' || f.Subfield('c') != "Frank Herbert." {
+		t.Errorf("245 = %+v", f)
+	}
+
+	again, err := record.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if !bytes.Equal(again, raw) {
+		t.Errorf("Encode(Decode(raw)) differs from raw:\n%q\n%q", again, raw)
+	}
+}
+
+func TestDecode_malformed(t *testing.T) {
+	raw := vendorRecord(t)
+
+	ascii := &Record{Leader: "00000nam  2200000 a 4500", Fields: []Field{
+		{Tag: "245", Ind1: '0', Ind2: '0', Subfields: []Subfield{{'a', "Caf\xe9"}}},
+	}}
+	marc8, _ := ascii.Encode()
+	// Encode marks records as UTF-8; mark this one MARC-8 again
+	marc8[9] = ' '
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "truncated", data: raw[:len(raw)-10]},
+		{name: "wrong length", data: append([]byte("99999"), raw[5:]...)},
+		{name: "MARC-8", data: marc8},
+		{name: "negative offset", data: withDirectoryStart(raw, "-0100")},
+		{name: "signed length", data: append([]byte("+"+string(raw[1:5])), raw[5:]...)},
+		{name: "short field length", data: withDirectoryLength(raw, -1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(tt.data); !errors.Is(err, ErrMalformed) {
+				t.Errorf("Decode() error = %v, want %v", err, ErrMalformed)
+			}
+		})
+	}
+}
+
+// withDirectoryStart replaces the starting position of the record's first
+// directory entry
+func withDirectoryStart(raw []byte, start string) []byte {
+	data := bytes.Clone(raw)
+	copy(data[leaderLen+7:leaderLen+12], start)
+	return data
+}
+
+// withDirectoryLength changes the length of the record's first directory
+// entry by delta, so the field no longer ends at its terminator
+func withDirectoryLength(raw []byte, delta int) []byte {
+	data := bytes.Clone(raw)
+	n, _ := number(string(data[leaderLen+3 : leaderLen+7]))
+	copy(data[leaderLen+3:leaderLen+7], fmt.Sprintf("%04d", n+delta))
+	return data
+}
+
+func TestReader(t *testing.T) {
+	raw := vendorRecord(t)
+	file := string(raw) + "\n" + string(raw) + "\n"
+
+	r := NewReader(strings.NewReader(file))
+	for i := 0; i < 2; i++ {
+		got, err := r.Read()
+		if err != nil {
+			t.Fatalf("Read() %d error = %v", i, err)
+		}
+		if !bytes.Equal(got, raw) {
+			t.Errorf("Read() %d = %q", i, got)
+		}
+	}
+	if _, err := r.Read(); !errors.Is(err, io.EOF) {
+		t.Errorf("Read() error = %v, want io.EOF", err)
+	}
+
+	r = NewReader(bytes.NewReader(raw[:50]))
+	if _, err := r.Read(); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Read() of a truncated file error = %v, want %v", err, ErrMalformed)
+	}
+}
+
+func TestToBook(t *testing.T) {
+	raw := vendorRecord(t)
+
+	book, err := ToBook(raw)
+	if err != nil {
+		t.Fatalf("ToBook() error = %v", err)
+	}
+
+	want := entities.Book{
+		ISBN:        "0441013597",
+		Author:      "Herbert, Frank",
+		Title:       "Dune: a novel",
+		Publisher:   "Ace Books",
+		PublishDate: time.Date(1965, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Description: "Paul Atreides comes of age on Arrakis.",
+		ItemType:    enums.ItemBook,
+		Dewey:       "813.54",
+		LCC:         "PS3558.E63 D8 1965",
+	}
+	if book.ISBN != want.ISBN || book.Author != want.Author || book.Title != want.Title ||
+		book.Publisher != want.Publisher || !book.PublishDate.Equal(want.PublishDate) ||
+		book.Description != want.Description || book.ItemType != want.ItemType ||
+		book.Dewey != want.Dewey || book.LCC != want.LCC {
+		t.Errorf("ToBook() = %+v\nwant %+v", book, want)
+	}
+	if !bytes.Equal(book.MARC, raw) {
+		t.Error("ToBook() did not keep the raw record")
+	}
+}
+
+func TestFromBook(t *testing.T) {
+	raw := vendorRecord(t)
+	stored, err := ToBook(raw)
+	if err != nil {
+		t.Fatalf("ToBook() error = %v", err)
+	}
+	stored.ISBN = "9780441013593"
+	stored.UpdatedAt = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("unchanged", func(t *testing.T) {
+		record := FromBook(stored)
+		original, _ := Decode(raw)
+
+		// Only the transaction date is new
+		if f := record.Field("005"); f == nil || f.Value != "20240301120000.0" {
+			t.Errorf("005 = %+v", f)
+		}
+		for _, tag := range []string{"020", "050", "082", "100", "245", "260", "520", "650"} {
+			if got, want := record.Field(tag), original.Field(tag); !equalField(got, want) {
+				t.Errorf("%s = %+v, want it kept as %+v", tag, got, want)
+			}
+		}
+	})
+
+	t.Run("edited", func(t *testing.T) {
+		edited := *stored
+		edited.Title = "Dune"
+		edited.Publisher = "Chilton Books"
+		edited.Description = ""
+		edited.Dewey = "813.54 H536d"
+
+		record := FromBook(&edited)
+
+		if f := record.Field("082"); f.Subfield('a') != "813.54 H536d" || f.Ind2 != '4' {
+			t.Errorf("082 = %+v", f)
+		}
+		if f := record.Field("050"); f.Subfield('b') != "D8 1965" {
+			t.Errorf("050 = %+v, want the unedited call number kept", f)
+		}
+
+		if f := record.Field("245"); f.Subfield('a') != "Dune" || f.Subfield('b') != "" || f.Subfield('c') != "Frank Herbert." {
+			t.Errorf("245 = %+v", f)
+		}
+		if f := record.Field("260"); f.Subfield('b') != "Chilton Books" || f.Subfield('a') != "New York :" || f.Subfield('c') != "1965" {
+			t.Errorf("260 = %+v", f)
+		}
+		if record.Field("520") != nil {
+			t.Error("520 kept after the description was cleared")
+		}
+		if f := record.Field("100"); f.Subfield('d') != "1920-1986." {
+			t.Errorf("100 = %+v, want the unedited author kept", f)
+		}
+		if record.Field("650") == nil {
+			t.Error("650 dropped")
+		}
+
+		// What is written maps back to the edited book
+		data, err := record.Encode()
+		if err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		back, err := ToBook(data)
+		if err != nil {
+			t.Fatalf("ToBook() error = %v", err)
+		}
+		if back.Title != edited.Title || back.Publisher != edited.Publisher || back.Description != "" || back.Dewey != edited.Dewey {
+			t.Errorf("round trip = %+v", back)
+		}
+	})
+
+	t.Run("new record", func(t *testing.T) {
+		record := FromBook(&entities.Book{
+			Title:       "Analog",
+			ISBN:        "9771059211002",
+			PublishDate: time.Date(1930, time.January, 1, 0, 0, 0, 0, time.UTC),
+			ItemType:    enums.ItemPeriodical,
+		})
+
+		if record.Leader[7] != 's' {
+			t.Errorf("leader = %q, want serial level", record.Leader)
+		}
+		if record.Field("100") != nil {
+			t.Error("100 written for a book without an author")
+		}
+		if f := record.Field("245"); f.Ind1 != '0' || f.Subfield('a') != "Analog" {
+			t.Errorf("245 = %+v", f)
+		}
+		if f := record.Field("264"); f == nil || f.Subfield('c') != "1930" {
+			t.Errorf("264 = %+v", f)
+		}
+		if _, err := record.Encode(); err != nil {
+			t.Errorf("Encode() error = %v", err)
+		}
+	})
+}
+
+func equalField(a, b *Field) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Tag != b.Tag || a.Value != b.Value || a.Ind1 != b.Ind1 || a.Ind2 != b.Ind2 || len(a.Subfields) != len(b.Subfields) {
+		return false
+	}
+	for i := range a.Subfields {
+		if a.Subfields[i] != b.Subfields[i] {
+			return false
+		}
+	}
+	return true
+}
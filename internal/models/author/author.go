@@ -0,0 +1,313 @@
+package author
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/names"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type Author interface {
+	Create(ctx context.Context, author *entities.Author) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Author, error)
+	List(ctx context.Context, query *entities.AuthorQuery) ([]entities.Author, int64, error)
+	Update(ctx context.Context, author *entities.Author) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Resolve(ctx context.Context, credited []string) ([]entities.Author, error)
+	Books(ctx context.Context, id uuid.UUID, role enums.ContributorRole) ([]entities.Book, error)
+	ForBook(ctx context.Context, bookID uuid.UUID) ([]entities.BookContributor, error)
+	SetContributors(ctx context.Context, bookID uuid.UUID, contributors []entities.BookContributor) error
+}
+
+type author struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Author {
+	return &author{db: db}
+}
+
+// NewAuthor returns an author for a name as credited on a book, filed
+// under its sort name
+func NewAuthor(credited string, now time.Time) entities.Author {
+	id, _ := uuid.NewV4()
+	return entities.Author{
+		ID:        id,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Name:      names.Display(credited),
+		SortName:  names.SortName(credited),
+		NameKey:   names.Key(credited),
+	}
+}
+
+func (a *author) Create(ctx context.Context, author *entities.Author) error {
+	author.ID, _ = uuid.NewV4()
+	author.CreatedAt = time.Now()
+	author.UpdatedAt = time.Now()
+	for i := range author.Aliases {
+		author.Aliases[i].AuthorID = author.ID
+	}
+
+	return a.db.Create(author).Error
+}
+
+func (a *author) GetByID(ctx context.Context, id uuid.UUID) (*entities.Author, error) {
+	var author entities.Author
+	result := a.db.Preload("Aliases").Where("id = ?", id).First(&author)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrAuthorNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &author, nil
+}
+
+// List returns one page of authors in sort name order, with the total
+// across all pages
+func (a *author) List(ctx context.Context, query *entities.AuthorQuery) ([]entities.Author, int64, error) {
+	matching := func() *gorm.DB {
+		db := a.db.Model(&entities.Author{})
+		if query.Name != "" {
+			pattern := "%" + query.Name + "%"
+			db = db.Where("name ILIKE ? OR id IN (SELECT author_id FROM author_aliases WHERE name ILIKE ?)", pattern, pattern)
+		}
+		return db
+	}
+
+	var total int64
+	if err := matching().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var authors []entities.Author
+	err := matching().Preload("Aliases").
+		Order("sort_name, id").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Find(&authors).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return authors, total, nil
+}
+
+// Update saves the author's details and replaces their aliases
+func (a *author) Update(ctx context.Context, author *entities.Author) error {
+	author.UpdatedAt = time.Now()
+
+	return a.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(author).
+			Select("name", "sort_name", "name_key", "birth_date", "death_date", "bio", "updated_at").
+			Updates(author)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrAuthorNotFound
+		}
+
+		if err := tx.Where("author_id = ?", author.ID).Delete(&entities.AuthorAlias{}).Error; err != nil {
+			return err
+		}
+		if len(author.Aliases) == 0 {
+			return nil
+		}
+		for i := range author.Aliases {
+			author.Aliases[i].AuthorID = author.ID
+		}
+		return tx.Create(&author.Aliases).Error
+	})
+}
+
+// Delete removes an author who is not credited on any book
+func (a *author) Delete(ctx context.Context, id uuid.UUID) error {
+	result := a.db.Where("id = ?", id).Delete(&entities.Author{})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return entities.ErrAuthorHasBooks
+		}
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return entities.ErrAuthorNotFound
+	}
+
+	return nil
+}
+
+// Resolve finds the author behind each credited name, by name or alias,
+// and creates the ones not found. When several authors share a name the
+// first catalogued is used. The authors are returned in the order
+// credited, without repeats.
+func (a *author) Resolve(ctx context.Context, credited []string) ([]entities.Author, error) {
+	var keys []string
+	for _, name := range credited {
+		if key := names.Key(name); key != "" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	var authors []entities.Author
+	err := a.db.Transaction(func(tx *gorm.DB) error {
+		var found []struct {
+			entities.Author `gorm:"embedded"`
+			MatchKey        string
+		}
+		err := tx.Raw(`SELECT DISTINCT ON (match_key) a.*, m.match_key FROM authors a
+			JOIN (
+				SELECT id AS author_id, name_key AS match_key FROM authors WHERE name_key IN ?
+				UNION ALL
+				SELECT author_id, name_key FROM author_aliases WHERE name_key IN ?
+			) m ON m.author_id = a.id
+			ORDER BY match_key, a.created_at, a.id`, keys, keys).
+			Scan(&found).Error
+		if err != nil {
+			return err
+		}
+
+		byKey := make(map[string]entities.Author, len(found))
+		for _, f := range found {
+			byKey[f.MatchKey] = f.Author
+		}
+
+		var created []entities.Author
+		now := time.Now()
+		seen := make(map[uuid.UUID]bool)
+		for _, name := range credited {
+			key := names.Key(name)
+			if key == "" {
+				continue
+			}
+			author, ok := byKey[key]
+			if !ok {
+				author = NewAuthor(name, now)
+				byKey[key] = author
+				created = append(created, author)
+			}
+			if !seen[author.ID] {
+				seen[author.ID] = true
+				authors = append(authors, author)
+			}
+		}
+
+		if len(created) == 0 {
+			return nil
+		}
+		return tx.Create(&created).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return authors, nil
+}
+
+// Books lists the books that credit the author, in any role or in the one
+// given, most recently published first. Books in the trash are left out.
+func (a *author) Books(ctx context.Context, id uuid.UUID, role enums.ContributorRole) ([]entities.Book, error) {
+	credits := a.db.Model(&entities.BookContributor{}).Select("book_id").Where("author_id = ?", id)
+	if role != "" {
+		credits = credits.Where("role = ?", role)
+	}
+
+	var books []entities.Book
+	err := a.db.Where("id IN (?)", credits).Order("publish_date DESC, title").Find(&books).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// ForBook returns a book's contributors with their authors, in the order
+// credited
+func (a *author) ForBook(ctx context.Context, bookID uuid.UUID) ([]entities.BookContributor, error) {
+	var contributors []entities.BookContributor
+	err := a.db.Preload("Author").
+		Where("book_id = ?", bookID).
+		Order("position, role").
+		Find(&contributors).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return contributors, nil
+}
+
+// SetContributors replaces the contributors of a book
+func (a *author) SetContributors(ctx context.Context, bookID uuid.UUID, contributors []entities.BookContributor) error {
+	return a.db.Transaction(func(tx *gorm.DB) error {
+		return ReplaceContributors(tx, bookID, contributors)
+	})
+}
+
+// RecreditAuthors replaces the authors of a book with the ones given,
+// within tx. Editors, translators and illustrators are kept after them,
+// and the links are left alone when the authors are credited already.
+func RecreditAuthors(tx *gorm.DB, bookID uuid.UUID, authors []entities.BookContributor) error {
+	var current []entities.BookContributor
+	err := tx.Where("book_id = ?", bookID).
+		Order("position, role").
+		Find(&current).Error
+	if err != nil {
+		return err
+	}
+
+	contributors := slices.Clone(authors)
+	unchanged := true
+	n := 0
+	for _, c := range current {
+		if c.Role != enums.ContributorAuthor {
+			if !slices.ContainsFunc(contributors, func(o entities.BookContributor) bool {
+				return o.AuthorID == c.AuthorID && o.Role == c.Role
+			}) {
+				contributors = append(contributors, entities.BookContributor{AuthorID: c.AuthorID, Role: c.Role, Position: len(contributors)})
+			}
+			continue
+		}
+		if n >= len(authors) || authors[n].AuthorID != c.AuthorID {
+			unchanged = false
+		}
+		n++
+	}
+	if unchanged && n == len(authors) {
+		return nil
+	}
+
+	return ReplaceContributors(tx, bookID, contributors)
+}
+
+// ReplaceContributors replaces the contributors of a book within tx
+func ReplaceContributors(tx *gorm.DB, bookID uuid.UUID, contributors []entities.BookContributor) error {
+	if err := tx.Where("book_id = ?", bookID).Delete(&entities.BookContributor{}).Error; err != nil {
+		return err
+	}
+	if len(contributors) == 0 {
+		return nil
+	}
+
+	for i := range contributors {
+		contributors[i].BookID = bookID
+	}
+	result := tx.Omit("Author").Create(&contributors)
+	if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+		return entities.ErrAuthorNotFound
+	}
+	return result.Error
+}
@@ -0,0 +1,187 @@
+package author
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func NewMock() (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening gorm stub database connection", err)
+	}
+	return gormDB, db, mock
+}
+
+func TestNewAuthor(t *testing.T) {
+	a := NewAuthor("Tolkien, J. R. R.", time.Now())
+	if a.Name != "J. R. R. Tolkien" || a.SortName != "Tolkien, J. R. R." || a.NameKey != "jrrtolkien" {
+		t.Errorf("NewAuthor() = %+v", a)
+	}
+}
+
+func Test_author_Delete(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	del := regexp.QuoteMeta(`DELETE FROM "authors" WHERE id = $1`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(id).WillReturnError(&pgconn.PgError{Code: "23503"})
+	mock.ExpectRollback()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "uncredited"},
+		{name: "credited on books", wantErr: entities.ErrAuthorHasBooks},
+		{name: "unknown author", wantErr: entities.ErrAuthorNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &author{db: gdb}
+			if err := a.Delete(context.Background(), id); err != tt.wantErr {
+				t.Errorf("author.Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_author_Resolve(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	herbert, _ := uuid.NewV4()
+
+	// Herbert is found by an alias; Gaiman is new
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT ON (match_key) a.*, m.match_key FROM authors a`)).
+		WithArgs("frankherbert", "neilgaiman", "frankherbert", "neilgaiman").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "sort_name", "name_key", "match_key"}).
+			AddRow(herbert, "Frank Herbert", "Herbert, Frank", "frankpatrickherbert", "frankherbert"))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "authors" ("id","created_at","updated_at","name","sort_name","name_key","birth_date","death_date","bio") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "Neil Gaiman", "Gaiman, Neil", "neilgaiman", nil, nil, "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	a := &author{db: gdb}
+	authors, err := a.Resolve(context.Background(), []string{"Herbert, Frank", "Neil Gaiman", "Frank Herbert"})
+	if err != nil {
+		t.Fatalf("author.Resolve() error = %v", err)
+	}
+	if len(authors) != 2 || authors[0].ID != herbert || authors[1].Name != "Neil Gaiman" || authors[1].ID == uuid.Nil {
+		t.Errorf("author.Resolve() = %+v", authors)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_author_SetContributors(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	bookID, _ := uuid.NewV4()
+	authorID, _ := uuid.NewV4()
+
+	del := regexp.QuoteMeta(`DELETE FROM "book_contributors" WHERE book_id = $1`)
+	insert := regexp.QuoteMeta(`INSERT INTO "book_contributors" ("book_id","author_id","role","position") VALUES ($1,$2,$3,$4)`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(bookID).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(insert).WithArgs(bookID, authorID, enums.ContributorTranslator, 0).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(bookID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(insert).WillReturnError(&pgconn.PgError{Code: "23503"})
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "replaced"},
+		{name: "unknown author", wantErr: entities.ErrAuthorNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &author{db: gdb}
+			err := a.SetContributors(context.Background(), bookID, []entities.BookContributor{
+				{AuthorID: authorID, Role: enums.ContributorTranslator},
+			})
+			if err != tt.wantErr {
+				t.Errorf("author.SetContributors() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_RecreditAuthors(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	bookID, _ := uuid.NewV4()
+	authorID, _ := uuid.NewV4()
+	editorID, _ := uuid.NewV4()
+
+	// Credited already, so the links are left alone
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_contributors" WHERE book_id = $1 ORDER BY position, role`)).
+		WithArgs(bookID).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id", "role", "position"}).
+			AddRow(bookID, authorID, enums.ContributorAuthor, 0).
+			AddRow(bookID, editorID, enums.ContributorEditor, 1))
+	mock.ExpectCommit()
+
+	err := gdb.Transaction(func(tx *gorm.DB) error {
+		return RecreditAuthors(tx, bookID, []entities.BookContributor{{AuthorID: authorID, Role: enums.ContributorAuthor}})
+	})
+	if err != nil {
+		t.Errorf("RecreditAuthors() error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "library-system/internal/entities"
+	enums "library-system/internal/entities/enums"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+// Author is an autogenerated mock type for the Author type
+type Author struct {
+	mock.Mock
+}
+
+// Books provides a mock function with given fields: ctx, id, role
+func (_m *Author) Books(ctx context.Context, id uuid.UUID, role enums.ContributorRole) ([]entities.Book, error) {
+	ret := _m.Called(ctx, id, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Books")
+	}
+
+	var r0 []entities.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, enums.ContributorRole) ([]entities.Book, error)); ok {
+		return rf(ctx, id, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, enums.ContributorRole) []entities.Book); ok {
+		r0 = rf(ctx, id, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, enums.ContributorRole) error); ok {
+		r1 = rf(ctx, id, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, _a1
+func (_m *Author) Create(ctx context.Context, _a1 *entities.Author) error {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Author) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *Author) Delete(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ForBook provides a mock function with given fields: ctx, bookID
+func (_m *Author) ForBook(ctx context.Context, bookID uuid.UUID) ([]entities.BookContributor, error) {
+	ret := _m.Called(ctx, bookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForBook")
+	}
+
+	var r0 []entities.BookContributor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]entities.BookContributor, error)); ok {
+		return rf(ctx, bookID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []entities.BookContributor); ok {
+		r0 = rf(ctx, bookID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.BookContributor)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, bookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Author) GetByID(ctx context.Context, id uuid.UUID) (*entities.Author, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entities.Author
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.Author, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.Author); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Author)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, query
+func (_m *Author) List(ctx context.Context, query *entities.AuthorQuery) ([]entities.Author, int64, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []entities.Author
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.AuthorQuery) ([]entities.Author, int64, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.AuthorQuery) []entities.Author); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Author)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.AuthorQuery) int64); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *entities.AuthorQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Resolve provides a mock function with given fields: ctx, credited
+func (_m *Author) Resolve(ctx context.Context, credited []string) ([]entities.Author, error) {
+	ret := _m.Called(ctx, credited)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resolve")
+	}
+
+	var r0 []entities.Author
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]entities.Author, error)); ok {
+		return rf(ctx, credited)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []entities.Author); ok {
+		r0 = rf(ctx, credited)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Author)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, credited)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetContributors provides a mock function with given fields: ctx, bookID, contributors
+func (_m *Author) SetContributors(ctx context.Context, bookID uuid.UUID, contributors []entities.BookContributor) error {
+	ret := _m.Called(ctx, bookID, contributors)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetContributors")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []entities.BookContributor) error); ok {
+		r0 = rf(ctx, bookID, contributors)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, _a1
+func (_m *Author) Update(ctx context.Context, _a1 *entities.Author) error {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Author) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAuthor creates a new instance of Author. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Author {
+	mock := &Author{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
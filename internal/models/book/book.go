@@ -3,20 +3,42 @@ package book
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models/author"
+	"library-system/internal/models/item"
+	"library-system/internal/models/subject"
 
 	"github.com/gofrs/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Book interface {
 	Create(ctx context.Context, book *entities.Book) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.Book, error)
+	GetByISBN(ctx context.Context, isbn string) (*entities.Book, error)
 	GetAll(ctx context.Context) ([]entities.Book, error)
-	Update(ctx context.Context, book *entities.Book) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, query *entities.BookQuery) ([]entities.Book, *entities.PageInfo, error)
+	Each(ctx context.Context, query *entities.BookQuery, fn func(*entities.Book) error) error
+	Search(ctx context.Context, query *entities.BookSearchQuery) (*entities.BookSearchResult, error)
+	Update(ctx context.Context, change *entities.BookChange) error
+	Upsert(ctx context.Context, books []entities.Book) ([]entities.UpsertResult, error)
+	Delete(ctx context.Context, id uuid.UUID, version int64) error
+	Trash(ctx context.Context) ([]entities.Book, error)
+	Restore(ctx context.Context, id uuid.UUID) (*entities.Book, error)
+	Purge(ctx context.Context, id uuid.UUID) error
+	PurgeTrash(ctx context.Context, deletedBefore time.Time) (int64, error)
+	Classes(ctx context.Context, scheme enums.ClassScheme) ([]entities.ClassCount, error)
+	SetCover(ctx context.Context, id uuid.UUID, key string, version int64) error
+	History(ctx context.Context, id uuid.UUID) ([]entities.BookRevision, error)
+	Revision(ctx context.Context, id uuid.UUID, revision int64) (*entities.BookRevision, error)
+	RevisionAt(ctx context.Context, id uuid.UUID, at time.Time) (*entities.BookRevision, error)
 }
 
 type book struct {
@@ -27,13 +49,23 @@ func New(db *gorm.DB) Book {
 	return &book{db: db}
 }
 
+// Create stores a new book and starts its history. An ISBN already
+// catalogued, even by a book in the trash, fails with ErrDuplicateISBN.
 func (b *book) Create(ctx context.Context, book *entities.Book) error {
 	book.ID, _ = uuid.NewV4()
 	book.CreatedAt = time.Now()
 	book.UpdatedAt = time.Now()
+	book.Version = 1
 
-	result := b.db.Create(book)
-	return result.Error
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(book).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return entities.ErrDuplicateISBN
+			}
+			return err
+		}
+		return record(ctx, tx, book.ID, book.Version, enums.RevisionCreated, nil, trackedFields(book))
+	})
 }
 
 func (b *book) GetByID(ctx context.Context, id uuid.UUID) (*entities.Book, error) {
@@ -42,7 +74,21 @@ func (b *book) GetByID(ctx context.Context, id uuid.UUID) (*entities.Book, error
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, errors.New("book not found")
+			return nil, entities.ErrBookNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &book, nil
+}
+
+func (b *book) GetByISBN(ctx context.Context, isbn string) (*entities.Book, error) {
+	var book entities.Book
+	result := b.db.Where("isbn = ?", isbn).First(&book)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrBookNotFound
 		}
 		return nil, result.Error
 	}
@@ -61,31 +107,401 @@ func (b *book) GetAll(ctx context.Context) ([]entities.Book, error) {
 	return books, nil
 }
 
-func (b *book) Update(ctx context.Context, book *entities.Book) error {
+// Update saves a change to a book in one transaction, with the book
+// locked, if the stored book is still at the version the change is based
+// on. Its columns, contributors, subjects and copies are written together,
+// and the version is bumped once more after them all, so that it moves
+// whenever any part of the book does; the book takes the version it ends
+// at. What changed goes into the book's history. The cover is only changed
+// by SetCover.
+func (b *book) Update(ctx context.Context, change *entities.BookChange) error {
+	book := change.Book
+	for _, column := range change.Columns {
+		if !slices.Contains(catalogueColumns, column) {
+			return fmt.Errorf("column %q cannot be updated", column)
+		}
+	}
+	if len(change.Columns) == 0 && change.Contributors == nil && change.SubjectIDs == nil && change.Copies == nil {
+		return nil
+	}
 	book.UpdatedAt = time.Now()
 
-	result := b.db.Save(book)
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		before, err := lockBook(tx, book.ID)
+		if err != nil {
+			return err
+		}
+		if before.Version != book.Version {
+			return entities.ErrBookModified
+		}
+
+		linked, err := readLinks(tx, book.ID, change)
+		if err != nil {
+			return err
+		}
+		if change.Contributors != nil {
+			if err := author.ReplaceContributors(tx, book.ID, change.Contributors); err != nil {
+				return err
+			}
+		}
+		if change.SubjectIDs != nil {
+			if err := subject.FileBook(tx, book.ID, change.SubjectIDs); err != nil {
+				return err
+			}
+		}
+		// Copies follow the items on the shelf, so adjust those instead
+		if change.Copies != nil {
+			if err := item.AdjustShelf(tx, book.ID, *change.Copies); err != nil {
+				return err
+			}
+		}
+
+		err = tx.Model(book).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "version"}}}).
+			Select(slices.Concat(change.Columns, []string{"updated_at"})).
+			Updates(book).Error
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return entities.ErrDuplicateISBN
+		}
+		if err != nil {
+			return err
+		}
+
+		relinked, err := readLinks(tx, book.ID, change)
+		if err != nil {
+			return err
+		}
+
+		fields := trackedFields(before)
+		after := withColumns(fields, book, change.Columns)
+		maps.Copy(fields, linked)
+		maps.Copy(after, relinked)
+		return record(ctx, tx, book.ID, book.Version, enums.RevisionUpdated, fields, after)
+	})
+}
+
+// catalogueColumns are the columns an update of a book may write
+var catalogueColumns = []string{
+	"title", "author", "isbn", "publisher", "publisher_id", "publish_date", "description",
+	"item_type", "dewey", "lcc", "work_id", "marc",
+}
+
+// lockBook reads a book that is not in the trash and locks it against
+// other changes until the transaction ends
+func lockBook(tx *gorm.DB, id uuid.UUID) (*entities.Book, error) {
+	var book entities.Book
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&book)
+
 	if result.Error != nil {
-		return result.Error
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrBookNotFound
+		}
+		return nil, result.Error
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.New("book not found")
+	return &book, nil
+}
+
+// Upsert creates or updates each book by ISBN in one transaction. Each book
+// runs under its own savepoint, so one that fails is reported in its
+// result and the rest still commit. Copies set the number of items on the
+// shelf, as for an update; a book without copies keeps its shelf, or gets
+// one copy when it is new. Contributors are the authors of the credit and
+// replace the stored ones, keeping the other roles; none leaves them
+// alone. A book with a MARC record replaces the stored one. The books get
+// the IDs they were stored under.
+func (b *book) Upsert(ctx context.Context, books []entities.Book) ([]entities.UpsertResult, error) {
+	results := make([]entities.UpsertResult, len(books))
+
+	err := b.db.Transaction(func(tx *gorm.DB) error {
+		for i := range books {
+			err := tx.Transaction(func(tx *gorm.DB) error {
+				outcome, err := upsert(ctx, tx, &books[i])
+				results[i].Outcome = outcome
+				return err
+			})
+			if err != nil {
+				results[i] = entities.UpsertResult{Outcome: enums.ImportRejected, Err: err}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return results, nil
 }
 
-func (b *book) Delete(ctx context.Context, id uuid.UUID) error {
-	result := b.db.Delete(&entities.Book{}, "id = ?", id)
+func upsert(ctx context.Context, tx *gorm.DB, book *entities.Book) (enums.ImportOutcome, error) {
+	now := time.Now()
 
+	var existing entities.Book
+	result := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("isbn = ?", book.ISBN).
+		Limit(1).
+		Find(&existing)
 	if result.Error != nil {
-		return result.Error
+		return "", result.Error
 	}
 
+	outcome := enums.ImportUpdated
 	if result.RowsAffected == 0 {
-		return errors.New("book not found")
+		outcome = enums.ImportCreated
+		if book.Copies == 0 {
+			book.Copies = 1
+		}
+		book.ID, _ = uuid.NewV4()
+		book.CreatedAt = now
+		book.UpdatedAt = now
+		book.Version = 1
+		if err := tx.Create(book).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return "", entities.ErrDuplicateISBN
+			}
+			return "", err
+		}
+		if err := record(ctx, tx, book.ID, book.Version, enums.RevisionCreated, nil, trackedFields(book)); err != nil {
+			return "", err
+		}
+	} else {
+		if existing.DeletedAt.Valid {
+			return "", entities.ErrBookInTrash
+		}
+		book.ID = existing.ID
+		book.CreatedAt = existing.CreatedAt
+		book.UpdatedAt = now
+		updates := map[string]interface{}{
+			"title":        book.Title,
+			"author":       book.Author,
+			"publisher":    book.Publisher,
+			"publisher_id": book.PublisherID,
+			"publish_date": book.PublishDate,
+			"description":  book.Description,
+			"item_type":    book.ItemType,
+			"updated_at":   book.UpdatedAt,
+		}
+		if book.MARC != nil {
+			updates["marc"] = book.MARC
+		}
+		// Call numbers are kept unless the row has them, as most files
+		// leave them out
+		if book.Dewey != "" {
+			updates["dewey"] = book.Dewey
+		}
+		if book.LCC != "" {
+			updates["lcc"] = book.LCC
+		}
+		before := trackedFields(&existing)
+		after := make(map[string]interface{}, len(before))
+		for name, value := range before {
+			after[name] = value
+			if update, ok := updates[name]; ok {
+				after[name] = update
+			}
+		}
+		// The shelf is adjusted first so the revision is recorded at the
+		// version the book ends up at
+		if book.Copies != 0 {
+			if err := item.AdjustShelf(tx, book.ID, book.Copies); err != nil {
+				return "", err
+			}
+		}
+		err := tx.Model(&existing).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "version"}}}).
+			Updates(updates).Error
+		if err != nil {
+			return "", err
+		}
+		book.Version = existing.Version
+		if book.Contributors != nil {
+			if err := author.RecreditAuthors(tx, book.ID, book.Contributors); err != nil {
+				return "", err
+			}
+		}
+		return outcome, record(ctx, tx, book.ID, existing.Version, enums.RevisionUpdated, before, after)
+	}
+
+	return outcome, item.AdjustShelf(tx, book.ID, book.Copies)
+}
+
+// Delete moves a book to the trash. It disappears from every query but
+// keeps its items, loans and holds until it is restored or purged. A
+// non-zero version only deletes the book if it is still at that version.
+func (b *book) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		book, err := lockBook(tx, id)
+		if err != nil {
+			return err
+		}
+		if version != 0 && book.Version != version {
+			return entities.ErrBookModified
+		}
+
+		if err := tx.Delete(book).Error; err != nil {
+			return err
+		}
+
+		fields := trackedFields(book)
+		return record(ctx, tx, id, book.Version+1, enums.RevisionDeleted, fields, fields)
+	})
+}
+
+// Trash returns the deleted books, most recently deleted first
+func (b *book) Trash(ctx context.Context) ([]entities.Book, error) {
+	var books []entities.Book
+	result := b.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&books)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return books, nil
+}
+
+// Restore takes a book out of the trash
+func (b *book) Restore(ctx context.Context, id uuid.UUID) (*entities.Book, error) {
+	var book entities.Book
+
+	err := b.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			First(&book)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return entities.ErrBookNotFound
+			}
+			return result.Error
+		}
+
+		book.DeletedAt = gorm.DeletedAt{}
+		book.UpdatedAt = time.Now()
+		err := tx.Unscoped().Model(&book).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"updated_at": book.UpdatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		book.Version++
+		fields := trackedFields(&book)
+		return record(ctx, tx, id, book.Version, enums.RevisionRestored, fields, fields)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &book, nil
+}
+
+// Purge permanently removes a book from the trash along with its items.
+// Books that were ever lent or held are kept so the history stays intact.
+// The book's revisions are kept, closed by the purge.
+func (b *book) Purge(ctx context.Context, id uuid.UUID) error {
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		var book entities.Book
+		result := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			First(&book)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return entities.ErrBookNotFound
+			}
+			return result.Error
+		}
+
+		if err := tx.Unscoped().Delete(&book).Error; err != nil {
+			if errors.Is(err, gorm.ErrForeignKeyViolated) {
+				return entities.ErrBookHasHistory
+			}
+			return err
+		}
+
+		fields := trackedFields(&book)
+		return record(ctx, tx, id, book.Version+1, enums.RevisionPurged, fields, fields)
+	})
+}
+
+// PurgeTrash permanently removes the books deleted before the cutoff,
+// skipping those with loan or hold history. It returns how many went.
+func (b *book) PurgeTrash(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var books []entities.Book
+
+	err := b.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().
+			Clauses(clause.Returning{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Where("NOT EXISTS (SELECT 1 FROM loans WHERE loans.book_id = books.id)").
+			Where("NOT EXISTS (SELECT 1 FROM holds WHERE holds.book_id = books.id)").
+			Delete(&books).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range books {
+			fields := trackedFields(&books[i])
+			if err := record(ctx, tx, books[i].ID, books[i].Version+1, enums.RevisionPurged, fields, fields); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(len(books)), nil
+}
+
+// classPatterns match the class at the start of each scheme's call numbers
+var classPatterns = map[enums.ClassScheme]struct{ column, pattern string }{
+	enums.SchemeDewey: {column: "dewey", pattern: "^[0-9]{3}"},
+	enums.SchemeLCC:   {column: "lcc", pattern: "^[A-Z]{1,3}"},
+}
+
+// Classes counts the books filed under each class of a scheme: Dewey
+// sections or LC class letters. Books without a call number in the scheme
+// and books in the trash are left out.
+func (b *book) Classes(ctx context.Context, scheme enums.ClassScheme) ([]entities.ClassCount, error) {
+	p, ok := classPatterns[scheme]
+	if !ok {
+		return nil, entities.ErrInvalidBookQuery
+	}
+
+	var counts []entities.ClassCount
+	err := b.db.Model(&entities.Book{}).
+		Select("substring("+p.column+" from ?) AS class, count(*) AS count", p.pattern).
+		Where(p.column+" ~ ?", p.pattern).
+		Group("class").
+		Order("class").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return counts, nil
+}
+
+// SetCover points the book at the storage key of its cover image, or at
+// none for an empty key. A non-zero version only changes the book if it
+// is still at that version.
+func (b *book) SetCover(ctx context.Context, id uuid.UUID, key string, version int64) error {
+	return b.db.Transaction(func(tx *gorm.DB) error {
+		book, err := lockBook(tx, id)
+		if err != nil {
+			return err
+		}
+		if version != 0 && book.Version != version {
+			return entities.ErrBookModified
+		}
+
+		before := trackedFields(book)
+		if err := tx.Model(book).Update("cover", key).Error; err != nil {
+			return err
+		}
+		book.Cover = key
+
+		return record(ctx, tx, id, book.Version+1, enums.RevisionUpdated, before, trackedFields(book))
+	})
 }
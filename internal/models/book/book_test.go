@@ -3,15 +3,22 @@ package book
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"regexp"
 	"testing"
 	"time"
 
+	"library-system/internal/auth"
 	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -23,18 +30,34 @@ func NewMock() (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
 	}
 	gormDB, err := gorm.Open(postgres.New(postgres.Config{
 		Conn: db,
-	}))
+	}), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatalf("an error '%s' was not expected when opening gorm stub database connection", err)
 	}
 	return gormDB, db, mock
 }
 
+// revisionStmt adds a revision to the history of a book
+var revisionStmt = regexp.QuoteMeta(`INSERT INTO "book_revisions" ("id","created_at","book_id","revision","action","actor_id","changes","snapshot") VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`)
+
+// withoutField matches a revision snapshot that leaves a field out
+type withoutField string
+
+func (f withoutField) Match(v driver.Value) bool {
+	var snapshot map[string]json.RawMessage
+	if s, ok := v.(string); !ok || json.Unmarshal([]byte(s), &snapshot) != nil {
+		return false
+	}
+	_, ok := snapshot[string(f)]
+	return !ok && len(snapshot) > 0
+}
+
 func Test_book_Create(t *testing.T) {
 	gdb, db, mock := NewMock()
 	defer db.Close()
 
 	testTime := time.Now()
+	librarian := &entities.Principal{UserID: uuid.Must(uuid.NewV4())}
 
 	validBook := entities.Book{
 		Title:       "Test Book",
@@ -44,12 +67,13 @@ func Test_book_Create(t *testing.T) {
 		PublishDate: testTime,
 		Description: "Test Description",
 		Copies:      5,
+		ItemType:    enums.ItemBook,
 	}
 
 	invalidBook := validBook
 	invalidBook.ISBN = "" // Simulate DB failure
 
-	insertStmt := regexp.QuoteMeta(`INSERT INTO "books" ("id","created_at","updated_at","deleted_at","title","author","isbn","publisher","publish_date","description","copies") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`)
+	insertStmt := regexp.QuoteMeta(`INSERT INTO "books" ("id","created_at","updated_at","deleted_at","title","author","isbn","publisher","publisher_id","publish_date","description","copies","item_type","dewey","lcc","work_id","version","cover","marc") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19)`)
 
 	// Setup valid expectation
 	mock.ExpectBegin()
@@ -63,9 +87,29 @@ func Test_book_Create(t *testing.T) {
 			validBook.Author,
 			validBook.ISBN,
 			validBook.Publisher,
+			validBook.PublisherID,
 			validBook.PublishDate,
 			validBook.Description,
 			validBook.Copies,
+			validBook.ItemType,
+			validBook.Dewey,
+			validBook.LCC,
+			validBook.WorkID,
+			int64(1), // version
+			validBook.Cover,
+			validBook.MARC,
+		).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(revisionStmt).
+		WithArgs(
+			sqlmock.AnyArg(), // id
+			sqlmock.AnyArg(), // created_at
+			sqlmock.AnyArg(), // book_id
+			int64(1),
+			enums.RevisionCreated,
+			librarian.UserID,
+			sqlmock.AnyArg(), // changes
+			sqlmock.AnyArg(), // snapshot
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
@@ -82,13 +126,27 @@ func Test_book_Create(t *testing.T) {
 			invalidBook.Author,
 			invalidBook.ISBN,
 			invalidBook.Publisher,
+			invalidBook.PublisherID,
 			invalidBook.PublishDate,
 			invalidBook.Description,
 			invalidBook.Copies,
+			invalidBook.ItemType,
+			invalidBook.Dewey,
+			invalidBook.LCC,
+			invalidBook.WorkID,
+			int64(1), // version
+			invalidBook.Cover,
+			invalidBook.MARC,
 		).
 		WillReturnError(sql.ErrConnDone)
 	mock.ExpectRollback()
 
+	// The ISBN is already catalogued
+	duplicateBook := validBook
+	mock.ExpectBegin()
+	mock.ExpectExec(insertStmt).WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
 	type args struct {
 		ctx  context.Context
 		book *entities.Book
@@ -97,26 +155,31 @@ func Test_book_Create(t *testing.T) {
 		name    string
 		b       *book
 		args    args
-		wantErr bool
+		wantErr error
 	}{
 		{
-			name:    "valid case",
-			b:       &book{db: gdb},
-			args:    args{ctx: context.Background(), book: &validBook},
-			wantErr: false,
+			name: "valid case",
+			b:    &book{db: gdb},
+			args: args{ctx: auth.WithPrincipal(context.Background(), librarian), book: &validBook},
 		},
 		{
 			name:    "insert error",
 			b:       &book{db: gdb},
 			args:    args{ctx: context.Background(), book: &invalidBook},
-			wantErr: true,
+			wantErr: sql.ErrConnDone,
+		},
+		{
+			name:    "duplicate isbn",
+			b:       &book{db: gdb},
+			args:    args{ctx: context.Background(), book: &duplicateBook},
+			wantErr: entities.ErrDuplicateISBN,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.b.Create(tt.args.ctx, tt.args.book); (err != nil) != tt.wantErr {
-				t.Errorf("book.Create() error = %v, wantErr %v", err, tt.wantErr)
+			if err := tt.b.Create(tt.args.ctx, tt.args.book); !errors.Is(err, tt.wantErr) {
+				t.Errorf("book.Create() error = %v, want %v", err, tt.wantErr)
 			}
 		})
 	}
@@ -202,6 +265,56 @@ func Test_book_GetByID(t *testing.T) {
 	}
 }
 
+func Test_book_GetByISBN(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	validID, _ := uuid.NewV4()
+	testTime := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "title", "isbn"}).
+		AddRow(validID, testTime, testTime, "To Kill a Mockingbird", "9780061120084")
+
+	validStmt := gdb.Session(&gorm.Session{DryRun: true}).Model(&entities.Book{}).Where("isbn = ?", "9780061120084").First(&entities.Book{}).Statement.SQL.String()
+
+	mock.ExpectQuery(regexp.QuoteMeta(validStmt)).WithArgs("9780061120084", 1).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(validStmt)).WithArgs("9780451524935", 1).WillReturnError(gorm.ErrRecordNotFound)
+
+	tests := []struct {
+		name    string
+		b       *book
+		isbn    string
+		wantID  uuid.UUID
+		wantErr error
+	}{
+		{
+			name:   "valid case",
+			b:      &book{db: gdb},
+			isbn:   "9780061120084",
+			wantID: validID,
+		},
+		{
+			name:    "not found error",
+			b:       &book{db: gdb},
+			isbn:    "9780451524935",
+			wantErr: entities.ErrBookNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.b.GetByISBN(context.Background(), tt.isbn)
+			if err != tt.wantErr {
+				t.Errorf("book.GetByISBN() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != nil && got.ID != tt.wantID {
+				t.Errorf("book.GetByISBN() got = %v, want id %v", got, tt.wantID)
+			}
+		})
+	}
+}
+
 func Test_book_GetAll(t *testing.T) {
 	gdb, db, mock := NewMock()
 	defer db.Close()
@@ -309,80 +422,143 @@ func Test_book_Update(t *testing.T) {
 		PublishDate: testTime,
 		Description: "Updated Description",
 		Copies:      10,
+		ItemType:    enums.ItemMedia,
+		Version:     3,
 	}
 
+	staleBook := validBook
+	staleBook.ID = uuid.Must(uuid.NewV4())
+
 	nonExistentBook := validBook
 	nonExistentBook.ID = uuid.Must(uuid.NewV4())
 
-	// Adjusted to include "deleted_at"
-	updateStmt := regexp.QuoteMeta(`UPDATE "books" SET "created_at"=$1,"updated_at"=$2,"deleted_at"=$3,"title"=$4,"author"=$5,"isbn"=$6,"publisher"=$7,"publish_date"=$8,"description"=$9,"copies"=$10 WHERE "id" = $11`)
+	// The stored book is locked to check the version the book was read at
+	// and to tell what changed. Only the catalogue columns are written;
+	// copies follow the book's items.
+	lockStmt := regexp.QuoteMeta(`SELECT * FROM "books" WHERE id = $1 AND "books"."deleted_at" IS NULL ORDER BY "books"."id" LIMIT $2 FOR UPDATE`)
+	updateStmt := regexp.QuoteMeta(`UPDATE "books" SET "updated_at"=$1,"title"=$2,"author"=$3,"isbn"=$4,"publisher"=$5,"publisher_id"=$6,"publish_date"=$7,"description"=$8,"item_type"=$9,"dewey"=$10,"lcc"=$11,"work_id"=$12,"marc"=$13 WHERE "books"."deleted_at" IS NULL AND "id" = $14 RETURNING "version"`)
+	stored := func(id uuid.UUID, version int64) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "title", "version"}).AddRow(id, "Old Book", version)
+	}
+	args := func(book entities.Book) []driver.Value {
+		return []driver.Value{
+			sqlmock.AnyArg(), // updated_at
+			book.Title,
+			book.Author,
+			book.ISBN,
+			book.Publisher,
+			book.PublisherID,
+			book.PublishDate,
+			book.Description,
+			book.ItemType,
+			book.Dewey,
+			book.LCC,
+			book.WorkID,
+			book.MARC,
+			book.ID,
+		}
+	}
 
-	// --- VALID BOOK EXPECTATION ---
 	mock.ExpectBegin()
-	mock.ExpectExec(updateStmt).WithArgs(
-		sqlmock.AnyArg(), // created_at
-		sqlmock.AnyArg(), // updated_at
-		sqlmock.AnyArg(), // deleted_at (zero/null)
-		validBook.Title,
-		validBook.Author,
-		validBook.ISBN,
-		validBook.Publisher,
-		validBook.PublishDate,
-		validBook.Description,
-		validBook.Copies,
-		sqlmock.AnyArg(), // ID
-	).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(lockStmt).WithArgs(validBook.ID, 1).WillReturnRows(stored(validBook.ID, 3))
+	mock.ExpectQuery(updateStmt).WithArgs(args(validBook)...).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+	mock.ExpectExec(revisionStmt).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), validBook.ID, int64(4), enums.RevisionUpdated, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
-	// --- BOOK NOT FOUND EXPECTATION ---
+	// Changed since it was read
 	mock.ExpectBegin()
-	mock.ExpectExec(updateStmt).WithArgs(
-		sqlmock.AnyArg(),
-		sqlmock.AnyArg(),
-		sqlmock.AnyArg(),
-		nonExistentBook.Title,
-		nonExistentBook.Author,
-		nonExistentBook.ISBN,
-		nonExistentBook.Publisher,
-		nonExistentBook.PublishDate,
-		nonExistentBook.Description,
-		nonExistentBook.Copies,
-		sqlmock.AnyArg(),
-	).WillReturnResult(sqlmock.NewResult(1, 0))
-	mock.ExpectCommit()
+	mock.ExpectQuery(lockStmt).WithArgs(staleBook.ID, 1).WillReturnRows(stored(staleBook.ID, 4))
+	mock.ExpectRollback()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockStmt).WithArgs(nonExistentBook.ID, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	// Given the ISBN of another book
+	duplicateBook := validBook
+	duplicateBook.ID = uuid.Must(uuid.NewV4())
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockStmt).WithArgs(duplicateBook.ID, 1).WillReturnRows(stored(duplicateBook.ID, 3))
+	mock.ExpectQuery(updateStmt).WithArgs(args(duplicateBook)...).WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
 
-	// --- TEST CASES ---
-	type args struct {
-		ctx  context.Context
-		book *entities.Book
-	}
 	tests := []struct {
 		name    string
-		b       *book
-		args    args
-		wantErr bool
+		book    *entities.Book
+		wantErr error
 	}{
-		{
-			name:    "valid case",
-			b:       &book{db: gdb},
-			args:    args{ctx: context.Background(), book: &validBook},
-			wantErr: false,
-		},
-		{
-			name:    "book not found",
-			b:       &book{db: gdb},
-			args:    args{ctx: context.Background(), book: &nonExistentBook},
-			wantErr: true,
-		},
+		{name: "valid case", book: &validBook},
+		{name: "changed since read", book: &staleBook, wantErr: entities.ErrBookModified},
+		{name: "book not found", book: &nonExistentBook, wantErr: entities.ErrBookNotFound},
+		{name: "duplicate isbn", book: &duplicateBook, wantErr: entities.ErrDuplicateISBN},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.b.Update(tt.args.ctx, tt.args.book); (err != nil) != tt.wantErr {
-				t.Errorf("book.Update() error = %v, wantErr %v", err, tt.wantErr)
+			if err := (&book{db: gdb}).Update(context.Background(), &entities.BookChange{Book: tt.book, Columns: catalogueColumns}); !errors.Is(err, tt.wantErr) {
+				t.Errorf("book.Update() error = %v, want %v", err, tt.wantErr)
 			}
 		})
 	}
+
+	if validBook.Version != 4 {
+		t.Errorf("book.Update() version = %d, want the bumped 4", validBook.Version)
+	}
+
+	// A partial update writes the changed columns only, and records them
+	// alone in the history
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockStmt).WithArgs(validBook.ID, 1).WillReturnRows(stored(validBook.ID, 4))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "books" SET "updated_at"=$1,"title"=$2 WHERE "books"."deleted_at" IS NULL AND "id" = $3 RETURNING "version"`)).
+		WithArgs(sqlmock.AnyArg(), validBook.Title, validBook.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+	mock.ExpectExec(revisionStmt).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), validBook.ID, int64(5), enums.RevisionUpdated, nil,
+			`{"title":{"before":"Old Book","after":"Updated Book"}}`, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	if err := (&book{db: gdb}).Update(context.Background(), &entities.BookChange{Book: &validBook, Columns: []string{"title"}}); err != nil || validBook.Version != 5 {
+		t.Errorf("book.Update() of title error = %v, version = %d", err, validBook.Version)
+	}
+	if err := (&book{db: gdb}).Update(context.Background(), &entities.BookChange{Book: &validBook, Columns: []string{"copies"}}); err == nil {
+		t.Error("book.Update() of copies succeeded")
+	}
+
+	// Refiling the book alone is saved under the same lock, still moves
+	// the version on and is recorded in the history
+	unfiled, filed := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+	subjectsStmt := regexp.QuoteMeta(`SELECT "subject_id" FROM "book_subjects" WHERE book_id = $1 ORDER BY subject_id`)
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockStmt).WithArgs(validBook.ID, 1).WillReturnRows(stored(validBook.ID, 5))
+	mock.ExpectQuery(subjectsStmt).WithArgs(validBook.ID).WillReturnRows(sqlmock.NewRows([]string{"subject_id"}).AddRow(unfiled))
+	mock.ExpectExec(`DELETE FROM "book_subjects"`).WithArgs(validBook.ID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "book_subjects"`).WithArgs(validBook.ID, filed).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "books" SET "updated_at"=$1 WHERE "books"."deleted_at" IS NULL AND "id" = $2 RETURNING "version"`)).
+		WithArgs(sqlmock.AnyArg(), validBook.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(6))
+	mock.ExpectQuery(subjectsStmt).WithArgs(validBook.ID).WillReturnRows(sqlmock.NewRows([]string{"subject_id"}).AddRow(filed))
+	mock.ExpectExec(revisionStmt).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), validBook.ID, int64(6), enums.RevisionUpdated, nil,
+			fmt.Sprintf(`{"subject_ids":{"before":["%s"],"after":["%s"]}}`, unfiled, filed), withoutField("subject_ids")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	if err := (&book{db: gdb}).Update(context.Background(), &entities.BookChange{Book: &validBook, SubjectIDs: []uuid.UUID{filed}}); err != nil || validBook.Version != 6 {
+		t.Errorf("book.Update() of subjects error = %v, version = %d", err, validBook.Version)
+	}
+
+	// A change of nothing writes nothing
+	if err := (&book{db: gdb}).Update(context.Background(), &entities.BookChange{Book: &validBook}); err != nil {
+		t.Errorf("book.Update() of nothing error = %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
 }
 
 func Test_book_Delete(t *testing.T) {
@@ -393,22 +569,35 @@ func Test_book_Delete(t *testing.T) {
 	validID, _ := uuid.NewV4()
 	invalidID, _ := uuid.NewV4()
 
-	// Generate SQL statements for mocking
-	validStmt := gdb.Session(&gorm.Session{DryRun: true}).Delete(&entities.Book{}, "id = ?", validID).Statement.SQL.String()
-	invalidStmt := gdb.Session(&gorm.Session{DryRun: true}).Delete(&entities.Book{}, "id = ?", invalidID).Statement.SQL.String()
+	lockStmt := regexp.QuoteMeta(`SELECT * FROM "books" WHERE id = $1 AND "books"."deleted_at" IS NULL ORDER BY "books"."id" LIMIT $2 FOR UPDATE`)
+	stored := sqlmock.NewRows([]string{"id", "title", "version"}).AddRow(validID, "Test Book", 3)
 
-	// Set up expectations
+	// Set up expectations; deleting only stamps deleted_at, and the
+	// history records the version the trigger bumps the book to
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(validStmt)).WithArgs(validID).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(lockStmt).WithArgs(validID, 1).WillReturnRows(stored)
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "books" SET "deleted_at"=$1 WHERE "books"."id" = $2 AND "books"."deleted_at" IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), validID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(revisionStmt).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), validID, int64(4), enums.RevisionDeleted, nil, "{}", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta(invalidStmt)).WithArgs(invalidID).WillReturnResult(sqlmock.NewResult(1, 0))
-	mock.ExpectCommit()
+	mock.ExpectQuery(lockStmt).WithArgs(invalidID, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	// With a version, a book changed since it was read is kept
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockStmt).WithArgs(validID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(validID, 3))
+	mock.ExpectRollback()
 
 	type args struct {
-		ctx context.Context
-		id  uuid.UUID
+		ctx     context.Context
+		id      uuid.UUID
+		version int64
 	}
 	tests := []struct {
 		name    string
@@ -428,13 +617,669 @@ func Test_book_Delete(t *testing.T) {
 			args:    args{ctx: context.Background(), id: invalidID},
 			wantErr: true,
 		},
+		{
+			name:    "changed since read",
+			b:       &book{db: gdb},
+			args:    args{ctx: context.Background(), id: validID, version: 2},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tt.b.Delete(tt.args.ctx, tt.args.id); (err != nil) != tt.wantErr {
+			if err := tt.b.Delete(tt.args.ctx, tt.args.id, tt.args.version); (err != nil) != tt.wantErr {
 				t.Errorf("book.Delete() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_book_List(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	testTime := time.Now()
+	id1, _ := uuid.NewV4()
+	id2, _ := uuid.NewV4()
+
+	columns := []string{"id", "created_at", "updated_at", "title", "author", "isbn", "publisher", "publish_date", "description", "copies"}
+	rows := func(n int) *sqlmock.Rows {
+		r := sqlmock.NewRows(columns)
+		for i, id := range []uuid.UUID{id1, id2}[:n] {
+			r.AddRow(id, testTime, testTime, fmt.Sprintf("Book %d", i), "Author", "isbn", "Publisher", testTime, "", 1)
+		}
+		return r
+	}
+
+	available := true
+	offsetQuery := &entities.BookQuery{Page: 2, Limit: 1, Author: "aut", Available: &available}
+
+	// Offset page: more rows than the limit means a next page exists
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "books" WHERE author ILIKE $1 AND copies > 0 AND "books"."deleted_at" IS NULL`)).
+		WithArgs("%aut%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "books" WHERE author ILIKE $1 AND copies > 0 AND "books"."deleted_at" IS NULL ORDER BY title ASC, id ASC LIMIT $2 OFFSET $3`)).
+		WithArgs("%aut%", 2, 1).
+		WillReturnRows(rows(2))
+
+	type args struct {
+		ctx   context.Context
+		query *entities.BookQuery
+	}
+	tests := []struct {
+		name      string
+		b         *book
+		args      args
+		wantLen   int
+		wantTotal int64
+		wantNext  bool
+		wantPrev  bool
+		wantErr   bool
+	}{
+		{
+			name:      "offset page",
+			b:         &book{db: gdb},
+			args:      args{ctx: context.Background(), query: offsetQuery},
+			wantLen:   1,
+			wantTotal: 3,
+			wantNext:  true,
+			wantPrev:  true,
+		},
+		{
+			name:    "unknown sort field",
+			b:       &book{db: gdb},
+			args:    args{ctx: context.Background(), query: &entities.BookQuery{Sort: []entities.SortField{{Field: "isbn"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "malformed cursor",
+			b:       &book{db: gdb},
+			args:    args{ctx: context.Background(), query: &entities.BookQuery{Cursor: "not-a-cursor"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, info, err := tt.b.List(tt.args.ctx, tt.args.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("book.List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if !errors.Is(err, entities.ErrInvalidBookQuery) {
+					t.Errorf("book.List() error = %v, want ErrInvalidBookQuery", err)
+				}
+				return
+			}
+			if len(got) != tt.wantLen || info.Total != tt.wantTotal {
+				t.Errorf("book.List() got %d books of %d, want %d of %d", len(got), info.Total, tt.wantLen, tt.wantTotal)
+			}
+			if (info.NextCursor != "") != tt.wantNext || (info.PrevCursor != "") != tt.wantPrev {
+				t.Errorf("book.List() next = %q, prev = %q", info.NextCursor, info.PrevCursor)
+			}
+		})
+	}
+}
+
+func Test_book_ListCursor(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	testTime := time.Now().UTC()
+	id, _ := uuid.NewV4()
+	last := entities.Book{ID: id, Title: "Dune", PublishDate: testTime}
+
+	sortFields := []entities.SortField{{Field: "title"}, {Field: "publish_date", Desc: true}}
+	terms, err := resolveSort(sortFields)
+	if err != nil {
+		t.Fatalf("resolveSort() error = %v", err)
+	}
+	next := encodeCursor(cursorNext, sortKey(sortFields), terms, &last)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "books"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(10))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "books" WHERE (((title > $1) OR (title = $2 AND publish_date < $3) OR (title = $4 AND publish_date = $5 AND id > $6))) AND "books"."deleted_at" IS NULL ORDER BY title ASC, publish_date DESC, id ASC LIMIT $7`)).
+		WithArgs("Dune", "Dune", testTime, "Dune", testTime, id, 6).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}))
+
+	books, info, err := (&book{db: gdb}).List(context.Background(), &entities.BookQuery{Cursor: next, Limit: 5, Sort: sortFields})
+	if err != nil {
+		t.Fatalf("book.List() error = %v", err)
+	}
+	if len(books) != 0 || info.Total != 10 {
+		t.Errorf("book.List() got %d books of %d", len(books), info.Total)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+
+	_, _, err = (&book{db: gdb}).List(context.Background(), &entities.BookQuery{Cursor: next, Sort: sortFields[:1]})
+	if !errors.Is(err, entities.ErrInvalidBookQuery) {
+		t.Errorf("book.List() with mismatched sort error = %v, want ErrInvalidBookQuery", err)
+	}
+}
+
+func Test_book_Each(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	testTime := time.Now()
+	id1, _ := uuid.NewV4()
+	id2, _ := uuid.NewV4()
+	from := time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "books" WHERE publisher ILIKE $1 AND publish_date >= $2 AND "books"."deleted_at" IS NULL ORDER BY isbn`)).
+		WithArgs("%ace%", from).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "isbn", "publish_date"}).
+			AddRow(id1, "Dune", "9780441013593", testTime).
+			AddRow(id2, "Dune Messiah", "9780593098233", testTime))
+
+	var got []uuid.UUID
+	err := (&book{db: gdb}).Each(context.Background(), &entities.BookQuery{Publisher: "ace", PublishedFrom: &from}, func(b *entities.Book) error {
+		got = append(got, b.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("book.Each() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != id1 || got[1] != id2 {
+		t.Errorf("book.Each() visited %v", got)
+	}
+
+	// An error from the callback stops the scan
+	stop := errors.New("stop")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "books" WHERE "books"."deleted_at" IS NULL ORDER BY isbn`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id1).AddRow(id2))
+
+	calls := 0
+	err = (&book{db: gdb}).Each(context.Background(), &entities.BookQuery{}, func(b *entities.Book) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("book.Each() error = %v after %d calls, want %v after 1", err, calls, stop)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func Test_book_Search(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	hitColumns := []string{"id", "title", "author", "rank", "title_highlight", "snippet"}
+
+	// Full-text match
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "books" CROSS JOIN to_tsquery('english', $1) AS query WHERE search_vector @@ query`)).
+		WithArgs("hobb:* & tolk:*").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	escaped := regexp.QuoteMeta(`ts_headline('english', replace(replace(replace(title, '&', '&amp;'), '<', '&lt;'), '>', '&gt;')`)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT books.*, ts_rank_cd(search_vector, query) AS rank, `) + escaped + `, query, \$1\) AS title_highlight`).
+		WillReturnRows(sqlmock.NewRows(hitColumns).AddRow(id, "The Hobbit", "J.R.R. Tolkien", 0.8, "The <mark>Hobbit</mark>", ""))
+
+	// Typo: no lexeme match, trigram fallback
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "books" CROSS JOIN to_tsquery('english', $1) AS query WHERE search_vector @@ query`)).
+		WithArgs("hobit:*").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "books" WHERE ($1 <% title OR $2 <% author OR $3 <% publisher) AND "books"."deleted_at" IS NULL`)).
+		WithArgs("hobit", "hobit", "hobit").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT books.*, greatest(word_similarity($1, title)`)+`.* AS rank, `+escaped+regexp.QuoteMeta(`, plainto_tsquery('english', $4), $5) AS title_highlight`)).
+		WithArgs("hobit", "hobit", "hobit", "hobit", headlineOptions, "hobit", snippetOptions, "hobit", "hobit", "hobit", 10).
+		WillReturnRows(sqlmock.NewRows(hitColumns).AddRow(id, "The Hobbit", "J.R.R. Tolkien", 0.6, "The Hobbit", ""))
+
+	// Database failure
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "books"`)).
+		WillReturnError(sql.ErrConnDone)
+
+	type args struct {
+		ctx   context.Context
+		query *entities.BookSearchQuery
+	}
+	tests := []struct {
+		name      string
+		b         *book
+		args      args
+		wantTotal int64
+		wantFuzzy bool
+		wantErr   bool
+	}{
+		{
+			name:      "full-text match",
+			b:         &book{db: gdb},
+			args:      args{ctx: context.Background(), query: &entities.BookSearchQuery{Q: "Hobb, Tolk", Limit: 10}},
+			wantTotal: 1,
+		},
+		{
+			name:      "fuzzy fallback",
+			b:         &book{db: gdb},
+			args:      args{ctx: context.Background(), query: &entities.BookSearchQuery{Q: "hobit", Limit: 10}},
+			wantTotal: 1,
+			wantFuzzy: true,
+		},
+		{
+			name:    "database error",
+			b:       &book{db: gdb},
+			args:    args{ctx: context.Background(), query: &entities.BookSearchQuery{Q: "dune"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.b.Search(tt.args.ctx, tt.args.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("book.Search() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Total != tt.wantTotal || got.Fuzzy != tt.wantFuzzy || len(got.Hits) != int(tt.wantTotal) {
+				t.Errorf("book.Search() = %+v", got)
+			}
+			if got.Hits[0].Book.ID != id || got.Hits[0].Rank == 0 {
+				t.Errorf("book.Search() hit = %+v", got.Hits[0])
+			}
+		})
+	}
+}
+
+func Test_book_Restore(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	lock := regexp.QuoteMeta(`SELECT * FROM "books" WHERE id = $1 AND deleted_at IS NOT NULL ORDER BY "books"."id" LIMIT $2 FOR UPDATE`)
+
+	// In the trash
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at", "version"}).AddRow(id, "Dune", time.Now(), 5))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "books" SET "deleted_at"=$1,"updated_at"=$2 WHERE "id" = $3`)).
+		WithArgs(nil, sqlmock.AnyArg(), id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(revisionStmt).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), id, int64(6), enums.RevisionRestored, nil, "{}", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Not deleted, or unknown
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	b := &book{db: gdb}
+	got, err := b.Restore(context.Background(), id)
+	if err != nil || got.DeletedAt.Valid || got.Version != 6 {
+		t.Errorf("book.Restore() = %+v, %v", got, err)
+	}
+	if _, err := b.Restore(context.Background(), id); err != entities.ErrBookNotFound {
+		t.Errorf("book.Restore() error = %v, want %v", err, entities.ErrBookNotFound)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_book_Purge(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	lock := regexp.QuoteMeta(`SELECT * FROM "books" WHERE id = $1 AND deleted_at IS NOT NULL ORDER BY "books"."id" LIMIT $2 FOR UPDATE`)
+	purge := regexp.QuoteMeta(`DELETE FROM "books" WHERE "books"."id" = $1`)
+	trashed := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "deleted_at", "version"}).AddRow(id, time.Now(), 2)
+	}
+
+	// The purge closes the book's history, which is kept
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, 1).WillReturnRows(trashed())
+	mock.ExpectExec(purge).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(revisionStmt).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), id, int64(3), enums.RevisionPurged, nil, "{}", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, 1).WillReturnRows(trashed())
+	mock.ExpectExec(purge).WithArgs(id).WillReturnError(&pgconn.PgError{Code: "23503"})
+	mock.ExpectRollback()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "in the trash"},
+		{name: "lent before", wantErr: entities.ErrBookHasHistory},
+		{name: "not in the trash", wantErr: entities.ErrBookNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &book{db: gdb}
+			if err := b.Purge(context.Background(), id); err != tt.wantErr {
+				t.Errorf("book.Purge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_book_PurgeTrash(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	cutoff := time.Now().AddDate(0, 0, -30)
+	purged := []uuid.UUID{uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())}
+
+	// Each purged book gets a revision closing its history
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`DELETE FROM "books" WHERE (deleted_at IS NOT NULL AND deleted_at < $1) AND ` +
+		`NOT EXISTS (SELECT 1 FROM loans WHERE loans.book_id = books.id) AND ` +
+		`NOT EXISTS (SELECT 1 FROM holds WHERE holds.book_id = books.id) RETURNING *`)).
+		WithArgs(cutoff).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(purged[0], 2).AddRow(purged[1], 7))
+	mock.ExpectExec(revisionStmt).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), purged[0], int64(3), enums.RevisionPurged, nil, "{}", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(revisionStmt).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), purged[1], int64(8), enums.RevisionPurged, nil, "{}", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	b := &book{db: gdb}
+	got, err := b.PurgeTrash(context.Background(), cutoff)
+	if err != nil || got != 2 {
+		t.Errorf("book.PurgeTrash() = %d, %v", got, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_book_Upsert(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	existingID, _ := uuid.NewV4()
+	trashedID, _ := uuid.NewV4()
+	lookup := regexp.QuoteMeta(`SELECT * FROM "books" WHERE isbn = $1 LIMIT $2 FOR UPDATE`)
+	shelf := regexp.QuoteMeta(`SELECT * FROM "items" WHERE book_id = $1 AND status = $2 ORDER BY created_at DESC FOR UPDATE SKIP LOCKED`)
+	update := regexp.QuoteMeta(`UPDATE "books" SET "author"=$1,"description"=$2,"item_type"=$3,"publish_date"=$4,"publisher"=$5,"publisher_id"=$6,"title"=$7,"updated_at"=$8 WHERE "books"."deleted_at" IS NULL AND "id" = $9 RETURNING "version"`)
+	contributors := regexp.QuoteMeta(`SELECT * FROM "book_contributors" WHERE book_id = $1 ORDER BY position, role`)
+	authorID, oldAuthorID, translatorID := uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4()), uuid.Must(uuid.NewV4())
+
+	mock.ExpectBegin()
+
+	// New ISBN
+	mock.ExpectExec("SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(lookup).WithArgs("9780441013593", 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "books"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(revisionStmt).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), int64(1), enums.RevisionCreated, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(shelf).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "items"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Known ISBN
+	mock.ExpectExec("SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(lookup).WithArgs("9780141439587", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "version"}).AddRow(existingID, "Old", 4))
+	// The shelf is adjusted before the details, and the revision recorded
+	// at the version the update returns
+	mock.ExpectQuery(shelf).WithArgs(existingID, enums.ItemAvailable).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.Must(uuid.NewV4())))
+	mock.ExpectQuery(update).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+	// The credited author replaces the old one; the translator is kept
+	mock.ExpectQuery(contributors).WithArgs(existingID).
+		WillReturnRows(sqlmock.NewRows([]string{"book_id", "author_id", "role", "position"}).
+			AddRow(existingID, oldAuthorID, enums.ContributorAuthor, 0).
+			AddRow(existingID, translatorID, enums.ContributorTranslator, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "book_contributors" WHERE book_id = $1`)).WithArgs(existingID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "book_contributors" ("book_id","author_id","role","position") VALUES ($1,$2,$3,$4),($5,$6,$7,$8)`)).
+		WithArgs(existingID, authorID, enums.ContributorAuthor, 0, existingID, translatorID, enums.ContributorTranslator, 1).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(revisionStmt).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), existingID, int64(5), enums.RevisionUpdated, nil,
+			`{"title":{"before":"Old","after":"Emma"}}`, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// ISBN of a book in the trash
+	mock.ExpectExec("SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(lookup).WithArgs("9780451524935", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "deleted_at"}).AddRow(trashedID, time.Now()))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Known ISBN from a MARC record without copies: the record is replaced
+	// and the shelf left alone
+	mock.ExpectExec("SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(lookup).WithArgs("9780060850524", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.Must(uuid.NewV4())))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "books" SET "author"=$1,"description"=$2,"item_type"=$3,"marc"=$4,"publish_date"=$5,"publisher"=$6,"publisher_id"=$7,"title"=$8,"updated_at"=$9 WHERE "books"."deleted_at" IS NULL AND "id" = $10 RETURNING "version"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.ExpectExec(revisionStmt).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Known ISBN crediting an author who is gone: the row fails whole
+	unlinkedID := uuid.Must(uuid.NewV4())
+	mock.ExpectExec("SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(lookup).WithArgs("9780547928227", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(unlinkedID))
+	mock.ExpectQuery(update).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectQuery(contributors).WithArgs(unlinkedID).WillReturnRows(sqlmock.NewRows([]string{"book_id"}))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "book_contributors"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "book_contributors"`)).WillReturnError(&pgconn.PgError{Code: "23503"})
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectCommit()
+
+	books := []entities.Book{
+		{ISBN: "9780441013593", Title: "Dune", Copies: 1},
+		{ISBN: "9780141439587", Title: "Emma", Copies: 1, Contributors: []entities.BookContributor{{AuthorID: authorID, Role: enums.ContributorAuthor}}},
+		{ISBN: "9780451524935", Title: "1984", Copies: 1},
+		{ISBN: "9780060850524", Title: "Brave New World", MARC: []byte("record")},
+		{ISBN: "9780547928227", Title: "The Hobbit", Contributors: []entities.BookContributor{{AuthorID: uuid.Must(uuid.NewV4()), Role: enums.ContributorAuthor}}},
+	}
+
+	b := &book{db: gdb}
+	got, err := b.Upsert(context.Background(), books)
+	if err != nil {
+		t.Fatalf("book.Upsert() error = %v", err)
+	}
+
+	want := []enums.ImportOutcome{enums.ImportCreated, enums.ImportUpdated, enums.ImportRejected, enums.ImportUpdated, enums.ImportRejected}
+	for i := range want {
+		if got[i].Outcome != want[i] {
+			t.Errorf("book.Upsert()[%d] = %+v, want %s", i, got[i], want[i])
+		}
+	}
+	if got[2].Err != entities.ErrBookInTrash {
+		t.Errorf("book.Upsert()[2].Err = %v, want %v", got[2].Err, entities.ErrBookInTrash)
+	}
+	if got[4].Err != entities.ErrAuthorNotFound {
+		t.Errorf("book.Upsert()[4].Err = %v, want %v", got[4].Err, entities.ErrAuthorNotFound)
+	}
+	if books[0].ID == uuid.Nil || books[1].ID != existingID {
+		t.Errorf("book.Upsert() ids = %s, %s", books[0].ID, books[1].ID)
+	}
+	if books[1].Version != 5 {
+		t.Errorf("book.Upsert() version = %d, want the 5 the revision was recorded at", books[1].Version)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_book_ListBySubject(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	subject, _ := uuid.NewV4()
+	id, _ := uuid.NewV4()
+
+	// The subject takes in the subjects below it; Dewey matches by prefix
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "books" WHERE id IN (SELECT book_id FROM book_subjects WHERE subject_id IN (`)).
+		WithArgs(subject, "81%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM below
+			)) AND dewey LIKE $2 AND "books"."deleted_at" IS NULL ORDER BY dewey ASC, id ASC LIMIT $3`)).
+		WithArgs(subject, "81%", 21).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "dewey"}).AddRow(id, "813.54 L478t"))
+
+	books, info, err := (&book{db: gdb}).List(context.Background(), &entities.BookQuery{
+		Subject: &subject,
+		Dewey:   "81",
+		Sort:    []entities.SortField{{Field: "dewey"}},
+	})
+	if err != nil {
+		t.Fatalf("book.List() error = %v", err)
+	}
+	if len(books) != 1 || info.Total != 1 || books[0].Dewey != "813.54 L478t" {
+		t.Errorf("book.List() = %+v of %d", books, info.Total)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_book_Classes(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT substring(lcc from $1) AS class, count(*) AS count FROM "books" WHERE lcc ~ $2 AND "books"."deleted_at" IS NULL GROUP BY "class" ORDER BY class`)).
+		WithArgs("^[A-Z]{1,3}", "^[A-Z]{1,3}").
+		WillReturnRows(sqlmock.NewRows([]string{"class", "count"}).AddRow("PR", 2).AddRow("PS", 5))
+
+	got, err := (&book{db: gdb}).Classes(context.Background(), enums.SchemeLCC)
+	if err != nil {
+		t.Fatalf("book.Classes() error = %v", err)
+	}
+	if len(got) != 2 || got[1] != (entities.ClassCount{Class: "PS", Count: 5}) {
+		t.Errorf("book.Classes() = %+v", got)
+	}
+
+	if _, err := (&book{db: gdb}).Classes(context.Background(), "udc"); !errors.Is(err, entities.ErrInvalidBookQuery) {
+		t.Errorf("book.Classes() with unknown scheme error = %v, want ErrInvalidBookQuery", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_book_SetCover(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id := uuid.Must(uuid.NewV4())
+	lockStmt := regexp.QuoteMeta(`SELECT * FROM "books" WHERE id = $1 AND "books"."deleted_at" IS NULL ORDER BY "books"."id" LIMIT $2 FOR UPDATE`)
+	updateStmt := regexp.QuoteMeta(`UPDATE "books" SET "cover"=$1,"updated_at"=$2 WHERE "books"."deleted_at" IS NULL AND "id" = $3`)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockStmt).WithArgs(id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(id, 2))
+	mock.ExpectExec(updateStmt).
+		WithArgs("covers/a.jpg", sqlmock.AnyArg(), id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(revisionStmt).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), id, int64(3), enums.RevisionUpdated, nil,
+			`{"cover":{"before":"","after":"covers/a.jpg"}}`, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockStmt).WithArgs(id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(id, 3))
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockStmt).WithArgs(id, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	if err := (&book{db: gdb}).SetCover(context.Background(), id, "covers/a.jpg", 2); err != nil {
+		t.Errorf("book.SetCover() error = %v", err)
+	}
+	if err := (&book{db: gdb}).SetCover(context.Background(), id, "", 2); !errors.Is(err, entities.ErrBookModified) {
+		t.Errorf("book.SetCover() of stale version error = %v, want ErrBookModified", err)
+	}
+	if err := (&book{db: gdb}).SetCover(context.Background(), id, "", 0); !errors.Is(err, entities.ErrBookNotFound) {
+		t.Errorf("book.SetCover() of missing book error = %v, want ErrBookNotFound", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_book_History(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id := uuid.Must(uuid.NewV4())
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "book_id", "revision", "action", "changes", "snapshot"}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_revisions" WHERE book_id = $1 ORDER BY revision DESC`)).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(uuid.Must(uuid.NewV4()), id, 2, "update", `{"title":{"before":"Dune","after":"Dune Messiah"}}`, `{"title":"Dune Messiah"}`).
+			AddRow(uuid.Must(uuid.NewV4()), id, 1, "create", `{"title":{"before":null,"after":"Dune"}}`, `{"title":"Dune"}`))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_revisions" WHERE book_id = $1 AND revision = $2 ORDER BY "book_revisions"."id" LIMIT $3`)).
+		WithArgs(id, int64(1), 1).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(uuid.Must(uuid.NewV4()), id, 1, "create", `{}`, `{"title":"Dune"}`))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_revisions" WHERE book_id = $1 AND revision = $2`)).
+		WithArgs(id, int64(9), 1).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "book_revisions" WHERE book_id = $1 AND created_at <= $2 ORDER BY revision DESC,"book_revisions"."id" LIMIT $3`)).
+		WithArgs(id, at, 1).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	b := &book{db: gdb}
+
+	history, err := b.History(context.Background(), id)
+	if err != nil || len(history) != 2 {
+		t.Fatalf("book.History() = %+v, %v", history, err)
+	}
+	if got := string(history[0].Changes["title"].After); history[0].Revision != 2 || got != `"Dune Messiah"` {
+		t.Errorf("book.History()[0] = revision %d, title %s", history[0].Revision, got)
+	}
+
+	rev, err := b.Revision(context.Background(), id, 1)
+	if err != nil || string(rev.Snapshot["title"]) != `"Dune"` {
+		t.Errorf("book.Revision() = %+v, %v", rev, err)
+	}
+	if _, err := b.Revision(context.Background(), id, 9); !errors.Is(err, entities.ErrRevisionNotFound) {
+		t.Errorf("book.Revision() of unknown revision error = %v, want ErrRevisionNotFound", err)
+	}
+
+	// Nothing recorded yet at that time
+	if _, err := b.RevisionAt(context.Background(), id, at); !errors.Is(err, entities.ErrRevisionNotFound) {
+		t.Errorf("book.RevisionAt() error = %v, want ErrRevisionNotFound", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
 }
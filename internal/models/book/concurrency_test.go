@@ -0,0 +1,178 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"library-system/internal/db/migrations"
+	"library-system/internal/entities"
+	"library-system/internal/models/loan"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB connects to the throwaway database TEST_DATABASE_URL names and
+// brings its schema up to date. Tests that need real locking and triggers
+// are skipped without one.
+func openTestDB(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
+	})
+	if err != nil {
+		t.Fatalf("connecting: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("connecting: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrations.New(sqlDB)
+	if err != nil {
+		t.Fatalf("loading migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("migrating: %v", err)
+	}
+
+	return db
+}
+
+// Changing the copies of a book locks the book before its items, while a
+// checkout or return locks an item before the copies sync updates the
+// book. Both running at once must not deadlock.
+func Test_book_Update_copiesDuringCirculation(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	b := &book{db: db}
+	loans := loan.New(db)
+
+	id := uuid.Must(uuid.NewV4())
+	stored := &entities.Book{
+		Title:       "Dune",
+		Author:      "Frank Herbert",
+		ISBN:        fmt.Sprintf("979%010d", time.Now().UnixNano()%1e10),
+		Publisher:   "Ace",
+		PublishDate: time.Date(1965, time.August, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := b.Create(ctx, stored); err != nil {
+		t.Fatalf("book.Create() error = %v", err)
+	}
+	copies := 2
+	if err := b.Update(ctx, &entities.BookChange{Book: stored, Copies: &copies}); err != nil {
+		t.Fatalf("book.Update() error = %v", err)
+	}
+
+	member := &entities.User{ID: id, CreatedAt: time.Now(), UpdatedAt: time.Now(), Name: "Reader", Email: id.String() + "@example.com", PasswordHash: "x"}
+	if err := db.Create(member).Error; err != nil {
+		t.Fatalf("creating member: %v", err)
+	}
+
+	const rounds = 50
+	errs := make(chan error, 2*rounds)
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < rounds; i++ {
+			current, err := b.GetByID(ctx, stored.ID)
+			if err != nil {
+				errs <- err
+				return
+			}
+			copies := 2 + i%2
+			err = b.Update(ctx, &entities.BookChange{Book: current, Copies: &copies})
+			if err != nil && !errors.Is(err, entities.ErrBookModified) {
+				errs <- fmt.Errorf("book.Update(): %w", err)
+			}
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < rounds; i++ {
+			now := time.Now()
+			l := &entities.Loan{BookID: stored.ID, UserID: member.ID, CheckedOutAt: now, DueAt: now.Add(24 * time.Hour)}
+			err := loans.Checkout(ctx, l, "")
+			if errors.Is(err, entities.ErrNoCopiesAvailable) {
+				continue
+			}
+			if err != nil {
+				errs <- fmt.Errorf("loan.Checkout(): %w", err)
+				continue
+			}
+			if _, err := loans.Return(ctx, l.ID, time.Now(), nil); err != nil {
+				errs <- fmt.Errorf("loan.Return(): %w", err)
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// Checkouts and returns recount the copies of a book but leave its version,
+// so an edit based on an earlier read still applies
+func Test_book_version_keptByCirculation(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	b := &book{db: db}
+	loans := loan.New(db)
+
+	id := uuid.Must(uuid.NewV4())
+	stored := &entities.Book{
+		Title:       "Solaris",
+		Author:      "Stanisław Lem",
+		ISBN:        fmt.Sprintf("979%010d", time.Now().UnixNano()%1e10),
+		Publisher:   "Walker",
+		PublishDate: time.Date(1961, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if err := b.Create(ctx, stored); err != nil {
+		t.Fatalf("book.Create() error = %v", err)
+	}
+	copies := 2
+	if err := b.Update(ctx, &entities.BookChange{Book: stored, Copies: &copies}); err != nil {
+		t.Fatalf("book.Update() error = %v", err)
+	}
+
+	member := &entities.User{ID: id, CreatedAt: time.Now(), UpdatedAt: time.Now(), Name: "Reader", Email: id.String() + "@example.com", PasswordHash: "x"}
+	if err := db.Create(member).Error; err != nil {
+		t.Fatalf("creating member: %v", err)
+	}
+
+	now := time.Now()
+	l := &entities.Loan{BookID: stored.ID, UserID: member.ID, CheckedOutAt: now, DueAt: now.Add(24 * time.Hour)}
+	if err := loans.Checkout(ctx, l, ""); err != nil {
+		t.Fatalf("loan.Checkout() error = %v", err)
+	}
+	got, err := b.GetByID(ctx, stored.ID)
+	if err != nil {
+		t.Fatalf("book.GetByID() error = %v", err)
+	}
+	if got.Copies != 1 || got.Version != stored.Version {
+		t.Errorf("after checkout copies = %d, version = %d, want 1 and %d", got.Copies, got.Version, stored.Version)
+	}
+
+	stored.Title = "Solaris (Walker edition)"
+	if err := b.Update(ctx, &entities.BookChange{Book: stored, Columns: []string{"title"}}); err != nil {
+		t.Errorf("book.Update() after checkout error = %v", err)
+	}
+}
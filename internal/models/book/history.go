@@ -0,0 +1,210 @@
+package book
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"time"
+
+	"library-system/internal/auth"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// History returns the revisions of a book, the latest first. A purged book
+// keeps its history.
+func (b *book) History(ctx context.Context, id uuid.UUID) ([]entities.BookRevision, error) {
+	var revisions []entities.BookRevision
+	result := b.db.Where("book_id = ?", id).Order("revision DESC").Find(&revisions)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return revisions, nil
+}
+
+// Revision returns one revision of a book
+func (b *book) Revision(ctx context.Context, id uuid.UUID, revision int64) (*entities.BookRevision, error) {
+	var rev entities.BookRevision
+	result := b.db.Where("book_id = ? AND revision = ?", id, revision).First(&rev)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrRevisionNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &rev, nil
+}
+
+// RevisionAt returns the revision a book was at, at the given time
+func (b *book) RevisionAt(ctx context.Context, id uuid.UUID, at time.Time) (*entities.BookRevision, error) {
+	var rev entities.BookRevision
+	result := b.db.Where("book_id = ? AND created_at <= ?", id, at).Order("revision DESC").First(&rev)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrRevisionNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &rev, nil
+}
+
+// trackedFields are the fields of a book kept in its history, by column.
+// Copies follow the items and the MARC record is too large to keep, so
+// neither is tracked. Times are kept in UTC so that a value read back
+// from the database compares equal to the one written.
+func trackedFields(book *entities.Book) map[string]interface{} {
+	return map[string]interface{}{
+		"created_at":   book.CreatedAt.UTC(),
+		"title":        book.Title,
+		"author":       book.Author,
+		"isbn":         book.ISBN,
+		"publisher":    book.Publisher,
+		"publisher_id": book.PublisherID,
+		"publish_date": book.PublishDate.UTC(),
+		"description":  book.Description,
+		"item_type":    book.ItemType,
+		"dewey":        book.Dewey,
+		"lcc":          book.LCC,
+		"work_id":      book.WorkID,
+		"cover":        book.Cover,
+	}
+}
+
+// linkFields are the links of a book kept in its history. A change to
+// them is listed with the revision, but they are not part of its snapshot:
+// a book seen as of a time, or reverted, keeps the links it has now.
+var linkFields = []string{"contributors", "subject_ids"}
+
+// readLinks reads, within tx, the links of a book a change replaces
+func readLinks(tx *gorm.DB, bookID uuid.UUID, change *entities.BookChange) (map[string]interface{}, error) {
+	links := map[string]interface{}{}
+	if change.Contributors != nil {
+		var contributors []entities.BookContributor
+		err := tx.Where("book_id = ?", bookID).Order("position, role, author_id").Find(&contributors).Error
+		if err != nil {
+			return nil, err
+		}
+		links["contributors"] = contributors
+	}
+	if change.SubjectIDs != nil {
+		var ids []uuid.UUID
+		err := tx.Model(&entities.BookSubject{}).Where("book_id = ?", bookID).Order("subject_id").Pluck("subject_id", &ids).Error
+		if err != nil {
+			return nil, err
+		}
+		links["subject_ids"] = ids
+	}
+	return links, nil
+}
+
+// Rewrite sets a tracked column of the books a scope selects, within tx
+// and with the books locked, and records a revision for each book it
+// changes. Books in the trash are included when the scope is unscoped. set
+// writes the new value into a book. It returns the number of books the
+// scope selected, changed or not.
+func Rewrite(ctx context.Context, tx *gorm.DB, scope func(*gorm.DB) *gorm.DB, column string, set func(*entities.Book)) (int, error) {
+	var books []entities.Book
+	err := scope(tx).Clauses(clause.Locking{Strength: "UPDATE"}).Order("id").Find(&books).Error
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for i := range books {
+		book := &books[i]
+		before := trackedFields(book)
+		set(book)
+		after := trackedFields(book)
+		if reflect.DeepEqual(before[column], after[column]) {
+			continue
+		}
+
+		book.UpdatedAt = now
+		err := tx.Unscoped().Model(book).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "version"}}}).
+			Select(column, "updated_at").
+			Updates(book).Error
+		if err != nil {
+			return 0, err
+		}
+		if err := record(ctx, tx, book.ID, book.Version, enums.RevisionUpdated, before, after); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(books), nil
+}
+
+// withColumns returns the tracked fields of a book after the given columns
+// were written from another
+func withColumns(before map[string]interface{}, book *entities.Book, columns []string) map[string]interface{} {
+	after := make(map[string]interface{}, len(before))
+	for name, value := range before {
+		after[name] = value
+	}
+	written := trackedFields(book)
+	for _, column := range columns {
+		if value, ok := written[column]; ok {
+			after[column] = value
+		}
+	}
+	return after
+}
+
+// record adds a revision to the history of a book. before is nil for a
+// book just created; an update that changes no tracked field is left out.
+// The actor is taken from the principal on the context, if any.
+func record(ctx context.Context, tx *gorm.DB, bookID uuid.UUID, revision int64, action enums.RevisionAction, before, after map[string]interface{}) error {
+	rev := &entities.BookRevision{
+		BookID:   bookID,
+		Revision: revision,
+		Action:   action,
+		Changes:  map[string]entities.FieldChange{},
+		Snapshot: make(map[string]json.RawMessage, len(after)),
+	}
+
+	for name, value := range after {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(linkFields, name) {
+			rev.Snapshot[name] = data
+		}
+
+		previous := json.RawMessage("null")
+		if before != nil {
+			previous, err = json.Marshal(before[name])
+			if err != nil {
+				return err
+			}
+		}
+		if !bytes.Equal(previous, data) {
+			rev.Changes[name] = entities.FieldChange{Before: previous, After: data}
+		}
+	}
+	if action == enums.RevisionUpdated && len(rev.Changes) == 0 {
+		return nil
+	}
+
+	rev.ID, _ = uuid.NewV4()
+	rev.CreatedAt = time.Now()
+	if p, ok := auth.PrincipalFromContext(ctx); ok {
+		rev.ActorID = &p.UserID
+	}
+
+	return tx.Create(rev).Error
+}
@@ -5,9 +5,12 @@ package mocks
 import (
 	context "context"
 	entities "library-system/internal/entities"
+	enums "library-system/internal/entities/enums"
 
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	uuid "github.com/gofrs/uuid"
 )
 
@@ -16,6 +19,36 @@ type Book struct {
 	mock.Mock
 }
 
+// Classes provides a mock function with given fields: ctx, scheme
+func (_m *Book) Classes(ctx context.Context, scheme enums.ClassScheme) ([]entities.ClassCount, error) {
+	ret := _m.Called(ctx, scheme)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Classes")
+	}
+
+	var r0 []entities.ClassCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, enums.ClassScheme) ([]entities.ClassCount, error)); ok {
+		return rf(ctx, scheme)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, enums.ClassScheme) []entities.ClassCount); ok {
+		r0 = rf(ctx, scheme)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.ClassCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, enums.ClassScheme) error); ok {
+		r1 = rf(ctx, scheme)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, _a1
 func (_m *Book) Create(ctx context.Context, _a1 *entities.Book) error {
 	ret := _m.Called(ctx, _a1)
@@ -34,17 +67,35 @@ func (_m *Book) Create(ctx context.Context, _a1 *entities.Book) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *Book) Delete(ctx context.Context, id uuid.UUID) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *Book) Delete(ctx context.Context, id uuid.UUID, version int64) error {
+	ret := _m.Called(ctx, id, version)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Each provides a mock function with given fields: ctx, query, fn
+func (_m *Book) Each(ctx context.Context, query *entities.BookQuery, fn func(*entities.Book) error) error {
+	ret := _m.Called(ctx, query, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Each")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookQuery, func(*entities.Book) error) error); ok {
+		r0 = rf(ctx, query, fn)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -112,17 +163,330 @@ func (_m *Book) GetByID(ctx context.Context, id uuid.UUID) (*entities.Book, erro
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, _a1
-func (_m *Book) Update(ctx context.Context, _a1 *entities.Book) error {
-	ret := _m.Called(ctx, _a1)
+// GetByISBN provides a mock function with given fields: ctx, isbn
+func (_m *Book) GetByISBN(ctx context.Context, isbn string) (*entities.Book, error) {
+	ret := _m.Called(ctx, isbn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByISBN")
+	}
+
+	var r0 *entities.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entities.Book, error)); ok {
+		return rf(ctx, isbn)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entities.Book); ok {
+		r0 = rf(ctx, isbn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, isbn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// History provides a mock function with given fields: ctx, id
+func (_m *Book) History(ctx context.Context, id uuid.UUID) ([]entities.BookRevision, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for History")
+	}
+
+	var r0 []entities.BookRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]entities.BookRevision, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []entities.BookRevision); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.BookRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, query
+func (_m *Book) List(ctx context.Context, query *entities.BookQuery) ([]entities.Book, *entities.PageInfo, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []entities.Book
+	var r1 *entities.PageInfo
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookQuery) ([]entities.Book, *entities.PageInfo, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookQuery) []entities.Book); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.BookQuery) *entities.PageInfo); ok {
+		r1 = rf(ctx, query)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*entities.PageInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *entities.BookQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Purge provides a mock function with given fields: ctx, id
+func (_m *Book) Purge(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeTrash provides a mock function with given fields: ctx, deletedBefore
+func (_m *Book) PurgeTrash(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ret := _m.Called(ctx, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeTrash")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *Book) Restore(ctx context.Context, id uuid.UUID) (*entities.Book, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 *entities.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.Book, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.Book); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revision provides a mock function with given fields: ctx, id, revision
+func (_m *Book) Revision(ctx context.Context, id uuid.UUID, revision int64) (*entities.BookRevision, error) {
+	ret := _m.Called(ctx, id, revision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revision")
+	}
+
+	var r0 *entities.BookRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) (*entities.BookRevision, error)); ok {
+		return rf(ctx, id, revision)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) *entities.BookRevision); ok {
+		r0 = rf(ctx, id, revision)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int64) error); ok {
+		r1 = rf(ctx, id, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevisionAt provides a mock function with given fields: ctx, id, at
+func (_m *Book) RevisionAt(ctx context.Context, id uuid.UUID, at time.Time) (*entities.BookRevision, error) {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevisionAt")
+	}
+
+	var r0 *entities.BookRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) (*entities.BookRevision, error)); ok {
+		return rf(ctx, id, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) *entities.BookRevision); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r1 = rf(ctx, id, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, query
+func (_m *Book) Search(ctx context.Context, query *entities.BookSearchQuery) (*entities.BookSearchResult, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 *entities.BookSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookSearchQuery) (*entities.BookSearchResult, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookSearchQuery) *entities.BookSearchResult); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.BookSearchQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetCover provides a mock function with given fields: ctx, id, key, version
+func (_m *Book) SetCover(ctx context.Context, id uuid.UUID, key string, version int64) error {
+	ret := _m.Called(ctx, id, key, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCover")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string, int64) error); ok {
+		r0 = rf(ctx, id, key, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Trash provides a mock function with given fields: ctx
+func (_m *Book) Trash(ctx context.Context) ([]entities.Book, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Trash")
+	}
+
+	var r0 []entities.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]entities.Book, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []entities.Book); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, change
+func (_m *Book) Update(ctx context.Context, change *entities.BookChange) error {
+	ret := _m.Called(ctx, change)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entities.Book) error); ok {
-		r0 = rf(ctx, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookChange) error); ok {
+		r0 = rf(ctx, change)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -130,6 +494,36 @@ func (_m *Book) Update(ctx context.Context, _a1 *entities.Book) error {
 	return r0
 }
 
+// Upsert provides a mock function with given fields: ctx, books
+func (_m *Book) Upsert(ctx context.Context, books []entities.Book) ([]entities.UpsertResult, error) {
+	ret := _m.Called(ctx, books)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upsert")
+	}
+
+	var r0 []entities.UpsertResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []entities.Book) ([]entities.UpsertResult, error)); ok {
+		return rf(ctx, books)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []entities.Book) []entities.UpsertResult); ok {
+		r0 = rf(ctx, books)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.UpsertResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []entities.Book) error); ok {
+		r1 = rf(ctx, books)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewBook creates a new instance of Book. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBook(t interface {
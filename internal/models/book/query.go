@@ -0,0 +1,333 @@
+package book
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"library-system/internal/entities"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type columnKind int
+
+const (
+	kindString columnKind = iota
+	kindTime
+	kindInt
+	kindUUID
+)
+
+// sortColumn maps a public sort field onto a books column
+type sortColumn struct {
+	column string
+	kind   columnKind
+	value  func(b *entities.Book) any
+}
+
+var sortColumns = map[string]sortColumn{
+	"title":        {column: "title", kind: kindString, value: func(b *entities.Book) any { return b.Title }},
+	"author":       {column: "author", kind: kindString, value: func(b *entities.Book) any { return b.Author }},
+	"publisher":    {column: "publisher", kind: kindString, value: func(b *entities.Book) any { return b.Publisher }},
+	"publish_date": {column: "publish_date", kind: kindTime, value: func(b *entities.Book) any { return b.PublishDate }},
+	"copies":       {column: "copies", kind: kindInt, value: func(b *entities.Book) any { return b.Copies }},
+	"dewey":        {column: "dewey", kind: kindString, value: func(b *entities.Book) any { return b.Dewey }},
+	"lcc":          {column: "lcc", kind: kindString, value: func(b *entities.Book) any { return b.LCC }},
+	"created_at":   {column: "created_at", kind: kindTime, value: func(b *entities.Book) any { return b.CreatedAt }},
+	"updated_at":   {column: "updated_at", kind: kindTime, value: func(b *entities.Book) any { return b.UpdatedAt }},
+}
+
+// idColumn is appended to every sort so that the order is total
+var idColumn = sortColumn{column: "id", kind: kindUUID, value: func(b *entities.Book) any { return b.ID }}
+
+var defaultSort = []entities.SortField{{Field: "title"}}
+
+type orderTerm struct {
+	sortColumn
+	desc bool
+}
+
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+// cursor is the opaque keyset position handed out to clients
+type cursor struct {
+	Dir    string            `json:"d"`
+	Sort   string            `json:"s"`
+	Values []json.RawMessage `json:"v"`
+}
+
+func (b *book) List(ctx context.Context, query *entities.BookQuery) ([]entities.Book, *entities.PageInfo, error) {
+	sortFields := query.Sort
+	if len(sortFields) == 0 {
+		sortFields = defaultSort
+	}
+
+	terms, err := resolveSort(sortFields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = entities.DefaultPageSize
+	}
+
+	tx := b.db.Scopes(filterBooks(query))
+
+	backward := false
+	if query.Cursor != "" {
+		c, values, err := decodeCursor(query.Cursor, sortKey(sortFields), terms)
+		if err != nil {
+			return nil, nil, err
+		}
+		backward = c.Dir == cursorPrev
+
+		cond, args := keysetCondition(terms, values, backward)
+		tx = tx.Where(cond, args...)
+	} else if query.Page > 1 {
+		tx = tx.Offset((query.Page - 1) * limit)
+	}
+
+	var total int64
+	if err := b.db.Model(&entities.Book{}).Scopes(filterBooks(query)).Count(&total).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var books []entities.Book
+	result := tx.Order(orderClause(terms, backward)).Limit(limit + 1).Find(&books)
+	if result.Error != nil {
+		return nil, nil, result.Error
+	}
+
+	hasMore := len(books) > limit
+	if hasMore {
+		books = books[:limit]
+	}
+
+	if backward {
+		for i, j := 0, len(books)-1; i < j; i, j = i+1, j-1 {
+			books[i], books[j] = books[j], books[i]
+		}
+	}
+
+	hasNext, hasPrev := hasMore, query.Page > 1
+	if query.Cursor != "" {
+		// Following a cursor implies there are rows on the side it came from
+		hasNext, hasPrev = hasMore, true
+		if backward {
+			hasNext, hasPrev = true, hasMore
+		}
+	}
+
+	info := &entities.PageInfo{Total: total}
+	if len(books) > 0 {
+		key := sortKey(sortFields)
+		if hasNext {
+			info.NextCursor = encodeCursor(cursorNext, key, terms, &books[len(books)-1])
+		}
+		if hasPrev {
+			info.PrevCursor = encodeCursor(cursorPrev, key, terms, &books[0])
+		}
+	}
+
+	return books, info, nil
+}
+
+// Each calls fn with every book matching the query's filters, in ISBN
+// order. Rows are read from one cursor as fn consumes them, so the
+// catalogue is never held in memory. Paging and sort are ignored; an error
+// from fn stops the scan and is returned.
+func (b *book) Each(ctx context.Context, query *entities.BookQuery, fn func(*entities.Book) error) error {
+	rows, err := b.db.Model(&entities.Book{}).Scopes(filterBooks(query)).Order("isbn").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var book entities.Book
+		if err := b.db.ScanRows(rows, &book); err != nil {
+			return err
+		}
+		if err := fn(&book); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// filterBooks applies the listing filters shared by the page and count queries
+func filterBooks(query *entities.BookQuery) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if query.Author != "" {
+			db = db.Where("author ILIKE ?", "%"+escapeLike(query.Author)+"%")
+		}
+		if query.Publisher != "" {
+			db = db.Where("publisher ILIKE ?", "%"+escapeLike(query.Publisher)+"%")
+		}
+		if query.PublishedFrom != nil {
+			db = db.Where("publish_date >= ?", *query.PublishedFrom)
+		}
+		if query.PublishedTo != nil {
+			db = db.Where("publish_date <= ?", *query.PublishedTo)
+		}
+		if query.Available != nil {
+			if *query.Available {
+				db = db.Where("copies > 0")
+			} else {
+				db = db.Where("copies = 0")
+			}
+		}
+		if query.Subject != nil {
+			db = db.Where(`id IN (SELECT book_id FROM book_subjects WHERE subject_id IN (
+				WITH RECURSIVE below AS (
+					SELECT id FROM subjects WHERE id = ?
+					UNION
+					SELECT s.id FROM subjects s JOIN below ON s.parent_id = below.id
+				)
+				SELECT id FROM below
+			))`, *query.Subject)
+		}
+		if query.Dewey != "" {
+			db = db.Where("dewey LIKE ?", escapeLike(query.Dewey)+"%")
+		}
+		if query.LCC != "" {
+			db = db.Where("lcc LIKE ?", escapeLike(query.LCC)+"%")
+		}
+		return db
+	}
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func resolveSort(fields []entities.SortField) ([]orderTerm, error) {
+	terms := make([]orderTerm, 0, len(fields)+1)
+	seen := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		col, ok := sortColumns[f.Field]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown sort field %q", entities.ErrInvalidBookQuery, f.Field)
+		}
+		if seen[f.Field] {
+			return nil, fmt.Errorf("%w: duplicate sort field %q", entities.ErrInvalidBookQuery, f.Field)
+		}
+		seen[f.Field] = true
+		terms = append(terms, orderTerm{sortColumn: col, desc: f.Desc})
+	}
+	return append(terms, orderTerm{sortColumn: idColumn}), nil
+}
+
+func sortKey(fields []entities.SortField) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = f.Field
+		if f.Desc {
+			parts[i] = "-" + f.Field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func orderClause(terms []orderTerm, backward bool) string {
+	parts := make([]string, len(terms))
+	for i, t := range terms {
+		dir := "ASC"
+		if t.desc != backward {
+			dir = "DESC"
+		}
+		parts[i] = t.column + " " + dir
+	}
+	return strings.Join(parts, ", ")
+}
+
+// keysetCondition builds the row comparison selecting everything strictly
+// after (or before, when backward) the cursor position. Sort directions may
+// be mixed, so it is expanded into the (a > x) OR (a = x AND b > y) form.
+func keysetCondition(terms []orderTerm, values []any, backward bool) (string, []any) {
+	var (
+		ors  []string
+		args []any
+	)
+	for i, t := range terms {
+		ands := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, terms[j].column+" = ?")
+			args = append(args, values[j])
+		}
+		op := ">"
+		if t.desc != backward {
+			op = "<"
+		}
+		ands = append(ands, t.column+" "+op+" ?")
+		args = append(args, values[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+func encodeCursor(dir, key string, terms []orderTerm, b *entities.Book) string {
+	c := cursor{Dir: dir, Sort: key, Values: make([]json.RawMessage, len(terms))}
+	for i, t := range terms {
+		c.Values[i], _ = json.Marshal(t.value(b))
+	}
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s, key string, terms []orderTerm) (*cursor, []any, error) {
+	invalid := fmt.Errorf("%w: malformed cursor", entities.ErrInvalidBookQuery)
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, nil, invalid
+	}
+
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, nil, invalid
+	}
+	if c.Dir != cursorNext && c.Dir != cursorPrev || len(c.Values) != len(terms) {
+		return nil, nil, invalid
+	}
+	if c.Sort != key {
+		return nil, nil, fmt.Errorf("%w: cursor was issued for a different sort order", entities.ErrInvalidBookQuery)
+	}
+
+	values := make([]any, len(terms))
+	for i, t := range terms {
+		var err error
+		switch t.kind {
+		case kindString:
+			var v string
+			err = json.Unmarshal(c.Values[i], &v)
+			values[i] = v
+		case kindTime:
+			var v time.Time
+			err = json.Unmarshal(c.Values[i], &v)
+			values[i] = v
+		case kindInt:
+			var v int
+			err = json.Unmarshal(c.Values[i], &v)
+			values[i] = v
+		case kindUUID:
+			var v uuid.UUID
+			err = json.Unmarshal(c.Values[i], &v)
+			values[i] = v
+		}
+		if err != nil {
+			return nil, nil, invalid
+		}
+	}
+
+	return &c, values, nil
+}
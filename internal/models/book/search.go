@@ -0,0 +1,114 @@
+package book
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"library-system/internal/entities"
+
+	"gorm.io/gorm"
+)
+
+const (
+	headlineOptions = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	snippetOptions  = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2"
+)
+
+// highlights selects the title and description of a book as HTML, escaped
+// so that the only markup is the <mark> tags around words of the tsquery
+// the placeholders take. Both search paths return them in this format.
+var highlights = "ts_headline('english', " + escapedHTML("title") + ", %[1]s, ?) AS title_highlight, " +
+	"ts_headline('english', " + escapedHTML("description") + ", %[1]s, ?) AS snippet"
+
+// escapedHTML escapes the text of a column for use as HTML
+func escapedHTML(column string) string {
+	return "replace(replace(replace(" + column + ", '&', '&amp;'), '<', '&lt;'), '>', '&gt;')"
+}
+
+func (b *book) Search(ctx context.Context, query *entities.BookSearchQuery) (*entities.BookSearchResult, error) {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = entities.DefaultPageSize
+	}
+	offset := 0
+	if query.Page > 1 {
+		offset = (query.Page - 1) * limit
+	}
+
+	result := &entities.BookSearchResult{}
+
+	tsq := prefixTSQuery(query.Q)
+	if tsq != "" {
+		fullText := func() *gorm.DB {
+			return b.db.Model(&entities.Book{}).
+				Joins("CROSS JOIN to_tsquery('english', ?) AS query", tsq).
+				Where("search_vector @@ query")
+		}
+
+		if err := fullText().Count(&result.Total).Error; err != nil {
+			return nil, err
+		}
+
+		if result.Total > 0 {
+			err := fullText().
+				Select("books.*, ts_rank_cd(search_vector, query) AS rank, "+fmt.Sprintf(highlights, "query"),
+					headlineOptions, snippetOptions).
+				Order("rank DESC, books.id").
+				Limit(limit).Offset(offset).
+				Scan(&result.Hits).Error
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+	}
+
+	// Nothing matched the lexemes, likely a typo: fall back to trigram
+	// similarity on the short fields
+	q := strings.TrimSpace(query.Q)
+	fuzzy := func() *gorm.DB {
+		return b.db.Model(&entities.Book{}).
+			Where("? <% title OR ? <% author OR ? <% publisher", q, q, q)
+	}
+
+	if err := fuzzy().Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+	result.Fuzzy = true
+
+	if result.Total == 0 {
+		return result, nil
+	}
+
+	// The words of the query are still highlighted where they are spelt
+	// right
+	err := fuzzy().
+		Select("books.*, greatest(word_similarity(?, title), word_similarity(?, author), word_similarity(?, publisher)) AS rank, "+
+			fmt.Sprintf(highlights, "plainto_tsquery('english', ?)"),
+			q, q, q, q, headlineOptions, q, snippetOptions).
+		Order("rank DESC, books.id").
+		Limit(limit).Offset(offset).
+		Scan(&result.Hits).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// prefixTSQuery turns free text into a to_tsquery expression where every
+// word must match as a prefix, so partially typed words still hit
+func prefixTSQuery(q string) string {
+	words := strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	for i := range words {
+		words[i] = strings.ToLower(words[i]) + ":*"
+	}
+	return strings.Join(words, " & ")
+}
@@ -0,0 +1,326 @@
+package hold
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// queueOrder is the order holds on a book are served in
+const queueOrder = "priority DESC, queued_at, id"
+
+type Hold interface {
+	Place(ctx context.Context, hold *entities.Hold) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Hold, error)
+	List(ctx context.Context, query *entities.HoldQuery) ([]entities.Hold, error)
+	Position(ctx context.Context, hold *entities.Hold) (int, error)
+	SetPriority(ctx context.Context, id uuid.UUID, priority int) (*entities.Hold, error)
+	Cancel(ctx context.Context, id uuid.UUID, cancelledAt time.Time) (*entities.Hold, error)
+	Allocate(ctx context.Context, bookID uuid.UUID, readyAt, expiresAt time.Time) ([]entities.Hold, error)
+	Expire(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+}
+
+type hold struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Hold {
+	return &hold{db: db}
+}
+
+// Place queues a hold on a book that has no copy on the shelf. The book row
+// is locked so the hold cannot slip in between an allocation and its check.
+func (h *hold) Place(ctx context.Context, hold *entities.Hold) error {
+	hold.ID, _ = uuid.NewV4()
+	hold.CreatedAt = time.Now()
+	hold.UpdatedAt = time.Now()
+	hold.QueuedAt = hold.CreatedAt
+	hold.Status = enums.HoldWaiting
+
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		book, err := lockBook(tx, hold.BookID)
+		if err != nil {
+			return err
+		}
+		if book.Copies > 0 {
+			return entities.ErrCopiesAvailable
+		}
+
+		result := tx.Create(hold)
+		switch {
+		case errors.Is(result.Error, gorm.ErrDuplicatedKey):
+			return entities.ErrHoldExists
+		case errors.Is(result.Error, gorm.ErrForeignKeyViolated):
+			return entities.ErrUserNotFound
+		}
+		return result.Error
+	})
+}
+
+func (h *hold) GetByID(ctx context.Context, id uuid.UUID) (*entities.Hold, error) {
+	var hold entities.Hold
+	result := h.db.Where("id = ?", id).First(&hold)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrHoldNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &hold, nil
+}
+
+// List returns matching holds. Holds on a single book come in queue order,
+// otherwise the most recent first.
+func (h *hold) List(ctx context.Context, query *entities.HoldQuery) ([]entities.Hold, error) {
+	tx := h.db
+	if query.BookID != nil {
+		tx = tx.Where("book_id = ?", *query.BookID).Order(queueOrder)
+	} else {
+		tx = tx.Order("queued_at DESC")
+	}
+	if query.UserID != nil {
+		tx = tx.Where("user_id = ?", *query.UserID)
+	}
+	if query.ActiveOnly {
+		tx = tx.Where("status IN ?", []enums.HoldStatus{enums.HoldWaiting, enums.HoldReady})
+	}
+
+	var holds []entities.Hold
+	if err := tx.Find(&holds).Error; err != nil {
+		return nil, err
+	}
+
+	return holds, nil
+}
+
+// Position is the 1-based place of a waiting hold in its book's queue
+func (h *hold) Position(ctx context.Context, hold *entities.Hold) (int, error) {
+	var ahead int64
+	err := h.db.Model(&entities.Hold{}).
+		Where("book_id = ? AND status = ?", hold.BookID, enums.HoldWaiting).
+		Where("priority > ? OR (priority = ? AND (queued_at, id) < (?, ?))",
+			hold.Priority, hold.Priority, hold.QueuedAt, hold.ID).
+		Count(&ahead).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return int(ahead) + 1, nil
+}
+
+// SetPriority changes where a waiting hold sits in the queue
+func (h *hold) SetPriority(ctx context.Context, id uuid.UUID, priority int) (*entities.Hold, error) {
+	var hold entities.Hold
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockHold(tx, id, &hold); err != nil {
+			return err
+		}
+		if hold.Status != enums.HoldWaiting {
+			return entities.ErrHoldNotWaiting
+		}
+
+		hold.Priority = priority
+		hold.UpdatedAt = time.Now()
+		return tx.Model(&hold).Updates(map[string]interface{}{
+			"priority":   hold.Priority,
+			"updated_at": hold.UpdatedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// Cancel withdraws an active hold. A copy set aside for it goes back on the
+// shelf, ready to be allocated to the next hold.
+func (h *hold) Cancel(ctx context.Context, id uuid.UUID, cancelledAt time.Time) (*entities.Hold, error) {
+	var hold entities.Hold
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockHold(tx, id, &hold); err != nil {
+			return err
+		}
+		if !hold.Status.IsActive() {
+			return entities.ErrHoldClosed
+		}
+
+		wasReady := hold.Status == enums.HoldReady
+		if err := closeHold(tx, &hold, enums.HoldCancelled, cancelledAt); err != nil {
+			return err
+		}
+		if !wasReady || hold.ItemID == nil {
+			return nil
+		}
+
+		return shelveItems(tx, []uuid.UUID{*hold.ItemID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// Allocate sets aside the book's items on the shelf for the holds at the
+// head of its queue, one item per hold, and returns the holds now ready for
+// pickup. Items being checked out concurrently are skipped.
+func (h *hold) Allocate(ctx context.Context, bookID uuid.UUID, readyAt, expiresAt time.Time) ([]entities.Hold, error) {
+	var allocated []entities.Hold
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := lockBook(tx, bookID); err != nil {
+			return err
+		}
+
+		var waiting []entities.Hold
+		err := tx.Where("book_id = ? AND status = ?", bookID, enums.HoldWaiting).
+			Order(queueOrder).
+			Find(&waiting).Error
+		if err != nil || len(waiting) == 0 {
+			return err
+		}
+
+		var items []entities.Item
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("book_id = ? AND status = ?", bookID, enums.ItemAvailable).
+			Order("created_at, id").
+			Limit(len(waiting)).
+			Find(&items).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for i := range items {
+			hold := waiting[i]
+			hold.Status = enums.HoldReady
+			hold.ItemID = &items[i].ID
+			hold.ReadyAt = &readyAt
+			hold.ExpiresAt = &expiresAt
+			hold.UpdatedAt = now
+
+			err := tx.Model(&hold).Updates(map[string]interface{}{
+				"status":     hold.Status,
+				"item_id":    hold.ItemID,
+				"ready_at":   hold.ReadyAt,
+				"expires_at": hold.ExpiresAt,
+				"updated_at": hold.UpdatedAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Model(&items[i]).Updates(map[string]interface{}{
+				"status":     enums.ItemOnHold,
+				"updated_at": now,
+			}).Error
+			if err != nil {
+				return err
+			}
+
+			allocated = append(allocated, hold)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return allocated, nil
+}
+
+// Expire closes ready holds whose pickup window has lapsed and puts their
+// items back on the shelf. It returns the books that got copies back.
+func (h *hold) Expire(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	var books []uuid.UUID
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var lapsed []entities.Hold
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND expires_at < ?", enums.HoldReady, now).
+			Find(&lapsed).Error
+		if err != nil {
+			return err
+		}
+
+		var items []uuid.UUID
+		returned := make(map[uuid.UUID]bool)
+		for i := range lapsed {
+			if err := closeHold(tx, &lapsed[i], enums.HoldExpired, now); err != nil {
+				return err
+			}
+			if lapsed[i].ItemID != nil {
+				items = append(items, *lapsed[i].ItemID)
+			}
+			if !returned[lapsed[i].BookID] {
+				returned[lapsed[i].BookID] = true
+				books = append(books, lapsed[i].BookID)
+			}
+		}
+
+		if len(items) == 0 {
+			return nil
+		}
+		return shelveItems(tx, items)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+func lockBook(tx *gorm.DB, id uuid.UUID) (*entities.Book, error) {
+	var book entities.Book
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&book)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrBookNotFound
+		}
+		return nil, result.Error
+	}
+	return &book, nil
+}
+
+func lockHold(tx *gorm.DB, id uuid.UUID, hold *entities.Hold) error {
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(hold)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return entities.ErrHoldNotFound
+		}
+		return result.Error
+	}
+	return nil
+}
+
+func closeHold(tx *gorm.DB, hold *entities.Hold, status enums.HoldStatus, at time.Time) error {
+	hold.Status = status
+	hold.ClosedAt = &at
+	hold.UpdatedAt = time.Now()
+	return tx.Model(hold).Updates(map[string]interface{}{
+		"status":     hold.Status,
+		"closed_at":  hold.ClosedAt,
+		"updated_at": hold.UpdatedAt,
+	}).Error
+}
+
+// shelveItems puts items set aside for holds back on the shelf
+func shelveItems(tx *gorm.DB, ids []uuid.UUID) error {
+	return tx.Model(&entities.Item{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"status":     enums.ItemAvailable,
+		"updated_at": time.Now(),
+	}).Error
+}
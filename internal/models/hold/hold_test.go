@@ -0,0 +1,203 @@
+package hold
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func NewMock() (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening gorm stub database connection", err)
+	}
+	return gormDB, db, mock
+}
+
+var lockBookQuery = regexp.QuoteMeta(`SELECT * FROM "books" WHERE id = $1 AND "books"."deleted_at" IS NULL ORDER BY "books"."id" LIMIT $2 FOR UPDATE`)
+
+func Test_hold_Place(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	bookID, _ := uuid.NewV4()
+	userID, _ := uuid.NewV4()
+	insert := regexp.QuoteMeta(`INSERT INTO "holds"`)
+	book := func(copies int) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "copies"}).AddRow(bookID, copies)
+	}
+
+	// Nothing on the shelf
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockBookQuery).WithArgs(bookID, 1).WillReturnRows(book(0))
+	mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Copies left to borrow
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockBookQuery).WithArgs(bookID, 1).WillReturnRows(book(2))
+	mock.ExpectRollback()
+
+	// Second active hold by the same member
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockBookQuery).WithArgs(bookID, 1).WillReturnRows(book(0))
+	mock.ExpectExec(insert).WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	// Unknown book
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockBookQuery).WithArgs(bookID, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "no copies"},
+		{name: "copies available", wantErr: entities.ErrCopiesAvailable},
+		{name: "already on hold", wantErr: entities.ErrHoldExists},
+		{name: "unknown book", wantErr: entities.ErrBookNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &hold{db: gdb}
+			hold := &entities.Hold{BookID: bookID, UserID: userID}
+			err := h.Place(context.Background(), hold)
+			if err != tt.wantErr {
+				t.Errorf("hold.Place() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (hold.Status != enums.HoldWaiting || hold.QueuedAt.IsZero()) {
+				t.Errorf("hold.Place() = %+v", hold)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_hold_Allocate(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	bookID, _ := uuid.NewV4()
+	first, _ := uuid.NewV4()
+	second, _ := uuid.NewV4()
+	itemID, _ := uuid.NewV4()
+	readyAt := time.Now()
+	expiresAt := readyAt.AddDate(0, 0, 3)
+
+	// Two members waiting, one copy on the shelf
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockBookQuery).WithArgs(bookID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "copies"}).AddRow(bookID, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "holds" WHERE book_id = $1 AND status = $2 ORDER BY priority DESC, queued_at, id`)).
+		WithArgs(bookID, enums.HoldWaiting).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}).
+			AddRow(first, bookID, enums.HoldWaiting).
+			AddRow(second, bookID, enums.HoldWaiting))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "items" WHERE book_id = $1 AND status = $2 ORDER BY created_at, id LIMIT $3 FOR UPDATE SKIP LOCKED`)).
+		WithArgs(bookID, enums.ItemAvailable, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "status"}).AddRow(itemID, bookID, enums.ItemAvailable))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "holds" SET "expires_at"=$1,"item_id"=$2,"ready_at"=$3,"status"=$4,"updated_at"=$5 WHERE "id" = $6`)).
+		WithArgs(expiresAt, itemID, readyAt, enums.HoldReady, sqlmock.AnyArg(), first).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "items" SET "status"=$1,"updated_at"=$2 WHERE "id" = $3`)).
+		WithArgs(enums.ItemOnHold, sqlmock.AnyArg(), itemID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	h := &hold{db: gdb}
+	got, err := h.Allocate(context.Background(), bookID, readyAt, expiresAt)
+	if err != nil {
+		t.Fatalf("hold.Allocate() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != first || got[0].Status != enums.HoldReady || *got[0].ItemID != itemID || !got[0].ExpiresAt.Equal(expiresAt) {
+		t.Errorf("hold.Allocate() = %+v", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_hold_Cancel(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	bookID, _ := uuid.NewV4()
+	itemID, _ := uuid.NewV4()
+	cancelledAt := time.Now()
+
+	lock := regexp.QuoteMeta(`SELECT * FROM "holds" WHERE id = $1 ORDER BY "holds"."id" LIMIT $2 FOR UPDATE`)
+	closeHold := regexp.QuoteMeta(`UPDATE "holds" SET "closed_at"=$1,"status"=$2,"updated_at"=$3 WHERE "id" = $4`)
+	columns := []string{"id", "book_id", "status", "item_id"}
+
+	// Waiting in the queue
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow(id, bookID, enums.HoldWaiting, nil))
+	mock.ExpectExec(closeHold).WithArgs(cancelledAt, enums.HoldCancelled, sqlmock.AnyArg(), id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Copy on the hold shelf goes back
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow(id, bookID, enums.HoldReady, itemID))
+	mock.ExpectExec(closeHold).WithArgs(cancelledAt, enums.HoldCancelled, sqlmock.AnyArg(), id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "items" SET "status"=$1,"updated_at"=$2 WHERE id IN ($3)`)).
+		WithArgs(enums.ItemAvailable, sqlmock.AnyArg(), itemID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Already picked up
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow(id, bookID, enums.HoldFulfilled, itemID))
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "waiting"},
+		{name: "ready"},
+		{name: "fulfilled", wantErr: entities.ErrHoldClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &hold{db: gdb}
+			got, err := h.Cancel(context.Background(), id, cancelledAt)
+			if err != tt.wantErr {
+				t.Errorf("hold.Cancel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Status != enums.HoldCancelled {
+				t.Errorf("hold.Cancel() status = %v", got.Status)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,259 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "library-system/internal/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+// Hold is an autogenerated mock type for the Hold type
+type Hold struct {
+	mock.Mock
+}
+
+// Allocate provides a mock function with given fields: ctx, bookID, readyAt, expiresAt
+func (_m *Hold) Allocate(ctx context.Context, bookID uuid.UUID, readyAt time.Time, expiresAt time.Time) ([]entities.Hold, error) {
+	ret := _m.Called(ctx, bookID, readyAt, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Allocate")
+	}
+
+	var r0 []entities.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Time) ([]entities.Hold, error)); ok {
+		return rf(ctx, bookID, readyAt, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, time.Time) []entities.Hold); ok {
+		r0 = rf(ctx, bookID, readyAt, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time, time.Time) error); ok {
+		r1 = rf(ctx, bookID, readyAt, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Cancel provides a mock function with given fields: ctx, id, cancelledAt
+func (_m *Hold) Cancel(ctx context.Context, id uuid.UUID, cancelledAt time.Time) (*entities.Hold, error) {
+	ret := _m.Called(ctx, id, cancelledAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cancel")
+	}
+
+	var r0 *entities.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) (*entities.Hold, error)); ok {
+		return rf(ctx, id, cancelledAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) *entities.Hold); ok {
+		r0 = rf(ctx, id, cancelledAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r1 = rf(ctx, id, cancelledAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Expire provides a mock function with given fields: ctx, now
+func (_m *Hold) Expire(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Expire")
+	}
+
+	var r0 []uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]uuid.UUID, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []uuid.UUID); ok {
+		r0 = rf(ctx, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Hold) GetByID(ctx context.Context, id uuid.UUID) (*entities.Hold, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entities.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.Hold, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.Hold); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, query
+func (_m *Hold) List(ctx context.Context, query *entities.HoldQuery) ([]entities.Hold, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []entities.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.HoldQuery) ([]entities.Hold, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.HoldQuery) []entities.Hold); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.HoldQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Place provides a mock function with given fields: ctx, _a1
+func (_m *Hold) Place(ctx context.Context, _a1 *entities.Hold) error {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Place")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Hold) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Position provides a mock function with given fields: ctx, _a1
+func (_m *Hold) Position(ctx context.Context, _a1 *entities.Hold) (int, error) {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Position")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Hold) (int, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Hold) int); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.Hold) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPriority provides a mock function with given fields: ctx, id, priority
+func (_m *Hold) SetPriority(ctx context.Context, id uuid.UUID, priority int) (*entities.Hold, error) {
+	ret := _m.Called(ctx, id, priority)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPriority")
+	}
+
+	var r0 *entities.Hold
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) (*entities.Hold, error)); ok {
+		return rf(ctx, id, priority)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int) *entities.Hold); ok {
+		r0 = rf(ctx, id, priority)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int) error); ok {
+		r1 = rf(ctx, id, priority)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewHold creates a new instance of Hold. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHold(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Hold {
+	mock := &Hold{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,146 @@
+package importjob
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type ImportJob interface {
+	Create(ctx context.Context, job *entities.ImportJob) error
+	GetByID(ctx context.Context, id uuid.UUID, outcome enums.ImportOutcome) (*entities.ImportJob, error)
+	Start(ctx context.Context, id uuid.UUID) error
+	Record(ctx context.Context, id uuid.UUID, rows []entities.ImportRow) error
+	Heartbeat(ctx context.Context, id uuid.UUID) error
+	Finish(ctx context.Context, id uuid.UUID, status enums.ImportStatus, message string) error
+	FailStale(ctx context.Context, before time.Time, message string) (int64, error)
+}
+
+type importJob struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) ImportJob {
+	return &importJob{db: db}
+}
+
+func (j *importJob) Create(ctx context.Context, job *entities.ImportJob) error {
+	job.ID, _ = uuid.NewV4()
+	job.CreatedAt = time.Now()
+	job.UpdatedAt = job.CreatedAt
+	job.Status = enums.ImportQueued
+
+	return j.db.Omit("Rows").Create(job).Error
+}
+
+// GetByID returns a job with its row reports in row order, only those with
+// the given outcome when one is set
+func (j *importJob) GetByID(ctx context.Context, id uuid.UUID, outcome enums.ImportOutcome) (*entities.ImportJob, error) {
+	var job entities.ImportJob
+	result := j.db.Preload("Rows", func(db *gorm.DB) *gorm.DB {
+		if outcome != "" {
+			db = db.Where("outcome = ?", outcome)
+		}
+		return db.Order("row")
+	}).Where("id = ?", id).First(&job)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrImportJobNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &job, nil
+}
+
+// Start moves a queued job to running, with its first heartbeat
+func (j *importJob) Start(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	return j.whileIn(id, enums.ImportQueued, map[string]interface{}{
+		"status":       enums.ImportRunning,
+		"heartbeat_at": now,
+		"updated_at":   now,
+	})
+}
+
+// Heartbeat shows the running job is still being worked on. A job that is
+// no longer running, such as one failed as stale, fails with
+// ErrImportJobNotRunning.
+func (j *importJob) Heartbeat(ctx context.Context, id uuid.UUID) error {
+	return j.whileIn(id, enums.ImportRunning, map[string]interface{}{"heartbeat_at": time.Now()})
+}
+
+// Record stores the reports of a committed batch and adds them to the
+// job's counts
+func (j *importJob) Record(ctx context.Context, id uuid.UUID, rows []entities.ImportRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	counts := make(map[enums.ImportOutcome]int)
+	for i := range rows {
+		rows[i].JobID = id
+		counts[rows[i].Outcome]++
+	}
+
+	return j.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entities.ImportJob{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"created":    gorm.Expr("created + ?", counts[enums.ImportCreated]),
+			"rejected":   gorm.Expr("rejected + ?", counts[enums.ImportRejected]),
+			"updated":    gorm.Expr("updated + ?", counts[enums.ImportUpdated]),
+			"updated_at": time.Now(),
+		}).Error
+	})
+}
+
+// Finish closes the running job as done, or as failed with the reason it
+// stopped. A job already closed, such as one failed as stale, is left as it
+// is with ErrImportJobNotRunning.
+func (j *importJob) Finish(ctx context.Context, id uuid.UUID, status enums.ImportStatus, message string) error {
+	now := time.Now()
+	return j.whileIn(id, enums.ImportRunning, map[string]interface{}{
+		"status":      status,
+		"error":       message,
+		"finished_at": now,
+		"updated_at":  now,
+	})
+}
+
+// FailStale closes as failed, with the message, the running jobs whose
+// heartbeat is older than before and the jobs queued since before then,
+// whose server is gone. It returns how many there were.
+func (j *importJob) FailStale(ctx context.Context, before time.Time, message string) (int64, error) {
+	now := time.Now()
+	result := j.db.Model(&entities.ImportJob{}).
+		Where("(status = ? AND heartbeat_at < ?) OR (status = ? AND updated_at < ?)",
+			enums.ImportRunning, before, enums.ImportQueued, before).
+		Updates(map[string]interface{}{
+			"status":      enums.ImportFailed,
+			"error":       message,
+			"finished_at": now,
+			"updated_at":  now,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// whileIn updates the job only while it has the given status
+func (j *importJob) whileIn(id uuid.UUID, status enums.ImportStatus, updates map[string]interface{}) error {
+	result := j.db.Model(&entities.ImportJob{}).Where("id = ? AND status = ?", id, status).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrImportJobNotRunning
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package importjob
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofrs/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func NewMock() (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening gorm stub database connection", err)
+	}
+	return gormDB, db, mock
+}
+
+func Test_importJob_Record(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "import_rows" ("job_id","row","isbn","book_id","outcome","reason") VALUES ($1,$2,$3,$4,$5,$6),($7,$8,$9,$10,$11,$12),($13,$14,$15,$16,$17,$18)`)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "import_jobs" SET "created"=created + $1,"rejected"=rejected + $2,"updated"=updated + $3,"updated_at"=$4 WHERE id = $5`)).
+		WithArgs(2, 1, 0, sqlmock.AnyArg(), id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	rows := []entities.ImportRow{
+		{Row: 1, ISBN: "9780441013593", Outcome: enums.ImportCreated},
+		{Row: 2, Outcome: enums.ImportRejected, Reason: "title is required"},
+		{Row: 3, ISBN: "9780141439587", Outcome: enums.ImportCreated},
+	}
+
+	j := &importJob{db: gdb}
+	if err := j.Record(context.Background(), id, rows); err != nil {
+		t.Fatalf("importJob.Record() error = %v", err)
+	}
+	for _, row := range rows {
+		if row.JobID != id {
+			t.Errorf("row %d job = %s, want %s", row.Row, row.JobID, id)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_importJob_GetByID(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "import_jobs" WHERE id = $1 ORDER BY "import_jobs"."id" LIMIT $2`)).
+		WithArgs(id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "rejected"}).AddRow(id, enums.ImportDone, 1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "import_rows" WHERE "import_rows"."job_id" = $1 AND outcome = $2 ORDER BY row`)).
+		WithArgs(id, enums.ImportRejected).
+		WillReturnRows(sqlmock.NewRows([]string{"job_id", "row", "outcome", "reason"}).AddRow(id, 2, enums.ImportRejected, "title is required"))
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "import_jobs" WHERE id = $1 ORDER BY "import_jobs"."id" LIMIT $2`)).
+		WithArgs(id, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	j := &importJob{db: gdb}
+	got, err := j.GetByID(context.Background(), id, enums.ImportRejected)
+	if err != nil || got.Status != enums.ImportDone || len(got.Rows) != 1 || got.Rows[0].Row != 2 {
+		t.Errorf("importJob.GetByID() = %+v, %v", got, err)
+	}
+	if _, err := j.GetByID(context.Background(), id, ""); err != entities.ErrImportJobNotFound {
+		t.Errorf("importJob.GetByID() error = %v, want %v", err, entities.ErrImportJobNotFound)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_importJob_FailStale(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	before := time.Now().Add(-time.Minute)
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "import_jobs" SET "error"=$1,"finished_at"=$2,"status"=$3,"updated_at"=$4 WHERE (status = $5 AND heartbeat_at < $6) OR (status = $7 AND updated_at < $8)`)).
+		WithArgs("interrupted", sqlmock.AnyArg(), enums.ImportFailed, sqlmock.AnyArg(), enums.ImportRunning, before, enums.ImportQueued, before).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	j := &importJob{db: gdb}
+	failed, err := j.FailStale(context.Background(), before, "interrupted")
+	if err != nil || failed != 2 {
+		t.Errorf("importJob.FailStale() = %d, %v, want 2", failed, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_importJob_Finish(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	finish := regexp.QuoteMeta(`UPDATE "import_jobs" SET "error"=$1,"finished_at"=$2,"status"=$3,"updated_at"=$4 WHERE id = $5 AND status = $6`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(finish).WithArgs("", sqlmock.AnyArg(), enums.ImportDone, sqlmock.AnyArg(), id, enums.ImportRunning).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// The job was failed as stale in the meantime and stays failed
+	mock.ExpectBegin()
+	mock.ExpectExec(finish).WithArgs("", sqlmock.AnyArg(), enums.ImportDone, sqlmock.AnyArg(), id, enums.ImportRunning).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	j := &importJob{db: gdb}
+	if err := j.Finish(context.Background(), id, enums.ImportDone, ""); err != nil {
+		t.Errorf("importJob.Finish() error = %v", err)
+	}
+	if err := j.Finish(context.Background(), id, enums.ImportDone, ""); err != entities.ErrImportJobNotRunning {
+		t.Errorf("importJob.Finish() of a failed job error = %v, want %v", err, entities.ErrImportJobNotRunning)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
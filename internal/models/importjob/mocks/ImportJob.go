@@ -0,0 +1,182 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "library-system/internal/entities"
+	enums "library-system/internal/entities/enums"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+// ImportJob is an autogenerated mock type for the ImportJob type
+type ImportJob struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, job
+func (_m *ImportJob) Create(ctx context.Context, job *entities.ImportJob) error {
+	ret := _m.Called(ctx, job)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.ImportJob) error); ok {
+		r0 = rf(ctx, job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FailStale provides a mock function with given fields: ctx, before, message
+func (_m *ImportJob) FailStale(ctx context.Context, before time.Time, message string) (int64, error) {
+	ret := _m.Called(ctx, before, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailStale")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string) (int64, error)); ok {
+		return rf(ctx, before, message)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, string) int64); ok {
+		r0 = rf(ctx, before, message)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, string) error); ok {
+		r1 = rf(ctx, before, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Finish provides a mock function with given fields: ctx, id, status, message
+func (_m *ImportJob) Finish(ctx context.Context, id uuid.UUID, status enums.ImportStatus, message string) error {
+	ret := _m.Called(ctx, id, status, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Finish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, enums.ImportStatus, string) error); ok {
+		r0 = rf(ctx, id, status, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByID provides a mock function with given fields: ctx, id, outcome
+func (_m *ImportJob) GetByID(ctx context.Context, id uuid.UUID, outcome enums.ImportOutcome) (*entities.ImportJob, error) {
+	ret := _m.Called(ctx, id, outcome)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entities.ImportJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, enums.ImportOutcome) (*entities.ImportJob, error)); ok {
+		return rf(ctx, id, outcome)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, enums.ImportOutcome) *entities.ImportJob); ok {
+		r0 = rf(ctx, id, outcome)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.ImportJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, enums.ImportOutcome) error); ok {
+		r1 = rf(ctx, id, outcome)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Heartbeat provides a mock function with given fields: ctx, id
+func (_m *ImportJob) Heartbeat(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Heartbeat")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Record provides a mock function with given fields: ctx, id, rows
+func (_m *ImportJob) Record(ctx context.Context, id uuid.UUID, rows []entities.ImportRow) error {
+	ret := _m.Called(ctx, id, rows)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []entities.ImportRow) error); ok {
+		r0 = rf(ctx, id, rows)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Start provides a mock function with given fields: ctx, id
+func (_m *ImportJob) Start(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewImportJob creates a new instance of ImportJob. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewImportJob(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ImportJob {
+	mock := &ImportJob{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
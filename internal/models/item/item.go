@@ -0,0 +1,192 @@
+package item
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Item interface {
+	Create(ctx context.Context, item *entities.Item) error
+	GetByID(ctx context.Context, bookID, id uuid.UUID) (*entities.Item, error)
+	List(ctx context.Context, bookID uuid.UUID) ([]entities.Item, error)
+	Update(ctx context.Context, item *entities.Item) error
+	Delete(ctx context.Context, bookID, id uuid.UUID) error
+	SetShelfCount(ctx context.Context, bookID uuid.UUID, count int) error
+}
+
+type item struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Item {
+	return &item{db: db}
+}
+
+// NewShelfItem returns an available copy of a book with a generated
+// barcode, for copies added by count rather than catalogued one by one
+func NewShelfItem(bookID uuid.UUID, acquiredAt time.Time) entities.Item {
+	id, _ := uuid.NewV4()
+	return entities.Item{
+		ID:         id,
+		CreatedAt:  acquiredAt,
+		UpdatedAt:  acquiredAt,
+		BookID:     bookID,
+		Barcode:    GeneratedBarcode(id),
+		Condition:  enums.ConditionGood,
+		AcquiredAt: acquiredAt,
+		Status:     enums.ItemAvailable,
+	}
+}
+
+// GeneratedBarcode derives a barcode from the item ID. The same scheme is
+// used when backfilling items for existing books.
+func GeneratedBarcode(id uuid.UUID) string {
+	return "AUTO-" + strings.ToUpper(strings.ReplaceAll(id.String(), "-", "")[:12])
+}
+
+func (i *item) Create(ctx context.Context, item *entities.Item) error {
+	item.ID, _ = uuid.NewV4()
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = time.Now()
+
+	result := i.db.Create(item)
+	switch {
+	case errors.Is(result.Error, gorm.ErrDuplicatedKey):
+		return entities.ErrBarcodeTaken
+	case errors.Is(result.Error, gorm.ErrForeignKeyViolated):
+		return entities.ErrBookNotFound
+	}
+	return result.Error
+}
+
+func (i *item) GetByID(ctx context.Context, bookID, id uuid.UUID) (*entities.Item, error) {
+	var item entities.Item
+	result := i.db.Where("id = ? AND book_id = ?", id, bookID).First(&item)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrItemNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &item, nil
+}
+
+// List returns the items of a book in barcode order
+func (i *item) List(ctx context.Context, bookID uuid.UUID) ([]entities.Item, error) {
+	var items []entities.Item
+	if err := i.db.Where("book_id = ?", bookID).Order("barcode").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Update saves an item's details. Items on loan or on hold keep their
+// status, which only circulation changes.
+func (i *item) Update(ctx context.Context, item *entities.Item) error {
+	item.UpdatedAt = time.Now()
+
+	return i.db.Transaction(func(tx *gorm.DB) error {
+		var current entities.Item
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND book_id = ?", item.ID, item.BookID).
+			First(&current)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return entities.ErrItemNotFound
+			}
+			return result.Error
+		}
+
+		if current.Status.InCirculation() && item.Status != current.Status {
+			return entities.ErrItemInCirculation
+		}
+
+		result = tx.Model(item).Select("barcode", "condition", "shelf_location", "acquired_at", "price", "status", "updated_at").Updates(item)
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return entities.ErrBarcodeTaken
+		}
+		return result.Error
+	})
+}
+
+// Delete removes an item that is not in circulation, such as one added by
+// mistake. Items that loans or holds refer to must be withdrawn instead.
+func (i *item) Delete(ctx context.Context, bookID, id uuid.UUID) error {
+	result := i.db.
+		Where("id = ? AND book_id = ? AND status NOT IN ?", id, bookID, []enums.ItemStatus{enums.ItemOnLoan, enums.ItemOnHold}).
+		Delete(&entities.Item{})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return entities.ErrItemHasHistory
+		}
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		if _, err := i.GetByID(ctx, bookID, id); err != nil {
+			return err
+		}
+		return entities.ErrItemInCirculation
+	}
+
+	return nil
+}
+
+// SetShelfCount adds or withdraws available items until the book has count
+// copies on the shelf
+func (i *item) SetShelfCount(ctx context.Context, bookID uuid.UUID, count int) error {
+	return i.db.Transaction(func(tx *gorm.DB) error {
+		return AdjustShelf(tx, bookID, count)
+	})
+}
+
+// AdjustShelf brings the book's available items to count within tx. New
+// items get generated barcodes; the most recently added copies are
+// withdrawn first. Callers hold the book's lock, while circulation locks an
+// item before its copies sync updates the book, so items being lent or
+// changed right now are skipped rather than waited for, which would
+// deadlock; they are not on the shelf to count.
+func AdjustShelf(tx *gorm.DB, bookID uuid.UUID, count int) error {
+	var available []entities.Item
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("book_id = ? AND status = ?", bookID, enums.ItemAvailable).
+		Order("created_at DESC").
+		Find(&available).Error
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(available) < count:
+		now := time.Now()
+		items := make([]entities.Item, count-len(available))
+		for n := range items {
+			items[n] = NewShelfItem(bookID, now)
+		}
+		return tx.Create(&items).Error
+
+	case len(available) > count:
+		ids := make([]uuid.UUID, len(available)-count)
+		for n := range ids {
+			ids[n] = available[n].ID
+		}
+		return tx.Model(&entities.Item{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":     enums.ItemWithdrawn,
+			"updated_at": time.Now(),
+		}).Error
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package item
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func NewMock() (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening gorm stub database connection", err)
+	}
+	return gormDB, db, mock
+}
+
+func TestGeneratedBarcode(t *testing.T) {
+	id := uuid.Must(uuid.FromString("1f82d0bb-3984-48f0-9585-f7cbc818b30d"))
+	if got := GeneratedBarcode(id); got != "AUTO-1F82D0BB3984" {
+		t.Errorf("GeneratedBarcode() = %q", got)
+	}
+}
+
+func Test_item_Create(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	bookID, _ := uuid.NewV4()
+	insert := regexp.QuoteMeta(`INSERT INTO "items" ("id","created_at","updated_at","book_id","barcode","condition","shelf_location","acquired_at","price","status") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).WillReturnError(&pgconn.PgError{Code: "23503"})
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "new barcode"},
+		{name: "barcode taken", wantErr: entities.ErrBarcodeTaken},
+		{name: "unknown book", wantErr: entities.ErrBookNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &item{db: gdb}
+			err := i.Create(context.Background(), &entities.Item{
+				BookID:     bookID,
+				Barcode:    "B1",
+				Condition:  enums.ConditionNew,
+				AcquiredAt: time.Now(),
+				Status:     enums.ItemAvailable,
+			})
+			if err != tt.wantErr {
+				t.Errorf("item.Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_item_Update(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	bookID, _ := uuid.NewV4()
+	id, _ := uuid.NewV4()
+
+	lock := regexp.QuoteMeta(`SELECT * FROM "items" WHERE id = $1 AND book_id = $2 ORDER BY "items"."id" LIMIT $3 FOR UPDATE`)
+	update := regexp.QuoteMeta(`UPDATE "items" SET "updated_at"=$1,"barcode"=$2,"condition"=$3,"shelf_location"=$4,"acquired_at"=$5,"price"=$6,"status"=$7 WHERE "id" = $8`)
+	columns := []string{"id", "book_id", "status"}
+
+	// Sent for repair from the shelf
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, bookID, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow(id, bookID, enums.ItemAvailable))
+	mock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Marked lost while on loan
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, bookID, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow(id, bookID, enums.ItemOnLoan))
+	mock.ExpectRollback()
+
+	// Unknown item
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, bookID, 1).WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		status  enums.ItemStatus
+		wantErr error
+	}{
+		{name: "in repair", status: enums.ItemInRepair},
+		{name: "on loan", status: enums.ItemLost, wantErr: entities.ErrItemInCirculation},
+		{name: "unknown item", status: enums.ItemInRepair, wantErr: entities.ErrItemNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &item{db: gdb}
+			err := i.Update(context.Background(), &entities.Item{ID: id, BookID: bookID, Barcode: "B1", Status: tt.status})
+			if err != tt.wantErr {
+				t.Errorf("item.Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_item_Delete(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	bookID, _ := uuid.NewV4()
+	id, _ := uuid.NewV4()
+
+	del := regexp.QuoteMeta(`DELETE FROM "items" WHERE id = $1 AND book_id = $2 AND status NOT IN ($3,$4)`)
+	get := regexp.QuoteMeta(`SELECT * FROM "items" WHERE id = $1 AND book_id = $2 ORDER BY "items"."id" LIMIT $3`)
+
+	// Added by mistake
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(id, bookID, enums.ItemOnLoan, enums.ItemOnHold).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Lent out
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectQuery(get).WithArgs(id, bookID, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "status"}).AddRow(id, enums.ItemOnLoan))
+
+	// Lent before
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WillReturnError(&pgconn.PgError{Code: "23503"})
+	mock.ExpectRollback()
+
+	// Unknown item
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectQuery(get).WithArgs(id, bookID, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "never circulated"},
+		{name: "on loan", wantErr: entities.ErrItemInCirculation},
+		{name: "has history", wantErr: entities.ErrItemHasHistory},
+		{name: "unknown item", wantErr: entities.ErrItemNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &item{db: gdb}
+			if err := i.Delete(context.Background(), bookID, id); err != tt.wantErr {
+				t.Errorf("item.Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_item_SetShelfCount(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	bookID, _ := uuid.NewV4()
+	newest, _ := uuid.NewV4()
+	oldest, _ := uuid.NewV4()
+
+	shelf := regexp.QuoteMeta(`SELECT * FROM "items" WHERE book_id = $1 AND status = $2 ORDER BY created_at DESC FOR UPDATE SKIP LOCKED`)
+	onShelf := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "book_id", "status"}).
+			AddRow(newest, bookID, enums.ItemAvailable).
+			AddRow(oldest, bookID, enums.ItemAvailable)
+	}
+
+	// Two more copies bought
+	mock.ExpectBegin()
+	mock.ExpectQuery(shelf).WithArgs(bookID, enums.ItemAvailable).WillReturnRows(onShelf())
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "items"`)).WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectCommit()
+
+	// Down to one copy
+	mock.ExpectBegin()
+	mock.ExpectQuery(shelf).WithArgs(bookID, enums.ItemAvailable).WillReturnRows(onShelf())
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "items" SET "status"=$1,"updated_at"=$2 WHERE id IN ($3)`)).
+		WithArgs(enums.ItemWithdrawn, sqlmock.AnyArg(), newest).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Unchanged
+	mock.ExpectBegin()
+	mock.ExpectQuery(shelf).WithArgs(bookID, enums.ItemAvailable).WillReturnRows(onShelf())
+	mock.ExpectCommit()
+
+	i := &item{db: gdb}
+	for _, count := range []int{4, 1, 2} {
+		if err := i.SetShelfCount(context.Background(), bookID, count); err != nil {
+			t.Errorf("item.SetShelfCount(%d) error = %v", count, err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
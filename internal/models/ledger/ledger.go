@@ -0,0 +1,86 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type Ledger interface {
+	Post(ctx context.Context, txn *entities.LedgerTransaction) error
+	Balance(ctx context.Context, memberID uuid.UUID) (int64, error)
+	Transactions(ctx context.Context, memberID uuid.UUID) ([]entities.LedgerTransaction, error)
+}
+
+type ledger struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Ledger {
+	return &ledger{db: db}
+}
+
+// Post writes a balanced transaction and its entries. Postings for a member
+// are serialized with an advisory lock, so a payment or waiver can never
+// take the member's balance below zero, even when two race.
+func (l *ledger) Post(ctx context.Context, txn *entities.LedgerTransaction) error {
+	if !txn.Balanced() {
+		return entities.ErrUnbalancedTransaction
+	}
+
+	return l.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "ledger:"+txn.MemberID.String()).Error; err != nil {
+			return err
+		}
+
+		if change := txn.ReceivableChange(); change < 0 {
+			balance, err := balance(tx, txn.MemberID)
+			if err != nil {
+				return err
+			}
+			if balance+change < 0 {
+				return entities.ErrAmountExceedsBalance
+			}
+		}
+
+		result := tx.Create(txn)
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return entities.ErrUserNotFound
+		}
+		return result.Error
+	})
+}
+
+// Balance is what the member owes: the sum of their receivable entries
+func (l *ledger) Balance(ctx context.Context, memberID uuid.UUID) (int64, error) {
+	return balance(l.db, memberID)
+}
+
+// Transactions lists a member's transactions with their entries, most
+// recent first
+func (l *ledger) Transactions(ctx context.Context, memberID uuid.UUID) ([]entities.LedgerTransaction, error) {
+	var txns []entities.LedgerTransaction
+	err := l.db.Preload("Entries").
+		Where("member_id = ?", memberID).
+		Order("created_at DESC").
+		Find(&txns).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return txns, nil
+}
+
+func balance(db *gorm.DB, memberID uuid.UUID) (int64, error) {
+	var balance int64
+	err := db.Model(&entities.LedgerEntry{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("account = ? AND member_id = ?", enums.AccountReceivable, memberID).
+		Scan(&balance).Error
+	return balance, err
+}
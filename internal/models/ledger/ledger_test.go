@@ -0,0 +1,98 @@
+package ledger
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofrs/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func NewMock() (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening gorm stub database connection", err)
+	}
+	return gormDB, db, mock
+}
+
+// payment builds a payment of amount by the member
+func payment(memberID uuid.UUID, amount int64) *entities.LedgerTransaction {
+	id := uuid.Must(uuid.NewV4())
+	return &entities.LedgerTransaction{
+		ID:       id,
+		MemberID: memberID,
+		Kind:     enums.LedgerPayment,
+		Amount:   amount,
+		Entries: []entities.LedgerEntry{
+			{ID: uuid.Must(uuid.NewV4()), TransactionID: id, Account: enums.AccountCash, Amount: amount},
+			{ID: uuid.Must(uuid.NewV4()), TransactionID: id, Account: enums.AccountReceivable, MemberID: &memberID, Amount: -amount},
+		},
+	}
+}
+
+func Test_ledger_Post(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	memberID, _ := uuid.NewV4()
+
+	lock := regexp.QuoteMeta(`SELECT pg_advisory_xact_lock(hashtext($1))`)
+	balance := regexp.QuoteMeta(`SELECT COALESCE(SUM(amount), 0) FROM "ledger_entries" WHERE account = $1 AND member_id = $2`)
+
+	// Pays part of what is owed
+	mock.ExpectBegin()
+	mock.ExpectExec(lock).WithArgs("ledger:" + memberID.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(balance).WithArgs(enums.AccountReceivable, memberID).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(800))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "ledger_transactions"`)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "ledger_entries"`)).WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectCommit()
+
+	// Pays more than is owed
+	mock.ExpectBegin()
+	mock.ExpectExec(lock).WithArgs("ledger:" + memberID.String()).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(balance).WithArgs(enums.AccountReceivable, memberID).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(800))
+	mock.ExpectRollback()
+
+	unbalanced := payment(memberID, 100)
+	unbalanced.Entries[1].Amount = -90
+
+	tests := []struct {
+		name    string
+		txn     *entities.LedgerTransaction
+		wantErr error
+	}{
+		{name: "partial payment", txn: payment(memberID, 500)},
+		{name: "overpayment", txn: payment(memberID, 900), wantErr: entities.ErrAmountExceedsBalance},
+		{name: "unbalanced", txn: unbalanced, wantErr: entities.ErrUnbalancedTransaction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &ledger{db: gdb}
+			if err := l.Post(context.Background(), tt.txn); err != tt.wantErr {
+				t.Errorf("ledger.Post() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "library-system/internal/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+// Ledger is an autogenerated mock type for the Ledger type
+type Ledger struct {
+	mock.Mock
+}
+
+// Balance provides a mock function with given fields: ctx, memberID
+func (_m *Ledger) Balance(ctx context.Context, memberID uuid.UUID) (int64, error) {
+	ret := _m.Called(ctx, memberID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Balance")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (int64, error)); ok {
+		return rf(ctx, memberID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) int64); ok {
+		r0 = rf(ctx, memberID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, memberID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Post provides a mock function with given fields: ctx, txn
+func (_m *Ledger) Post(ctx context.Context, txn *entities.LedgerTransaction) error {
+	ret := _m.Called(ctx, txn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Post")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.LedgerTransaction) error); ok {
+		r0 = rf(ctx, txn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Transactions provides a mock function with given fields: ctx, memberID
+func (_m *Ledger) Transactions(ctx context.Context, memberID uuid.UUID) ([]entities.LedgerTransaction, error) {
+	ret := _m.Called(ctx, memberID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Transactions")
+	}
+
+	var r0 []entities.LedgerTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]entities.LedgerTransaction, error)); ok {
+		return rf(ctx, memberID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []entities.LedgerTransaction); ok {
+		r0 = rf(ctx, memberID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.LedgerTransaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, memberID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLedger creates a new instance of Ledger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLedger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Ledger {
+	mock := &Ledger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,229 @@
+package loan
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Loan interface {
+	Checkout(ctx context.Context, loan *entities.Loan, barcode string) error
+	Return(ctx context.Context, id uuid.UUID, returnedAt time.Time, fine *entities.LedgerTransaction) (*entities.Loan, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Loan, error)
+	List(ctx context.Context, query *entities.LoanQuery) ([]entities.Loan, error)
+}
+
+type loan struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Loan {
+	return &loan{db: db}
+}
+
+// Checkout lends one item of the book and records the loan in a single
+// transaction. A given barcode picks the item; otherwise the copy set aside
+// for the borrower's ready hold is handed out, or else any item on the
+// shelf. Shelf items are locked with SKIP LOCKED so concurrent checkouts of
+// the last copy cannot both take it. Any hold the borrower has on the book
+// is fulfilled.
+func (l *loan) Checkout(ctx context.Context, loan *entities.Loan, barcode string) error {
+	loan.ID, _ = uuid.NewV4()
+	loan.CreatedAt = time.Now()
+	loan.UpdatedAt = time.Now()
+
+	return l.db.Transaction(func(tx *gorm.DB) error {
+		// Books in the trash keep their items but cannot be lent
+		var count int64
+		if err := tx.Model(&entities.Book{}).Where("id = ?", loan.BookID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return entities.ErrBookNotFound
+		}
+
+		var ready entities.Hold
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("book_id = ? AND user_id = ? AND status = ?", loan.BookID, loan.UserID, enums.HoldReady).
+			Limit(1).
+			Find(&ready).Error
+		if err != nil {
+			return err
+		}
+
+		item, err := takeItem(tx, loan.BookID, barcode, ready.ItemID)
+		if err != nil {
+			return err
+		}
+
+		// The borrower took a different copy than the one set aside
+		if ready.ItemID != nil && *ready.ItemID != item.ID {
+			if err := setItemStatus(tx, *ready.ItemID, enums.ItemAvailable, loan.UpdatedAt); err != nil {
+				return err
+			}
+		}
+
+		if err := setItemStatus(tx, item.ID, enums.ItemOnLoan, loan.UpdatedAt); err != nil {
+			return err
+		}
+
+		err = tx.Model(&entities.Hold{}).
+			Where("book_id = ? AND user_id = ? AND status IN ?", loan.BookID, loan.UserID,
+				[]enums.HoldStatus{enums.HoldWaiting, enums.HoldReady}).
+			Updates(map[string]interface{}{
+				"status":     enums.HoldFulfilled,
+				"closed_at":  loan.CheckedOutAt,
+				"updated_at": loan.UpdatedAt,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		loan.ItemID = &item.ID
+		result := tx.Create(loan)
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return entities.ErrUserNotFound
+		}
+		return result.Error
+	})
+}
+
+// Return closes the loan and puts its item back on the shelf. The loan row
+// is locked so a double return cannot shelve the item twice. An overdue
+// fine, if any, is posted to the ledger in the same transaction.
+func (l *loan) Return(ctx context.Context, id uuid.UUID, returnedAt time.Time, fine *entities.LedgerTransaction) (*entities.Loan, error) {
+	var loan entities.Loan
+
+	err := l.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&loan)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return entities.ErrLoanNotFound
+			}
+			return result.Error
+		}
+
+		if loan.ReturnedAt != nil {
+			return entities.ErrLoanAlreadyReturned
+		}
+
+		loan.ReturnedAt = &returnedAt
+		loan.UpdatedAt = time.Now()
+		if err := tx.Model(&loan).Updates(map[string]interface{}{
+			"returned_at": loan.ReturnedAt,
+			"updated_at":  loan.UpdatedAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		if loan.ItemID != nil {
+			if err := setItemStatus(tx, *loan.ItemID, enums.ItemAvailable, loan.UpdatedAt); err != nil {
+				return err
+			}
+		}
+		if fine == nil {
+			return nil
+		}
+
+		if !fine.Balanced() {
+			return entities.ErrUnbalancedTransaction
+		}
+		return tx.Create(fine).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &loan, nil
+}
+
+func (l *loan) GetByID(ctx context.Context, id uuid.UUID) (*entities.Loan, error) {
+	var loan entities.Loan
+	result := l.db.Where("id = ?", id).First(&loan)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrLoanNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &loan, nil
+}
+
+// List returns matching loans, most recent checkout first
+func (l *loan) List(ctx context.Context, query *entities.LoanQuery) ([]entities.Loan, error) {
+	tx := l.db.Order("checked_out_at DESC")
+	if query.UserID != nil {
+		tx = tx.Where("user_id = ?", *query.UserID)
+	}
+	if query.ActiveOnly {
+		tx = tx.Where("returned_at IS NULL")
+	}
+
+	var loans []entities.Loan
+	if err := tx.Find(&loans).Error; err != nil {
+		return nil, err
+	}
+
+	return loans, nil
+}
+
+// takeItem locks the item to lend: the one with the given barcode, the one
+// set aside for the borrower's hold, or the first free one on the shelf
+func takeItem(tx *gorm.DB, bookID uuid.UUID, barcode string, held *uuid.UUID) (*entities.Item, error) {
+	var item entities.Item
+
+	switch {
+	case barcode != "":
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("book_id = ? AND barcode = ?", bookID, barcode).
+			First(&item)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return nil, entities.ErrItemNotFound
+			}
+			return nil, result.Error
+		}
+		if item.Status != enums.ItemAvailable && (held == nil || *held != item.ID) {
+			return nil, entities.ErrItemUnavailable
+		}
+		return &item, nil
+
+	case held != nil:
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", *held).First(&item)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		return &item, nil
+	}
+
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("book_id = ? AND status = ?", bookID, enums.ItemAvailable).
+		Order("created_at, id").
+		Limit(1).
+		Find(&item)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, entities.ErrNoCopiesAvailable
+	}
+
+	return &item, nil
+}
+
+func setItemStatus(tx *gorm.DB, id uuid.UUID, status enums.ItemStatus, at time.Time) error {
+	return tx.Model(&entities.Item{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     status,
+		"updated_at": at,
+	}).Error
+}
@@ -0,0 +1,231 @@
+package loan
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofrs/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func NewMock() (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening gorm stub database connection", err)
+	}
+	return gormDB, db, mock
+}
+
+func Test_loan_Checkout(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	bookID, _ := uuid.NewV4()
+	userID, _ := uuid.NewV4()
+	itemID, _ := uuid.NewV4()
+	heldID, _ := uuid.NewV4()
+	holdID, _ := uuid.NewV4()
+
+	ready := regexp.QuoteMeta(`SELECT * FROM "holds" WHERE book_id = $1 AND user_id = $2 AND status = $3 LIMIT $4 FOR UPDATE`)
+	shelf := regexp.QuoteMeta(`SELECT * FROM "items" WHERE book_id = $1 AND status = $2 ORDER BY created_at, id LIMIT $3 FOR UPDATE SKIP LOCKED`)
+	held := regexp.QuoteMeta(`SELECT * FROM "items" WHERE id = $1 ORDER BY "items"."id" LIMIT $2 FOR UPDATE`)
+	scanned := regexp.QuoteMeta(`SELECT * FROM "items" WHERE book_id = $1 AND barcode = $2 ORDER BY "items"."id" LIMIT $3 FOR UPDATE`)
+	setStatus := regexp.QuoteMeta(`UPDATE "items" SET "status"=$1,"updated_at"=$2 WHERE id = $3`)
+	exists := regexp.QuoteMeta(`SELECT count(*) FROM "books" WHERE id = $1 AND "books"."deleted_at" IS NULL`)
+	found := func() *sqlmock.Rows { return sqlmock.NewRows([]string{"count"}).AddRow(1) }
+	fulfil := regexp.QuoteMeta(`UPDATE "holds" SET "closed_at"=$1,"status"=$2,"updated_at"=$3 WHERE book_id = $4 AND user_id = $5 AND status IN ($6,$7)`)
+	insert := regexp.QuoteMeta(`INSERT INTO "loans" ("id","created_at","updated_at","book_id","item_id","user_id","checked_out_at","due_at","returned_at")`)
+
+	holdColumns := []string{"id", "book_id", "user_id", "status", "item_id"}
+	itemColumns := []string{"id", "book_id", "barcode", "status"}
+	noHold := sqlmock.NewRows(holdColumns)
+
+	// Copy on the shelf
+	mock.ExpectBegin()
+	mock.ExpectQuery(exists).WithArgs(bookID).WillReturnRows(found())
+	mock.ExpectQuery(ready).WithArgs(bookID, userID, enums.HoldReady, 1).WillReturnRows(noHold)
+	mock.ExpectQuery(shelf).WithArgs(bookID, enums.ItemAvailable, 1).
+		WillReturnRows(sqlmock.NewRows(itemColumns).AddRow(itemID, bookID, "B1", enums.ItemAvailable))
+	mock.ExpectExec(setStatus).WithArgs(enums.ItemOnLoan, sqlmock.AnyArg(), itemID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(fulfil).WithArgs(sqlmock.AnyArg(), enums.HoldFulfilled, sqlmock.AnyArg(), bookID, userID, enums.HoldWaiting, enums.HoldReady).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Copy set aside for the borrower's hold
+	mock.ExpectBegin()
+	mock.ExpectQuery(exists).WithArgs(bookID).WillReturnRows(found())
+	mock.ExpectQuery(ready).WithArgs(bookID, userID, enums.HoldReady, 1).
+		WillReturnRows(sqlmock.NewRows(holdColumns).AddRow(holdID, bookID, userID, enums.HoldReady, heldID))
+	mock.ExpectQuery(held).WithArgs(heldID, 1).
+		WillReturnRows(sqlmock.NewRows(itemColumns).AddRow(heldID, bookID, "B2", enums.ItemOnHold))
+	mock.ExpectExec(setStatus).WithArgs(enums.ItemOnLoan, sqlmock.AnyArg(), heldID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(fulfil).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// A different copy scanned than the one set aside
+	mock.ExpectBegin()
+	mock.ExpectQuery(exists).WithArgs(bookID).WillReturnRows(found())
+	mock.ExpectQuery(ready).WithArgs(bookID, userID, enums.HoldReady, 1).
+		WillReturnRows(sqlmock.NewRows(holdColumns).AddRow(holdID, bookID, userID, enums.HoldReady, heldID))
+	mock.ExpectQuery(scanned).WithArgs(bookID, "B1", 1).
+		WillReturnRows(sqlmock.NewRows(itemColumns).AddRow(itemID, bookID, "B1", enums.ItemAvailable))
+	mock.ExpectExec(setStatus).WithArgs(enums.ItemAvailable, sqlmock.AnyArg(), heldID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(setStatus).WithArgs(enums.ItemOnLoan, sqlmock.AnyArg(), itemID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(fulfil).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Scanned copy is already lent
+	mock.ExpectBegin()
+	mock.ExpectQuery(exists).WithArgs(bookID).WillReturnRows(found())
+	mock.ExpectQuery(ready).WillReturnRows(sqlmock.NewRows(holdColumns))
+	mock.ExpectQuery(scanned).WithArgs(bookID, "B1", 1).
+		WillReturnRows(sqlmock.NewRows(itemColumns).AddRow(itemID, bookID, "B1", enums.ItemOnLoan))
+	mock.ExpectRollback()
+
+	// Last copy already taken
+	mock.ExpectBegin()
+	mock.ExpectQuery(exists).WithArgs(bookID).WillReturnRows(found())
+	mock.ExpectQuery(ready).WillReturnRows(sqlmock.NewRows(holdColumns))
+	mock.ExpectQuery(shelf).WillReturnRows(sqlmock.NewRows(itemColumns))
+	mock.ExpectRollback()
+
+	// Unknown or deleted book
+	mock.ExpectBegin()
+	mock.ExpectQuery(exists).WithArgs(bookID).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name     string
+		barcode  string
+		wantItem uuid.UUID
+		wantErr  error
+	}{
+		{name: "copy on the shelf", wantItem: itemID},
+		{name: "hold ready for pickup", wantItem: heldID},
+		{name: "other copy scanned", barcode: "B1", wantItem: itemID},
+		{name: "scanned copy on loan", barcode: "B1", wantErr: entities.ErrItemUnavailable},
+		{name: "no copies left", wantErr: entities.ErrNoCopiesAvailable},
+		{name: "unknown book", wantErr: entities.ErrBookNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &loan{db: gdb}
+			got := &entities.Loan{BookID: bookID, UserID: userID, CheckedOutAt: time.Now(), DueAt: time.Now()}
+			err := l.Checkout(context.Background(), got, tt.barcode)
+			if err != tt.wantErr {
+				t.Errorf("loan.Checkout() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.ItemID == nil || *got.ItemID != tt.wantItem) {
+				t.Errorf("loan.Checkout() item = %v, want %v", got.ItemID, tt.wantItem)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_loan_Return(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	loanID, _ := uuid.NewV4()
+	bookID, _ := uuid.NewV4()
+	itemID, _ := uuid.NewV4()
+	returnedAt := time.Now()
+
+	lock := regexp.QuoteMeta(`SELECT * FROM "loans" WHERE id = $1 ORDER BY "loans"."id" LIMIT $2 FOR UPDATE`)
+	columns := []string{"id", "book_id", "item_id", "returned_at"}
+
+	// Open loan
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(loanID, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow(loanID, bookID, itemID, nil))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "loans" SET "returned_at"=$1,"updated_at"=$2 WHERE "id" = $3`)).
+		WithArgs(returnedAt, sqlmock.AnyArg(), loanID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "items" SET "status"=$1,"updated_at"=$2 WHERE id = $3`)).
+		WithArgs(enums.ItemAvailable, sqlmock.AnyArg(), itemID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Returned late with a fine
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(loanID, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow(loanID, bookID, itemID, nil))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "loans" SET`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "items" SET "status"=$1`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "ledger_transactions"`)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "ledger_entries"`)).WillReturnResult(sqlmock.NewResult(2, 2))
+	mock.ExpectCommit()
+
+	// Returned twice
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(loanID, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow(loanID, bookID, itemID, returnedAt))
+	mock.ExpectRollback()
+
+	// Unknown loan
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(loanID, 1).WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectRollback()
+
+	txnID, _ := uuid.NewV4()
+	fine := &entities.LedgerTransaction{
+		ID:     txnID,
+		Kind:   enums.LedgerFine,
+		Amount: 50,
+		LoanID: &loanID,
+		Entries: []entities.LedgerEntry{
+			{ID: uuid.Must(uuid.NewV4()), TransactionID: txnID, Account: enums.AccountReceivable, Amount: 50},
+			{ID: uuid.Must(uuid.NewV4()), TransactionID: txnID, Account: enums.AccountFineIncome, Amount: -50},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		fine    *entities.LedgerTransaction
+		wantErr error
+	}{
+		{name: "open loan"},
+		{name: "overdue loan", fine: fine},
+		{name: "already returned", fine: fine, wantErr: entities.ErrLoanAlreadyReturned},
+		{name: "unknown loan", wantErr: entities.ErrLoanNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &loan{db: gdb}
+			got, err := l.Return(context.Background(), loanID, returnedAt, tt.fine)
+			if err != tt.wantErr {
+				t.Errorf("loan.Return() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && (got.ReturnedAt == nil || !got.ReturnedAt.Equal(returnedAt)) {
+				t.Errorf("loan.Return() = %+v", got)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "library-system/internal/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+// Loan is an autogenerated mock type for the Loan type
+type Loan struct {
+	mock.Mock
+}
+
+// Checkout provides a mock function with given fields: ctx, _a1, barcode
+func (_m *Loan) Checkout(ctx context.Context, _a1 *entities.Loan, barcode string) error {
+	ret := _m.Called(ctx, _a1, barcode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Checkout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Loan, string) error); ok {
+		r0 = rf(ctx, _a1, barcode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Loan) GetByID(ctx context.Context, id uuid.UUID) (*entities.Loan, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entities.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.Loan, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.Loan); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Loan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, query
+func (_m *Loan) List(ctx context.Context, query *entities.LoanQuery) ([]entities.Loan, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []entities.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.LoanQuery) ([]entities.Loan, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.LoanQuery) []entities.Loan); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Loan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.LoanQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Return provides a mock function with given fields: ctx, id, returnedAt, fine
+func (_m *Loan) Return(ctx context.Context, id uuid.UUID, returnedAt time.Time, fine *entities.LedgerTransaction) (*entities.Loan, error) {
+	ret := _m.Called(ctx, id, returnedAt, fine)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Return")
+	}
+
+	var r0 *entities.Loan
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, *entities.LedgerTransaction) (*entities.Loan, error)); ok {
+		return rf(ctx, id, returnedAt, fine)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, *entities.LedgerTransaction) *entities.Loan); ok {
+		r0 = rf(ctx, id, returnedAt, fine)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Loan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time, *entities.LedgerTransaction) error); ok {
+		r1 = rf(ctx, id, returnedAt, fine)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLoan creates a new instance of Loan. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoan(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Loan {
+	mock := &Loan{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -1,18 +1,54 @@
 package models
 
 import (
+	"library-system/internal/models/author"
 	"library-system/internal/models/book"
+	"library-system/internal/models/hold"
+	"library-system/internal/models/importjob"
+	"library-system/internal/models/item"
+	"library-system/internal/models/ledger"
+	"library-system/internal/models/loan"
+	"library-system/internal/models/publisher"
+	"library-system/internal/models/reservation"
+	"library-system/internal/models/series"
+	"library-system/internal/models/subject"
+	"library-system/internal/models/user"
+	"library-system/internal/models/work"
 
 	"gorm.io/gorm"
 )
 
 type Model struct {
-	Book book.Book
+	Book        book.Book
+	Author      author.Author
+	Publisher   publisher.Publisher
+	Subject     subject.Subject
+	Work        work.Work
+	Series      series.Series
+	Item        item.Item
+	User        user.User
+	Loan        loan.Loan
+	Hold        hold.Hold
+	Ledger      ledger.Ledger
+	Reservation reservation.Reservation
+	ImportJob   importjob.ImportJob
 }
 
 // New creates a new instance of Model
 func New(gdb *gorm.DB) *Model {
 	return &Model{
-		Book: book.New(gdb),
+		Book:        book.New(gdb),
+		Author:      author.New(gdb),
+		Publisher:   publisher.New(gdb),
+		Subject:     subject.New(gdb),
+		Work:        work.New(gdb),
+		Series:      series.New(gdb),
+		Item:        item.New(gdb),
+		User:        user.New(gdb),
+		Loan:        loan.New(gdb),
+		Hold:        hold.New(gdb),
+		Ledger:      ledger.New(gdb),
+		Reservation: reservation.New(gdb),
+		ImportJob:   importjob.New(gdb),
 	}
 }
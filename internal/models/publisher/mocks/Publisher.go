@@ -0,0 +1,230 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "library-system/internal/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+// Publisher is an autogenerated mock type for the Publisher type
+type Publisher struct {
+	mock.Mock
+}
+
+// Books provides a mock function with given fields: ctx, id, imprints
+func (_m *Publisher) Books(ctx context.Context, id uuid.UUID, imprints bool) ([]entities.Book, error) {
+	ret := _m.Called(ctx, id, imprints)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Books")
+	}
+
+	var r0 []entities.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool) ([]entities.Book, error)); ok {
+		return rf(ctx, id, imprints)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool) []entities.Book); ok {
+		r0 = rf(ctx, id, imprints)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, bool) error); ok {
+		r1 = rf(ctx, id, imprints)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, _a1
+func (_m *Publisher) Create(ctx context.Context, _a1 *entities.Publisher) error {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Publisher) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *Publisher) Delete(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Publisher) GetByID(ctx context.Context, id uuid.UUID) (*entities.Publisher, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entities.Publisher
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.Publisher, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.Publisher); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Publisher)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, query
+func (_m *Publisher) List(ctx context.Context, query *entities.PublisherQuery) ([]entities.Publisher, int64, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []entities.Publisher
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.PublisherQuery) ([]entities.Publisher, int64, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.PublisherQuery) []entities.Publisher); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Publisher)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.PublisherQuery) int64); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *entities.PublisherQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Merge provides a mock function with given fields: ctx, id, from
+func (_m *Publisher) Merge(ctx context.Context, id uuid.UUID, from []uuid.UUID) error {
+	ret := _m.Called(ctx, id, from)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Merge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []uuid.UUID) error); ok {
+		r0 = rf(ctx, id, from)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Resolve provides a mock function with given fields: ctx, credited
+func (_m *Publisher) Resolve(ctx context.Context, credited []string) ([]*uuid.UUID, error) {
+	ret := _m.Called(ctx, credited)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resolve")
+	}
+
+	var r0 []*uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]*uuid.UUID, error)); ok {
+		return rf(ctx, credited)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*uuid.UUID); ok {
+		r0 = rf(ctx, credited)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, credited)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, _a1
+func (_m *Publisher) Update(ctx context.Context, _a1 *entities.Publisher) error {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Publisher) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPublisher creates a new instance of Publisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPublisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Publisher {
+	mock := &Publisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,349 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/models/book"
+	"library-system/internal/names"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Publisher interface {
+	Create(ctx context.Context, publisher *entities.Publisher) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Publisher, error)
+	List(ctx context.Context, query *entities.PublisherQuery) ([]entities.Publisher, int64, error)
+	Update(ctx context.Context, publisher *entities.Publisher) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Resolve(ctx context.Context, credited []string) ([]*uuid.UUID, error)
+	Books(ctx context.Context, id uuid.UUID, imprints bool) ([]entities.Book, error)
+	Merge(ctx context.Context, id uuid.UUID, from []uuid.UUID) error
+}
+
+type publisher struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Publisher {
+	return &publisher{db: db}
+}
+
+// bookCount selects publishers with the number of books linked to each
+const bookCount = `publishers.*, (SELECT count(*) FROM books
+	WHERE books.publisher_id = publishers.id AND books.deleted_at IS NULL) AS book_count`
+
+func (p *publisher) Create(ctx context.Context, publisher *entities.Publisher) error {
+	publisher.ID, _ = uuid.NewV4()
+	publisher.CreatedAt = time.Now()
+	publisher.UpdatedAt = time.Now()
+	for i := range publisher.Aliases {
+		publisher.Aliases[i].PublisherID = publisher.ID
+	}
+
+	result := p.db.Omit("Parent", "Imprints").Create(publisher)
+	if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+		return entities.ErrInvalidPublisher
+	}
+	return result.Error
+}
+
+// GetByID returns a publisher with its aliases, parent and imprints
+func (p *publisher) GetByID(ctx context.Context, id uuid.UUID) (*entities.Publisher, error) {
+	var publisher entities.Publisher
+	result := p.db.Select(bookCount).
+		Preload("Aliases").
+		Preload("Parent").
+		Preload("Imprints", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).
+		Where("id = ?", id).
+		First(&publisher)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrPublisherNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &publisher, nil
+}
+
+// List returns one page of publishers in name order, with the total across
+// all pages
+func (p *publisher) List(ctx context.Context, query *entities.PublisherQuery) ([]entities.Publisher, int64, error) {
+	matching := func() *gorm.DB {
+		db := p.db.Model(&entities.Publisher{})
+		if query.Name != "" {
+			pattern := "%" + query.Name + "%"
+			db = db.Where("name ILIKE ? OR id IN (SELECT publisher_id FROM publisher_aliases WHERE name ILIKE ?)", pattern, pattern)
+		}
+		if query.TopLevel {
+			db = db.Where("parent_id IS NULL")
+		}
+		return db
+	}
+
+	var total int64
+	if err := matching().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var publishers []entities.Publisher
+	err := matching().Select(bookCount).
+		Preload("Aliases").
+		Preload("Parent").
+		Order("name, id").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Find(&publishers).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return publishers, total, nil
+}
+
+// Update saves the publisher's name and parent and replaces its aliases. A
+// publisher cannot become an imprint of itself or of one of its imprints.
+func (p *publisher) Update(ctx context.Context, publisher *entities.Publisher) error {
+	publisher.UpdatedAt = time.Now()
+
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		if publisher.ParentID != nil {
+			cycle, err := descends(tx, *publisher.ParentID, []uuid.UUID{publisher.ID})
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return entities.ErrInvalidPublisher
+			}
+		}
+
+		result := tx.Model(publisher).Select("name", "name_key", "parent_id", "updated_at").Updates(publisher)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+				return entities.ErrInvalidPublisher
+			}
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrPublisherNotFound
+		}
+
+		if err := tx.Where("publisher_id = ?", publisher.ID).Delete(&entities.PublisherAlias{}).Error; err != nil {
+			return err
+		}
+		if len(publisher.Aliases) == 0 {
+			return nil
+		}
+		for i := range publisher.Aliases {
+			publisher.Aliases[i].PublisherID = publisher.ID
+		}
+		return tx.Create(&publisher.Aliases).Error
+	})
+}
+
+// Delete removes a publisher without books or imprints
+func (p *publisher) Delete(ctx context.Context, id uuid.UUID) error {
+	result := p.db.Where("id = ?", id).Delete(&entities.Publisher{})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return entities.ErrPublisherInUse
+		}
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return entities.ErrPublisherNotFound
+	}
+
+	return nil
+}
+
+// Resolve finds the publisher behind each credited name, by name or alias,
+// and registers the ones not found. It returns the publisher ID of each
+// name in turn, nil for names without a single letter or digit. When
+// several publishers share a name the first registered is used.
+func (p *publisher) Resolve(ctx context.Context, credited []string) ([]*uuid.UUID, error) {
+	var keys []string
+	for _, name := range credited {
+		if key := names.OrganizationKey(name); key != "" && !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+
+	ids := make([]*uuid.UUID, len(credited))
+	if len(keys) == 0 {
+		return ids, nil
+	}
+
+	err := p.db.Transaction(func(tx *gorm.DB) error {
+		var found []struct {
+			ID       uuid.UUID
+			MatchKey string
+		}
+		err := tx.Raw(`SELECT DISTINCT ON (match_key) p.id, m.match_key FROM publishers p
+			JOIN (
+				SELECT id AS publisher_id, name_key AS match_key FROM publishers WHERE name_key IN ?
+				UNION ALL
+				SELECT publisher_id, name_key FROM publisher_aliases WHERE name_key IN ?
+			) m ON m.publisher_id = p.id
+			ORDER BY match_key, p.created_at, p.id`, keys, keys).
+			Scan(&found).Error
+		if err != nil {
+			return err
+		}
+
+		byKey := make(map[string]uuid.UUID, len(keys))
+		for _, f := range found {
+			byKey[f.MatchKey] = f.ID
+		}
+
+		var created []entities.Publisher
+		now := time.Now()
+		for i, name := range credited {
+			key := names.OrganizationKey(name)
+			if key == "" {
+				continue
+			}
+			id, ok := byKey[key]
+			if !ok {
+				id, _ = uuid.NewV4()
+				byKey[key] = id
+				created = append(created, entities.Publisher{
+					ID:        id,
+					CreatedAt: now,
+					UpdatedAt: now,
+					Name:      strings.Join(strings.Fields(name), " "),
+					NameKey:   key,
+				})
+			}
+			ids[i] = &id
+		}
+
+		if len(created) == 0 {
+			return nil
+		}
+		return tx.Omit("Parent", "Imprints", "Aliases").Create(&created).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Books lists the books linked to a publisher, and with imprints to any of
+// its imprints at any depth, most recently published first. Books in the
+// trash are left out.
+func (p *publisher) Books(ctx context.Context, id uuid.UUID, imprints bool) ([]entities.Book, error) {
+	db := p.db.Where("publisher_id = ?", id)
+	if imprints {
+		db = p.db.Where(`publisher_id IN (
+			WITH RECURSIVE tree AS (
+				SELECT id FROM publishers WHERE id = ?
+				UNION
+				SELECT p.id FROM publishers p JOIN tree ON p.parent_id = tree.id
+			)
+			SELECT id FROM tree
+		)`, id)
+	}
+
+	var books []entities.Book
+	if err := db.Order("publish_date DESC, title").Find(&books).Error; err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// Merge folds duplicate publishers into the one with the given ID. Their
+// books move over with the change in their history, their imprints move
+// over, their names and aliases become its aliases
+// and the duplicates are removed.
+func (p *publisher) Merge(ctx context.Context, id uuid.UUID, from []uuid.UUID) error {
+	if slices.Contains(from, id) {
+		return entities.ErrInvalidPublisher
+	}
+	// A duplicate named twice is merged once
+	unique := make([]uuid.UUID, 0, len(from))
+	for _, dup := range from {
+		if !slices.Contains(unique, dup) {
+			unique = append(unique, dup)
+		}
+	}
+	from = unique
+
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		var locked []entities.Publisher
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", append([]uuid.UUID{id}, from...)).
+			Find(&locked).Error
+		if err != nil {
+			return err
+		}
+		if len(locked) != len(from)+1 {
+			return entities.ErrPublisherNotFound
+		}
+
+		// Moving the imprints of a publisher the target descends from
+		// would make the target its own imprint
+		cycle, err := descends(tx, id, from)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return entities.ErrInvalidPublisher
+		}
+
+		now := time.Now()
+		_, err = book.Rewrite(ctx, tx, func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped().Where("publisher_id IN ?", from)
+		}, "publisher_id", func(b *entities.Book) { b.PublisherID = &id })
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&entities.Publisher{}).
+			Where("parent_id IN ?", from).
+			Updates(map[string]interface{}{"parent_id": id, "updated_at": now}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Exec(`INSERT INTO publisher_aliases (publisher_id, name, name_key)
+			SELECT ?, name, name_key FROM publishers WHERE id IN ?
+			UNION
+			SELECT ?, name, name_key FROM publisher_aliases WHERE publisher_id IN ?
+			ON CONFLICT DO NOTHING`, id, from, id, from).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("id IN ?", from).Delete(&entities.Publisher{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&entities.Publisher{}).Where("id = ?", id).Update("updated_at", now).Error
+	})
+}
+
+// descends reports whether the publisher is one of the ancestors, or an
+// imprint of one at any depth
+func descends(tx *gorm.DB, id uuid.UUID, ancestors []uuid.UUID) (bool, error) {
+	var count int64
+	err := tx.Raw(`WITH RECURSIVE up AS (
+			SELECT id, parent_id FROM publishers WHERE id = ?
+			UNION
+			SELECT p.id, p.parent_id FROM publishers p JOIN up ON p.id = up.parent_id
+		)
+		SELECT count(*) FROM up WHERE id IN ?`, id, ancestors).
+		Scan(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,205 @@
+package publisher
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"testing"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func NewMock() (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening gorm stub database connection", err)
+	}
+	return gormDB, db, mock
+}
+
+func Test_publisher_Resolve(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	penguin, _ := uuid.NewV4()
+
+	// Penguin is known; Ace is registered under its name as credited
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT ON (match_key) p.id, m.match_key FROM publishers p`)).
+		WithArgs("penguinbooks", "acebooks", "penguinbooks", "acebooks").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "match_key"}).AddRow(penguin, "penguinbooks"))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "publishers" ("id","created_at","updated_at","name","name_key","parent_id") VALUES ($1,$2,$3,$4,$5,$6)`)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "Ace Books", "acebooks", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	p := &publisher{db: gdb}
+	ids, err := p.Resolve(context.Background(), []string{"Penguin Books Ltd", "Ace  Books", "--", "Penguin Books"})
+	if err != nil {
+		t.Fatalf("publisher.Resolve() error = %v", err)
+	}
+	if len(ids) != 4 || *ids[0] != penguin || ids[1] == nil || *ids[1] == penguin || ids[2] != nil || *ids[3] != penguin {
+		t.Errorf("publisher.Resolve() = %v", ids)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_publisher_Merge(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	dup, _ := uuid.NewV4()
+	bookID, _ := uuid.NewV4()
+
+	lock := regexp.QuoteMeta(`SELECT * FROM "publishers" WHERE id IN ($1,$2) FOR UPDATE`)
+	cycle := regexp.QuoteMeta(`WITH RECURSIVE up AS (`)
+
+	// Merged; the books moved over record the change in their history
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, dup).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id).AddRow(dup))
+	mock.ExpectQuery(cycle).WithArgs(id, dup).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "books" WHERE publisher_id IN ($1) ORDER BY id FOR UPDATE`)).
+		WithArgs(dup).WillReturnRows(sqlmock.NewRows([]string{"id", "publisher_id", "version"}).AddRow(bookID, dup, 2))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "books" SET "updated_at"=$1,"publisher_id"=$2 WHERE "id" = $3 RETURNING "version"`)).
+		WithArgs(sqlmock.AnyArg(), id, bookID).WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(3))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "book_revisions"`)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), bookID, int64(3), enums.RevisionUpdated, nil,
+			fmt.Sprintf(`{"publisher_id":{"before":"%s","after":"%s"}}`, dup, id), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "publishers" SET "parent_id"=$1,"updated_at"=$2 WHERE parent_id IN ($3)`)).
+		WithArgs(id, sqlmock.AnyArg(), dup).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO publisher_aliases (publisher_id, name, name_key)`)).
+		WithArgs(id, dup, id, dup).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "publishers" WHERE id IN ($1)`)).
+		WithArgs(dup).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "publishers" SET "updated_at"=$1 WHERE id = $2`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// The target is an imprint of the duplicate
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, dup).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id).AddRow(dup))
+	mock.ExpectQuery(cycle).WithArgs(id, dup).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	// The duplicate named twice is locked and counted once
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, dup).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id).AddRow(dup))
+	mock.ExpectQuery(cycle).WithArgs(id, dup).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	// The duplicate is gone
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, dup).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(id))
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		from    []uuid.UUID
+		wantErr error
+	}{
+		{name: "merged", from: []uuid.UUID{dup}},
+		{name: "imprint of duplicate", from: []uuid.UUID{dup}, wantErr: entities.ErrInvalidPublisher},
+		{name: "duplicate named twice", from: []uuid.UUID{dup, dup}, wantErr: entities.ErrInvalidPublisher},
+		{name: "unknown duplicate", from: []uuid.UUID{dup}, wantErr: entities.ErrPublisherNotFound},
+		{name: "into itself", from: []uuid.UUID{id}, wantErr: entities.ErrInvalidPublisher},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &publisher{db: gdb}
+			if err := p.Merge(context.Background(), id, tt.from); err != tt.wantErr {
+				t.Errorf("publisher.Merge() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_publisher_Delete(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	del := regexp.QuoteMeta(`DELETE FROM "publishers" WHERE id = $1`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(id).WillReturnError(&pgconn.PgError{Code: "23503"})
+	mock.ExpectRollback()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "unused"},
+		{name: "has books or imprints", wantErr: entities.ErrPublisherInUse},
+		{name: "unknown publisher", wantErr: entities.ErrPublisherNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &publisher{db: gdb}
+			if err := p.Delete(context.Background(), id); err != tt.wantErr {
+				t.Errorf("publisher.Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_publisher_Update(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	imprint, _ := uuid.NewV4()
+
+	// Made an imprint of its own imprint
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`WITH RECURSIVE up AS (`)).WithArgs(imprint, id).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	p := &publisher{db: gdb}
+	err := p.Update(context.Background(), &entities.Publisher{ID: id, Name: "Penguin", NameKey: "penguin", ParentID: &imprint})
+	if err != entities.ErrInvalidPublisher {
+		t.Errorf("publisher.Update() error = %v, want %v", err, entities.ErrInvalidPublisher)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "library-system/internal/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+// Reservation is an autogenerated mock type for the Reservation type
+type Reservation struct {
+	mock.Mock
+}
+
+// Book provides a mock function with given fields: ctx, _a1, limits
+func (_m *Reservation) Book(ctx context.Context, _a1 *entities.Reservation, limits entities.ReservationLimits) error {
+	ret := _m.Called(ctx, _a1, limits)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Book")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Reservation, entities.ReservationLimits) error); ok {
+		r0 = rf(ctx, _a1, limits)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Cancel provides a mock function with given fields: ctx, id, cancelledAt
+func (_m *Reservation) Cancel(ctx context.Context, id uuid.UUID, cancelledAt time.Time) (*entities.Reservation, error) {
+	ret := _m.Called(ctx, id, cancelledAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Cancel")
+	}
+
+	var r0 *entities.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) (*entities.Reservation, error)); ok {
+		return rf(ctx, id, cancelledAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) *entities.Reservation); ok {
+		r0 = rf(ctx, id, cancelledAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r1 = rf(ctx, id, cancelledAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Reservation) GetByID(ctx context.Context, id uuid.UUID) (*entities.Reservation, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entities.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.Reservation, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.Reservation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, query
+func (_m *Reservation) List(ctx context.Context, query *entities.ReservationQuery) ([]entities.Reservation, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []entities.Reservation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.ReservationQuery) ([]entities.Reservation, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.ReservationQuery) []entities.Reservation); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Reservation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.ReservationQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Occupancy provides a mock function with given fields: ctx, date
+func (_m *Reservation) Occupancy(ctx context.Context, date time.Time) ([]entities.SlotOccupancy, error) {
+	ret := _m.Called(ctx, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Occupancy")
+	}
+
+	var r0 []entities.SlotOccupancy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]entities.SlotOccupancy, error)); ok {
+		return rf(ctx, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []entities.SlotOccupancy); ok {
+		r0 = rf(ctx, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.SlotOccupancy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewReservation creates a new instance of Reservation. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReservation(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Reservation {
+	mock := &Reservation{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
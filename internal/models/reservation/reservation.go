@@ -0,0 +1,168 @@
+package reservation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Reservation interface {
+	Book(ctx context.Context, reservation *entities.Reservation, limits entities.ReservationLimits) error
+	Cancel(ctx context.Context, id uuid.UUID, cancelledAt time.Time) (*entities.Reservation, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Reservation, error)
+	List(ctx context.Context, query *entities.ReservationQuery) ([]entities.Reservation, error)
+	Occupancy(ctx context.Context, date time.Time) ([]entities.SlotOccupancy, error)
+}
+
+type reservation struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Reservation {
+	return &reservation{db: db}
+}
+
+// Book records the reservation if the member has no seat in the slot yet,
+// is under the daily limit and a seat is left in the location. Bookings for
+// the same day are serialized with a transaction-scoped advisory lock, so
+// the checks and the insert cannot interleave with another booking.
+func (r *reservation) Book(ctx context.Context, reservation *entities.Reservation, limits entities.ReservationLimits) error {
+	reservation.ID, _ = uuid.NewV4()
+	reservation.CreatedAt = time.Now()
+	reservation.UpdatedAt = time.Now()
+	reservation.Status = enums.Booked
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "reservations:"+reservation.Date.Format("2006-01-02")).Error; err != nil {
+			return err
+		}
+
+		booked := func() *gorm.DB {
+			return tx.Model(&entities.Reservation{}).
+				Where("date = ? AND status = ?", reservation.Date, enums.Booked)
+		}
+
+		var count int64
+		if err := booked().Where("user_id = ? AND slot = ?", reservation.UserID, reservation.Slot).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return entities.ErrReservationConflict
+		}
+
+		if err := booked().Where("user_id = ?", reservation.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(limits.DailyLimit) {
+			return entities.ErrReservationLimitReached
+		}
+
+		if err := booked().Where("slot = ? AND location = ?", reservation.Slot, reservation.Location).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(limits.Capacity) {
+			return entities.ErrSlotFull
+		}
+
+		result := tx.Create(reservation)
+		switch {
+		case errors.Is(result.Error, gorm.ErrDuplicatedKey):
+			return entities.ErrReservationConflict
+		case errors.Is(result.Error, gorm.ErrForeignKeyViolated):
+			return entities.ErrUserNotFound
+		}
+		return result.Error
+	})
+}
+
+// Cancel releases a booked seat. The row is locked so a concurrent cancel
+// sees the first one's result.
+func (r *reservation) Cancel(ctx context.Context, id uuid.UUID, cancelledAt time.Time) (*entities.Reservation, error) {
+	var reservation entities.Reservation
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&reservation)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return entities.ErrReservationNotFound
+			}
+			return result.Error
+		}
+
+		if reservation.Status == enums.Cancelled {
+			return entities.ErrReservationCancelled
+		}
+
+		reservation.Status = enums.Cancelled
+		reservation.CancelledAt = &cancelledAt
+		reservation.UpdatedAt = time.Now()
+		return tx.Model(&reservation).Updates(map[string]interface{}{
+			"status":       reservation.Status,
+			"cancelled_at": reservation.CancelledAt,
+			"updated_at":   reservation.UpdatedAt,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+func (r *reservation) GetByID(ctx context.Context, id uuid.UUID) (*entities.Reservation, error) {
+	var reservation entities.Reservation
+	result := r.db.Where("id = ?", id).First(&reservation)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrReservationNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &reservation, nil
+}
+
+// List returns matching reservations in chronological order
+func (r *reservation) List(ctx context.Context, query *entities.ReservationQuery) ([]entities.Reservation, error) {
+	tx := r.db.Order("date, slot")
+	if query.UserID != nil {
+		tx = tx.Where("user_id = ?", *query.UserID)
+	}
+	if query.Date != nil {
+		tx = tx.Where("date = ?", *query.Date)
+	}
+	if query.Status != "" {
+		tx = tx.Where("status = ?", query.Status)
+	}
+
+	var reservations []entities.Reservation
+	if err := tx.Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+
+	return reservations, nil
+}
+
+// Occupancy counts the booked seats of a day per slot and location. Slots
+// without bookings are omitted.
+func (r *reservation) Occupancy(ctx context.Context, date time.Time) ([]entities.SlotOccupancy, error) {
+	var occupancy []entities.SlotOccupancy
+	err := r.db.Model(&entities.Reservation{}).
+		Select("slot, location, count(*) AS booked").
+		Where("date = ? AND status = ?", date, enums.Booked).
+		Group("slot, location").
+		Scan(&occupancy).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return occupancy, nil
+}
@@ -0,0 +1,154 @@
+package reservation
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofrs/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func NewMock() (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening gorm stub database connection", err)
+	}
+	return gormDB, db, mock
+}
+
+func Test_reservation_Book(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	userID, _ := uuid.NewV4()
+	date := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+	limits := entities.ReservationLimits{Capacity: 10, DailyLimit: 2}
+
+	lock := regexp.QuoteMeta(`SELECT pg_advisory_xact_lock(hashtext($1))`)
+	memberSlot := regexp.QuoteMeta(`SELECT count(*) FROM "reservations" WHERE (date = $1 AND status = $2) AND (user_id = $3 AND slot = $4)`)
+	memberDay := regexp.QuoteMeta(`SELECT count(*) FROM "reservations" WHERE (date = $1 AND status = $2) AND user_id = $3`)
+	seats := regexp.QuoteMeta(`SELECT count(*) FROM "reservations" WHERE (date = $1 AND status = $2) AND (slot = $3 AND location = $4)`)
+	insert := regexp.QuoteMeta(`INSERT INTO "reservations"`)
+	count := func(n int) *sqlmock.Rows { return sqlmock.NewRows([]string{"count"}).AddRow(n) }
+
+	// Seat left
+	mock.ExpectBegin()
+	mock.ExpectExec(lock).WithArgs("reservations:2024-05-01").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(memberSlot).WithArgs(date, enums.Booked, userID, enums.Slot6).WillReturnRows(count(0))
+	mock.ExpectQuery(memberDay).WithArgs(date, enums.Booked, userID).WillReturnRows(count(1))
+	mock.ExpectQuery(seats).WithArgs(date, enums.Booked, enums.Slot6, enums.Inside).WillReturnRows(count(9))
+	mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Member already sits in this slot
+	mock.ExpectBegin()
+	mock.ExpectExec(lock).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(memberSlot).WillReturnRows(count(1))
+	mock.ExpectRollback()
+
+	// Member used up the day
+	mock.ExpectBegin()
+	mock.ExpectExec(lock).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(memberSlot).WillReturnRows(count(0))
+	mock.ExpectQuery(memberDay).WillReturnRows(count(2))
+	mock.ExpectRollback()
+
+	// Slot full
+	mock.ExpectBegin()
+	mock.ExpectExec(lock).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(memberSlot).WillReturnRows(count(0))
+	mock.ExpectQuery(memberDay).WillReturnRows(count(0))
+	mock.ExpectQuery(seats).WillReturnRows(count(10))
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "seat left"},
+		{name: "conflicting reservation", wantErr: entities.ErrReservationConflict},
+		{name: "daily limit reached", wantErr: entities.ErrReservationLimitReached},
+		{name: "slot full", wantErr: entities.ErrSlotFull},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &reservation{db: gdb}
+			res := &entities.Reservation{UserID: userID, Date: date, Slot: enums.Slot6, Location: enums.Inside}
+			err := r.Book(context.Background(), res, limits)
+			if err != tt.wantErr {
+				t.Errorf("reservation.Book() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && res.Status != enums.Booked {
+				t.Errorf("reservation.Book() status = %v, want %v", res.Status, enums.Booked)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_reservation_Cancel(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	cancelledAt := time.Now()
+
+	lock := regexp.QuoteMeta(`SELECT * FROM "reservations" WHERE id = $1 ORDER BY "reservations"."id" LIMIT $2 FOR UPDATE`)
+	columns := []string{"id", "status"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow(id, enums.Booked))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "reservations" SET "cancelled_at"=$1,"status"=$2,"updated_at"=$3 WHERE "id" = $4`)).
+		WithArgs(cancelledAt, enums.Cancelled, sqlmock.AnyArg(), id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lock).WithArgs(id, 1).WillReturnRows(sqlmock.NewRows(columns).AddRow(id, enums.Cancelled))
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "booked"},
+		{name: "already cancelled", wantErr: entities.ErrReservationCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &reservation{db: gdb}
+			got, err := r.Cancel(context.Background(), id, cancelledAt)
+			if err != tt.wantErr {
+				t.Errorf("reservation.Cancel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Status != enums.Cancelled {
+				t.Errorf("reservation.Cancel() status = %v, want %v", got.Status, enums.Cancelled)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package series
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"library-system/internal/entities"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type Series interface {
+	Create(ctx context.Context, series *entities.Series) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Series, error)
+	List(ctx context.Context, name string) ([]entities.Series, error)
+	Update(ctx context.Context, series *entities.Series) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type series struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Series {
+	return &series{db: db}
+}
+
+func (s *series) Create(ctx context.Context, series *entities.Series) error {
+	series.ID, _ = uuid.NewV4()
+	series.CreatedAt = time.Now()
+	series.UpdatedAt = time.Now()
+
+	return saveError(s.db.Create(series).Error)
+}
+
+func (s *series) GetByID(ctx context.Context, id uuid.UUID) (*entities.Series, error) {
+	var series entities.Series
+	result := s.db.Where("id = ?", id).First(&series)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrSeriesNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &series, nil
+}
+
+// List returns the series whose name contains name, in name order
+func (s *series) List(ctx context.Context, name string) ([]entities.Series, error) {
+	db := s.db
+	if name != "" {
+		db = db.Where("name ILIKE ?", "%"+name+"%")
+	}
+
+	var series []entities.Series
+	if err := db.Order("lower(name), id").Find(&series).Error; err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// Update saves the series' name and description
+func (s *series) Update(ctx context.Context, series *entities.Series) error {
+	series.UpdatedAt = time.Now()
+
+	result := s.db.Model(series).Select("name", "description", "updated_at").Updates(series)
+	if result.Error != nil {
+		return saveError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return entities.ErrSeriesNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a series. Its works stay in the catalogue outside any
+// series.
+func (s *series) Delete(ctx context.Context, id uuid.UUID) error {
+	result := s.db.Where("id = ?", id).Delete(&entities.Series{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return entities.ErrSeriesNotFound
+	}
+
+	return nil
+}
+
+// saveError maps the unique name violation of a series insert or update
+func saveError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return entities.ErrDuplicateSeries
+	}
+	return err
+}
@@ -0,0 +1,193 @@
+package subject
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"library-system/internal/entities"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type Subject interface {
+	Create(ctx context.Context, subject *entities.Subject) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Subject, error)
+	Update(ctx context.Context, subject *entities.Subject) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Tree(ctx context.Context) ([]entities.Subject, error)
+	ForBook(ctx context.Context, bookID uuid.UUID) ([]entities.Subject, error)
+	SetForBook(ctx context.Context, bookID uuid.UUID, ids []uuid.UUID) error
+}
+
+type subject struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Subject {
+	return &subject{db: db}
+}
+
+func (s *subject) Create(ctx context.Context, subject *entities.Subject) error {
+	subject.ID, _ = uuid.NewV4()
+	subject.CreatedAt = time.Now()
+	subject.UpdatedAt = time.Now()
+
+	return saveError(s.db.Omit("Parent").Create(subject).Error)
+}
+
+func (s *subject) GetByID(ctx context.Context, id uuid.UUID) (*entities.Subject, error) {
+	var subject entities.Subject
+	result := s.db.Where("id = ?", id).First(&subject)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrSubjectNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &subject, nil
+}
+
+// Update saves the subject's name, kind and parent. A subject cannot be
+// moved under itself or under a subject below it.
+func (s *subject) Update(ctx context.Context, subject *entities.Subject) error {
+	subject.UpdatedAt = time.Now()
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if subject.ParentID != nil {
+			cycle, err := descends(tx, *subject.ParentID, subject.ID)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return entities.ErrInvalidSubject
+			}
+		}
+
+		result := tx.Model(subject).Select("name", "kind", "parent_id", "updated_at").Updates(subject)
+		if result.Error != nil {
+			return saveError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrSubjectNotFound
+		}
+		return nil
+	})
+}
+
+// Delete removes a subject no book is filed under and with no narrower
+// subjects
+func (s *subject) Delete(ctx context.Context, id uuid.UUID) error {
+	result := s.db.Where("id = ?", id).Delete(&entities.Subject{})
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+			return entities.ErrSubjectInUse
+		}
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return entities.ErrSubjectNotFound
+	}
+
+	return nil
+}
+
+// Tree returns every subject in name order, counting the books filed under
+// it and those filed under it or any subject below it. A book filed under
+// several subjects of a branch is counted once. Books in the trash are
+// left out.
+func (s *subject) Tree(ctx context.Context) ([]entities.Subject, error) {
+	var subjects []entities.Subject
+	err := s.db.Raw(`WITH RECURSIVE below AS (
+			SELECT id AS subject_id, id FROM subjects
+			UNION ALL
+			SELECT below.subject_id, s.id FROM subjects s JOIN below ON s.parent_id = below.id
+		), filed AS (
+			SELECT bs.book_id, bs.subject_id FROM book_subjects bs
+			JOIN books b ON b.id = bs.book_id AND b.deleted_at IS NULL
+		)
+		SELECT subjects.*,
+			(SELECT count(*) FROM filed WHERE filed.subject_id = subjects.id) AS book_count,
+			(SELECT count(DISTINCT filed.book_id) FROM below JOIN filed ON filed.subject_id = below.id
+				WHERE below.subject_id = subjects.id) AS total_count
+		FROM subjects
+		ORDER BY lower(name), id`).
+		Scan(&subjects).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return subjects, nil
+}
+
+// ForBook lists the subjects a book is filed under, in name order
+func (s *subject) ForBook(ctx context.Context, bookID uuid.UUID) ([]entities.Subject, error) {
+	var subjects []entities.Subject
+	err := s.db.Where("id IN (SELECT subject_id FROM book_subjects WHERE book_id = ?)", bookID).
+		Order("lower(name), id").
+		Find(&subjects).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return subjects, nil
+}
+
+// SetForBook replaces the subjects a book is filed under
+func (s *subject) SetForBook(ctx context.Context, bookID uuid.UUID, ids []uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return FileBook(tx, bookID, ids)
+	})
+}
+
+// FileBook replaces the subjects a book is filed under within tx
+func FileBook(tx *gorm.DB, bookID uuid.UUID, ids []uuid.UUID) error {
+	if err := tx.Where("book_id = ?", bookID).Delete(&entities.BookSubject{}).Error; err != nil {
+		return err
+	}
+
+	var filed []entities.BookSubject
+	for _, id := range ids {
+		if !slices.ContainsFunc(filed, func(f entities.BookSubject) bool { return f.SubjectID == id }) {
+			filed = append(filed, entities.BookSubject{BookID: bookID, SubjectID: id})
+		}
+	}
+	if len(filed) == 0 {
+		return nil
+	}
+
+	result := tx.Omit("Subject").Create(&filed)
+	if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+		return entities.ErrInvalidSubject
+	}
+	return result.Error
+}
+
+// saveError maps the constraint violations of a subject insert or update
+func saveError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return entities.ErrDuplicateSubject
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return entities.ErrInvalidSubject
+	}
+	return err
+}
+
+// descends reports whether the subject is the ancestor or sits below it
+func descends(tx *gorm.DB, id, ancestor uuid.UUID) (bool, error) {
+	var count int64
+	err := tx.Raw(`WITH RECURSIVE up AS (
+			SELECT id, parent_id FROM subjects WHERE id = ?
+			UNION
+			SELECT s.id, s.parent_id FROM subjects s JOIN up ON s.id = up.parent_id
+		)
+		SELECT count(*) FROM up WHERE id = ?`, id, ancestor).
+		Scan(&count).Error
+	return count > 0, err
+}
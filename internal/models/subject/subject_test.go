@@ -0,0 +1,168 @@
+package subject
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+
+	"library-system/internal/entities"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func NewMock() (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening gorm stub database connection", err)
+	}
+	return gormDB, db, mock
+}
+
+func Test_subject_Update(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	parent, _ := uuid.NewV4()
+	cycle := regexp.QuoteMeta(`WITH RECURSIVE up AS (`)
+	update := regexp.QuoteMeta(`UPDATE "subjects" SET "updated_at"=$1,"name"=$2,"kind"=$3,"parent_id"=$4 WHERE "id" = $5`)
+
+	// Moved under another subject
+	mock.ExpectBegin()
+	mock.ExpectQuery(cycle).WithArgs(parent, id).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(update).WithArgs(sqlmock.AnyArg(), "Science fiction", "genre", parent, id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Moved under a subject below it
+	mock.ExpectBegin()
+	mock.ExpectQuery(cycle).WithArgs(parent, id).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	// A sibling has the name already
+	mock.ExpectBegin()
+	mock.ExpectQuery(cycle).WithArgs(parent, id).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(update).WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "moved"},
+		{name: "under its own narrower subject", wantErr: entities.ErrInvalidSubject},
+		{name: "duplicate name", wantErr: entities.ErrDuplicateSubject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &subject{db: gdb}
+			err := s.Update(context.Background(), &entities.Subject{ID: id, Name: "Science fiction", Kind: "genre", ParentID: &parent})
+			if err != tt.wantErr {
+				t.Errorf("subject.Update() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_subject_Delete(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	del := regexp.QuoteMeta(`DELETE FROM "subjects" WHERE id = $1`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(id).WillReturnError(&pgconn.PgError{Code: "23503"})
+	mock.ExpectRollback()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "unused"},
+		{name: "has books or narrower subjects", wantErr: entities.ErrSubjectInUse},
+		{name: "unknown subject", wantErr: entities.ErrSubjectNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &subject{db: gdb}
+			if err := s.Delete(context.Background(), id); err != tt.wantErr {
+				t.Errorf("subject.Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_subject_SetForBook(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	bookID, _ := uuid.NewV4()
+	fiction, _ := uuid.NewV4()
+	unknown, _ := uuid.NewV4()
+	del := regexp.QuoteMeta(`DELETE FROM "book_subjects" WHERE book_id = $1`)
+	insert := regexp.QuoteMeta(`INSERT INTO "book_subjects" ("book_id","subject_id") VALUES ($1,$2)`)
+
+	// Listed twice, filed once
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(bookID).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(insert).WithArgs(bookID, fiction).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(del).WithArgs(bookID).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insert).WithArgs(bookID, unknown).WillReturnError(&pgconn.PgError{Code: "23503"})
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		ids     []uuid.UUID
+		wantErr error
+	}{
+		{name: "filed", ids: []uuid.UUID{fiction, fiction}},
+		{name: "unknown subject", ids: []uuid.UUID{unknown}, wantErr: entities.ErrInvalidSubject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &subject{db: gdb}
+			if err := s.SetForBook(context.Background(), bookID, tt.ids); err != tt.wantErr {
+				t.Errorf("subject.SetForBook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type User interface {
+	Create(ctx context.Context, user *entities.User) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
+	GetByEmail(ctx context.Context, email string) (*entities.User, error)
+
+	GrantRole(ctx context.Context, userID uuid.UUID, role enums.Role, actorID uuid.UUID) error
+	RevokeRole(ctx context.Context, userID uuid.UUID, role enums.Role, actorID uuid.UUID) error
+	ListRoleAudit(ctx context.Context, userID *uuid.UUID) ([]entities.RoleAudit, error)
+}
+
+type user struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) User {
+	return &user{db: db}
+}
+
+// Create stores a new user with its roles. Roles beyond member are granted
+// by the new user themselves, as no one else is there to grant them, and
+// are recorded in the audit trail in the same transaction.
+func (u *user) Create(ctx context.Context, user *entities.User) error {
+	user.ID, _ = uuid.NewV4()
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = time.Now()
+
+	for i := range user.Roles {
+		user.Roles[i].CreatedAt = user.CreatedAt
+		if user.Roles[i].Role != enums.RoleMember && user.Roles[i].GrantedBy == nil {
+			user.Roles[i].GrantedBy = &user.ID
+		}
+	}
+
+	err := u.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		for _, role := range user.Roles {
+			if role.GrantedBy == nil {
+				continue
+			}
+			audit := newRoleAudit(user.ID, role.Role, enums.RoleGranted, *role.GrantedBy, user.CreatedAt)
+			if err := tx.Create(audit).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return entities.ErrEmailTaken
+	}
+	return err
+}
+
+func (u *user) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	var user entities.User
+	result := u.db.Preload("Roles").Where("id = ?", id).First(&user)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrUserNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+func (u *user) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	var user entities.User
+	result := u.db.Preload("Roles").Where("email = ?", email).First(&user)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrUserNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &user, nil
+}
+
+// GrantRole adds the role and records the change in the audit trail
+func (u *user) GrantRole(ctx context.Context, userID uuid.UUID, role enums.Role, actorID uuid.UUID) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Create(&entities.UserRole{UserID: userID, Role: role, GrantedBy: &actorID, CreatedAt: now})
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+				return entities.ErrRoleAlreadyGranted
+			}
+			if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+				return entities.ErrUserNotFound
+			}
+			return result.Error
+		}
+
+		return tx.Create(newRoleAudit(userID, role, enums.RoleGranted, actorID, now)).Error
+	})
+}
+
+// RevokeRole removes the role and records the change in the audit trail.
+// The admin role is never taken from the last user holding it; the admins
+// are locked while they are counted, so two revokes cannot each leave the
+// other's admin as the last one.
+func (u *user) RevokeRole(ctx context.Context, userID uuid.UUID, role enums.Role, actorID uuid.UUID) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		if role == enums.RoleAdmin {
+			var admins []uuid.UUID
+			err := tx.Model(&entities.UserRole{}).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("role = ?", enums.RoleAdmin).
+				Pluck("user_id", &admins).Error
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(admins, userID) {
+				return entities.ErrRoleNotGranted
+			}
+			if len(admins) <= 1 {
+				return entities.ErrLastAdmin
+			}
+		}
+
+		result := tx.Delete(&entities.UserRole{}, "user_id = ? AND role = ?", userID, role)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrRoleNotGranted
+		}
+
+		return tx.Create(newRoleAudit(userID, role, enums.RoleRevoked, actorID, time.Now())).Error
+	})
+}
+
+// ListRoleAudit returns the most recent role changes first, optionally for one user
+func (u *user) ListRoleAudit(ctx context.Context, userID *uuid.UUID) ([]entities.RoleAudit, error) {
+	tx := u.db.Order("created_at DESC")
+	if userID != nil {
+		tx = tx.Where("user_id = ?", *userID)
+	}
+
+	var audits []entities.RoleAudit
+	if err := tx.Find(&audits).Error; err != nil {
+		return nil, err
+	}
+
+	return audits, nil
+}
+
+func newRoleAudit(userID uuid.UUID, role enums.Role, action enums.RoleAction, actorID uuid.UUID, at time.Time) *entities.RoleAudit {
+	id, _ := uuid.NewV4()
+	return &entities.RoleAudit{
+		ID:        id,
+		CreatedAt: at,
+		UserID:    userID,
+		Role:      role,
+		Action:    action,
+		ActorID:   actorID,
+	}
+}
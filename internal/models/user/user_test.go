@@ -0,0 +1,284 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"regexp"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func NewMock() (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening gorm stub database connection", err)
+	}
+	return gormDB, db, mock
+}
+
+func Test_user_Create(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	newUser := func() *entities.User {
+		return &entities.User{Name: "Reader", Email: "reader@example.com", PasswordHash: "hash"}
+	}
+
+	insertStmt := regexp.QuoteMeta(`INSERT INTO "users" ("id","created_at","updated_at","name","email","password_hash") VALUES ($1,$2,$3,$4,$5,$6)`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insertStmt).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "Reader", "reader@example.com", "hash").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insertStmt).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		u       *user
+		wantErr error
+	}{
+		{
+			name: "valid case",
+			u:    &user{db: gdb},
+		},
+		{
+			name:    "duplicate email",
+			u:       &user{db: gdb},
+			wantErr: entities.ErrEmailTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newUser()
+			if err := tt.u.Create(context.Background(), u); err != tt.wantErr {
+				t.Errorf("user.Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if u.ID == uuid.Nil {
+				t.Errorf("user.Create() did not assign an id")
+			}
+		})
+	}
+}
+
+// An account created with a role beyond member is granted it by itself,
+// and the grant is audited in the same transaction
+func Test_user_Create_withRole(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "users"`)).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "user_roles" ("user_id","role","granted_by","created_at") VALUES ($1,$2,$3,$4),($5,$6,$7,$8)`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "role_audits" ("id","created_at","user_id","role","action","actor_id") VALUES ($1,$2,$3,$4,$5,$6)`)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), enums.RoleAdmin, enums.RoleGranted, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	admin := &entities.User{
+		Name:         "Ada",
+		Email:        "ada@example.com",
+		PasswordHash: "hash",
+		Roles:        []entities.UserRole{{Role: enums.RoleMember}, {Role: enums.RoleAdmin}},
+	}
+	if err := (&user{db: gdb}).Create(context.Background(), admin); err != nil {
+		t.Fatalf("user.Create() error = %v", err)
+	}
+	if admin.Roles[0].GrantedBy != nil || admin.Roles[1].GrantedBy == nil || *admin.Roles[1].GrantedBy != admin.ID {
+		t.Errorf("user.Create() granted_by = %v, %v", admin.Roles[0].GrantedBy, admin.Roles[1].GrantedBy)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_user_GetByEmail(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	validID, _ := uuid.NewV4()
+	testTime := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "name", "email", "password_hash"}).
+		AddRow(validID, testTime, testTime, "Reader", "reader@example.com", "hash")
+
+	stmt := gdb.Session(&gorm.Session{DryRun: true}).Model(&entities.User{}).Where("email = ?", "").First(&entities.User{}).Statement.SQL.String()
+
+	mock.ExpectQuery(regexp.QuoteMeta(stmt)).WithArgs("reader@example.com", 1).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "user_roles" WHERE "user_roles"."user_id" = $1`)).
+		WithArgs(validID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "role"}).AddRow(validID, "librarian"))
+	mock.ExpectQuery(regexp.QuoteMeta(stmt)).WithArgs("nobody@example.com", 1).WillReturnError(gorm.ErrRecordNotFound)
+	mock.ExpectQuery(regexp.QuoteMeta(stmt)).WithArgs("broken@example.com", 1).WillReturnError(sql.ErrConnDone)
+
+	tests := []struct {
+		name    string
+		u       *user
+		email   string
+		wantID  uuid.UUID
+		wantErr error
+	}{
+		{
+			name:   "valid case",
+			u:      &user{db: gdb},
+			email:  "reader@example.com",
+			wantID: validID,
+		},
+		{
+			name:    "not found",
+			u:       &user{db: gdb},
+			email:   "nobody@example.com",
+			wantErr: entities.ErrUserNotFound,
+		},
+		{
+			name:    "database error",
+			u:       &user{db: gdb},
+			email:   "broken@example.com",
+			wantErr: sql.ErrConnDone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.u.GetByEmail(context.Background(), tt.email)
+			if err != tt.wantErr {
+				t.Errorf("user.GetByEmail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != nil && (got.ID != tt.wantID || len(got.Roles) != 1 || got.Roles[0].Role != enums.RoleLibrarian) {
+				t.Errorf("user.GetByEmail() got = %v, want id %v with librarian role", got, tt.wantID)
+			}
+		})
+	}
+}
+
+func Test_user_GrantRole(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	userID, _ := uuid.NewV4()
+	actorID, _ := uuid.NewV4()
+
+	insertRole := regexp.QuoteMeta(`INSERT INTO "user_roles" ("user_id","role","granted_by","created_at") VALUES ($1,$2,$3,$4)`)
+	insertAudit := regexp.QuoteMeta(`INSERT INTO "role_audits" ("id","created_at","user_id","role","action","actor_id") VALUES ($1,$2,$3,$4,$5,$6)`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insertRole).WithArgs(userID, enums.RoleLibrarian, actorID, sqlmock.AnyArg()).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insertAudit).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), userID, enums.RoleLibrarian, enums.RoleGranted, actorID).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insertRole).WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(insertRole).WillReturnError(&pgconn.PgError{Code: "23503"})
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "granted"},
+		{name: "already granted", wantErr: entities.ErrRoleAlreadyGranted},
+		{name: "unknown user", wantErr: entities.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &user{db: gdb}
+			if err := u.GrantRole(context.Background(), userID, enums.RoleLibrarian, actorID); err != tt.wantErr {
+				t.Errorf("user.GrantRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_user_RevokeRole(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	userID, _ := uuid.NewV4()
+	actorID, _ := uuid.NewV4()
+
+	deleteRole := regexp.QuoteMeta(`DELETE FROM "user_roles" WHERE user_id = $1 AND role = $2`)
+	insertAudit := regexp.QuoteMeta(`INSERT INTO "role_audits"`)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(deleteRole).WithArgs(userID, enums.RoleLibrarian).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertAudit).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), userID, enums.RoleLibrarian, enums.RoleRevoked, actorID).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(deleteRole).WithArgs(userID, enums.RoleLibrarian).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	// The admins are locked while counted; the target is checked to hold
+	// the role before it is found to be the last admin
+	lockAdmins := regexp.QuoteMeta(`SELECT "user_id" FROM "user_roles" WHERE role = $1 FOR UPDATE`)
+	admins := func(ids ...uuid.UUID) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"user_id"})
+		for _, id := range ids {
+			rows.AddRow(id)
+		}
+		return rows
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockAdmins).WithArgs(enums.RoleAdmin).WillReturnRows(admins(actorID, userID))
+	mock.ExpectExec(deleteRole).WithArgs(userID, enums.RoleAdmin).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(insertAudit).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), userID, enums.RoleAdmin, enums.RoleRevoked, actorID).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockAdmins).WithArgs(enums.RoleAdmin).WillReturnRows(admins(userID))
+	mock.ExpectRollback()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(lockAdmins).WithArgs(enums.RoleAdmin).WillReturnRows(admins(actorID))
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		role    enums.Role
+		wantErr error
+	}{
+		{name: "revoked", role: enums.RoleLibrarian},
+		{name: "not granted", role: enums.RoleLibrarian, wantErr: entities.ErrRoleNotGranted},
+		{name: "one of several admins", role: enums.RoleAdmin},
+		{name: "last admin", role: enums.RoleAdmin, wantErr: entities.ErrLastAdmin},
+		{name: "admin role not granted", role: enums.RoleAdmin, wantErr: entities.ErrRoleNotGranted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &user{db: gdb}
+			if err := u.RevokeRole(context.Background(), userID, tt.role, actorID); err != tt.wantErr {
+				t.Errorf("user.RevokeRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	entities "library-system/internal/entities"
+
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/gofrs/uuid"
+)
+
+// Work is an autogenerated mock type for the Work type
+type Work struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, _a1, bookIDs
+func (_m *Work) Create(ctx context.Context, _a1 *entities.Work, bookIDs []uuid.UUID) error {
+	ret := _m.Called(ctx, _a1, bookIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Work, []uuid.UUID) error); ok {
+		r0 = rf(ctx, _a1, bookIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *Work) Delete(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Editions provides a mock function with given fields: ctx, id
+func (_m *Work) Editions(ctx context.Context, id uuid.UUID) ([]entities.Book, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Editions")
+	}
+
+	var r0 []entities.Book
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]entities.Book, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []entities.Book); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *Work) GetByID(ctx context.Context, id uuid.UUID) (*entities.Work, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *entities.Work
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.Work, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.Work); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Work)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InSeries provides a mock function with given fields: ctx, seriesID
+func (_m *Work) InSeries(ctx context.Context, seriesID uuid.UUID) ([]entities.Work, error) {
+	ret := _m.Called(ctx, seriesID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InSeries")
+	}
+
+	var r0 []entities.Work
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]entities.Work, error)); ok {
+		return rf(ctx, seriesID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []entities.Work); ok {
+		r0 = rf(ctx, seriesID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Work)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, seriesID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, query
+func (_m *Work) List(ctx context.Context, query *entities.WorkQuery) ([]entities.Work, int64, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []entities.Work
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.WorkQuery) ([]entities.Work, int64, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.WorkQuery) []entities.Work); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Work)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.WorkQuery) int64); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *entities.WorkQuery) error); ok {
+		r2 = rf(ctx, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Matching provides a mock function with given fields: ctx, titleKey, authorKey
+func (_m *Work) Matching(ctx context.Context, titleKey string, authorKey string) ([]entities.Work, error) {
+	ret := _m.Called(ctx, titleKey, authorKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Matching")
+	}
+
+	var r0 []entities.Work
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]entities.Work, error)); ok {
+		return rf(ctx, titleKey, authorKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []entities.Work); ok {
+		r0 = rf(ctx, titleKey, authorKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Work)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, titleKey, authorKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, _a1, bookIDs
+func (_m *Work) Update(ctx context.Context, _a1 *entities.Work, bookIDs []uuid.UUID) error {
+	ret := _m.Called(ctx, _a1, bookIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.Work, []uuid.UUID) error); ok {
+		r0 = rf(ctx, _a1, bookIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWork creates a new instance of Work. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWork(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Work {
+	mock := &Work{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,219 @@
+package work
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/models/book"
+
+	"github.com/gofrs/uuid"
+	"gorm.io/gorm"
+)
+
+type Work interface {
+	Create(ctx context.Context, work *entities.Work, bookIDs []uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Work, error)
+	List(ctx context.Context, query *entities.WorkQuery) ([]entities.Work, int64, error)
+	Update(ctx context.Context, work *entities.Work, bookIDs []uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Editions(ctx context.Context, id uuid.UUID) ([]entities.Book, error)
+	InSeries(ctx context.Context, seriesID uuid.UUID) ([]entities.Work, error)
+	Matching(ctx context.Context, titleKey, authorKey string) ([]entities.Work, error)
+}
+
+type work struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) Work {
+	return &work{db: db}
+}
+
+// editionCount selects works with the number of books of each
+const editionCount = `works.*, (SELECT count(*) FROM books
+	WHERE books.work_id = works.id AND books.deleted_at IS NULL) AS edition_count`
+
+// Create adds a work and groups the books given under it
+func (w *work) Create(ctx context.Context, work *entities.Work, bookIDs []uuid.UUID) error {
+	work.ID, _ = uuid.NewV4()
+	work.CreatedAt = time.Now()
+	work.UpdatedAt = time.Now()
+
+	return w.db.Transaction(func(tx *gorm.DB) error {
+		if err := saveError(tx.Omit("Series").Create(work).Error); err != nil {
+			return err
+		}
+		return group(ctx, tx, work.ID, bookIDs)
+	})
+}
+
+// GetByID returns a work with its series and number of editions
+func (w *work) GetByID(ctx context.Context, id uuid.UUID) (*entities.Work, error) {
+	var work entities.Work
+	result := w.db.Select(editionCount).
+		Preload("Series").
+		Where("id = ?", id).
+		First(&work)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrWorkNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &work, nil
+}
+
+// List returns one page of works in title order, with the total across
+// all pages
+func (w *work) List(ctx context.Context, query *entities.WorkQuery) ([]entities.Work, int64, error) {
+	matching := func() *gorm.DB {
+		db := w.db.Model(&entities.Work{})
+		if query.Title != "" {
+			db = db.Where("title ILIKE ?", "%"+query.Title+"%")
+		}
+		if query.Author != "" {
+			db = db.Where("author ILIKE ?", "%"+query.Author+"%")
+		}
+		return db
+	}
+
+	var total int64
+	if err := matching().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var works []entities.Work
+	err := matching().Select(editionCount).
+		Preload("Series").
+		Order("lower(title), id").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Find(&works).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return works, total, nil
+}
+
+// Update saves the work's title, author and place in a series, and groups
+// the books given under it as well
+func (w *work) Update(ctx context.Context, work *entities.Work, bookIDs []uuid.UUID) error {
+	work.UpdatedAt = time.Now()
+
+	return w.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(work).
+			Select("title", "author", "title_key", "author_key", "series_id", "series_volume", "updated_at").
+			Updates(work)
+		if result.Error != nil {
+			return saveError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrWorkNotFound
+		}
+		return group(ctx, tx, work.ID, bookIDs)
+	})
+}
+
+// Delete removes a work. Its editions stay in the catalogue, ungrouped,
+// with the change in their history.
+func (w *work) Delete(ctx context.Context, id uuid.UUID) error {
+	return w.db.Transaction(func(tx *gorm.DB) error {
+		_, err := book.Rewrite(ctx, tx, func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped().Where("work_id = ?", id)
+		}, "work_id", func(b *entities.Book) { b.WorkID = nil })
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ?", id).Delete(&entities.Work{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return entities.ErrWorkNotFound
+		}
+
+		return nil
+	})
+}
+
+// Editions lists the books of a work, oldest first. Books in the trash are
+// left out.
+func (w *work) Editions(ctx context.Context, id uuid.UUID) ([]entities.Book, error) {
+	var books []entities.Book
+	err := w.db.Where("work_id = ?", id).Order("publish_date, title, id").Find(&books).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return books, nil
+}
+
+// InSeries lists the works of a series in volume order. Works without a
+// volume number come last, by title.
+func (w *work) InSeries(ctx context.Context, seriesID uuid.UUID) ([]entities.Work, error) {
+	var works []entities.Work
+	err := w.db.Where("series_id = ?", seriesID).
+		Order("series_volume NULLS LAST, lower(title), id").
+		Find(&works).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return works, nil
+}
+
+// Matching lists the works with the title and author keys given, oldest
+// first
+func (w *work) Matching(ctx context.Context, titleKey, authorKey string) ([]entities.Work, error) {
+	var works []entities.Work
+	err := w.db.Where("title_key = ? AND author_key = ?", titleKey, authorKey).
+		Order("created_at, id").
+		Find(&works).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return works, nil
+}
+
+// group makes the books editions of a work, moving them from any work
+// they were grouped under, and records the change in their history. Every
+// book must exist outside the trash.
+func group(ctx context.Context, tx *gorm.DB, id uuid.UUID, bookIDs []uuid.UUID) error {
+	var ids []uuid.UUID
+	for _, bookID := range bookIDs {
+		if !slices.Contains(ids, bookID) {
+			ids = append(ids, bookID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	found, err := book.Rewrite(ctx, tx, func(db *gorm.DB) *gorm.DB {
+		return db.Where("id IN ?", ids)
+	}, "work_id", func(b *entities.Book) { b.WorkID = &id })
+	if err != nil {
+		return err
+	}
+	if found != len(ids) {
+		return entities.ErrInvalidWork
+	}
+
+	return nil
+}
+
+// saveError maps an unknown series on a work insert or update
+func saveError(err error) error {
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return entities.ErrInvalidWork
+	}
+	return err
+}
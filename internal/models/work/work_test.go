@@ -0,0 +1,172 @@
+package work
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"testing"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofrs/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func NewMock() (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{TranslateError: true})
+	if err != nil {
+		log.Fatalf("an error '%s' was not expected when opening gorm stub database connection", err)
+	}
+	return gormDB, db, mock
+}
+
+func Test_work_Create(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	first, _ := uuid.NewV4()
+	second, _ := uuid.NewV4()
+	insert := regexp.QuoteMeta(`INSERT INTO "works" ("id","created_at","updated_at","title","author","title_key","author_key","series_id","series_volume") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`)
+	books := regexp.QuoteMeta(`SELECT * FROM "books" WHERE id IN ($1,$2) AND "books"."deleted_at" IS NULL ORDER BY id FOR UPDATE`)
+	group := regexp.QuoteMeta(`UPDATE "books" SET "updated_at"=$1,"work_id"=$2 WHERE "id" = $3 RETURNING "version"`)
+	revision := regexp.QuoteMeta(`INSERT INTO "book_revisions"`)
+
+	// Both books are grouped, each with a revision; a book listed twice
+	// counts once
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(books).WithArgs(first, second).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(first, 1).AddRow(second, 4))
+	mock.ExpectQuery(group).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), first).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.ExpectExec(revision).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), first, int64(2), enums.RevisionUpdated, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(group).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), second).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(5))
+	mock.ExpectExec(revision).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), second, int64(5), enums.RevisionUpdated, nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// One of the books does not exist or is in the trash
+	mock.ExpectBegin()
+	mock.ExpectExec(insert).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(books).WithArgs(first, second).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "version"}).AddRow(first, 1))
+	mock.ExpectQuery(group).WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), first).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(2))
+	mock.ExpectExec(revision).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "books grouped"},
+		{name: "unknown book", wantErr: entities.ErrInvalidWork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &work{db: gdb}
+			work := &entities.Work{Title: "The Hobbit", Author: "J. R. R. Tolkien", TitleKey: "hobbit", AuthorKey: "jrrtolkien"}
+			err := w.Create(context.Background(), work, []uuid.UUID{first, second, first})
+			if err != tt.wantErr {
+				t.Errorf("work.Create() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_work_Delete(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	id, _ := uuid.NewV4()
+	edition, _ := uuid.NewV4()
+	editions := regexp.QuoteMeta(`SELECT * FROM "books" WHERE work_id = $1 ORDER BY id FOR UPDATE`)
+	del := regexp.QuoteMeta(`DELETE FROM "works" WHERE id = $1`)
+
+	// The editions, the trashed ones too, are ungrouped with a revision
+	// before the work goes
+	mock.ExpectBegin()
+	mock.ExpectQuery(editions).WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "work_id", "version"}).AddRow(edition, id, 3))
+	mock.ExpectQuery(regexp.QuoteMeta(`UPDATE "books" SET "updated_at"=$1,"work_id"=$2 WHERE "id" = $3 RETURNING "version"`)).
+		WithArgs(sqlmock.AnyArg(), nil, edition).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(4))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "book_revisions"`)).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), edition, int64(4), enums.RevisionUpdated, nil,
+			fmt.Sprintf(`{"work_id":{"before":"%s","after":null}}`, id), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(del).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// The work does not exist
+	mock.ExpectBegin()
+	mock.ExpectQuery(editions).WithArgs(id).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(del).WithArgs(id).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	tests := []struct {
+		name    string
+		wantErr error
+	}{
+		{name: "editions ungrouped"},
+		{name: "unknown work", wantErr: entities.ErrWorkNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &work{db: gdb}
+			if err := w.Delete(context.Background(), id); err != tt.wantErr {
+				t.Errorf("work.Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func Test_work_InSeries(t *testing.T) {
+	gdb, db, mock := NewMock()
+	defer db.Close()
+
+	seriesID, _ := uuid.NewV4()
+	one, two := 1.0, 2.0
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "works" WHERE series_id = $1 ORDER BY series_volume NULLS LAST, lower(title), id`)).
+		WithArgs(seriesID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "series_id", "series_volume"}).
+			AddRow(uuid.Must(uuid.NewV4()), "The Fellowship of the Ring", seriesID, one).
+			AddRow(uuid.Must(uuid.NewV4()), "The Two Towers", seriesID, two).
+			AddRow(uuid.Must(uuid.NewV4()), "The Adventures of Tom Bombadil", seriesID, nil))
+
+	w := &work{db: gdb}
+	works, err := w.InSeries(context.Background(), seriesID)
+	if err != nil {
+		t.Fatalf("work.InSeries() error = %v", err)
+	}
+	if len(works) != 3 || *works[1].SeriesVolume != two || works[2].SeriesVolume != nil {
+		t.Errorf("work.InSeries() = %+v", works)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
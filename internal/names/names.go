@@ -0,0 +1,128 @@
+// Package names splits the author credit of a book into personal names and
+// normalizes the names of people and publishers, and book titles, for
+// matching. Migrations 0014 and 0015 implement the same rules in SQL for
+// their backfills; keep them in step.
+package names
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// separator joins names in a credit: "A and B", "A & B", "A; B"
+var separator = regexp.MustCompile(`(?i)\s*(?:;|&|\band\b)\s*`)
+
+// Split splits a credit line into the names it lists. Commas separate
+// names only when every part is a full name of at least two words, so an
+// inverted "Tolkien, J. R. R." stays one name while "Neil Gaiman, Terry
+// Pratchett" is two.
+func Split(credit string) []string {
+	credit = strings.Join(strings.Fields(credit), " ")
+
+	var out []string
+	for _, part := range separator.Split(credit, -1) {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		pieces := strings.Split(part, ",")
+		full := len(pieces) > 1
+		for i := range pieces {
+			pieces[i] = strings.TrimSpace(pieces[i])
+			if len(strings.Fields(pieces[i])) < 2 {
+				full = false
+			}
+		}
+		if full {
+			out = append(out, pieces...)
+		} else {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// Display turns an inverted "Last, First" into "First Last". Other names,
+// including those with more than one comma, are returned as they are.
+func Display(name string) string {
+	name = strings.TrimSpace(name)
+	last, first, ok := strings.Cut(name, ",")
+	if !ok || strings.Contains(first, ",") {
+		return name
+	}
+	last, first = strings.TrimSpace(last), strings.TrimSpace(first)
+	if last == "" || first == "" {
+		return name
+	}
+	return first + " " + last
+}
+
+// SortName files a name under its last word: "Frank Herbert" sorts as
+// "Herbert, Frank". Names that are already inverted are kept.
+func SortName(name string) string {
+	name = strings.TrimSpace(name)
+	if strings.Contains(name, ",") {
+		return name
+	}
+	i := strings.LastIndex(name, " ")
+	if i < 0 {
+		return name
+	}
+	return name[i+1:] + ", " + name[:i]
+}
+
+// Key is the form names are matched by: the display form in lower case
+// with everything but letters and digits removed, so "J.R.R. Tolkien",
+// "J. R. R. Tolkien" and "Tolkien, J. R. R." all match
+func Key(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(Display(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// corporate are the legal suffixes left out of publisher keys
+var corporate = map[string]bool{
+	"co": true, "company": true, "corp": true, "corporation": true, "gmbh": true,
+	"inc": true, "incorporated": true, "limited": true, "llc": true, "ltd": true, "plc": true,
+}
+
+// OrganizationKey is the form publishers are matched by: the words of the
+// name in lower case without punctuation, a leading "the" or trailing legal
+// suffixes, so "Penguin Books Ltd." and "Penguin Books" match
+func OrganizationKey(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for len(words) > 1 && corporate[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	if len(words) > 1 && words[0] == "the" {
+		words = words[1:]
+	}
+	return strings.Join(words, "")
+}
+
+// articles are left off the front of title keys
+var articles = map[string]bool{"a": true, "an": true, "the": true}
+
+// TitleKey is the form works are matched by: the words of the title before
+// any subtitle, in lower case without punctuation or a leading article, so
+// "The Hobbit: or There and Back Again" and "Hobbit" match
+func TitleKey(title string) string {
+	if i := strings.Index(title, ":"); i > 0 {
+		title = title[:i]
+	}
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 && articles[words[0]] {
+		words = words[1:]
+	}
+	return strings.Join(words, "")
+}
@@ -0,0 +1,88 @@
+package names
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		credit string
+		want   []string
+	}{
+		{"Frank Herbert", []string{"Frank Herbert"}},
+		{"Tolkien, J. R. R.", []string{"Tolkien, J. R. R."}},
+		{"Terry Pratchett and Neil Gaiman", []string{"Terry Pratchett", "Neil Gaiman"}},
+		{"Terry Pratchett & Neil  Gaiman", []string{"Terry Pratchett", "Neil Gaiman"}},
+		{"Brian Kernighan; Dennis Ritchie", []string{"Brian Kernighan", "Dennis Ritchie"}},
+		{"Alan Donovan, Brian Kernighan", []string{"Alan Donovan", "Brian Kernighan"}},
+		{"Smith, John, Jr.", []string{"Smith, John, Jr."}},
+		{"Sandy Anderson", []string{"Sandy Anderson"}},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		if got := Split(tt.credit); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%q) = %q, want %q", tt.credit, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayAndSortName(t *testing.T) {
+	tests := []struct {
+		name, display, sort string
+	}{
+		{"Frank Herbert", "Frank Herbert", "Herbert, Frank"},
+		{"Herbert, Frank", "Frank Herbert", "Herbert, Frank"},
+		{"Homer", "Homer", "Homer"},
+		{"Smith, John, Jr.", "Smith, John, Jr.", "Smith, John, Jr."},
+	}
+	for _, tt := range tests {
+		if got := Display(tt.name); got != tt.display {
+			t.Errorf("Display(%q) = %q, want %q", tt.name, got, tt.display)
+		}
+		if got := SortName(tt.name); got != tt.sort {
+			t.Errorf("SortName(%q) = %q, want %q", tt.name, got, tt.sort)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	want := Key("J.R.R. Tolkien")
+	for _, name := range []string{"J. R. R. Tolkien", "Tolkien, J. R. R.", "j r r tolkien"} {
+		if got := Key(name); got != want {
+			t.Errorf("Key(%q) = %q, want %q", name, got, want)
+		}
+	}
+	if Key("Gabriel García Márquez") != "gabrielgarcíamárquez" {
+		t.Errorf("Key() dropped letters: %q", Key("Gabriel García Márquez"))
+	}
+}
+
+func TestOrganizationKey(t *testing.T) {
+	want := OrganizationKey("Penguin Books")
+	for _, name := range []string{"Penguin Books Ltd.", "penguin books, inc", "The Penguin Books Co."} {
+		if got := OrganizationKey(name); got != want {
+			t.Errorf("OrganizationKey(%q) = %q, want %q", name, got, want)
+		}
+	}
+	for _, name := range []string{"The Company", "Co."} {
+		if OrganizationKey(name) == "" {
+			t.Errorf("OrganizationKey(%q) dropped every word", name)
+		}
+	}
+}
+
+func TestTitleKey(t *testing.T) {
+	want := TitleKey("The Hobbit")
+	for _, title := range []string{"Hobbit", "The Hobbit: or There and Back Again", "the hobbit."} {
+		if got := TitleKey(title); got != want {
+			t.Errorf("TitleKey(%q) = %q, want %q", title, got, want)
+		}
+	}
+	if TitleKey("The Hobbit") == TitleKey("The Hobbit Companion") {
+		t.Errorf("TitleKey() matched a longer title")
+	}
+	if TitleKey("It") == "" || TitleKey("A") == "" {
+		t.Errorf("TitleKey() dropped every word")
+	}
+}
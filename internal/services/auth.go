@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"library-system/internal/auth"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Register creates a member account with a bcrypt-hashed password
+func (s *service) Register(ctx context.Context, req *entities.RegisterRequest) (*entities.UserResponse, error) {
+	return s.createAccount(ctx, req)
+}
+
+// createAccount creates an account holding the member role and any roles
+// given, all in one transaction
+func (s *service) createAccount(ctx context.Context, req *entities.RegisterRequest, roles ...enums.Role) (*entities.UserResponse, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &entities.User{
+		Name:         strings.TrimSpace(req.Name),
+		Email:        normalizeEmail(req.Email),
+		PasswordHash: string(hash),
+		Roles:        []entities.UserRole{{Role: enums.RoleMember}},
+	}
+	for _, role := range roles {
+		user.Roles = append(user.Roles, entities.UserRole{Role: role})
+	}
+
+	if err := s.model.User.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return toUserResponse(user), nil
+}
+
+// dummyHash is compared against when no account has the email, so an
+// unknown email takes as long to reject as a wrong password
+const dummyHash = "$2a$10$pYdS.aB3chWciNJor3CAHOzGSGaJ7hCSTHO.DWRhWdb6yN7MpY2sK"
+
+// Login verifies the credentials and issues a token pair
+func (s *service) Login(ctx context.Context, req *entities.LoginRequest) (*entities.TokenResponse, error) {
+	user, err := s.model.User.GetByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		// Don't reveal which accounts exist, by the answer or its timing
+		if errors.Is(err, entities.ErrUserNotFound) {
+			bcrypt.CompareHashAndPassword([]byte(dummyHash), []byte(req.Password))
+			return nil, entities.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, entities.ErrInvalidCredentials
+	}
+
+	return s.tokens.Issue(toPrincipal(user))
+}
+
+// RefreshToken exchanges a valid refresh token for a new token pair
+func (s *service) RefreshToken(ctx context.Context, req *entities.RefreshRequest) (*entities.TokenResponse, error) {
+	principal, err := s.tokens.ParseRefresh(req.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-read the account so deleted users can't keep refreshing
+	current, err := s.currentPrincipal(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokens.Issue(current)
+}
+
+// Authenticate resolves the principal of an access token. The roles are
+// read from the account rather than the token, so a revoked role stops
+// working at once instead of when the token expires.
+func (s *service) Authenticate(ctx context.Context, token string) (*entities.Principal, error) {
+	principal, err := s.tokens.ParseAccess(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.currentPrincipal(ctx, principal.UserID)
+}
+
+// currentPrincipal builds the principal of an account as it is now. A
+// token of a deleted account is invalid.
+func (s *service) currentPrincipal(ctx context.Context, userID uuid.UUID) (*entities.Principal, error) {
+	user, err := s.model.User.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, entities.ErrUserNotFound) {
+			return nil, entities.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return toPrincipal(user), nil
+}
+
+// GetCurrentUser returns the account of the authenticated caller
+func (s *service) GetCurrentUser(ctx context.Context) (*entities.UserResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, entities.ErrInvalidToken
+	}
+
+	user, err := s.model.User.GetByID(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserResponse(user), nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func toUserResponse(user *entities.User) *entities.UserResponse {
+	return &entities.UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Roles:     user.RoleNames(),
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+func toPrincipal(user *entities.User) *entities.Principal {
+	return &entities.Principal{UserID: user.ID, Email: user.Email, Roles: user.RoleNames()}
+}
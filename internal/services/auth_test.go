@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"library-system/internal/auth"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models"
+	userMock "library-system/internal/models/user/mocks"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func Test_service_Register(t *testing.T) {
+	req := &entities.RegisterRequest{Name: " Reader ", Email: "Reader@Example.com ", Password: "correct horse"}
+
+	successMock := userMock.User{}
+	successMock.On("Create", mock.Anything, mock.MatchedBy(func(u *entities.User) bool {
+		return u.Name == "Reader" &&
+			u.Email == "reader@example.com" &&
+			len(u.Roles) == 1 && u.Roles[0].Role == enums.RoleMember &&
+			bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(req.Password)) == nil
+	})).Return(nil)
+
+	takenMock := userMock.User{}
+	takenMock.On("Create", mock.Anything, mock.Anything).Return(entities.ErrEmailTaken)
+
+	tests := []struct {
+		name    string
+		s       *service
+		wantErr error
+	}{
+		{
+			name: "successful registration",
+			s:    &service{model: models.Model{User: &successMock}},
+		},
+		{
+			name:    "email taken",
+			s:       &service{model: models.Model{User: &takenMock}},
+			wantErr: entities.ErrEmailTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Register(context.Background(), req)
+			if err != tt.wantErr {
+				t.Errorf("Register() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Email != "reader@example.com" {
+				t.Errorf("Register() = %v", got)
+			}
+			tt.s.model.User.(*userMock.User).AssertExpectations(t)
+		})
+	}
+}
+
+func Test_service_Login(t *testing.T) {
+	userID, _ := uuid.NewV4()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	user := &entities.User{ID: userID, Email: "reader@example.com", PasswordHash: string(hash)}
+
+	tokens := auth.NewTokenManager("secret", time.Minute, time.Hour)
+
+	successMock := userMock.User{}
+	successMock.On("GetByEmail", mock.Anything, "reader@example.com").Return(user, nil)
+
+	unknownMock := userMock.User{}
+	unknownMock.On("GetByEmail", mock.Anything, "reader@example.com").Return(nil, entities.ErrUserNotFound)
+
+	errorMock := userMock.User{}
+	errorMock.On("GetByEmail", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+	tests := []struct {
+		name     string
+		s        *service
+		password string
+		wantErr  error
+	}{
+		{
+			name:     "valid credentials",
+			s:        &service{model: models.Model{User: &successMock}, tokens: tokens},
+			password: "correct horse",
+		},
+		{
+			name:     "wrong password",
+			s:        &service{model: models.Model{User: &successMock}, tokens: tokens},
+			password: "battery staple",
+			wantErr:  entities.ErrInvalidCredentials,
+		},
+		{
+			name:     "unknown email",
+			s:        &service{model: models.Model{User: &unknownMock}, tokens: tokens},
+			password: "correct horse",
+			wantErr:  entities.ErrInvalidCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Login(context.Background(), &entities.LoginRequest{Email: " READER@example.com", Password: tt.password})
+			if err != tt.wantErr {
+				t.Fatalf("Login() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				principal, err := tokens.ParseAccess(got.AccessToken)
+				if err != nil || principal.UserID != userID {
+					t.Errorf("Login() issued access token for %v, err %v", principal, err)
+				}
+			}
+			tt.s.model.User.(*userMock.User).AssertExpectations(t)
+		})
+	}
+
+	s := &service{model: models.Model{User: &errorMock}, tokens: tokens}
+	if _, err := s.Login(context.Background(), &entities.LoginRequest{Email: "x@example.com", Password: "x"}); err == nil || err == entities.ErrInvalidCredentials {
+		t.Errorf("Login() database error = %v", err)
+	}
+
+	// An unknown email costs what checking a password registered with the
+	// default cost does
+	if cost, err := bcrypt.Cost([]byte(dummyHash)); err != nil || cost != bcrypt.DefaultCost {
+		t.Errorf("dummyHash cost = %d, %v, want %d", cost, err, bcrypt.DefaultCost)
+	}
+}
+
+func Test_service_RefreshToken(t *testing.T) {
+	userID, _ := uuid.NewV4()
+	deletedID, _ := uuid.NewV4()
+	tokens := auth.NewTokenManager("secret", time.Minute, time.Hour)
+
+	valid, _ := tokens.Issue(&entities.Principal{UserID: userID, Email: "reader@example.com"})
+	deleted, _ := tokens.Issue(&entities.Principal{UserID: deletedID, Email: "gone@example.com"})
+
+	userModel := userMock.User{}
+	userModel.On("GetByID", mock.Anything, userID).Return(&entities.User{ID: userID, Email: "reader@example.com"}, nil)
+	userModel.On("GetByID", mock.Anything, deletedID).Return(nil, entities.ErrUserNotFound)
+
+	s := &service{model: models.Model{User: &userModel}, tokens: tokens}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "valid refresh token", token: valid.RefreshToken},
+		{name: "access token rejected", token: valid.AccessToken, wantErr: entities.ErrInvalidToken},
+		{name: "deleted user", token: deleted.RefreshToken, wantErr: entities.ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.RefreshToken(context.Background(), &entities.RefreshRequest{RefreshToken: tt.token})
+			if err != tt.wantErr {
+				t.Fatalf("RefreshToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.AccessToken == "" {
+				t.Errorf("RefreshToken() = %+v", got)
+			}
+		})
+	}
+}
+
+func Test_service_Authenticate(t *testing.T) {
+	userID, _ := uuid.NewV4()
+	deletedID, _ := uuid.NewV4()
+	tokens := auth.NewTokenManager("secret", time.Minute, time.Hour)
+
+	// The token was issued while the user was an admin
+	valid, _ := tokens.Issue(&entities.Principal{UserID: userID, Email: "ada@example.com", Roles: []enums.Role{enums.RoleMember, enums.RoleAdmin}})
+	deleted, _ := tokens.Issue(&entities.Principal{UserID: deletedID, Email: "gone@example.com"})
+
+	userModel := userMock.User{}
+	userModel.On("GetByID", mock.Anything, userID).Return(&entities.User{
+		ID: userID, Email: "ada@example.com", Roles: []entities.UserRole{{UserID: userID, Role: enums.RoleMember}},
+	}, nil)
+	userModel.On("GetByID", mock.Anything, deletedID).Return(nil, entities.ErrUserNotFound)
+
+	s := &service{model: models.Model{User: &userModel}, tokens: tokens}
+
+	tests := []struct {
+		name      string
+		token     string
+		wantRoles []enums.Role
+		wantErr   error
+	}{
+		{name: "revoked role", token: valid.AccessToken, wantRoles: []enums.Role{enums.RoleMember}},
+		{name: "refresh token rejected", token: valid.RefreshToken, wantErr: entities.ErrInvalidToken},
+		{name: "deleted user", token: deleted.AccessToken, wantErr: entities.ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Authenticate(context.Background(), tt.token)
+			if err != tt.wantErr {
+				t.Fatalf("Authenticate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !slices.Equal(got.Roles, tt.wantRoles) {
+				t.Errorf("Authenticate() roles = %v, want %v", got.Roles, tt.wantRoles)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/names"
+
+	"github.com/gofrs/uuid"
+)
+
+// CreateAuthor adds an author to the catalogue
+func (s *service) CreateAuthor(ctx context.Context, req *entities.AuthorRequest) (*entities.AuthorResponse, error) {
+	author := &entities.Author{}
+	if err := applyAuthorRequest(author, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.model.Author.Create(ctx, author); err != nil {
+		return nil, err
+	}
+
+	return toAuthorResponse(author), nil
+}
+
+// GetAuthor retrieves an author with their aliases
+func (s *service) GetAuthor(ctx context.Context, id uuid.UUID) (*entities.AuthorResponse, error) {
+	author, err := s.model.Author.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toAuthorResponse(author), nil
+}
+
+// ListAuthors retrieves one page of authors, optionally matching a name
+func (s *service) ListAuthors(ctx context.Context, query *entities.AuthorQuery) (*entities.AuthorPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = entities.DefaultPageSize
+	}
+	if query.Limit > entities.MaxPageSize {
+		query.Limit = entities.MaxPageSize
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	query.Name = strings.TrimSpace(query.Name)
+
+	authors, total, err := s.model.Author.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entities.AuthorResponse, len(authors))
+	for i := range authors {
+		resp[i] = toAuthorResponse(&authors[i])
+	}
+
+	return &entities.AuthorPage{
+		Data:  resp,
+		Total: total,
+		Page:  query.Page,
+		Limit: query.Limit,
+	}, nil
+}
+
+// UpdateAuthor changes an author's details. The aliases given replace the
+// author's aliases.
+func (s *service) UpdateAuthor(ctx context.Context, id uuid.UUID, req *entities.AuthorRequest) (*entities.AuthorResponse, error) {
+	author, err := s.model.Author.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyAuthorRequest(author, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.model.Author.Update(ctx, author); err != nil {
+		return nil, err
+	}
+
+	return toAuthorResponse(author), nil
+}
+
+// DeleteAuthor removes an author who is not credited on any book
+func (s *service) DeleteAuthor(ctx context.Context, id uuid.UUID) error {
+	return s.model.Author.Delete(ctx, id)
+}
+
+// ListAuthorBooks lists the books an author is credited on, in any role or
+// in the one given
+func (s *service) ListAuthorBooks(ctx context.Context, id uuid.UUID, role enums.ContributorRole) ([]*entities.BookResponse, error) {
+	if role != "" && !role.IsValid() {
+		return nil, fmt.Errorf("%w: unknown role %q", entities.ErrInvalidAuthor, role)
+	}
+
+	if _, err := s.model.Author.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	books, err := s.model.Author.Books(ctx, id, role)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entities.BookResponse, len(books))
+	for i := range books {
+		resp[i] = s.toBookResponse(&books[i])
+	}
+
+	return resp, nil
+}
+
+// requestedContributors resolves the contributors of a book request. With
+// none given, the authors are the names in the author credit.
+func (s *service) requestedContributors(ctx context.Context, req *entities.BookRequest) ([]entities.BookContributor, error) {
+	if len(req.Contributors) == 0 {
+		return s.creditedAuthors(ctx, req.Author)
+	}
+
+	var contributors []entities.BookContributor
+	for _, c := range req.Contributors {
+		role := c.Role
+		if role == "" {
+			role = enums.ContributorAuthor
+		}
+
+		var authorID uuid.UUID
+		if c.AuthorID != nil {
+			if _, err := s.model.Author.GetByID(ctx, *c.AuthorID); err != nil {
+				if errors.Is(err, entities.ErrAuthorNotFound) {
+					return nil, fmt.Errorf("%w: %v", entities.ErrInvalidAuthor, err)
+				}
+				return nil, err
+			}
+			authorID = *c.AuthorID
+		} else {
+			authors, err := s.model.Author.Resolve(ctx, []string{c.Name})
+			if err != nil {
+				return nil, err
+			}
+			if len(authors) == 0 {
+				return nil, fmt.Errorf("%w: name %q", entities.ErrInvalidAuthor, c.Name)
+			}
+			authorID = authors[0].ID
+		}
+
+		contributors = appendContributor(contributors, authorID, role)
+	}
+
+	return contributors, nil
+}
+
+// creditedAuthors resolves the names in an author credit to authors
+func (s *service) creditedAuthors(ctx context.Context, credit string) ([]entities.BookContributor, error) {
+	authors, err := s.model.Author.Resolve(ctx, names.Split(credit))
+	if err != nil {
+		return nil, err
+	}
+
+	var contributors []entities.BookContributor
+	for _, author := range authors {
+		contributors = appendContributor(contributors, author.ID, enums.ContributorAuthor)
+	}
+	return contributors, nil
+}
+
+// recreditedContributors returns the contributors of a book with its
+// authors replaced by those named in the credit, or nil when they are the
+// ones credited already
+func (s *service) recreditedContributors(ctx context.Context, bookID uuid.UUID, credit string) ([]entities.BookContributor, error) {
+	authors, err := s.creditedAuthors(ctx, credit)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.model.Author.ForBook(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	contributors := authors
+	unchanged := true
+	n := 0
+	for _, c := range current {
+		if c.Role != enums.ContributorAuthor {
+			contributors = appendContributor(contributors, c.AuthorID, c.Role)
+			continue
+		}
+		if n >= len(authors) || authors[n].AuthorID != c.AuthorID {
+			unchanged = false
+		}
+		n++
+	}
+	if unchanged && n == len(authors) {
+		return nil, nil
+	}
+
+	// Empty, rather than none, when the credit names no one
+	return append([]entities.BookContributor{}, contributors...), nil
+}
+
+// appendContributor adds a credit in the next position, unless the author
+// already holds that role
+func appendContributor(contributors []entities.BookContributor, authorID uuid.UUID, role enums.ContributorRole) []entities.BookContributor {
+	for _, c := range contributors {
+		if c.AuthorID == authorID && c.Role == role {
+			return contributors
+		}
+	}
+	return append(contributors, entities.BookContributor{
+		AuthorID: authorID,
+		Role:     role,
+		Position: len(contributors),
+	})
+}
+
+// applyAuthorRequest copies the request onto the author, filling in the
+// sort name and the matching keys
+func applyAuthorRequest(author *entities.Author, req *entities.AuthorRequest) error {
+	if req.BirthDate != nil && req.DeathDate != nil && req.DeathDate.Before(*req.BirthDate) {
+		return fmt.Errorf("%w: death date is before birth date", entities.ErrInvalidAuthor)
+	}
+
+	author.Name = strings.TrimSpace(req.Name)
+	author.SortName = strings.TrimSpace(req.SortName)
+	if author.SortName == "" {
+		author.SortName = names.SortName(author.Name)
+	}
+	author.NameKey = names.Key(author.Name)
+	author.BirthDate = req.BirthDate
+	author.DeathDate = req.DeathDate
+	author.Bio = req.Bio
+
+	// Aliases that match the name or each other would never be looked up
+	author.Aliases = nil
+	seen := map[string]bool{author.NameKey: true}
+	for _, alias := range req.Aliases {
+		alias = strings.TrimSpace(alias)
+		key := names.Key(alias)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		author.Aliases = append(author.Aliases, entities.AuthorAlias{AuthorID: author.ID, Name: alias, NameKey: key})
+	}
+
+	return nil
+}
+
+// toAuthorResponse maps a stored author onto its API representation
+func toAuthorResponse(author *entities.Author) *entities.AuthorResponse {
+	aliases := make([]string, len(author.Aliases))
+	for i, alias := range author.Aliases {
+		aliases[i] = alias.Name
+	}
+
+	return &entities.AuthorResponse{
+		ID:        author.ID,
+		Name:      author.Name,
+		SortName:  author.SortName,
+		Aliases:   aliases,
+		BirthDate: author.BirthDate,
+		DeathDate: author.DeathDate,
+		Bio:       author.Bio,
+		CreatedAt: author.CreatedAt,
+		UpdatedAt: author.UpdatedAt,
+	}
+}
+
+// toContributorResponses lists a book's contributors with their names
+func toContributorResponses(contributors []entities.BookContributor) []entities.ContributorResponse {
+	resp := make([]entities.ContributorResponse, 0, len(contributors))
+	for _, c := range contributors {
+		r := entities.ContributorResponse{AuthorID: c.AuthorID, Role: c.Role}
+		if c.Author != nil {
+			r.Name = c.Author.Name
+			r.SortName = c.Author.SortName
+		}
+		resp = append(resp, r)
+	}
+	return resp
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models"
+	authorMock "library-system/internal/models/author/mocks"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_CreateAuthor(t *testing.T) {
+	born := time.Date(1920, time.October, 8, 0, 0, 0, 0, time.UTC)
+	died := time.Date(1986, time.February, 11, 0, 0, 0, 0, time.UTC)
+
+	authors := authorMock.Author{}
+	authors.On("Create", mock.Anything, mock.MatchedBy(func(a *entities.Author) bool {
+		return a.Name == "Frank Herbert" && a.SortName == "Herbert, Frank" && a.NameKey == "frankherbert" &&
+			len(a.Aliases) == 1 && a.Aliases[0].Name == "Frank Patrick Herbert" && a.Aliases[0].NameKey == "frankpatrickherbert"
+	})).Return(nil)
+
+	s := &service{model: models.Model{Author: &authors}}
+
+	got, err := s.CreateAuthor(context.Background(), &entities.AuthorRequest{
+		Name:      " Frank Herbert ",
+		Aliases:   []string{"Frank Patrick Herbert", "Herbert, Frank", "Frank Patrick  Herbert"},
+		BirthDate: &born,
+		DeathDate: &died,
+	})
+	if err != nil {
+		t.Fatalf("CreateAuthor() error = %v", err)
+	}
+	if len(got.Aliases) != 1 || got.SortName != "Herbert, Frank" {
+		t.Errorf("CreateAuthor() = %+v", got)
+	}
+	authors.AssertExpectations(t)
+
+	_, err = s.CreateAuthor(context.Background(), &entities.AuthorRequest{Name: "Frank Herbert", BirthDate: &died, DeathDate: &born})
+	if !errors.Is(err, entities.ErrInvalidAuthor) {
+		t.Errorf("CreateAuthor() with death before birth error = %v, want %v", err, entities.ErrInvalidAuthor)
+	}
+}
+
+func Test_service_ListAuthorBooks(t *testing.T) {
+	id, _ := uuid.NewV4()
+	unknown, _ := uuid.NewV4()
+
+	authors := authorMock.Author{}
+	authors.On("GetByID", mock.Anything, id).Return(&entities.Author{ID: id}, nil)
+	authors.On("GetByID", mock.Anything, unknown).Return(nil, entities.ErrAuthorNotFound)
+	authors.On("Books", mock.Anything, id, enums.ContributorTranslator).Return([]entities.Book{{ID: uuid.Must(uuid.NewV4())}}, nil)
+
+	s := &service{model: models.Model{Author: &authors}}
+
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		role    enums.ContributorRole
+		want    int
+		wantErr error
+	}{
+		{name: "translated", id: id, role: enums.ContributorTranslator, want: 1},
+		{name: "unknown role", id: id, role: "narrator", wantErr: entities.ErrInvalidAuthor},
+		{name: "unknown author", id: unknown, wantErr: entities.ErrAuthorNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.ListAuthorBooks(context.Background(), tt.id, tt.role)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ListAuthorBooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("ListAuthorBooks() = %d books, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -2,32 +2,89 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
 
+	"library-system/internal/classification"
 	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/isbn"
+	"library-system/internal/models/item"
 
 	"github.com/gofrs/uuid"
 )
 
-// CreateBook adds a new book to the library
-func (s *service) CreateBook(ctx context.Context, req *entities.BookRequest) error {
+// CreateBook adds a new book to the library. A book not grouped under a
+// work comes back with suggestions of works it may be an edition of.
+func (s *service) CreateBook(ctx context.Context, req *entities.BookRequest) (*entities.BookResponse, error) {
+	isbn13, err := canonicalISBN(req.ISBN)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create book entity from request
 	book := &entities.Book{
 		Title:       req.Title,
 		Author:      req.Author,
-		ISBN:        req.ISBN,
+		ISBN:        isbn13,
 		Publisher:   req.Publisher,
 		PublishDate: req.PublishDate,
 		Description: req.Description,
 		Copies:      req.Copies,
+		ItemType:    itemType(req.ItemType),
+		Dewey:       classification.Normalize(enums.SchemeDewey, req.Dewey),
+		LCC:         classification.Normalize(enums.SchemeLCC, req.LCC),
+	}
+
+	// Each copy is catalogued as an item with a generated barcode, created
+	// along with the book
+	now := time.Now()
+	book.Items = make([]entities.Item, req.Copies)
+	for i := range book.Items {
+		book.Items[i] = item.NewShelfItem(uuid.Nil, now)
+	}
+
+	// The authors are linked in the same insert; authors not yet in the
+	// catalogue are added first
+	book.Contributors, err = s.requestedContributors(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	book.Subjects, err = s.requestedSubjects(ctx, req.SubjectIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// The publisher credit links to the registry, registering new
+	// publishers as they are first credited
+	book.PublisherID, err = s.bookPublisher(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.WorkID != nil {
+		book.WorkID, err = s.bookWork(ctx, *req.WorkID)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Save to database
-	err := s.model.Book.Create(ctx, book)
+	err = s.model.Book.Create(ctx, book)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	resp := s.toBookResponse(book)
+	if book.WorkID == nil {
+		resp.WorkSuggestions = s.workSuggestions(ctx, book)
+	}
+
+	return resp, nil
 }
 
 // GetBookByID retrieves a book by its ID
@@ -38,79 +95,372 @@ func (s *service) GetBookByID(ctx context.Context, id uuid.UUID) (*entities.Book
 		return nil, err
 	}
 
-	return &entities.BookResponse{
-		ID:          book.ID,
-		Title:       book.Title,
-		Author:      book.Author,
-		ISBN:        book.ISBN,
-		Publisher:   book.Publisher,
-		PublishDate: book.PublishDate,
-		Description: book.Description,
-		Copies:      book.Copies,
-		CreatedAt:   book.CreatedAt,
-		UpdatedAt:   book.UpdatedAt,
-	}, nil
+	return s.withDetails(ctx, book)
 }
 
-// GetAllBooks retrieves all books from the library
-func (s *service) GetAllBooks(ctx context.Context) ([]*entities.BookResponse, error) {
-	// Get all books from database
-	books, err := s.model.Book.GetAll(ctx)
+// GetBookByISBN retrieves a book by its ISBN-10 or ISBN-13
+func (s *service) GetBookByISBN(ctx context.Context, raw string) (*entities.BookResponse, error) {
+	isbn13, err := canonicalISBN(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := s.model.Book.GetByISBN(ctx, isbn13)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withDetails(ctx, book)
+}
+
+// withDetails maps a book onto its response along with the people
+// credited on it and the subjects it is filed under
+func (s *service) withDetails(ctx context.Context, book *entities.Book) (*entities.BookResponse, error) {
+	contributors, err := s.model.Author.ForBook(ctx, book.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	subjects, err := s.model.Subject.ForBook(ctx, book.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := s.toBookResponse(book)
+	resp.Contributors = toContributorResponses(contributors)
+	for i := range subjects {
+		resp.Subjects = append(resp.Subjects, toSubjectSummary(&subjects[i]))
+	}
+	return resp, nil
+}
+
+// GetAllBooks retrieves one page of books matching the query
+func (s *service) GetAllBooks(ctx context.Context, query *entities.BookQuery) (*entities.BookPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = entities.DefaultPageSize
+	}
+	if query.Limit > entities.MaxPageSize {
+		query.Limit = entities.MaxPageSize
+	}
+	if query.Page < 1 && query.Cursor == "" {
+		query.Page = 1
+	}
+
+	// Get the page of books from database
+	books, info, err := s.model.Book.List(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 
 	resp := make([]*entities.BookResponse, len(books))
-	for i, book := range books {
-		resp[i] = &entities.BookResponse{
-			ID:          book.ID,
-			Title:       book.Title,
-			Author:      book.Author,
-			ISBN:        book.ISBN,
-			Publisher:   book.Publisher,
-			PublishDate: book.PublishDate,
-			Description: book.Description,
-			Copies:      book.Copies,
-			CreatedAt:   book.CreatedAt,
-			UpdatedAt:   book.UpdatedAt,
+	for i := range books {
+		resp[i] = s.toBookResponse(&books[i])
+	}
+
+	page := &entities.BookPage{
+		Data:       resp,
+		Total:      info.Total,
+		Limit:      query.Limit,
+		NextCursor: info.NextCursor,
+		PrevCursor: info.PrevCursor,
+	}
+	if query.Cursor == "" {
+		page.Page = query.Page
+	}
+
+	return page, nil
+}
+
+// SearchBooks runs a ranked full-text search, falling back to fuzzy matching
+func (s *service) SearchBooks(ctx context.Context, query *entities.BookSearchQuery) (*entities.BookSearchPage, error) {
+	query.Q = strings.TrimSpace(query.Q)
+	if query.Q == "" {
+		return nil, fmt.Errorf("%w: search text is required", entities.ErrInvalidBookQuery)
+	}
+	if query.Limit <= 0 {
+		query.Limit = entities.DefaultPageSize
+	}
+	if query.Limit > entities.MaxPageSize {
+		query.Limit = entities.MaxPageSize
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+
+	result, err := s.model.Book.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entities.BookSearchResponse, len(result.Hits))
+	for i, hit := range result.Hits {
+		resp[i] = &entities.BookSearchResponse{
+			BookResponse:   *s.toBookResponse(&hit.Book),
+			Rank:           hit.Rank,
+			TitleHighlight: hit.TitleHighlight,
+			Snippet:        hit.Snippet,
 		}
 	}
 
-	return resp, nil
+	return &entities.BookSearchPage{
+		Data:  resp,
+		Total: result.Total,
+		Page:  query.Page,
+		Limit: query.Limit,
+		Fuzzy: result.Fuzzy,
+	}, nil
 }
 
-// UpdateBook modifies an existing book in the library
-func (s *service) UpdateBook(ctx context.Context, id uuid.UUID, req *entities.BookRequest) error {
-	// Check if book exists
-	existingBook, err := s.model.Book.GetByID(ctx, id)
+// UpdateBook replaces the details of a book and returns it as stored. A
+// non-zero version is the one the change was based on: the update fails
+// with ErrBookModified if the book has changed since.
+func (s *service) UpdateBook(ctx context.Context, id uuid.UUID, version int64, req *entities.BookRequest) (*entities.BookResponse, error) {
+	book, err := s.bookAt(ctx, id, version)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	existingBook.Title = req.Title
-	existingBook.Author = req.Author
-	existingBook.ISBN = req.ISBN
-	existingBook.Publisher = req.Publisher
-	existingBook.PublishDate = req.PublishDate
-	existingBook.Description = req.Description
-	existingBook.Copies = req.Copies
+	return s.saveBook(ctx, book, req, nil)
+}
 
-	err = s.model.Book.Update(ctx, existingBook)
+// saveBook applies a request to a stored book and saves the columns and
+// links that changed. current is the book as a request when the request
+// was made by patching it, so that the links it holds are compared with
+// the stored ones; without it, links left out of the request are kept.
+func (s *service) saveBook(ctx context.Context, book *entities.Book, req, current *entities.BookRequest) (*entities.BookResponse, error) {
+	isbn13, err := canonicalISBN(req.ISBN)
+	if err != nil {
+		return nil, err
+	}
+	id := book.ID
+	before := *book
+
+	change := &entities.BookChange{Book: book}
+
+	// Contributors given replace them all; otherwise a changed credit
+	// replaces the authors only
+	replaceContributors := len(req.Contributors) > 0
+	if current != nil {
+		replaceContributors = !slices.EqualFunc(req.Contributors, current.Contributors, sameContributor)
+	}
+	switch {
+	case replaceContributors:
+		change.Contributors, err = s.requestedContributors(ctx, req)
+	case book.Author != req.Author:
+		change.Contributors, err = s.recreditedContributors(ctx, id, req.Author)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if replaceContributors && change.Contributors == nil {
+		change.Contributors = []entities.BookContributor{}
+	}
+
+	// The registry link only follows a changed publisher credit, so a
+	// publisher picked by hand is kept. A patch that leaves the link alone
+	// is treated as leaving it out.
+	if current != nil && sameID(req.PublisherID, current.PublisherID) {
+		req.PublisherID = nil
+	}
+	if req.PublisherID != nil || book.Publisher != req.Publisher {
+		book.PublisherID, err = s.bookPublisher(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case req.WorkID != nil && (current == nil || !sameID(req.WorkID, current.WorkID)):
+		book.WorkID, err = s.bookWork(ctx, *req.WorkID)
+		if err != nil {
+			return nil, err
+		}
+	case req.WorkID == nil && current != nil:
+		book.WorkID = nil
+	}
+
+	book.Title = req.Title
+	book.Author = req.Author
+	book.ISBN = isbn13
+	book.Publisher = req.Publisher
+	book.PublishDate = req.PublishDate
+	book.Description = req.Description
+	book.ItemType = itemType(req.ItemType)
+	book.Dewey = classification.Normalize(enums.SchemeDewey, req.Dewey)
+	book.LCC = classification.Normalize(enums.SchemeLCC, req.LCC)
+
+	change.Columns = changedColumns(&before, book)
+
+	replaceSubjects := req.SubjectIDs != nil
+	if current != nil {
+		replaceSubjects = !slices.Equal(req.SubjectIDs, current.SubjectIDs)
+	}
+	if replaceSubjects {
+		// An empty list, rather than none, clears the subjects
+		change.SubjectIDs = append([]uuid.UUID{}, req.SubjectIDs...)
+	}
+
+	if current == nil || req.Copies != current.Copies {
+		change.Copies = &req.Copies
+	}
+
+	// The columns, links and copies are saved together under the book's
+	// lock, which checks the version again
+	if err := s.model.Book.Update(ctx, change); err != nil {
+		return nil, err
+	}
+
+	// New copies go to members waiting in the hold queue first
+	if change.Copies != nil && req.Copies > 0 {
+		s.allocateHolds(ctx, id)
+	}
+
+	// Allocating holds may have moved the version on again, so read the
+	// book back for the caller's next conditional request
+	return s.GetBookByID(ctx, id)
+}
+
+// bookAt reads a book for a change based on a version, which fails with
+// ErrBookModified if the book has moved on. Version zero takes any.
+func (s *service) bookAt(ctx context.Context, id uuid.UUID, version int64) (*entities.Book, error) {
+	book, err := s.model.Book.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && book.Version != version {
+		return nil, entities.ErrBookModified
+	}
+	return book, nil
+}
+
+// changedColumns lists the catalogue columns that differ between two
+// states of a book
+func changedColumns(before, after *entities.Book) []string {
+	var columns []string
+	changed := func(column string, differs bool) {
+		if differs {
+			columns = append(columns, column)
+		}
+	}
+	changed("title", before.Title != after.Title)
+	changed("author", before.Author != after.Author)
+	changed("isbn", before.ISBN != after.ISBN)
+	changed("publisher", before.Publisher != after.Publisher)
+	changed("publisher_id", !sameID(before.PublisherID, after.PublisherID))
+	changed("publish_date", !before.PublishDate.Equal(after.PublishDate))
+	changed("description", before.Description != after.Description)
+	changed("item_type", before.ItemType != after.ItemType)
+	changed("dewey", before.Dewey != after.Dewey)
+	changed("lcc", before.LCC != after.LCC)
+	changed("work_id", !sameID(before.WorkID, after.WorkID))
+	return columns
+}
+
+func sameID(a, b *uuid.UUID) bool {
+	return a == b || (a != nil && b != nil && *a == *b)
+}
+
+// DeleteBook moves a book to the trash. A non-zero version must be the
+// book's current one.
+func (s *service) DeleteBook(ctx context.Context, id uuid.UUID, version int64) error {
+
+	err := s.model.Book.Delete(ctx, id, version)
 	if err != nil {
 		return err
 	}
 
-	// Return response
 	return nil
 }
 
-// DeleteBook removes a book from the library
-func (s *service) DeleteBook(ctx context.Context, id uuid.UUID) error {
+// ListTrash lists the deleted books awaiting restore or purge
+func (s *service) ListTrash(ctx context.Context) ([]*entities.BookResponse, error) {
+	books, err := s.model.Book.Trash(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entities.BookResponse, len(books))
+	for i := range books {
+		resp[i] = s.toBookResponse(&books[i])
+	}
+
+	return resp, nil
+}
+
+// RestoreBook takes a deleted book out of the trash
+func (s *service) RestoreBook(ctx context.Context, id uuid.UUID) (*entities.BookResponse, error) {
+	book, err := s.model.Book.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toBookResponse(book), nil
+}
 
-	err := s.model.Book.Delete(ctx, id)
+// PurgeBook permanently removes a deleted book
+func (s *service) PurgeBook(ctx context.Context, id uuid.UUID) error {
+	return s.model.Book.Purge(ctx, id)
+}
+
+// PurgeTrash permanently removes books that have been in the trash longer
+// than the retention period
+func (s *service) PurgeTrash(ctx context.Context) error {
+	purged, err := s.model.Book.PurgeTrash(ctx, time.Now().Add(-s.config.TrashRetention))
 	if err != nil {
 		return err
 	}
 
+	if purged > 0 {
+		log.Printf("purged %d books from the trash", purged)
+	}
 	return nil
 }
+
+// canonicalISBN converts either ISBN form to the ISBN-13 stored on books
+func canonicalISBN(raw string) (string, error) {
+	isbn13, err := isbn.To13(raw)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", entities.ErrInvalidISBN, err)
+	}
+	return isbn13, nil
+}
+
+// itemType defaults an unset item type to a plain book
+func itemType(t enums.ItemType) enums.ItemType {
+	if t == "" {
+		return enums.ItemBook
+	}
+	return t
+}
+
+// toBookResponse maps a stored book onto its API representation
+func (s *service) toBookResponse(book *entities.Book) *entities.BookResponse {
+	isbn10, _ := isbn.To10(book.ISBN)
+
+	resp := &entities.BookResponse{
+		ID:          book.ID,
+		Title:       book.Title,
+		Author:      book.Author,
+		ISBN:        book.ISBN,
+		ISBN10:      isbn10,
+		Publisher:   book.Publisher,
+		PublisherID: book.PublisherID,
+		PublishDate: book.PublishDate,
+		Description: book.Description,
+		Copies:      book.Copies,
+		ItemType:    book.ItemType,
+		Dewey:       book.Dewey,
+		LCC:         book.LCC,
+		WorkID:      book.WorkID,
+		Version:     book.Version,
+		CoverURLs:   s.coverURLs(book),
+		CreatedAt:   book.CreatedAt,
+		UpdatedAt:   book.UpdatedAt,
+		MARC:        book.MARC,
+	}
+	if book.DeletedAt.Valid {
+		resp.DeletedAt = &book.DeletedAt.Time
+	}
+
+	return resp
+}
@@ -4,12 +4,21 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"slices"
 	"testing"
 	"time"
 
+	"library-system/internal/config"
 	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
 	"library-system/internal/models"
+	authorMock "library-system/internal/models/author/mocks"
 	bookMock "library-system/internal/models/book/mocks"
+	holdMock "library-system/internal/models/hold/mocks"
+	itemMock "library-system/internal/models/item/mocks"
+	publisherMock "library-system/internal/models/publisher/mocks"
+	subjectMock "library-system/internal/models/subject/mocks"
+	workMock "library-system/internal/models/work/mocks"
 
 	"github.com/gofrs/uuid"
 	"github.com/stretchr/testify/mock"
@@ -22,22 +31,52 @@ func Test_service_CreateBook(t *testing.T) {
 	req := &entities.BookRequest{
 		Title:       "Test Book",
 		Author:      "Test Author",
-		ISBN:        "1234567890",
+		ISBN:        "0-06-112008-1",
 		Publisher:   "Test Publisher",
 		PublishDate: testTime,
 		Description: "Test Description",
 		Copies:      5,
 	}
 
+	// The credited author is found or added, and linked with the book
+	authorID, _ := uuid.NewV4()
+	authors := authorMock.Author{}
+	authors.On("Resolve", mock.Anything, []string{"Test Author"}).Return([]entities.Author{{ID: authorID, Name: "Test Author"}}, nil)
+
+	// Another edition by the author is not grouped yet, and a work of the
+	// same title and author exists
+	editionID, _ := uuid.NewV4()
+	authors.On("Books", mock.Anything, authorID, enums.ContributorAuthor).Return([]entities.Book{
+		{ID: bookID, Title: "Test Book"},
+		{ID: editionID, Title: "The Test Book: Revised Edition"},
+		{Title: "Another Test Book"},
+	}, nil)
+	workID, _ := uuid.NewV4()
+	works := workMock.Work{}
+	works.On("Matching", mock.Anything, "testbook", "testauthor").Return([]entities.Work{{ID: workID, Title: "Test Book"}}, nil)
+	works.On("GetByID", mock.Anything, workID).Return(&entities.Work{ID: workID}, nil)
+
+	// So is the publisher
+	publisherID, _ := uuid.NewV4()
+	publishers := publisherMock.Publisher{}
+	publishers.On("Resolve", mock.Anything, []string{"Test Publisher"}).Return([]*uuid.UUID{&publisherID}, nil)
+
 	successMock := bookMock.Book{}
 	successMock.On("Create", mock.Anything, mock.MatchedBy(func(b *entities.Book) bool {
 		return b.Title == req.Title &&
 			b.Author == req.Author &&
-			b.ISBN == req.ISBN &&
+			b.ISBN == "9780061120084" &&
 			b.Publisher == req.Publisher &&
+			*b.PublisherID == publisherID &&
 			b.PublishDate.Equal(req.PublishDate) &&
 			b.Description == req.Description &&
-			b.Copies == req.Copies
+			b.Copies == req.Copies &&
+			len(b.Items) == req.Copies &&
+			b.Items[0].Status == enums.ItemAvailable &&
+			b.Items[0].Barcode != b.Items[1].Barcode &&
+			len(b.Contributors) == 1 &&
+			b.Contributors[0].AuthorID == authorID &&
+			b.Contributors[0].Role == enums.ContributorAuthor
 	})).Return(nil).Run(func(args mock.Arguments) {
 		book := args.Get(1).(*entities.Book)
 		book.ID = bookID
@@ -48,30 +87,54 @@ func Test_service_CreateBook(t *testing.T) {
 	errorMock := bookMock.Book{}
 	errorMock.On("Create", mock.Anything, mock.Anything).Return(errors.New("database error"))
 
+	invalidISBN := *req
+	invalidISBN.ISBN = "1234567890"
+
+	grouped := *req
+	grouped.WorkID = &workID
+
 	tests := []struct {
-		name    string
-		s       *service
-		req     *entities.BookRequest
-		wantErr bool
+		name            string
+		s               *service
+		req             *entities.BookRequest
+		wantSuggestions []entities.WorkSuggestion
+		wantErr         bool
 	}{
 		{
 			name: "successful creation",
-			s:    &service{model: models.Model{Book: &successMock}},
-			req:  req, wantErr: false,
+			s:    &service{model: models.Model{Book: &successMock, Author: &authors, Publisher: &publishers, Work: &works}},
+			req:  req,
+			wantSuggestions: []entities.WorkSuggestion{
+				{Work: &entities.WorkSummary{ID: workID, Title: "Test Book"}},
+				{BookIDs: []uuid.UUID{bookID, editionID}},
+			},
+		},
+		{
+			name: "grouped under a work",
+			s:    &service{model: models.Model{Book: &successMock, Author: &authors, Publisher: &publishers, Work: &works}},
+			req:  &grouped,
 		},
 		{
 			name: "database error",
-			s:    &service{model: models.Model{Book: &errorMock}},
+			s:    &service{model: models.Model{Book: &errorMock, Author: &authors, Publisher: &publishers}},
 			req:  req, wantErr: true,
 		},
+		{
+			name: "invalid isbn",
+			s:    &service{model: models.Model{Book: &bookMock.Book{}}},
+			req:  &invalidISBN, wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.s.CreateBook(context.Background(), tt.req)
+			got, err := tt.s.CreateBook(context.Background(), tt.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateBook() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if got != nil && !reflect.DeepEqual(got.WorkSuggestions, tt.wantSuggestions) {
+				t.Errorf("CreateBook() suggestions = %+v, want %+v", got.WorkSuggestions, tt.wantSuggestions)
+			}
 			tt.s.model.Book.(*bookMock.Book).AssertExpectations(t)
 		})
 	}
@@ -79,6 +142,8 @@ func Test_service_CreateBook(t *testing.T) {
 
 func Test_service_GetBookByID(t *testing.T) {
 	bookID, _ := uuid.NewV4()
+	authorID, _ := uuid.NewV4()
+	subjectID, _ := uuid.NewV4()
 	invalidID, _ := uuid.NewV4()
 	testTime := time.Now()
 
@@ -93,6 +158,7 @@ func Test_service_GetBookByID(t *testing.T) {
 		PublishDate: testTime,
 		Description: "Test Description",
 		Copies:      5,
+		Version:     3,
 	}
 
 	expected := &entities.BookResponse{
@@ -104,13 +170,27 @@ func Test_service_GetBookByID(t *testing.T) {
 		PublishDate: book.PublishDate,
 		Description: book.Description,
 		Copies:      book.Copies,
+		Version:     3,
 		CreatedAt:   testTime,
 		UpdatedAt:   testTime,
+		Contributors: []entities.ContributorResponse{
+			{AuthorID: authorID, Name: "Test Author", SortName: "Author, Test", Role: enums.ContributorAuthor},
+		},
+		Subjects: []entities.SubjectSummary{{ID: subjectID, Name: "Fiction", Kind: enums.SubjectGenre}},
 	}
 
 	successMock := bookMock.Book{}
 	successMock.On("GetByID", mock.Anything, bookID).Return(book, nil)
 
+	authors := authorMock.Author{}
+	authors.On("ForBook", mock.Anything, bookID).Return([]entities.BookContributor{{
+		BookID: bookID, AuthorID: authorID, Role: enums.ContributorAuthor,
+		Author: &entities.Author{ID: authorID, Name: "Test Author", SortName: "Author, Test"},
+	}}, nil)
+
+	subjects := subjectMock.Subject{}
+	subjects.On("ForBook", mock.Anything, bookID).Return([]entities.Subject{{ID: subjectID, Name: "Fiction", Kind: enums.SubjectGenre}}, nil)
+
 	notFoundMock := bookMock.Book{}
 	notFoundMock.On("GetByID", mock.Anything, invalidID).Return(nil, errors.New("book not found"))
 
@@ -123,7 +203,7 @@ func Test_service_GetBookByID(t *testing.T) {
 	}{
 		{
 			name: "found",
-			s:    &service{model: models.Model{Book: &successMock}},
+			s:    &service{model: models.Model{Book: &successMock, Author: &authors, Subject: &subjects}},
 			id:   bookID,
 			want: expected,
 		},
@@ -149,6 +229,66 @@ func Test_service_GetBookByID(t *testing.T) {
 	}
 }
 
+func Test_service_GetBookByISBN(t *testing.T) {
+	bookID, _ := uuid.NewV4()
+	now := time.Now()
+
+	book := &entities.Book{ID: bookID, Title: "To Kill a Mockingbird", ISBN: "9780061120084", CreatedAt: now, UpdatedAt: now}
+
+	successMock := bookMock.Book{}
+	successMock.On("GetByISBN", mock.Anything, "9780061120084").Return(book, nil)
+
+	notFoundMock := bookMock.Book{}
+	notFoundMock.On("GetByISBN", mock.Anything, "9780451524935").Return(nil, entities.ErrBookNotFound)
+
+	authors := authorMock.Author{}
+	authors.On("ForBook", mock.Anything, bookID).Return([]entities.BookContributor{}, nil)
+
+	subjects := subjectMock.Subject{}
+	subjects.On("ForBook", mock.Anything, bookID).Return([]entities.Subject{}, nil)
+
+	tests := []struct {
+		name    string
+		s       *service
+		isbn    string
+		want    *entities.BookResponse
+		wantErr error
+	}{
+		{
+			name: "lookup by isbn-10",
+			s:    &service{model: models.Model{Book: &successMock, Author: &authors, Subject: &subjects}},
+			isbn: "0-06-112008-1",
+			want: &entities.BookResponse{ID: bookID, Title: book.Title, ISBN: "9780061120084", ISBN10: "0061120081", CreatedAt: now, UpdatedAt: now,
+				Contributors: []entities.ContributorResponse{}},
+		},
+		{
+			name:    "not found",
+			s:       &service{model: models.Model{Book: &notFoundMock}},
+			isbn:    "9780451524935",
+			wantErr: entities.ErrBookNotFound,
+		},
+		{
+			name:    "invalid isbn",
+			s:       &service{model: models.Model{Book: &bookMock.Book{}}},
+			isbn:    "9780451524936",
+			wantErr: entities.ErrInvalidISBN,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.GetBookByISBN(context.Background(), tt.isbn)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetBookByISBN() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBookByISBN() = %v, want %v", got, tt.want)
+			}
+			tt.s.model.Book.(*bookMock.Book).AssertExpectations(t)
+		})
+	}
+}
+
 func Test_service_GetAllBooks(t *testing.T) {
 	id1, _ := uuid.NewV4()
 	id2, _ := uuid.NewV4()
@@ -159,49 +299,75 @@ func Test_service_GetAllBooks(t *testing.T) {
 		{ID: id2, Title: "Book 2", Author: "Author 2", ISBN: "222", Publisher: "Pub 2", PublishDate: now, Description: "Desc 2", Copies: 3, CreatedAt: now, UpdatedAt: now},
 	}
 
-	expected := []*entities.BookResponse{
-		{ID: id1, Title: "Book 1", Author: "Author 1", ISBN: "111", Publisher: "Pub 1", PublishDate: now, Description: "Desc 1", Copies: 2, CreatedAt: now, UpdatedAt: now},
-		{ID: id2, Title: "Book 2", Author: "Author 2", ISBN: "222", Publisher: "Pub 2", PublishDate: now, Description: "Desc 2", Copies: 3, CreatedAt: now, UpdatedAt: now},
+	expected := &entities.BookPage{
+		Data: []*entities.BookResponse{
+			{ID: id1, Title: "Book 1", Author: "Author 1", ISBN: "111", Publisher: "Pub 1", PublishDate: now, Description: "Desc 1", Copies: 2, CreatedAt: now, UpdatedAt: now},
+			{ID: id2, Title: "Book 2", Author: "Author 2", ISBN: "222", Publisher: "Pub 2", PublishDate: now, Description: "Desc 2", Copies: 3, CreatedAt: now, UpdatedAt: now},
+		},
+		Total:      5,
+		Page:       1,
+		Limit:      2,
+		NextCursor: "next",
 	}
 
 	successMock := bookMock.Book{}
-	successMock.On("GetAll", mock.Anything).Return(books, nil)
+	successMock.On("List", mock.Anything, mock.MatchedBy(func(q *entities.BookQuery) bool {
+		return q.Page == 1 && q.Limit == 2
+	})).Return(books, &entities.PageInfo{Total: 5, NextCursor: "next"}, nil)
 
 	errorMock := bookMock.Book{}
-	errorMock.On("GetAll", mock.Anything).Return(nil, errors.New("db error"))
+	errorMock.On("List", mock.Anything, mock.Anything).Return(nil, nil, errors.New("db error"))
 
 	emptyMock := bookMock.Book{}
-	emptyMock.On("GetAll", mock.Anything).Return([]entities.Book{}, nil)
+	emptyMock.On("List", mock.Anything, mock.MatchedBy(func(q *entities.BookQuery) bool {
+		return q.Limit == entities.DefaultPageSize
+	})).Return([]entities.Book{}, &entities.PageInfo{}, nil)
+
+	cappedMock := bookMock.Book{}
+	cappedMock.On("List", mock.Anything, mock.MatchedBy(func(q *entities.BookQuery) bool {
+		return q.Page == 0 && q.Limit == entities.MaxPageSize
+	})).Return([]entities.Book{}, &entities.PageInfo{}, nil)
 
 	tests := []struct {
 		name    string
 		s       *service
-		want    []*entities.BookResponse
+		query   *entities.BookQuery
+		want    *entities.BookPage
 		wantErr bool
 	}{
 		{
 			name:    "successful retrieval",
 			s:       &service{model: models.Model{Book: &successMock}},
+			query:   &entities.BookQuery{Limit: 2},
 			want:    expected,
 			wantErr: false,
 		},
 		{
 			name:    "database error",
 			s:       &service{model: models.Model{Book: &errorMock}},
+			query:   &entities.BookQuery{},
 			want:    nil,
 			wantErr: true,
 		},
 		{
 			name:    "empty result",
 			s:       &service{model: models.Model{Book: &emptyMock}},
-			want:    []*entities.BookResponse{},
+			query:   &entities.BookQuery{},
+			want:    &entities.BookPage{Data: []*entities.BookResponse{}, Page: 1, Limit: entities.DefaultPageSize},
+			wantErr: false,
+		},
+		{
+			name:    "cursor page with oversized limit",
+			s:       &service{model: models.Model{Book: &cappedMock}},
+			query:   &entities.BookQuery{Cursor: "abc", Limit: 1000},
+			want:    &entities.BookPage{Data: []*entities.BookResponse{}, Limit: entities.MaxPageSize},
 			wantErr: false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := tt.s.GetAllBooks(context.Background())
+			got, err := tt.s.GetAllBooks(context.Background(), tt.query)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetAllBooks() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -221,7 +387,7 @@ func Test_service_UpdateBook(t *testing.T) {
 	req := &entities.BookRequest{
 		Title:       "Updated",
 		Author:      "Updated Author",
-		ISBN:        "999",
+		ISBN:        "978-0-451-52493-5",
 		Publisher:   "Updated Publisher",
 		PublishDate: now,
 		Description: "Updated Desc",
@@ -230,39 +396,75 @@ func Test_service_UpdateBook(t *testing.T) {
 
 	existing := &entities.Book{
 		ID: bookID, Title: "Old", Author: "Old Author", ISBN: "111", Publisher: "Old Pub",
-		PublishDate: now, Description: "Old Desc", Copies: 1, CreatedAt: now, UpdatedAt: now,
+		PublishDate: now, Description: "Old Desc", Copies: 1, CreatedAt: now, UpdatedAt: now, Version: 4,
 	}
 
 	successMock := bookMock.Book{}
 	successMock.On("GetByID", mock.Anything, bookID).Return(existing, nil)
-	successMock.On("Update", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
-		book := args.Get(1).(*entities.Book)
+	// The new credit replaces the author; the illustrator stays. Copies are
+	// set by stocking the shelf with items. All of it is saved at once.
+	authorID, _ := uuid.NewV4()
+	illustratorID, _ := uuid.NewV4()
+	successMock.On("Update", mock.Anything, mock.MatchedBy(func(c *entities.BookChange) bool {
+		return c.Book.PublisherID != nil &&
+			slices.Equal(c.Columns, []string{"title", "author", "isbn", "publisher", "publisher_id", "description", "item_type"}) &&
+			slices.Equal(c.Contributors, []entities.BookContributor{
+				{AuthorID: authorID, Role: enums.ContributorAuthor, Position: 0},
+				{AuthorID: illustratorID, Role: enums.ContributorIllustrator, Position: 1},
+			}) &&
+			c.SubjectIDs == nil && c.Copies != nil && *c.Copies == req.Copies
+	})).Return(nil).Run(func(args mock.Arguments) {
+		book := args.Get(1).(*entities.BookChange).Book
 		book.Title = req.Title
 		book.Author = req.Author
-		book.ISBN = req.ISBN
+		book.ISBN = "9780451524935"
 		book.Publisher = req.Publisher
 		book.PublishDate = req.PublishDate
 		book.Description = req.Description
-		book.Copies = req.Copies
 	})
 
+	authors := authorMock.Author{}
+	authors.On("Resolve", mock.Anything, []string{"Updated Author"}).Return([]entities.Author{{ID: authorID}}, nil)
+	authors.On("ForBook", mock.Anything, bookID).Return([]entities.BookContributor{
+		{BookID: bookID, AuthorID: illustratorID, Role: enums.ContributorIllustrator},
+		{BookID: bookID, AuthorID: uuid.Must(uuid.NewV4()), Role: enums.ContributorAuthor, Position: 1},
+	}, nil)
+	// The publisher credit changed, so the book is linked anew
+	publisherID, _ := uuid.NewV4()
+	publishers := publisherMock.Publisher{}
+	publishers.On("Resolve", mock.Anything, []string{"Updated Publisher"}).Return([]*uuid.UUID{&publisherID}, nil)
+
+	// The copies went up, so the hold queue gets the first pick
+	allocateMock := holdMock.Hold{}
+	allocateMock.On("Allocate", mock.Anything, bookID, mock.Anything, mock.Anything).Return([]entities.Hold{}, nil)
+
+	// The book is read back with its credits and subjects
+	subjects := subjectMock.Subject{}
+	subjects.On("ForBook", mock.Anything, bookID).Return([]entities.Subject{}, nil)
+
 	notFoundMock := bookMock.Book{}
 	notFoundMock.On("GetByID", mock.Anything, invalidID).Return(nil, errors.New("not found"))
 
+	unchanged := *existing
 	updateErrMock := bookMock.Book{}
-	updateErrMock.On("GetByID", mock.Anything, bookID).Return(existing, nil)
+	updateErrMock.On("GetByID", mock.Anything, bookID).Return(&unchanged, nil)
 	updateErrMock.On("Update", mock.Anything, mock.Anything).Return(errors.New("update error"))
 
+	staleMock := bookMock.Book{}
+	staleMock.On("GetByID", mock.Anything, bookID).Return(existing, nil)
+
 	tests := []struct {
 		name    string
 		s       *service
 		id      uuid.UUID
+		version int64
 		wantErr bool
 	}{
 		{
 			name:    "success",
-			s:       &service{model: models.Model{Book: &successMock}},
+			s:       &service{model: models.Model{Book: &successMock, Author: &authors, Publisher: &publishers, Hold: &allocateMock, Subject: &subjects}, config: &config.Config{HoldPickupDays: 3}},
 			id:      bookID,
+			version: 4,
 			wantErr: false,
 		},
 		{
@@ -272,19 +474,38 @@ func Test_service_UpdateBook(t *testing.T) {
 		},
 		{
 			name: "update error",
-			s:    &service{model: models.Model{Book: &updateErrMock}},
+			s:    &service{model: models.Model{Book: &updateErrMock, Author: &authors, Publisher: &publishers}},
 			id:   bookID, wantErr: true,
 		},
+		{
+			name:    "changed since read",
+			s:       &service{model: models.Model{Book: &staleMock}},
+			id:      bookID,
+			version: 3,
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.s.UpdateBook(context.Background(), tt.id, req)
+			got, err := tt.s.UpdateBook(context.Background(), tt.id, tt.version, req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("UpdateBook() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if err == nil && got.Title != req.Title {
+				t.Errorf("UpdateBook() = %+v, want the stored book", got)
+			}
 
 			tt.s.model.Book.(*bookMock.Book).AssertExpectations(t)
+			if tt.s.model.Author != nil {
+				tt.s.model.Author.(*authorMock.Author).AssertExpectations(t)
+			}
+			if tt.s.model.Item != nil {
+				tt.s.model.Item.(*itemMock.Item).AssertExpectations(t)
+			}
+			if tt.s.model.Hold != nil {
+				tt.s.model.Hold.(*holdMock.Hold).AssertExpectations(t)
+			}
 		})
 	}
 }
@@ -294,10 +515,10 @@ func Test_service_DeleteBook(t *testing.T) {
 	invalidID, _ := uuid.NewV4()
 
 	successMock := bookMock.Book{}
-	successMock.On("Delete", mock.Anything, bookID).Return(nil)
+	successMock.On("Delete", mock.Anything, bookID, int64(2)).Return(nil)
 
 	notFoundMock := bookMock.Book{}
-	notFoundMock.On("Delete", mock.Anything, invalidID).Return(errors.New("not found"))
+	notFoundMock.On("Delete", mock.Anything, invalidID, int64(2)).Return(errors.New("not found"))
 
 	tests := []struct {
 		name    string
@@ -320,7 +541,7 @@ func Test_service_DeleteBook(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := tt.s.DeleteBook(context.Background(), tt.id)
+			err := tt.s.DeleteBook(context.Background(), tt.id, 2)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("DeleteBook() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -328,3 +549,109 @@ func Test_service_DeleteBook(t *testing.T) {
 		})
 	}
 }
+
+func Test_service_SearchBooks(t *testing.T) {
+	id, _ := uuid.NewV4()
+	now := time.Now()
+
+	hit := entities.BookSearchHit{
+		Book:           entities.Book{ID: id, Title: "The Hobbit", Author: "J.R.R. Tolkien", CreatedAt: now, UpdatedAt: now},
+		Rank:           0.5,
+		TitleHighlight: "The <mark>Hobbit</mark>",
+		Snippet:        "A <mark>hobbit</mark> goes on an adventure",
+	}
+
+	successMock := bookMock.Book{}
+	successMock.On("Search", mock.Anything, mock.MatchedBy(func(q *entities.BookSearchQuery) bool {
+		return q.Q == "hobbit" && q.Page == 1 && q.Limit == entities.DefaultPageSize
+	})).Return(&entities.BookSearchResult{Hits: []entities.BookSearchHit{hit}, Total: 1, Fuzzy: true}, nil)
+
+	errorMock := bookMock.Book{}
+	errorMock.On("Search", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+	tests := []struct {
+		name    string
+		s       *service
+		query   *entities.BookSearchQuery
+		want    *entities.BookSearchPage
+		wantErr bool
+	}{
+		{
+			name:  "hits",
+			s:     &service{model: models.Model{Book: &successMock}},
+			query: &entities.BookSearchQuery{Q: "  hobbit "},
+			want: &entities.BookSearchPage{
+				Data: []*entities.BookSearchResponse{{
+					BookResponse:   entities.BookResponse{ID: id, Title: "The Hobbit", Author: "J.R.R. Tolkien", CreatedAt: now, UpdatedAt: now},
+					Rank:           0.5,
+					TitleHighlight: hit.TitleHighlight,
+					Snippet:        hit.Snippet,
+				}},
+				Total: 1,
+				Page:  1,
+				Limit: entities.DefaultPageSize,
+				Fuzzy: true,
+			},
+		},
+		{
+			name:    "empty search text",
+			s:       &service{model: models.Model{Book: &bookMock.Book{}}},
+			query:   &entities.BookSearchQuery{Q: "   "},
+			wantErr: true,
+		},
+		{
+			name:    "database error",
+			s:       &service{model: models.Model{Book: &errorMock}},
+			query:   &entities.BookSearchQuery{Q: "dune"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.SearchBooks(context.Background(), tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SearchBooks() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchBooks() = %v, want %v", got, tt.want)
+			}
+			tt.s.model.Book.(*bookMock.Book).AssertExpectations(t)
+		})
+	}
+}
+
+func Test_service_PurgeTrash(t *testing.T) {
+	retention := 30 * 24 * time.Hour
+
+	purgeMock := bookMock.Book{}
+	purgeMock.On("PurgeTrash", mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+		age := time.Since(cutoff)
+		return age >= retention && age < retention+time.Minute
+	})).Return(int64(2), nil)
+
+	s := &service{model: models.Model{Book: &purgeMock}, config: &config.Config{TrashRetention: retention}}
+	if err := s.PurgeTrash(context.Background()); err != nil {
+		t.Errorf("PurgeTrash() error = %v", err)
+	}
+
+	purgeMock.AssertExpectations(t)
+}
+
+func Test_service_RestoreBook(t *testing.T) {
+	bookID, _ := uuid.NewV4()
+
+	restoreMock := bookMock.Book{}
+	restoreMock.On("Restore", mock.Anything, bookID).Return(&entities.Book{ID: bookID, ISBN: "9780061120084"}, nil)
+
+	s := &service{model: models.Model{Book: &restoreMock}}
+	got, err := s.RestoreBook(context.Background(), bookID)
+	if err != nil {
+		t.Fatalf("RestoreBook() error = %v", err)
+	}
+	if got.ID != bookID || got.DeletedAt != nil {
+		t.Errorf("RestoreBook() = %+v", got)
+	}
+
+	restoreMock.AssertExpectations(t)
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"library-system/internal/covers"
+	"library-system/internal/entities"
+
+	"github.com/gofrs/uuid"
+)
+
+// SetCover stores an uploaded cover image for a book with its thumbnails,
+// replacing the book's previous cover. Version zero takes the book at any
+// version.
+func (s *service) SetCover(ctx context.Context, id uuid.UUID, version int64, r io.Reader) (*entities.BookResponse, error) {
+	book, err := s.bookAt(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, s.config.CoverMaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entities.ErrInvalidCover, err)
+	}
+	if int64(len(data)) > s.config.CoverMaxBytes {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", entities.ErrCoverTooLarge, s.config.CoverMaxBytes)
+	}
+
+	cover, err := covers.Process(id, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", entities.ErrInvalidCover, err)
+	}
+	for _, blob := range cover.Blobs() {
+		if err := s.store.Put(ctx, blob.Key, blob.Data, blob.ContentType); err != nil {
+			return nil, err
+		}
+	}
+
+	// The version is checked again under the book's lock; a book that
+	// moved on meanwhile keeps its cover and the new files are dropped
+	if err := s.model.Book.SetCover(ctx, id, cover.Original.Key, version); err != nil {
+		if book.Cover != cover.Original.Key {
+			s.deleteCoverBlobs(ctx, cover.Original.Key)
+		}
+		return nil, err
+	}
+	if book.Cover != cover.Original.Key {
+		s.deleteCoverBlobs(ctx, book.Cover)
+	}
+
+	// Setting the cover moved the version on, so read the book back for
+	// the caller's next conditional request
+	return s.GetBookByID(ctx, id)
+}
+
+// DeleteCover removes a book's cover image and its thumbnails. Version
+// zero takes the book at any version.
+func (s *service) DeleteCover(ctx context.Context, id uuid.UUID, version int64) error {
+	book, err := s.bookAt(ctx, id, version)
+	if err != nil {
+		return err
+	}
+	if book.Cover == "" {
+		return entities.ErrNoCover
+	}
+
+	if err := s.model.Book.SetCover(ctx, id, "", version); err != nil {
+		return err
+	}
+	s.deleteCoverBlobs(ctx, book.Cover)
+
+	return nil
+}
+
+// deleteCoverBlobs removes the files of a cover no book points at. A
+// failure only leaves unused files behind, so it is logged.
+func (s *service) deleteCoverBlobs(ctx context.Context, original string) {
+	for _, key := range covers.Keys(original) {
+		if err := s.store.Delete(ctx, key); err != nil {
+			log.Printf("deleting cover blob %s: %v", key, err)
+		}
+	}
+}
+
+// coverURLs links each size of a book's cover below the media base URL
+func (s *service) coverURLs(book *entities.Book) map[string]string {
+	keys := covers.Keys(book.Cover)
+	if keys == nil {
+		return nil
+	}
+
+	urls := make(map[string]string, len(keys))
+	for size, key := range keys {
+		urls[size] = s.config.MediaBaseURL + "/" + key
+	}
+	return urls
+}
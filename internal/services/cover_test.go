@@ -0,0 +1,103 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"library-system/internal/config"
+	"library-system/internal/entities"
+	"library-system/internal/models"
+	authorMock "library-system/internal/models/author/mocks"
+	bookMock "library-system/internal/models/book/mocks"
+	subjectMock "library-system/internal/models/subject/mocks"
+	"library-system/internal/storage"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_SetCover(t *testing.T) {
+	id := uuid.Must(uuid.NewV4())
+	old := "covers/" + id.String() + "/0000000000000000.png"
+
+	dir := t.TempDir()
+	store, err := storage.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Put(context.Background(), old, []byte("old"), "image/png")
+
+	stored := entities.Book{ID: id, Cover: old, Version: 2}
+	books := bookMock.Book{}
+	books.On("GetByID", mock.Anything, id).Return(func(context.Context, uuid.UUID) (*entities.Book, error) {
+		book := stored
+		return &book, nil
+	})
+	books.On("SetCover", mock.Anything, id, mock.AnythingOfType("string"), int64(2)).
+		Run(func(args mock.Arguments) {
+			stored.Cover = args.String(2)
+			stored.Version++
+		}).
+		Return(nil).Once()
+	books.On("SetCover", mock.Anything, id, mock.AnythingOfType("string"), int64(3)).Return(entities.ErrBookModified)
+	authors := authorMock.Author{}
+	authors.On("ForBook", mock.Anything, id).Return([]entities.BookContributor{}, nil)
+	subjects := subjectMock.Subject{}
+	subjects.On("ForBook", mock.Anything, id).Return([]entities.Subject{}, nil)
+
+	s := &service{
+		model:  models.Model{Book: &books, Author: &authors, Subject: &subjects},
+		config: &config.Config{MediaBaseURL: "/media", CoverMaxBytes: 64 << 10},
+		store:  store,
+	}
+
+	var upload bytes.Buffer
+	png.Encode(&upload, image.NewGray(image.Rect(0, 0, 100, 150)))
+
+	if _, err := s.SetCover(context.Background(), id, 1, bytes.NewReader(upload.Bytes())); !errors.Is(err, entities.ErrBookModified) {
+		t.Errorf("SetCover() at a stale version error = %v, want %v", err, entities.ErrBookModified)
+	}
+
+	got, err := s.SetCover(context.Background(), id, 2, bytes.NewReader(upload.Bytes()))
+	if err != nil {
+		t.Fatalf("SetCover() error = %v", err)
+	}
+	if got.Version != 3 {
+		t.Errorf("SetCover() version = %d, want 3", got.Version)
+	}
+	if len(got.CoverURLs) != 4 || !strings.HasPrefix(got.CoverURLs["medium"], "/media/covers/"+id.String()+"/") {
+		t.Errorf("SetCover() cover_urls = %v", got.CoverURLs)
+	}
+	for size, url := range got.CoverURLs {
+		if _, err := store.Get(context.Background(), strings.TrimPrefix(url, "/media/")); err != nil {
+			t.Errorf("cover %s not stored: %v", size, err)
+		}
+	}
+	if _, err := store.Get(context.Background(), old); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("old cover left behind, Get() error = %v", err)
+	}
+
+	// A book changed while the upload was processed keeps its cover
+	var other bytes.Buffer
+	png.Encode(&other, image.NewGray(image.Rect(0, 0, 90, 150)))
+	if _, err := s.SetCover(context.Background(), id, 3, bytes.NewReader(other.Bytes())); !errors.Is(err, entities.ErrBookModified) {
+		t.Errorf("SetCover() of a book changed meanwhile error = %v, want %v", err, entities.ErrBookModified)
+	}
+	if files, _ := os.ReadDir(filepath.Join(dir, "covers", id.String())); len(files) != 4 {
+		t.Errorf("%d cover files stored, want the 4 of the current cover", len(files))
+	}
+
+	if _, err := s.SetCover(context.Background(), id, 0, strings.NewReader("plain text")); !errors.Is(err, entities.ErrInvalidCover) {
+		t.Errorf("SetCover() of text error = %v, want %v", err, entities.ErrInvalidCover)
+	}
+	if _, err := s.SetCover(context.Background(), id, 0, bytes.NewReader(make([]byte, 65<<10))); !errors.Is(err, entities.ErrCoverTooLarge) {
+		t.Errorf("SetCover() of large upload error = %v, want %v", err, entities.ErrCoverTooLarge)
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"library-system/internal/bookio"
+	"library-system/internal/entities"
+)
+
+// ExportBooks writes every book matching the query's filters to w in the
+// given format, streaming them from the database. It returns how many
+// books were written. Once the first book is written an error can only
+// be reported, not undone.
+func (s *service) ExportBooks(ctx context.Context, format string, query *entities.BookQuery, w io.Writer) (int, error) {
+	if query.PublishedFrom != nil && query.PublishedTo != nil && query.PublishedFrom.After(*query.PublishedTo) {
+		return 0, fmt.Errorf("%w: published_from is after published_to", entities.ErrInvalidBookQuery)
+	}
+
+	bw, err := bookio.NewWriter(format, w)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", entities.ErrInvalidBookQuery, err)
+	}
+
+	written := 0
+	err = s.model.Book.Each(ctx, query, func(book *entities.Book) error {
+		if err := bw.Write(s.toBookResponse(book)); err != nil {
+			return err
+		}
+		written++
+		return nil
+	})
+	if err != nil {
+		return written, err
+	}
+
+	return written, bw.Flush()
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/models"
+	bookMock "library-system/internal/models/book/mocks"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_ExportBooks(t *testing.T) {
+	query := &entities.BookQuery{Author: "herbert"}
+	published := time.Date(1965, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	books := bookMock.Book{}
+	books.On("Each", mock.Anything, query, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(2).(func(*entities.Book) error)
+		fn(&entities.Book{Title: "Dune", Author: "Frank Herbert", ISBN: "9780441013593", PublishDate: published, Copies: 2})
+		fn(&entities.Book{Title: "Dune Messiah", Author: "Frank Herbert", ISBN: "9780593098233", PublishDate: published, Copies: 1})
+	}).Return(nil)
+
+	s := &service{model: models.Model{Book: &books}}
+
+	var buf bytes.Buffer
+	n, err := s.ExportBooks(context.Background(), "csv", query, &buf)
+	if err != nil {
+		t.Fatalf("ExportBooks() error = %v", err)
+	}
+	if n != 2 {
+		t.Errorf("ExportBooks() wrote %d books, want 2", n)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "9780441013593,Dune,Frank Herbert") {
+		t.Errorf("ExportBooks() output =\n%s", buf.String())
+	}
+
+	books.AssertExpectations(t)
+}
+
+func Test_service_ExportBooks_invalid(t *testing.T) {
+	from := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		format string
+		query  *entities.BookQuery
+	}{
+		{name: "unknown format", format: "xlsx", query: &entities.BookQuery{}},
+		{name: "reversed date range", format: "csv", query: &entities.BookQuery{PublishedFrom: &from, PublishedTo: &to}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{model: models.Model{Book: &bookMock.Book{}}}
+
+			var buf bytes.Buffer
+			_, err := s.ExportBooks(context.Background(), tt.format, tt.query, &buf)
+			if !errors.Is(err, entities.ErrInvalidBookQuery) {
+				t.Errorf("ExportBooks() error = %v, want %v", err, entities.ErrInvalidBookQuery)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("ExportBooks() wrote %q before failing", buf.String())
+			}
+		})
+	}
+}
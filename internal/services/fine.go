@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"library-system/internal/auth"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// GetMemberBalance reports what a member owes and whether they are in good
+// standing. Members see their own balance, staff anyone's.
+func (s *service) GetMemberBalance(ctx context.Context, memberID uuid.UUID) (*entities.MemberBalance, error) {
+	if err := s.authorizeMember(ctx, memberID); err != nil {
+		return nil, err
+	}
+
+	return s.memberBalance(ctx, memberID)
+}
+
+// ListMemberTransactions lists the ledger transactions of a member
+func (s *service) ListMemberTransactions(ctx context.Context, memberID uuid.UUID) ([]entities.LedgerTransaction, error) {
+	if err := s.authorizeMember(ctx, memberID); err != nil {
+		return nil, err
+	}
+
+	return s.model.Ledger.Transactions(ctx, memberID)
+}
+
+// IsInGoodStanding reports whether the member's balance is within the
+// good standing limit
+func (s *service) IsInGoodStanding(ctx context.Context, memberID uuid.UUID) (bool, error) {
+	balance, err := s.memberBalance(ctx, memberID)
+	if err != nil {
+		return false, err
+	}
+	return balance.GoodStanding, nil
+}
+
+// RecordPayment credits a payment against the member's balance
+func (s *service) RecordPayment(ctx context.Context, memberID uuid.UUID, req *entities.PaymentRequest) (*entities.LedgerTransaction, error) {
+	txn := newLedgerTransaction(enums.LedgerPayment, memberID, req.Amount, enums.AccountCash, enums.AccountReceivable)
+	txn.Description = req.Description
+
+	return s.post(ctx, txn)
+}
+
+// WaiveFine writes off part or all of the member's balance
+func (s *service) WaiveFine(ctx context.Context, memberID uuid.UUID, req *entities.WaiverRequest) (*entities.LedgerTransaction, error) {
+	txn := newLedgerTransaction(enums.LedgerWaiver, memberID, req.Amount, enums.AccountWaived, enums.AccountReceivable)
+	txn.Description = req.Reason
+	txn.LoanID = req.LoanID
+
+	return s.post(ctx, txn)
+}
+
+// ChargeMember bills a member for a lost or damaged copy
+func (s *service) ChargeMember(ctx context.Context, memberID uuid.UUID, req *entities.ChargeRequest) (*entities.LedgerTransaction, error) {
+	txn := newLedgerTransaction(enums.LedgerCharge, memberID, req.Amount, enums.AccountReceivable, enums.AccountChargeIncome)
+	txn.Description = req.Description
+	txn.LoanID = req.LoanID
+
+	return s.post(ctx, txn)
+}
+
+// post records a staff-initiated transaction in the name of the caller
+func (s *service) post(ctx context.Context, txn *entities.LedgerTransaction) (*entities.LedgerTransaction, error) {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		txn.ActorID = &principal.UserID
+	}
+
+	if err := s.model.Ledger.Post(ctx, txn); err != nil {
+		return nil, err
+	}
+
+	return txn, nil
+}
+
+func (s *service) memberBalance(ctx context.Context, memberID uuid.UUID) (*entities.MemberBalance, error) {
+	balance, err := s.model.Ledger.Balance(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.MemberBalance{
+		MemberID:     memberID,
+		Balance:      balance,
+		Threshold:    s.config.GoodStandingLimit,
+		GoodStanding: balance <= s.config.GoodStandingLimit,
+	}, nil
+}
+
+// authorizeMember lets members act on their own account and staff on anyone's
+func (s *service) authorizeMember(ctx context.Context, memberID uuid.UUID) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return entities.ErrUnauthenticated
+	}
+	if memberID != principal.UserID && !auth.Can(principal, auth.PermFinesManage) {
+		return entities.ErrForbidden
+	}
+	return nil
+}
+
+// overdueFine builds the fine for a loan returned late, or nil when nothing
+// is owed. The rate depends on the item type of the book.
+func (s *service) overdueFine(ctx context.Context, loan *entities.Loan, returnedAt time.Time) (*entities.LedgerTransaction, error) {
+	if !returnedAt.After(loan.DueAt) {
+		return nil, nil
+	}
+
+	itemType := enums.ItemBook
+	book, err := s.model.Book.GetByID(ctx, loan.BookID)
+	switch {
+	case err == nil:
+		itemType = book.ItemType
+	case !errors.Is(err, entities.ErrBookNotFound):
+		return nil, err
+	}
+
+	days, amount := fineAmount(loan.DueAt, returnedAt, s.config.FineRates[itemType], s.config.FineGraceDays, s.config.FineCap)
+	if amount == 0 {
+		return nil, nil
+	}
+
+	txn := newLedgerTransaction(enums.LedgerFine, loan.UserID, amount, enums.AccountReceivable, enums.AccountFineIncome)
+	txn.LoanID = &loan.ID
+	txn.Description = fmt.Sprintf("%d days overdue", days)
+
+	return txn, nil
+}
+
+// fineAmount charges every started day past the due date, less the grace
+// days, at the daily rate and up to the cap. It returns the days late too.
+func fineAmount(dueAt, returnedAt time.Time, rate int64, graceDays int, limit int64) (int, int64) {
+	if !returnedAt.After(dueAt) {
+		return 0, 0
+	}
+
+	days := int(returnedAt.Sub(dueAt)/(24*time.Hour)) + 1
+	fined := days - graceDays
+	if fined <= 0 {
+		return days, 0
+	}
+
+	return days, min(int64(fined)*rate, limit)
+}
+
+// newLedgerTransaction moves amount from the credited to the debited
+// account. Receivable entries carry the member so balances can be summed.
+func newLedgerTransaction(kind enums.LedgerKind, memberID uuid.UUID, amount int64, debit, credit enums.LedgerAccount) *entities.LedgerTransaction {
+	txn := &entities.LedgerTransaction{
+		CreatedAt: time.Now(),
+		MemberID:  memberID,
+		Kind:      kind,
+		Amount:    amount,
+	}
+	txn.ID, _ = uuid.NewV4()
+
+	entry := func(account enums.LedgerAccount, amount int64) entities.LedgerEntry {
+		e := entities.LedgerEntry{TransactionID: txn.ID, Account: account, Amount: amount}
+		e.ID, _ = uuid.NewV4()
+		if account == enums.AccountReceivable {
+			e.MemberID = &memberID
+		}
+		return e
+	}
+	txn.Entries = []entities.LedgerEntry{entry(debit, amount), entry(credit, -amount)}
+
+	return txn
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"library-system/internal/auth"
+	"library-system/internal/config"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models"
+	ledgerMock "library-system/internal/models/ledger/mocks"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_fineAmount(t *testing.T) {
+	due := time.Date(2024, time.March, 5, 23, 59, 59, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		returnedAt time.Time
+		wantDays   int
+		wantAmount int64
+	}{
+		{name: "on time", returnedAt: due.Add(-time.Hour), wantDays: 0, wantAmount: 0},
+		{name: "within grace", returnedAt: due.Add(10 * time.Hour), wantDays: 1, wantAmount: 0},
+		{name: "three days late", returnedAt: due.Add(50 * time.Hour), wantDays: 3, wantAmount: 50},
+		{name: "capped", returnedAt: due.AddDate(0, 0, 59).Add(time.Hour), wantDays: 60, wantAmount: 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			days, amount := fineAmount(due, tt.returnedAt, 25, 1, 1000)
+			if days != tt.wantDays || amount != tt.wantAmount {
+				t.Errorf("fineAmount() = %d, %d, want %d, %d", days, amount, tt.wantDays, tt.wantAmount)
+			}
+		})
+	}
+}
+
+func Test_service_GetMemberBalance(t *testing.T) {
+	memberID, _ := uuid.NewV4()
+	otherID, _ := uuid.NewV4()
+	memberCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: memberID, Roles: []enums.Role{enums.RoleMember}})
+	librarianCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: otherID, Roles: []enums.Role{enums.RoleLibrarian}})
+	cfg := &config.Config{GoodStandingLimit: 500}
+
+	ledger := ledgerMock.Ledger{}
+	ledger.On("Balance", mock.Anything, memberID).Return(int64(750), nil)
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		member  uuid.UUID
+		want    *entities.MemberBalance
+		wantErr error
+	}{
+		{
+			name:   "own balance",
+			ctx:    memberCtx,
+			member: memberID,
+			want:   &entities.MemberBalance{MemberID: memberID, Balance: 750, Threshold: 500, GoodStanding: false},
+		},
+		{
+			name:   "staff looks up a member",
+			ctx:    librarianCtx,
+			member: memberID,
+			want:   &entities.MemberBalance{MemberID: memberID, Balance: 750, Threshold: 500, GoodStanding: false},
+		},
+		{
+			name:    "someone else's balance",
+			ctx:     memberCtx,
+			member:  otherID,
+			wantErr: entities.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{model: models.Model{Ledger: &ledger}, config: cfg}
+			got, err := s.GetMemberBalance(tt.ctx, tt.member)
+			if err != tt.wantErr {
+				t.Errorf("GetMemberBalance() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && *got != *tt.want {
+				t.Errorf("GetMemberBalance() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_service_RecordPayment(t *testing.T) {
+	memberID, _ := uuid.NewV4()
+	librarianID, _ := uuid.NewV4()
+	ctx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: librarianID, Roles: []enums.Role{enums.RoleLibrarian}})
+
+	ledger := ledgerMock.Ledger{}
+	ledger.On("Post", ctx, mock.MatchedBy(func(txn *entities.LedgerTransaction) bool {
+		return txn.Amount == 300
+	})).Return(nil)
+	ledger.On("Post", ctx, mock.Anything).Return(entities.ErrAmountExceedsBalance)
+
+	s := &service{model: models.Model{Ledger: &ledger}}
+
+	got, err := s.RecordPayment(ctx, memberID, &entities.PaymentRequest{Amount: 300, Description: "cash"})
+	if err != nil {
+		t.Fatalf("RecordPayment() error = %v", err)
+	}
+	if got.Kind != enums.LedgerPayment || got.ReceivableChange() != -300 || !got.Balanced() || *got.ActorID != librarianID {
+		t.Errorf("RecordPayment() = %+v", got)
+	}
+
+	if _, err := s.RecordPayment(ctx, memberID, &entities.PaymentRequest{Amount: 5000}); err != entities.ErrAmountExceedsBalance {
+		t.Errorf("RecordPayment() error = %v, want %v", err, entities.ErrAmountExceedsBalance)
+	}
+}
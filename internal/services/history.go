@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// ListBookHistory lists the changes made to a book, the latest first. A
+// purged book's history is still listed.
+func (s *service) ListBookHistory(ctx context.Context, id uuid.UUID) ([]entities.BookRevision, error) {
+	revisions, err := s.model.Book.History(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, entities.ErrBookNotFound
+	}
+
+	return revisions, nil
+}
+
+// GetBookAsOf shows a book as it was at a point in time: the catalogue
+// details of the revision it was at, with the copies, credits, subjects
+// and cover it has now. A book that was not catalogued yet or was in the
+// trash at the time is not found.
+func (s *service) GetBookAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*entities.BookResponse, error) {
+	book, err := s.model.Book.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rev, err := s.model.Book.RevisionAt(ctx, id, at)
+	if err != nil {
+		if errors.Is(err, entities.ErrRevisionNotFound) {
+			return nil, entities.ErrBookNotFound
+		}
+		return nil, err
+	}
+	if rev.Action == enums.RevisionDeleted || rev.Action == enums.RevisionPurged {
+		return nil, entities.ErrBookNotFound
+	}
+
+	if err := applySnapshot(rev, book); err != nil {
+		return nil, err
+	}
+	book.Version = rev.Revision
+	book.UpdatedAt = rev.CreatedAt
+
+	return s.withDetails(ctx, book)
+}
+
+// RevertBook restores the catalogue details a book had at a revision, as
+// a change based on the given version that goes into the history like any
+// other. Copies, subjects and the cover are left as they are; the credited
+// authors follow the restored credit as they would on an update.
+func (s *service) RevertBook(ctx context.Context, id uuid.UUID, version, revision int64) (*entities.BookResponse, error) {
+	rev, err := s.model.Book.Revision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := s.bookAt(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.bookRequest(ctx, book)
+	if err != nil {
+		return nil, err
+	}
+	req := *current
+	if err := applySnapshot(rev, &req); err != nil {
+		return nil, err
+	}
+
+	reverted, err := s.saveBook(ctx, book, &req, current)
+	if errors.Is(err, entities.ErrInvalidPublisher) || errors.Is(err, entities.ErrInvalidWork) {
+		// The publisher or work the revision points at has gone
+		return nil, fmt.Errorf("%w: %v", entities.ErrRevisionOutdated, err)
+	}
+	return reverted, err
+}
+
+// applySnapshot sets the fields a revision kept onto a book or a book
+// request, whose JSON names they share
+func applySnapshot(rev *entities.BookRevision, v interface{}) error {
+	data, err := json.Marshal(rev.Snapshot)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
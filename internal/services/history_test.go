@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models"
+	authorMock "library-system/internal/models/author/mocks"
+	bookMock "library-system/internal/models/book/mocks"
+	subjectMock "library-system/internal/models/subject/mocks"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_ListBookHistory(t *testing.T) {
+	bookID := uuid.Must(uuid.NewV4())
+	purged := uuid.Must(uuid.NewV4())
+
+	books := bookMock.Book{}
+	books.On("History", mock.Anything, bookID).Return([]entities.BookRevision{
+		{BookID: bookID, Revision: 2, Action: enums.RevisionUpdated},
+		{BookID: bookID, Revision: 1, Action: enums.RevisionCreated},
+	}, nil)
+	books.On("History", mock.Anything, purged).Return([]entities.BookRevision{}, nil)
+
+	s := &service{model: models.Model{Book: &books}}
+
+	got, err := s.ListBookHistory(context.Background(), bookID)
+	if err != nil || len(got) != 2 || got[0].Revision != 2 {
+		t.Errorf("ListBookHistory() = %+v, %v", got, err)
+	}
+	if _, err := s.ListBookHistory(context.Background(), purged); !errors.Is(err, entities.ErrBookNotFound) {
+		t.Errorf("ListBookHistory() of unknown book error = %v, want %v", err, entities.ErrBookNotFound)
+	}
+}
+
+func Test_service_GetBookAsOf(t *testing.T) {
+	bookID := uuid.Must(uuid.NewV4())
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	stored := func() *entities.Book {
+		return &entities.Book{ID: bookID, Title: "Dune Messiah", Author: "Frank Herbert", Copies: 2, Version: 5}
+	}
+
+	tests := []struct {
+		name      string
+		rev       *entities.BookRevision
+		revErr    error
+		wantTitle string
+		wantErr   error
+	}{
+		{
+			name: "catalogued then",
+			rev: &entities.BookRevision{
+				Revision: 2, Action: enums.RevisionUpdated, CreatedAt: at.Add(-time.Hour),
+				Snapshot: map[string]json.RawMessage{"title": json.RawMessage(`"Dune"`), "author": json.RawMessage(`"Frank Herbert"`)},
+			},
+			wantTitle: "Dune",
+		},
+		{
+			name:    "in the trash then",
+			rev:     &entities.BookRevision{Revision: 3, Action: enums.RevisionDeleted},
+			wantErr: entities.ErrBookNotFound,
+		},
+		{
+			name:    "not catalogued yet",
+			revErr:  entities.ErrRevisionNotFound,
+			wantErr: entities.ErrBookNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			books := bookMock.Book{}
+			books.On("GetByID", mock.Anything, bookID).Return(stored(), nil)
+			books.On("RevisionAt", mock.Anything, bookID, at).Return(tt.rev, tt.revErr)
+
+			authors := authorMock.Author{}
+			authors.On("ForBook", mock.Anything, bookID).Return([]entities.BookContributor{}, nil)
+			subjects := subjectMock.Subject{}
+			subjects.On("ForBook", mock.Anything, bookID).Return([]entities.Subject{}, nil)
+
+			s := &service{model: models.Model{Book: &books, Author: &authors, Subject: &subjects}}
+
+			got, err := s.GetBookAsOf(context.Background(), bookID, at)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetBookAsOf() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			// Copies are not part of the history and read as they are now
+			if got.Title != tt.wantTitle || got.Version != tt.rev.Revision || got.Copies != 2 {
+				t.Errorf("GetBookAsOf() = %+v", got)
+			}
+		})
+	}
+}
+
+func Test_service_RevertBook(t *testing.T) {
+	bookID := uuid.Must(uuid.NewV4())
+	authorID := uuid.Must(uuid.NewV4())
+	filed := uuid.Must(uuid.NewV4())
+
+	book := &entities.Book{
+		ID: bookID, Title: "Nineteen Eighty-Four", Author: "George Orwell", ISBN: "9780451524935",
+		Publisher: "Signet", PublishDate: time.Date(1950, 7, 1, 0, 0, 0, 0, time.UTC),
+		Description: "Overwritten", ItemType: enums.ItemBook, Copies: 3, Version: 6,
+	}
+	rev := &entities.BookRevision{
+		BookID: bookID, Revision: 2, Action: enums.RevisionUpdated,
+		Snapshot: map[string]json.RawMessage{
+			"title":       json.RawMessage(`"Nineteen Eighty-Four"`),
+			"author":      json.RawMessage(`"George Orwell"`),
+			"description": json.RawMessage(`"A dystopia"`),
+			"lcc":         json.RawMessage(`"PR6029.R8 N49"`),
+			"work_id":     json.RawMessage(`null`),
+			"cover":       json.RawMessage(`"covers/old.jpg"`),
+		},
+	}
+
+	books := bookMock.Book{}
+	books.On("Revision", mock.Anything, bookID, int64(2)).Return(rev, nil)
+	books.On("Revision", mock.Anything, bookID, int64(9)).Return(nil, entities.ErrRevisionNotFound)
+	books.On("GetByID", mock.Anything, bookID).Return(book, nil)
+	books.On("Update", mock.Anything, mock.MatchedBy(func(c *entities.BookChange) bool {
+		return c.Book == book && slices.Equal(c.Columns, []string{"description", "lcc"}) &&
+			c.Contributors == nil && c.SubjectIDs == nil && c.Copies == nil
+	})).Return(nil)
+
+	authors := authorMock.Author{}
+	authors.On("ForBook", mock.Anything, bookID).Return([]entities.BookContributor{
+		{BookID: bookID, AuthorID: authorID, Role: enums.ContributorAuthor},
+	}, nil)
+	subjects := subjectMock.Subject{}
+	subjects.On("ForBook", mock.Anything, bookID).Return([]entities.Subject{{ID: filed}}, nil)
+
+	s := &service{model: models.Model{Book: &books, Author: &authors, Subject: &subjects}}
+
+	if _, err := s.RevertBook(context.Background(), bookID, 5, 2); !errors.Is(err, entities.ErrBookModified) {
+		t.Errorf("RevertBook() of an older version error = %v, want %v", err, entities.ErrBookModified)
+	}
+	if _, err := s.RevertBook(context.Background(), bookID, 6, 9); !errors.Is(err, entities.ErrRevisionNotFound) {
+		t.Errorf("RevertBook() to an unknown revision error = %v, want %v", err, entities.ErrRevisionNotFound)
+	}
+
+	got, err := s.RevertBook(context.Background(), bookID, 6, 2)
+	if err != nil || got.Description != "A dystopia" || got.LCC != "PR6029.R8 N49" {
+		t.Fatalf("RevertBook() = %+v, %v", got, err)
+	}
+
+	// Only the reverted details are written: the cover, copies, credits and
+	// subjects stay
+	books.AssertExpectations(t)
+	if book.Cover != "" {
+		t.Errorf("RevertBook() cover = %q", book.Cover)
+	}
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"library-system/internal/auth"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// PlaceHold queues the caller for a book with no copy on the shelf. Staff
+// may place a hold on behalf of another member.
+func (s *service) PlaceHold(ctx context.Context, bookID uuid.UUID, req *entities.HoldRequest) (*entities.HoldResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, entities.ErrUnauthenticated
+	}
+
+	member := principal.UserID
+	if req.UserID != nil && *req.UserID != principal.UserID {
+		if !auth.Can(principal, auth.PermHoldsManage) {
+			return nil, entities.ErrForbidden
+		}
+		member = *req.UserID
+	}
+
+	hold := &entities.Hold{
+		BookID: bookID,
+		UserID: member,
+	}
+	if err := s.model.Hold.Place(ctx, hold); err != nil {
+		return nil, err
+	}
+
+	return s.toHoldResponse(ctx, hold)
+}
+
+// CancelHold withdraws a hold. A copy that was waiting for pickup passes to
+// the next member in the queue.
+func (s *service) CancelHold(ctx context.Context, id uuid.UUID) (*entities.HoldResponse, error) {
+	if _, err := s.authorizeHold(ctx, id); err != nil {
+		return nil, err
+	}
+
+	hold, err := s.model.Hold.Cancel(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.allocateHolds(ctx, hold.BookID)
+
+	return s.toHoldResponse(ctx, hold)
+}
+
+// GetHold retrieves a hold with its queue position
+func (s *service) GetHold(ctx context.Context, id uuid.UUID) (*entities.HoldResponse, error) {
+	hold, err := s.authorizeHold(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toHoldResponse(ctx, hold)
+}
+
+// ListHolds lists the caller's holds. Staff may list any member's, or
+// everyone's when no member is given.
+func (s *service) ListHolds(ctx context.Context, query *entities.HoldQuery) ([]*entities.HoldResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, entities.ErrUnauthenticated
+	}
+
+	if !auth.Can(principal, auth.PermHoldsManage) {
+		if query.UserID != nil && *query.UserID != principal.UserID {
+			return nil, entities.ErrForbidden
+		}
+		query.UserID = &principal.UserID
+	}
+
+	holds, err := s.model.Hold.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entities.HoldResponse, len(holds))
+	for i := range holds {
+		if resp[i], err = s.toHoldResponse(ctx, &holds[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// GetHoldQueue lists the active holds on a book in the order they will be
+// served
+func (s *service) GetHoldQueue(ctx context.Context, bookID uuid.UUID) ([]*entities.HoldResponse, error) {
+	if _, err := s.model.Book.GetByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	holds, err := s.model.Hold.List(ctx, &entities.HoldQuery{BookID: &bookID, ActiveOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// The list is in queue order, so positions follow from it
+	resp := make([]*entities.HoldResponse, len(holds))
+	position := 0
+	for i := range holds {
+		resp[i] = toHoldResponse(&holds[i])
+		if holds[i].Status == enums.HoldWaiting {
+			position++
+			resp[i].Position = position
+		}
+	}
+
+	return resp, nil
+}
+
+// SetHoldPriority moves a waiting hold ahead of holds with lower priority
+func (s *service) SetHoldPriority(ctx context.Context, id uuid.UUID, req *entities.HoldPriorityRequest) (*entities.HoldResponse, error) {
+	hold, err := s.model.Hold.SetPriority(ctx, id, req.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.toHoldResponse(ctx, hold)
+}
+
+// ExpireHolds closes holds not picked up in time and offers their copies to
+// the next members in line
+func (s *service) ExpireHolds(ctx context.Context) error {
+	books, err := s.model.Hold.Expire(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, bookID := range books {
+		s.allocateHolds(ctx, bookID)
+	}
+
+	return nil
+}
+
+// allocateHolds sets aside free copies of a book for the head of its hold
+// queue. It runs after the copies went up and that change stands on its
+// own, so failures are logged rather than returned; the next change to the
+// book or the expiry sweep allocates again.
+func (s *service) allocateHolds(ctx context.Context, bookID uuid.UUID) {
+	now := time.Now()
+	ready, err := s.model.Hold.Allocate(ctx, bookID, now, dueDate(now, s.config.HoldPickupDays))
+	if err != nil {
+		log.Printf("allocating holds on book %s: %v", bookID, err)
+		return
+	}
+
+	for _, hold := range ready {
+		log.Printf("hold %s on book %s ready for pickup by user %s until %s",
+			hold.ID, hold.BookID, hold.UserID, hold.ExpiresAt.Format(time.RFC3339))
+	}
+}
+
+// authorizeHold loads a hold the caller is allowed to act on: their own, or
+// any hold for staff
+func (s *service) authorizeHold(ctx context.Context, id uuid.UUID) (*entities.Hold, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, entities.ErrUnauthenticated
+	}
+
+	hold, err := s.model.Hold.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.UserID != principal.UserID && !auth.Can(principal, auth.PermHoldsManage) {
+		// Don't reveal other members' holds
+		return nil, entities.ErrHoldNotFound
+	}
+
+	return hold, nil
+}
+
+// toHoldResponse maps a hold and looks up its queue position while waiting
+func (s *service) toHoldResponse(ctx context.Context, hold *entities.Hold) (*entities.HoldResponse, error) {
+	resp := toHoldResponse(hold)
+	if hold.Status != enums.HoldWaiting {
+		return resp, nil
+	}
+
+	position, err := s.model.Hold.Position(ctx, hold)
+	if err != nil {
+		return nil, err
+	}
+	resp.Position = position
+
+	return resp, nil
+}
+
+func toHoldResponse(hold *entities.Hold) *entities.HoldResponse {
+	return &entities.HoldResponse{
+		ID:        hold.ID,
+		BookID:    hold.BookID,
+		UserID:    hold.UserID,
+		Status:    hold.Status,
+		Priority:  hold.Priority,
+		ItemID:    hold.ItemID,
+		QueuedAt:  hold.QueuedAt,
+		ReadyAt:   hold.ReadyAt,
+		ExpiresAt: hold.ExpiresAt,
+		ClosedAt:  hold.ClosedAt,
+	}
+}
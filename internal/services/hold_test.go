@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"library-system/internal/auth"
+	"library-system/internal/config"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models"
+	bookMock "library-system/internal/models/book/mocks"
+	holdMock "library-system/internal/models/hold/mocks"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_PlaceHold(t *testing.T) {
+	bookID, _ := uuid.NewV4()
+	memberID, _ := uuid.NewV4()
+	otherID, _ := uuid.NewV4()
+	memberCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: memberID, Roles: []enums.Role{enums.RoleMember}})
+	librarianCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: otherID, Roles: []enums.Role{enums.RoleLibrarian}})
+
+	placeMock := holdMock.Hold{}
+	placeMock.On("Place", memberCtx, mock.MatchedBy(func(h *entities.Hold) bool {
+		return h.BookID == bookID && h.UserID == memberID
+	})).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*entities.Hold).Status = enums.HoldWaiting
+	})
+	placeMock.On("Position", memberCtx, mock.Anything).Return(4, nil)
+
+	onBehalfMock := holdMock.Hold{}
+	onBehalfMock.On("Place", librarianCtx, mock.MatchedBy(func(h *entities.Hold) bool {
+		return h.UserID == memberID
+	})).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*entities.Hold).Status = enums.HoldWaiting
+	})
+	onBehalfMock.On("Position", librarianCtx, mock.Anything).Return(1, nil)
+
+	availableMock := holdMock.Hold{}
+	availableMock.On("Place", memberCtx, mock.Anything).Return(entities.ErrCopiesAvailable)
+
+	tests := []struct {
+		name         string
+		s            *service
+		ctx          context.Context
+		req          *entities.HoldRequest
+		wantPosition int
+		wantErr      error
+	}{
+		{
+			name:         "member joins the queue",
+			s:            &service{model: models.Model{Hold: &placeMock}},
+			ctx:          memberCtx,
+			req:          &entities.HoldRequest{},
+			wantPosition: 4,
+		},
+		{
+			name:         "librarian places a hold for a member",
+			s:            &service{model: models.Model{Hold: &onBehalfMock}},
+			ctx:          librarianCtx,
+			req:          &entities.HoldRequest{UserID: &memberID},
+			wantPosition: 1,
+		},
+		{
+			name:    "member cannot place holds for others",
+			s:       &service{model: models.Model{Hold: &holdMock.Hold{}}},
+			ctx:     memberCtx,
+			req:     &entities.HoldRequest{UserID: &otherID},
+			wantErr: entities.ErrForbidden,
+		},
+		{
+			name:    "copies on the shelf",
+			s:       &service{model: models.Model{Hold: &availableMock}},
+			ctx:     memberCtx,
+			req:     &entities.HoldRequest{},
+			wantErr: entities.ErrCopiesAvailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.PlaceHold(tt.ctx, bookID, tt.req)
+			if err != tt.wantErr {
+				t.Errorf("PlaceHold() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Position != tt.wantPosition {
+				t.Errorf("PlaceHold() position = %d, want %d", got.Position, tt.wantPosition)
+			}
+			tt.s.model.Hold.(*holdMock.Hold).AssertExpectations(t)
+		})
+	}
+}
+
+func Test_service_GetHoldQueue(t *testing.T) {
+	bookID, _ := uuid.NewV4()
+	readyAt := time.Now()
+
+	books := bookMock.Book{}
+	books.On("GetByID", mock.Anything, bookID).Return(&entities.Book{ID: bookID}, nil)
+
+	holds := holdMock.Hold{}
+	holds.On("List", mock.Anything, &entities.HoldQuery{BookID: &bookID, ActiveOnly: true}).Return([]entities.Hold{
+		{Status: enums.HoldReady, ReadyAt: &readyAt},
+		{Status: enums.HoldWaiting, Priority: 10},
+		{Status: enums.HoldWaiting},
+	}, nil)
+
+	s := &service{model: models.Model{Book: &books, Hold: &holds}}
+	got, err := s.GetHoldQueue(context.Background(), bookID)
+	if err != nil {
+		t.Fatalf("GetHoldQueue() error = %v", err)
+	}
+
+	positions := []int{got[0].Position, got[1].Position, got[2].Position}
+	if positions[0] != 0 || positions[1] != 1 || positions[2] != 2 {
+		t.Errorf("GetHoldQueue() positions = %v, want [0 1 2]", positions)
+	}
+}
+
+func Test_service_CancelHold(t *testing.T) {
+	id, _ := uuid.NewV4()
+	bookID, _ := uuid.NewV4()
+	memberID, _ := uuid.NewV4()
+	nextID, _ := uuid.NewV4()
+	memberCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: memberID, Roles: []enums.Role{enums.RoleMember}})
+
+	expiresAt := time.Now().AddDate(0, 0, 3)
+	cancelled := &entities.Hold{ID: id, BookID: bookID, UserID: memberID, Status: enums.HoldCancelled}
+
+	holds := holdMock.Hold{}
+	holds.On("GetByID", memberCtx, id).Return(&entities.Hold{ID: id, BookID: bookID, UserID: memberID, Status: enums.HoldReady}, nil)
+	holds.On("Cancel", memberCtx, id, mock.Anything).Return(cancelled, nil)
+	// The released copy passes to the next member in line
+	holds.On("Allocate", memberCtx, bookID, mock.Anything, mock.Anything).
+		Return([]entities.Hold{{ID: nextID, BookID: bookID, Status: enums.HoldReady, ExpiresAt: &expiresAt}}, nil)
+
+	s := &service{model: models.Model{Hold: &holds}, config: &config.Config{HoldPickupDays: 3}}
+	got, err := s.CancelHold(memberCtx, id)
+	if err != nil {
+		t.Fatalf("CancelHold() error = %v", err)
+	}
+	if got.Status != enums.HoldCancelled || got.Position != 0 {
+		t.Errorf("CancelHold() = %+v", got)
+	}
+	holds.AssertExpectations(t)
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"library-system/internal/auth"
+	"library-system/internal/bookio"
+	"library-system/internal/classification"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/validation"
+
+	"github.com/gofrs/uuid"
+)
+
+// ImportBooks creates or updates a book per row of the upload, matching
+// on ISBN. Uploads within the sync limit are imported before returning;
+// larger ones are spooled to disk and imported in the background, and the
+// queued job is returned for polling.
+func (s *service) ImportBooks(ctx context.Context, req *entities.ImportRequest) (*entities.ImportJob, error) {
+	if !bookio.Readable(req.Format) {
+		return nil, fmt.Errorf("%w: format must be csv, ndjson or marc", entities.ErrInvalidImport)
+	}
+
+	if req.Size > s.config.ImportMaxBytes {
+		return nil, fmt.Errorf("%w: the limit is %d bytes", entities.ErrImportTooLarge, s.config.ImportMaxBytes)
+	}
+
+	job := &entities.ImportJob{Format: req.Format}
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		job.ActorID = &principal.UserID
+	}
+
+	if req.Wait || (req.Size >= 0 && req.Size <= s.config.ImportSyncLimit) {
+		r, err := bookio.NewReader(req.Format, req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", entities.ErrInvalidImport, err)
+		}
+		if err := s.model.ImportJob.Create(ctx, job); err != nil {
+			return nil, err
+		}
+
+		s.runImport(ctx, job.ID, r)
+		return s.model.ImportJob.GetByID(ctx, job.ID, "")
+	}
+
+	f, err := spool(req.Body, s.config.ImportMaxBytes)
+	if err != nil {
+		return nil, err
+	}
+	r, err := bookio.NewReader(req.Format, f)
+	if err != nil {
+		discard(f)
+		return nil, fmt.Errorf("%w: %v", entities.ErrInvalidImport, err)
+	}
+	if err := s.model.ImportJob.Create(ctx, job); err != nil {
+		discard(f)
+		return nil, err
+	}
+
+	// The import outlives the request but keeps who started it, for the
+	// books' history
+	go func() {
+		defer discard(f)
+		s.runImport(context.WithoutCancel(ctx), job.ID, r)
+	}()
+
+	return job, nil
+}
+
+// GetImportJob returns an import job with its row reports, optionally only
+// those with one outcome
+func (s *service) GetImportJob(ctx context.Context, id uuid.UUID, outcome enums.ImportOutcome) (*entities.ImportJob, error) {
+	if outcome != "" && !outcome.IsValid() {
+		return nil, fmt.Errorf("%w: unknown outcome %q", entities.ErrInvalidImport, outcome)
+	}
+	return s.model.ImportJob.GetByID(ctx, id, outcome)
+}
+
+// runImport works through the rows in batches, each committed in one
+// transaction and then recorded on the job. A read or database failure
+// stops the job; the batches before it stay imported. The job's heartbeat
+// is kept up while it runs; should the job be failed as stale meanwhile,
+// the import stops after the batch in hand.
+func (s *service) runImport(ctx context.Context, jobID uuid.UUID, r bookio.Reader) {
+	if err := s.model.ImportJob.Start(ctx, jobID); err != nil {
+		log.Printf("starting import %s: %v", jobID, err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	go s.beatImport(ctx, cancel, jobID)
+
+	// A panic fails the job rather than leaving it running, or taking the
+	// server down when the import runs in the background
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("import %s panicked: %v\n%s", jobID, p, debug.Stack())
+			if err := s.model.ImportJob.Finish(ctx, jobID, enums.ImportFailed, entities.ErrInternal.Message); err != nil {
+				log.Printf("finishing import %s: %v", jobID, err)
+			}
+		}
+	}()
+
+	err := s.importRows(ctx, jobID, r)
+
+	status, message := enums.ImportDone, ""
+	if err != nil {
+		status, message = enums.ImportFailed, importReason(fmt.Sprintf("import %s", jobID), err)
+	}
+	if err := s.model.ImportJob.Finish(ctx, jobID, status, message); err != nil {
+		log.Printf("finishing import %s: %v", jobID, err)
+	}
+}
+
+// beatImport keeps up the heartbeat of a running job until ctx is done,
+// cancelling the import if the job is no longer running
+func (s *service) beatImport(ctx context.Context, cancel context.CancelFunc, jobID uuid.UUID) {
+	ticker := time.NewTicker(s.config.ImportLease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.model.ImportJob.Heartbeat(ctx, jobID)
+			if errors.Is(err, entities.ErrImportJobNotRunning) {
+				cancel()
+				return
+			}
+			if err != nil {
+				log.Printf("heartbeat of import %s: %v", jobID, err)
+			}
+		}
+	}
+}
+
+func (s *service) importRows(ctx context.Context, jobID uuid.UUID, r bookio.Reader) error {
+	var (
+		reports []entities.ImportRow
+		books   []entities.Book
+		// pending maps each book in the batch to its report
+		pending []int
+	)
+
+	flush := func() error {
+		var results []entities.UpsertResult
+		if len(books) > 0 {
+			// Publishers are linked for the whole batch at once
+			credited := make([]string, len(books))
+			for i := range books {
+				credited[i] = books[i].Publisher
+			}
+			publishers, err := s.model.Publisher.Resolve(ctx, credited)
+			if err != nil {
+				return err
+			}
+			for i := range books {
+				books[i].PublisherID = publishers[i]
+			}
+			// Authors are linked under the same savepoint as the book, so
+			// a row is only saved with its credit
+			for i := range books {
+				authors, err := s.creditedAuthors(ctx, books[i].Author)
+				if err != nil {
+					return err
+				}
+				books[i].Contributors = append([]entities.BookContributor{}, authors...)
+			}
+
+			if results, err = s.model.Book.Upsert(ctx, books); err != nil {
+				return err
+			}
+		}
+
+		for i, result := range results {
+			report := &reports[pending[i]]
+			report.Outcome = result.Outcome
+			if result.Err != nil {
+				report.Reason = importReason(fmt.Sprintf("import %s row %d", jobID, report.Row), result.Err)
+				continue
+			}
+			report.BookID = &books[i].ID
+		}
+		if err := s.model.ImportJob.Record(ctx, jobID, reports); err != nil {
+			return err
+		}
+
+		// Added copies go to members waiting in the hold queue first
+		for i, result := range results {
+			if result.Outcome == enums.ImportUpdated && books[i].Copies > 0 {
+				s.allocateHolds(ctx, books[i].ID)
+			}
+		}
+
+		reports, books, pending = nil, nil, nil
+		return nil
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		row, req, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var rowErr *bookio.RowError
+		switch {
+		case errors.As(err, &rowErr):
+			reports = append(reports, rejected(row, "", rowErr.Err.Error()))
+		case err != nil:
+			return err
+		default:
+			book, reason := s.importedBook(req)
+			if reason != "" {
+				reports = append(reports, rejected(row, req.ISBN, reason))
+				break
+			}
+			pending = append(pending, len(reports))
+			reports = append(reports, entities.ImportRow{Row: row, ISBN: book.ISBN})
+			books = append(books, *book)
+		}
+
+		if len(reports) >= s.config.ImportBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return flush()
+}
+
+// importedBook validates a row the way a create request is validated. It
+// returns why the row is rejected, if it is. MARC records carry no copies,
+// so theirs are not required.
+func (s *service) importedBook(req *entities.BookRequest) (*entities.Book, string) {
+	var err error
+	if req.MARC != nil {
+		err = s.validate.StructExcept(req, "Copies")
+	} else {
+		err = s.validate.Struct(req)
+	}
+	if err != nil {
+		return nil, validation.Reason(err)
+	}
+	isbn13, err := canonicalISBN(req.ISBN)
+	if err != nil {
+		return nil, importReason("import of ISBN "+req.ISBN, err)
+	}
+
+	return &entities.Book{
+		Title:       req.Title,
+		Author:      req.Author,
+		ISBN:        isbn13,
+		Publisher:   req.Publisher,
+		PublishDate: req.PublishDate,
+		Description: req.Description,
+		Copies:      req.Copies,
+		ItemType:    itemType(req.ItemType),
+		Dewey:       classification.Normalize(enums.SchemeDewey, req.Dewey),
+		LCC:         classification.Normalize(enums.SchemeLCC, req.LCC),
+		MARC:        req.MARC,
+	}, ""
+}
+
+// FailStaleImports fails the jobs whose heartbeat is older than the import
+// lease: the server running them stopped, and their uploads went with it
+func (s *service) FailStaleImports(ctx context.Context) error {
+	failed, err := s.model.ImportJob.FailStale(ctx, time.Now().Add(-s.config.ImportLease), "import interrupted by a server stopping")
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		log.Printf("failed %d stale import jobs", failed)
+	}
+	return nil
+}
+
+// importReason is how an import error is reported on the job or a row.
+// Errors of the entities taxonomy are reported as the API would; anything
+// else, from the database or a driver, is logged and reported as internal.
+func importReason(what string, err error) string {
+	var known *entities.Error
+	if errors.As(err, &known) && known.Kind != entities.KindInternal {
+		return err.Error()
+	}
+	log.Printf("%s failed: %v", what, err)
+	return entities.ErrInternal.Message
+}
+
+func rejected(row int, isbn, reason string) entities.ImportRow {
+	return entities.ImportRow{Row: row, ISBN: isbn, Outcome: enums.ImportRejected, Reason: reason}
+}
+
+// spool copies an upload of up to max bytes to a temporary file so it
+// outlives the request
+func spool(body io.Reader, max int64) (*os.File, error) {
+	f, err := os.CreateTemp("", "book-import-*")
+	if err != nil {
+		return nil, err
+	}
+	n, err := io.Copy(f, io.LimitReader(body, max+1))
+	if err != nil {
+		discard(f)
+		return nil, err
+	}
+	if n > max {
+		discard(f)
+		return nil, fmt.Errorf("%w: the limit is %d bytes", entities.ErrImportTooLarge, max)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		discard(f)
+		return nil, err
+	}
+	return f, nil
+}
+
+func discard(f *os.File) {
+	f.Close()
+	os.Remove(f.Name())
+}
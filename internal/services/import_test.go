@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"library-system/internal/config"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models"
+	authorMock "library-system/internal/models/author/mocks"
+	bookMock "library-system/internal/models/book/mocks"
+	holdMock "library-system/internal/models/hold/mocks"
+	importJobMock "library-system/internal/models/importjob/mocks"
+	publisherMock "library-system/internal/models/publisher/mocks"
+	"library-system/internal/validation"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_ImportBooks(t *testing.T) {
+	csv := "title,author,isbn,publisher,publish_date,copies\n" +
+		"Dune,Frank Herbert,0441013597,Ace,1965-08-01,2\n" +
+		",Jane Austen,9780141439587,Penguin,1815-12-23,1\n" +
+		"Emma,Jane Austen,9780141439587,Penguin,1815-12-23,1\n" +
+		"Dune,Frank Herbert,0441013597,Ace,not a date,2\n"
+
+	// Each book is saved with the authors of its credit, to be linked in
+	// the same transaction
+	herbert, _ := uuid.NewV4()
+	austen, _ := uuid.NewV4()
+	authors := authorMock.Author{}
+	authors.On("Resolve", mock.Anything, []string{"Frank Herbert"}).Return([]entities.Author{{ID: herbert}}, nil)
+	authors.On("Resolve", mock.Anything, []string{"Jane Austen"}).Return([]entities.Author{{ID: austen}}, nil)
+
+	books := bookMock.Book{}
+	books.On("Upsert", mock.Anything, mock.MatchedBy(func(b []entities.Book) bool {
+		return len(b) == 2 && b[0].ISBN == "9780441013593" && b[0].ItemType == enums.ItemBook &&
+			slices.Equal(b[0].Contributors, []entities.BookContributor{{AuthorID: herbert, Role: enums.ContributorAuthor}}) &&
+			b[1].Title == "Emma" && b[1].PublisherID != nil &&
+			slices.Equal(b[1].Contributors, []entities.BookContributor{{AuthorID: austen, Role: enums.ContributorAuthor}})
+	})).Return([]entities.UpsertResult{
+		{Outcome: enums.ImportCreated},
+		{Outcome: enums.ImportUpdated},
+	}, nil)
+
+	// Both saved books are linked to their publishers in one lookup
+	ace, _ := uuid.NewV4()
+	penguin, _ := uuid.NewV4()
+	publishers := publisherMock.Publisher{}
+	publishers.On("Resolve", mock.Anything, []string{"Ace", "Penguin"}).Return([]*uuid.UUID{&ace, &penguin}, nil)
+
+	// Copies added to an existing book go to its hold queue
+	holds := holdMock.Hold{}
+	holds.On("Allocate", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]entities.Hold{}, nil)
+
+	jobs := importJobMock.ImportJob{}
+	jobs.On("Create", mock.Anything, mock.MatchedBy(func(j *entities.ImportJob) bool {
+		return j.Format == "csv"
+	})).Return(nil)
+	jobs.On("Start", mock.Anything, mock.Anything).Return(nil)
+	jobs.On("Record", mock.Anything, mock.Anything, mock.MatchedBy(func(rows []entities.ImportRow) bool {
+		return len(rows) == 4 &&
+			rows[0].Outcome == enums.ImportCreated && rows[0].BookID != nil &&
+			rows[1].Outcome == enums.ImportRejected && rows[1].Reason == "title is required" &&
+			rows[2].Outcome == enums.ImportUpdated &&
+			rows[3].Outcome == enums.ImportRejected && rows[3].Row == 4
+	})).Return(nil)
+	jobs.On("Finish", mock.Anything, mock.Anything, enums.ImportDone, "").Return(nil)
+	jobs.On("GetByID", mock.Anything, mock.Anything, enums.ImportOutcome("")).
+		Return(&entities.ImportJob{Status: enums.ImportDone, Created: 1, Updated: 1, Rejected: 2}, nil)
+
+	s := &service{
+		model:    models.Model{Book: &books, Author: &authors, Publisher: &publishers, Hold: &holds, ImportJob: &jobs},
+		config:   &config.Config{ImportSyncLimit: 1 << 20, ImportMaxBytes: 1 << 20, ImportBatchSize: 500, ImportLease: time.Minute},
+		validate: validation.New(),
+	}
+
+	job, err := s.ImportBooks(context.Background(), &entities.ImportRequest{
+		Format: "csv",
+		Body:   strings.NewReader(csv),
+		Size:   int64(len(csv)),
+	})
+	if err != nil {
+		t.Fatalf("ImportBooks() error = %v", err)
+	}
+	if job.Status != enums.ImportDone {
+		t.Errorf("ImportBooks() status = %v, want %v", job.Status, enums.ImportDone)
+	}
+
+	books.AssertExpectations(t)
+	authors.AssertExpectations(t)
+	publishers.AssertExpectations(t)
+	holds.AssertExpectations(t)
+	jobs.AssertExpectations(t)
+}
+
+func Test_service_ImportBooks_unknownFormat(t *testing.T) {
+	s := &service{model: models.Model{ImportJob: &importJobMock.ImportJob{}}, config: &config.Config{}}
+
+	_, err := s.ImportBooks(context.Background(), &entities.ImportRequest{Format: "xml", Body: strings.NewReader("")})
+	if !errors.Is(err, entities.ErrInvalidImport) {
+		t.Errorf("ImportBooks() error = %v, want %v", err, entities.ErrInvalidImport)
+	}
+}
+
+func Test_service_ImportBooks_tooLarge(t *testing.T) {
+	s := &service{model: models.Model{ImportJob: &importJobMock.ImportJob{}}, config: &config.Config{ImportMaxBytes: 16}}
+	csv := "title,author,isbn,publisher,publish_date,copies\n"
+
+	// Declared too large, or found to be while spooling an upload of
+	// unknown length; no job is queued for either
+	for _, size := range []int64{int64(len(csv)), -1} {
+		_, err := s.ImportBooks(context.Background(), &entities.ImportRequest{Format: "csv", Body: strings.NewReader(csv), Size: size})
+		if !errors.Is(err, entities.ErrImportTooLarge) {
+			t.Errorf("ImportBooks() of size %d error = %v, want %v", size, err, entities.ErrImportTooLarge)
+		}
+	}
+}
+
+func Test_service_importedBook(t *testing.T) {
+	s := &service{validate: validation.New()}
+	req := entities.BookRequest{
+		Title:       "Dune",
+		Author:      "Frank Herbert",
+		ISBN:        "0441013597",
+		Publisher:   "Ace",
+		PublishDate: time.Date(1965, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	if _, reason := s.importedBook(&req); reason != "copies is required" {
+		t.Errorf("importedBook() reason = %q, want copies required", reason)
+	}
+
+	// MARC records carry no copies
+	req.MARC = []byte("record")
+	book, reason := s.importedBook(&req)
+	if reason != "" {
+		t.Fatalf("importedBook() reason = %q", reason)
+	}
+	if book.ISBN != "9780441013593" || book.Copies != 0 || string(book.MARC) != "record" {
+		t.Errorf("importedBook() = %+v", book)
+	}
+}
+
+func Test_importReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "known", err: entities.ErrBookInTrash, want: entities.ErrBookInTrash.Message},
+		{name: "known with a detail", err: fmt.Errorf("%w: too short", entities.ErrInvalidISBN), want: entities.ErrInvalidISBN.Message + ": too short"},
+		{name: "from the database", err: errors.New(`pq: relation "books" does not exist`), want: entities.ErrInternal.Message},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := importReason("import", tt.err); got != tt.want {
+				t.Errorf("importReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
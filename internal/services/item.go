@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// ListItems lists the physical copies of a book
+func (s *service) ListItems(ctx context.Context, bookID uuid.UUID) ([]entities.Item, error) {
+	if _, err := s.model.Book.GetByID(ctx, bookID); err != nil {
+		return nil, err
+	}
+
+	return s.model.Item.List(ctx, bookID)
+}
+
+// GetItem retrieves one copy of a book
+func (s *service) GetItem(ctx context.Context, bookID, id uuid.UUID) (*entities.Item, error) {
+	return s.model.Item.GetByID(ctx, bookID, id)
+}
+
+// CreateItem catalogues a new copy of a book. A copy put on the shelf goes
+// to the hold queue first.
+func (s *service) CreateItem(ctx context.Context, bookID uuid.UUID, req *entities.ItemRequest) (*entities.Item, error) {
+	item := &entities.Item{BookID: bookID}
+	applyItemRequest(item, req)
+
+	if err := s.model.Item.Create(ctx, item); err != nil {
+		return nil, err
+	}
+
+	if item.Status == enums.ItemAvailable {
+		s.allocateHolds(ctx, bookID)
+	}
+
+	return item, nil
+}
+
+// UpdateItem changes a copy's details, or moves it between the shelf,
+// repair, lost and withdrawn
+func (s *service) UpdateItem(ctx context.Context, bookID, id uuid.UUID, req *entities.ItemRequest) (*entities.Item, error) {
+	item, err := s.model.Item.GetByID(ctx, bookID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Circulation owns the status of lent and held copies
+	if req.Status == "" {
+		req.Status = item.Status
+	}
+	applyItemRequest(item, req)
+
+	if err := s.model.Item.Update(ctx, item); err != nil {
+		return nil, err
+	}
+
+	if item.Status == enums.ItemAvailable {
+		s.allocateHolds(ctx, bookID)
+	}
+
+	return item, nil
+}
+
+// DeleteItem removes a copy that never circulated
+func (s *service) DeleteItem(ctx context.Context, bookID, id uuid.UUID) error {
+	return s.model.Item.Delete(ctx, bookID, id)
+}
+
+// applyItemRequest copies the request onto the item, filling in defaults
+func applyItemRequest(item *entities.Item, req *entities.ItemRequest) {
+	item.Barcode = req.Barcode
+	item.Condition = req.Condition
+	if item.Condition == "" {
+		item.Condition = enums.ConditionGood
+	}
+	item.ShelfLocation = req.ShelfLocation
+	if req.AcquiredAt != nil {
+		item.AcquiredAt = *req.AcquiredAt
+	} else if item.AcquiredAt.IsZero() {
+		item.AcquiredAt = time.Now()
+	}
+	item.Price = req.Price
+	item.Status = req.Status
+	if item.Status == "" {
+		item.Status = enums.ItemAvailable
+	}
+}
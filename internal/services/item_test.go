@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"library-system/internal/config"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models"
+	holdMock "library-system/internal/models/hold/mocks"
+	itemMock "library-system/internal/models/item/mocks"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_CreateItem(t *testing.T) {
+	bookID, _ := uuid.NewV4()
+	cfg := &config.Config{HoldPickupDays: 3}
+
+	shelfMock := itemMock.Item{}
+	shelfMock.On("Create", mock.Anything, mock.MatchedBy(func(i *entities.Item) bool {
+		return i.BookID == bookID && i.Barcode == "B1" && i.Status == enums.ItemAvailable &&
+			i.Condition == enums.ConditionGood && !i.AcquiredAt.IsZero()
+	})).Return(nil)
+
+	repairMock := itemMock.Item{}
+	repairMock.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	takenMock := itemMock.Item{}
+	takenMock.On("Create", mock.Anything, mock.Anything).Return(entities.ErrBarcodeTaken)
+
+	// A copy put on the shelf goes to the hold queue first
+	allocateMock := holdMock.Hold{}
+	allocateMock.On("Allocate", mock.Anything, bookID, mock.Anything, mock.Anything).Return([]entities.Hold{}, nil)
+
+	tests := []struct {
+		name    string
+		s       *service
+		req     *entities.ItemRequest
+		wantErr error
+	}{
+		{
+			name: "on the shelf",
+			s:    &service{model: models.Model{Item: &shelfMock, Hold: &allocateMock}, config: cfg},
+			req:  &entities.ItemRequest{Barcode: "B1"},
+		},
+		{
+			name: "straight to repair",
+			s:    &service{model: models.Model{Item: &repairMock, Hold: &holdMock.Hold{}}, config: cfg},
+			req:  &entities.ItemRequest{Barcode: "B2", Status: enums.ItemInRepair},
+		},
+		{
+			name:    "barcode taken",
+			s:       &service{model: models.Model{Item: &takenMock, Hold: &holdMock.Hold{}}, config: cfg},
+			req:     &entities.ItemRequest{Barcode: "B1"},
+			wantErr: entities.ErrBarcodeTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.s.CreateItem(context.Background(), bookID, tt.req)
+			if err != tt.wantErr {
+				t.Errorf("CreateItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			tt.s.model.Item.(*itemMock.Item).AssertExpectations(t)
+			tt.s.model.Hold.(*holdMock.Hold).AssertExpectations(t)
+		})
+	}
+}
+
+func Test_service_UpdateItem(t *testing.T) {
+	bookID, _ := uuid.NewV4()
+	id, _ := uuid.NewV4()
+	acquired := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	lent := &entities.Item{ID: id, BookID: bookID, Barcode: "B1", Status: enums.ItemOnLoan, AcquiredAt: acquired}
+
+	// Leaving the status out keeps the copy on loan
+	updateMock := itemMock.Item{}
+	updateMock.On("GetByID", mock.Anything, bookID, id).Return(lent, nil)
+	updateMock.On("Update", mock.Anything, mock.MatchedBy(func(i *entities.Item) bool {
+		return i.Status == enums.ItemOnLoan && i.ShelfLocation == "A-12" && i.AcquiredAt.Equal(acquired)
+	})).Return(nil)
+
+	s := &service{model: models.Model{Item: &updateMock, Hold: &holdMock.Hold{}}}
+	got, err := s.UpdateItem(context.Background(), bookID, id, &entities.ItemRequest{Barcode: "B1", ShelfLocation: "A-12"})
+	if err != nil {
+		t.Fatalf("UpdateItem() error = %v", err)
+	}
+	if got.Status != enums.ItemOnLoan {
+		t.Errorf("UpdateItem() status = %v", got.Status)
+	}
+
+	updateMock.AssertExpectations(t)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"library-system/internal/auth"
+	"library-system/internal/entities"
+
+	"github.com/gofrs/uuid"
+)
+
+// CheckoutBook lends a copy of the book, or the copy with the requested
+// barcode. Members borrow for themselves; staff may name another borrower.
+func (s *service) CheckoutBook(ctx context.Context, bookID uuid.UUID, req *entities.CheckoutRequest) (*entities.LoanResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, entities.ErrUnauthenticated
+	}
+
+	borrower := principal.UserID
+	if req.UserID != nil && *req.UserID != principal.UserID {
+		if !auth.Can(principal, auth.PermLoansManage) {
+			return nil, entities.ErrForbidden
+		}
+		borrower = *req.UserID
+	}
+
+	ok, err := s.IsInGoodStanding(ctx, borrower)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, entities.ErrNotInGoodStanding
+	}
+
+	now := time.Now()
+	loan := &entities.Loan{
+		BookID:       bookID,
+		UserID:       borrower,
+		CheckedOutAt: now,
+		DueAt:        dueDate(now, s.config.LoanPeriodDays),
+	}
+
+	if err := s.model.Loan.Checkout(ctx, loan, req.Barcode); err != nil {
+		return nil, err
+	}
+
+	return toLoanResponse(loan, now), nil
+}
+
+// ReturnLoan checks a loaned copy back in, fining the borrower if it is late
+func (s *service) ReturnLoan(ctx context.Context, id uuid.UUID) (*entities.LoanResponse, error) {
+	loan, err := s.authorizeLoan(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	fine, err := s.overdueFine(ctx, loan, now)
+	if err != nil {
+		return nil, err
+	}
+
+	loan, err = s.model.Loan.Return(ctx, id, now, fine)
+	if err != nil {
+		return nil, err
+	}
+
+	s.allocateHolds(ctx, loan.BookID)
+
+	return toLoanResponse(loan, now), nil
+}
+
+// GetLoan retrieves a loan of the caller, or of anyone for staff
+func (s *service) GetLoan(ctx context.Context, id uuid.UUID) (*entities.LoanResponse, error) {
+	loan, err := s.authorizeLoan(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toLoanResponse(loan, time.Now()), nil
+}
+
+// ListLoans lists the caller's loans. Staff may list any member's loans,
+// or everyone's when no member is given.
+func (s *service) ListLoans(ctx context.Context, query *entities.LoanQuery) ([]*entities.LoanResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, entities.ErrUnauthenticated
+	}
+
+	if !auth.Can(principal, auth.PermLoansManage) {
+		if query.UserID != nil && *query.UserID != principal.UserID {
+			return nil, entities.ErrForbidden
+		}
+		query.UserID = &principal.UserID
+	}
+
+	loans, err := s.model.Loan.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	resp := make([]*entities.LoanResponse, len(loans))
+	for i := range loans {
+		resp[i] = toLoanResponse(&loans[i], now)
+	}
+
+	return resp, nil
+}
+
+// authorizeLoan loads a loan the caller is allowed to act on: their own,
+// or any loan for staff
+func (s *service) authorizeLoan(ctx context.Context, id uuid.UUID) (*entities.Loan, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, entities.ErrUnauthenticated
+	}
+
+	loan, err := s.model.Loan.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if loan.UserID != principal.UserID && !auth.Can(principal, auth.PermLoansManage) {
+		// Don't reveal other members' loans
+		return nil, entities.ErrLoanNotFound
+	}
+
+	return loan, nil
+}
+
+// dueDate is the end of the day the loan period lapses, in the server's
+// time zone, so a book is never due in the middle of opening hours
+func dueDate(checkedOut time.Time, days int) time.Time {
+	d := checkedOut.AddDate(0, 0, days)
+	return time.Date(d.Year(), d.Month(), d.Day(), 23, 59, 59, 0, d.Location())
+}
+
+func toLoanResponse(loan *entities.Loan, now time.Time) *entities.LoanResponse {
+	return &entities.LoanResponse{
+		ID:           loan.ID,
+		BookID:       loan.BookID,
+		ItemID:       loan.ItemID,
+		UserID:       loan.UserID,
+		CheckedOutAt: loan.CheckedOutAt,
+		DueAt:        loan.DueAt,
+		ReturnedAt:   loan.ReturnedAt,
+		Overdue:      loan.ReturnedAt == nil && now.After(loan.DueAt),
+	}
+}
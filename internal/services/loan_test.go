@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"library-system/internal/auth"
+	"library-system/internal/config"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models"
+	bookMocks "library-system/internal/models/book/mocks"
+	holdMock "library-system/internal/models/hold/mocks"
+	ledgerMock "library-system/internal/models/ledger/mocks"
+	loanMock "library-system/internal/models/loan/mocks"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_dueDate(t *testing.T) {
+	checkedOut := time.Date(2024, time.February, 20, 10, 30, 0, 0, time.UTC)
+
+	got := dueDate(checkedOut, 14)
+	want := time.Date(2024, time.March, 5, 23, 59, 59, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("dueDate() = %v, want %v", got, want)
+	}
+}
+
+func Test_service_CheckoutBook(t *testing.T) {
+	bookID, _ := uuid.NewV4()
+	memberID, _ := uuid.NewV4()
+	otherID, _ := uuid.NewV4()
+
+	memberCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: memberID, Roles: []enums.Role{enums.RoleMember}})
+	librarianCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: otherID, Roles: []enums.Role{enums.RoleLibrarian}})
+	cfg := &config.Config{LoanPeriodDays: 14, GoodStandingLimit: 500}
+
+	settled := ledgerMock.Ledger{}
+	settled.On("Balance", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+	owing := ledgerMock.Ledger{}
+	owing.On("Balance", memberCtx, memberID).Return(int64(501), nil)
+
+	selfMock := loanMock.Loan{}
+	selfMock.On("Checkout", memberCtx, mock.MatchedBy(func(l *entities.Loan) bool {
+		return l.BookID == bookID && l.UserID == memberID &&
+			l.DueAt.Sub(l.CheckedOutAt) > 14*24*time.Hour && l.DueAt.Sub(l.CheckedOutAt) < 15*24*time.Hour
+	}), "").Return(nil)
+
+	onBehalfMock := loanMock.Loan{}
+	onBehalfMock.On("Checkout", librarianCtx, mock.MatchedBy(func(l *entities.Loan) bool {
+		return l.UserID == memberID
+	}), "B1").Return(nil)
+
+	noCopiesMock := loanMock.Loan{}
+	noCopiesMock.On("Checkout", memberCtx, mock.Anything, "").Return(entities.ErrNoCopiesAvailable)
+
+	tests := []struct {
+		name    string
+		s       *service
+		ctx     context.Context
+		req     *entities.CheckoutRequest
+		wantErr error
+	}{
+		{
+			name: "member borrows for themselves",
+			s:    &service{model: models.Model{Loan: &selfMock, Ledger: &settled}, config: cfg},
+			ctx:  memberCtx,
+			req:  &entities.CheckoutRequest{},
+		},
+		{
+			name: "librarian checks out for a member",
+			s:    &service{model: models.Model{Loan: &onBehalfMock, Ledger: &settled}, config: cfg},
+			ctx:  librarianCtx,
+			req:  &entities.CheckoutRequest{UserID: &memberID, Barcode: "B1"},
+		},
+		{
+			name:    "member cannot borrow for someone else",
+			s:       &service{model: models.Model{Loan: &loanMock.Loan{}}, config: cfg},
+			ctx:     memberCtx,
+			req:     &entities.CheckoutRequest{UserID: &otherID},
+			wantErr: entities.ErrForbidden,
+		},
+		{
+			name:    "no copies left",
+			s:       &service{model: models.Model{Loan: &noCopiesMock, Ledger: &settled}, config: cfg},
+			ctx:     memberCtx,
+			req:     &entities.CheckoutRequest{},
+			wantErr: entities.ErrNoCopiesAvailable,
+		},
+		{
+			name:    "member owes too much",
+			s:       &service{model: models.Model{Loan: &loanMock.Loan{}, Ledger: &owing}, config: cfg},
+			ctx:     memberCtx,
+			req:     &entities.CheckoutRequest{},
+			wantErr: entities.ErrNotInGoodStanding,
+		},
+		{
+			name:    "anonymous",
+			s:       &service{model: models.Model{Loan: &loanMock.Loan{}}, config: cfg},
+			ctx:     context.Background(),
+			req:     &entities.CheckoutRequest{},
+			wantErr: entities.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.CheckoutBook(tt.ctx, bookID, tt.req)
+			if err != tt.wantErr {
+				t.Errorf("CheckoutBook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.BookID != bookID || got.Overdue) {
+				t.Errorf("CheckoutBook() = %+v", got)
+			}
+			tt.s.model.Loan.(*loanMock.Loan).AssertExpectations(t)
+		})
+	}
+}
+
+func Test_service_ReturnLoan(t *testing.T) {
+	loanID, _ := uuid.NewV4()
+	memberID, _ := uuid.NewV4()
+	strangerID, _ := uuid.NewV4()
+
+	memberCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: memberID, Roles: []enums.Role{enums.RoleMember}})
+	strangerCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: strangerID, Roles: []enums.Role{enums.RoleMember}})
+
+	now := time.Now()
+	open := &entities.Loan{ID: loanID, UserID: memberID, CheckedOutAt: now.AddDate(0, 0, -20), DueAt: now.AddDate(0, 0, -6)}
+	returned := *open
+	returned.ReturnedAt = &now
+
+	ownerMock := loanMock.Loan{}
+	ownerMock.On("GetByID", memberCtx, loanID).Return(open, nil)
+	ownerMock.On("Return", memberCtx, loanID, mock.Anything, mock.MatchedBy(func(fine *entities.LedgerTransaction) bool {
+		// Six days and a moment late is seven started days, one of grace
+		return fine.Kind == enums.LedgerFine && fine.Amount == 600 && fine.MemberID == memberID &&
+			*fine.LoanID == loanID && fine.Balanced() && fine.ReceivableChange() == 600
+	})).Return(&returned, nil)
+
+	bookMock := bookMocks.Book{}
+	bookMock.On("GetByID", memberCtx, open.BookID).Return(&entities.Book{ItemType: enums.ItemMedia}, nil)
+
+	allocateMock := holdMock.Hold{}
+	allocateMock.On("Allocate", memberCtx, returned.BookID, mock.Anything, mock.Anything).Return([]entities.Hold{}, nil)
+
+	strangerMock := loanMock.Loan{}
+	strangerMock.On("GetByID", strangerCtx, loanID).Return(open, nil)
+
+	tests := []struct {
+		name    string
+		s       *service
+		ctx     context.Context
+		wantErr error
+	}{
+		{
+			name: "borrower returns",
+			s: &service{model: models.Model{Loan: &ownerMock, Hold: &allocateMock, Book: &bookMock}, config: &config.Config{
+				HoldPickupDays: 3,
+				FineRates:      map[enums.ItemType]int64{enums.ItemMedia: 100},
+				FineGraceDays:  1,
+				FineCap:        1000,
+			}},
+			ctx: memberCtx,
+		},
+		{
+			name:    "someone else's loan",
+			s:       &service{model: models.Model{Loan: &strangerMock}},
+			ctx:     strangerCtx,
+			wantErr: entities.ErrLoanNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ReturnLoan(tt.ctx, loanID)
+			if err != tt.wantErr {
+				t.Errorf("ReturnLoan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.ReturnedAt == nil || got.Overdue) {
+				t.Errorf("ReturnLoan() = %+v", got)
+			}
+			tt.s.model.Loan.(*loanMock.Loan).AssertExpectations(t)
+		})
+	}
+}
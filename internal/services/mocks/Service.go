@@ -5,9 +5,14 @@ package mocks
 import (
 	context "context"
 	entities "library-system/internal/entities"
+	enums "library-system/internal/entities/enums"
+
+	io "io"
 
 	mock "github.com/stretchr/testify/mock"
 
+	time "time"
+
 	uuid "github.com/gofrs/uuid"
 )
 
@@ -16,65 +21,89 @@ type Service struct {
 	mock.Mock
 }
 
-// CreateBook provides a mock function with given fields: ctx, req
-func (_m *Service) CreateBook(ctx context.Context, req *entities.BookRequest) error {
-	ret := _m.Called(ctx, req)
+// Authenticate provides a mock function with given fields: ctx, token
+func (_m *Service) Authenticate(ctx context.Context, token string) (*entities.Principal, error) {
+	ret := _m.Called(ctx, token)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateBook")
+		panic("no return value specified for Authenticate")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookRequest) error); ok {
-		r0 = rf(ctx, req)
+	var r0 *entities.Principal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entities.Principal, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entities.Principal); ok {
+		r0 = rf(ctx, token)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Principal)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// DeleteBook provides a mock function with given fields: ctx, id
-func (_m *Service) DeleteBook(ctx context.Context, id uuid.UUID) error {
-	ret := _m.Called(ctx, id)
+// BrowseClasses provides a mock function with given fields: ctx, scheme
+func (_m *Service) BrowseClasses(ctx context.Context, scheme enums.ClassScheme) ([]*entities.ClassNode, error) {
+	ret := _m.Called(ctx, scheme)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteBook")
+		panic("no return value specified for BrowseClasses")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
-		r0 = rf(ctx, id)
+	var r0 []*entities.ClassNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, enums.ClassScheme) ([]*entities.ClassNode, error)); ok {
+		return rf(ctx, scheme)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, enums.ClassScheme) []*entities.ClassNode); ok {
+		r0 = rf(ctx, scheme)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.ClassNode)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, enums.ClassScheme) error); ok {
+		r1 = rf(ctx, scheme)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// GetAllBooks provides a mock function with given fields: ctx
-func (_m *Service) GetAllBooks(ctx context.Context) ([]*entities.BookResponse, error) {
-	ret := _m.Called(ctx)
+// BrowseSubjects provides a mock function with given fields: ctx, kind
+func (_m *Service) BrowseSubjects(ctx context.Context, kind enums.SubjectKind) (*entities.SubjectTree, error) {
+	ret := _m.Called(ctx, kind)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllBooks")
+		panic("no return value specified for BrowseSubjects")
 	}
 
-	var r0 []*entities.BookResponse
+	var r0 *entities.SubjectTree
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]*entities.BookResponse, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, enums.SubjectKind) (*entities.SubjectTree, error)); ok {
+		return rf(ctx, kind)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []*entities.BookResponse); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, enums.SubjectKind) *entities.SubjectTree); ok {
+		r0 = rf(ctx, kind)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*entities.BookResponse)
+			r0 = ret.Get(0).(*entities.SubjectTree)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, enums.SubjectKind) error); ok {
+		r1 = rf(ctx, kind)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -82,24 +111,24 @@ func (_m *Service) GetAllBooks(ctx context.Context) ([]*entities.BookResponse, e
 	return r0, r1
 }
 
-// GetBookByID provides a mock function with given fields: ctx, id
-func (_m *Service) GetBookByID(ctx context.Context, id uuid.UUID) (*entities.BookResponse, error) {
+// CancelHold provides a mock function with given fields: ctx, id
+func (_m *Service) CancelHold(ctx context.Context, id uuid.UUID) (*entities.HoldResponse, error) {
 	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetBookByID")
+		panic("no return value specified for CancelHold")
 	}
 
-	var r0 *entities.BookResponse
+	var r0 *entities.HoldResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.BookResponse, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.HoldResponse, error)); ok {
 		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.BookResponse); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.HoldResponse); ok {
 		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entities.BookResponse)
+			r0 = ret.Get(0).(*entities.HoldResponse)
 		}
 	}
 
@@ -112,22 +141,2382 @@ func (_m *Service) GetBookByID(ctx context.Context, id uuid.UUID) (*entities.Boo
 	return r0, r1
 }
 
-// UpdateBook provides a mock function with given fields: ctx, id, req
-func (_m *Service) UpdateBook(ctx context.Context, id uuid.UUID, req *entities.BookRequest) error {
-	ret := _m.Called(ctx, id, req)
+// CancelReservation provides a mock function with given fields: ctx, id
+func (_m *Service) CancelReservation(ctx context.Context, id uuid.UUID) (*entities.ReservationResponse, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateBook")
+		panic("no return value specified for CancelReservation")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.BookRequest) error); ok {
-		r0 = rf(ctx, id, req)
+	var r0 *entities.ReservationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.ReservationResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.ReservationResponse); ok {
+		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.ReservationResponse)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ChargeMember provides a mock function with given fields: ctx, memberID, req
+func (_m *Service) ChargeMember(ctx context.Context, memberID uuid.UUID, req *entities.ChargeRequest) (*entities.LedgerTransaction, error) {
+	ret := _m.Called(ctx, memberID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChargeMember")
+	}
+
+	var r0 *entities.LedgerTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.ChargeRequest) (*entities.LedgerTransaction, error)); ok {
+		return rf(ctx, memberID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.ChargeRequest) *entities.LedgerTransaction); ok {
+		r0 = rf(ctx, memberID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.LedgerTransaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.ChargeRequest) error); ok {
+		r1 = rf(ctx, memberID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckoutBook provides a mock function with given fields: ctx, bookID, req
+func (_m *Service) CheckoutBook(ctx context.Context, bookID uuid.UUID, req *entities.CheckoutRequest) (*entities.LoanResponse, error) {
+	ret := _m.Called(ctx, bookID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckoutBook")
+	}
+
+	var r0 *entities.LoanResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.CheckoutRequest) (*entities.LoanResponse, error)); ok {
+		return rf(ctx, bookID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.CheckoutRequest) *entities.LoanResponse); ok {
+		r0 = rf(ctx, bookID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.LoanResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.CheckoutRequest) error); ok {
+		r1 = rf(ctx, bookID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAdmin provides a mock function with given fields: ctx, req
+func (_m *Service) CreateAdmin(ctx context.Context, req *entities.RegisterRequest) (*entities.UserResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAdmin")
+	}
+
+	var r0 *entities.UserResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.RegisterRequest) (*entities.UserResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.RegisterRequest) *entities.UserResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.UserResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.RegisterRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAuthor provides a mock function with given fields: ctx, req
+func (_m *Service) CreateAuthor(ctx context.Context, req *entities.AuthorRequest) (*entities.AuthorResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAuthor")
+	}
+
+	var r0 *entities.AuthorResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.AuthorRequest) (*entities.AuthorResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.AuthorRequest) *entities.AuthorResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.AuthorResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.AuthorRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateBook provides a mock function with given fields: ctx, req
+func (_m *Service) CreateBook(ctx context.Context, req *entities.BookRequest) (*entities.BookResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBook")
+	}
+
+	var r0 *entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookRequest) (*entities.BookResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookRequest) *entities.BookResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.BookRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateItem provides a mock function with given fields: ctx, bookID, req
+func (_m *Service) CreateItem(ctx context.Context, bookID uuid.UUID, req *entities.ItemRequest) (*entities.Item, error) {
+	ret := _m.Called(ctx, bookID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateItem")
+	}
+
+	var r0 *entities.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.ItemRequest) (*entities.Item, error)); ok {
+		return rf(ctx, bookID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.ItemRequest) *entities.Item); ok {
+		r0 = rf(ctx, bookID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.ItemRequest) error); ok {
+		r1 = rf(ctx, bookID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreatePublisher provides a mock function with given fields: ctx, req
+func (_m *Service) CreatePublisher(ctx context.Context, req *entities.PublisherRequest) (*entities.PublisherResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePublisher")
+	}
+
+	var r0 *entities.PublisherResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.PublisherRequest) (*entities.PublisherResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.PublisherRequest) *entities.PublisherResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.PublisherResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.PublisherRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateSeries provides a mock function with given fields: ctx, req
+func (_m *Service) CreateSeries(ctx context.Context, req *entities.SeriesRequest) (*entities.SeriesResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSeries")
+	}
+
+	var r0 *entities.SeriesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.SeriesRequest) (*entities.SeriesResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.SeriesRequest) *entities.SeriesResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.SeriesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.SeriesRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateSubject provides a mock function with given fields: ctx, req
+func (_m *Service) CreateSubject(ctx context.Context, req *entities.SubjectRequest) (*entities.SubjectNode, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSubject")
+	}
+
+	var r0 *entities.SubjectNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.SubjectRequest) (*entities.SubjectNode, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.SubjectRequest) *entities.SubjectNode); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.SubjectNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.SubjectRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWork provides a mock function with given fields: ctx, req
+func (_m *Service) CreateWork(ctx context.Context, req *entities.WorkRequest) (*entities.WorkResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWork")
+	}
+
+	var r0 *entities.WorkResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.WorkRequest) (*entities.WorkResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.WorkRequest) *entities.WorkResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.WorkResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.WorkRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAuthor provides a mock function with given fields: ctx, id
+func (_m *Service) DeleteAuthor(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAuthor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteBook provides a mock function with given fields: ctx, id, version
+func (_m *Service) DeleteBook(ctx context.Context, id uuid.UUID, version int64) error {
+	ret := _m.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCover provides a mock function with given fields: ctx, id, version
+func (_m *Service) DeleteCover(ctx context.Context, id uuid.UUID, version int64) error {
+	ret := _m.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCover")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) error); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteItem provides a mock function with given fields: ctx, bookID, id
+func (_m *Service) DeleteItem(ctx context.Context, bookID uuid.UUID, id uuid.UUID) error {
+	ret := _m.Called(ctx, bookID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteItem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, bookID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePublisher provides a mock function with given fields: ctx, id
+func (_m *Service) DeletePublisher(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePublisher")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSeries provides a mock function with given fields: ctx, id
+func (_m *Service) DeleteSeries(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSeries")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSubject provides a mock function with given fields: ctx, id
+func (_m *Service) DeleteSubject(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSubject")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWork provides a mock function with given fields: ctx, id
+func (_m *Service) DeleteWork(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWork")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExpireHolds provides a mock function with given fields: ctx
+func (_m *Service) ExpireHolds(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExpireHolds")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportBooks provides a mock function with given fields: ctx, format, query, w
+func (_m *Service) ExportBooks(ctx context.Context, format string, query *entities.BookQuery, w io.Writer) (int, error) {
+	ret := _m.Called(ctx, format, query, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportBooks")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *entities.BookQuery, io.Writer) (int, error)); ok {
+		return rf(ctx, format, query, w)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *entities.BookQuery, io.Writer) int); ok {
+		r0 = rf(ctx, format, query, w)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *entities.BookQuery, io.Writer) error); ok {
+		r1 = rf(ctx, format, query, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FailStaleImports provides a mock function with given fields: ctx
+func (_m *Service) FailStaleImports(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FailStaleImports")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllBooks provides a mock function with given fields: ctx, query
+func (_m *Service) GetAllBooks(ctx context.Context, query *entities.BookQuery) (*entities.BookPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllBooks")
+	}
+
+	var r0 *entities.BookPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookQuery) (*entities.BookPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookQuery) *entities.BookPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.BookQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAuthor provides a mock function with given fields: ctx, id
+func (_m *Service) GetAuthor(ctx context.Context, id uuid.UUID) (*entities.AuthorResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuthor")
+	}
+
+	var r0 *entities.AuthorResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.AuthorResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.AuthorResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.AuthorResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBookAsOf provides a mock function with given fields: ctx, id, at
+func (_m *Service) GetBookAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*entities.BookResponse, error) {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBookAsOf")
+	}
+
+	var r0 *entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) (*entities.BookResponse, error)); ok {
+		return rf(ctx, id, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) *entities.BookResponse); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r1 = rf(ctx, id, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBookByID provides a mock function with given fields: ctx, id
+func (_m *Service) GetBookByID(ctx context.Context, id uuid.UUID) (*entities.BookResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBookByID")
+	}
+
+	var r0 *entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.BookResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.BookResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBookByISBN provides a mock function with given fields: ctx, isbn
+func (_m *Service) GetBookByISBN(ctx context.Context, isbn string) (*entities.BookResponse, error) {
+	ret := _m.Called(ctx, isbn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBookByISBN")
+	}
+
+	var r0 *entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entities.BookResponse, error)); ok {
+		return rf(ctx, isbn)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entities.BookResponse); ok {
+		r0 = rf(ctx, isbn)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, isbn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCurrentUser provides a mock function with given fields: ctx
+func (_m *Service) GetCurrentUser(ctx context.Context) (*entities.UserResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCurrentUser")
+	}
+
+	var r0 *entities.UserResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*entities.UserResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *entities.UserResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.UserResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHold provides a mock function with given fields: ctx, id
+func (_m *Service) GetHold(ctx context.Context, id uuid.UUID) (*entities.HoldResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHold")
+	}
+
+	var r0 *entities.HoldResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.HoldResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.HoldResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.HoldResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHoldQueue provides a mock function with given fields: ctx, bookID
+func (_m *Service) GetHoldQueue(ctx context.Context, bookID uuid.UUID) ([]*entities.HoldResponse, error) {
+	ret := _m.Called(ctx, bookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHoldQueue")
+	}
+
+	var r0 []*entities.HoldResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*entities.HoldResponse, error)); ok {
+		return rf(ctx, bookID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*entities.HoldResponse); ok {
+		r0 = rf(ctx, bookID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.HoldResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, bookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetImportJob provides a mock function with given fields: ctx, id, outcome
+func (_m *Service) GetImportJob(ctx context.Context, id uuid.UUID, outcome enums.ImportOutcome) (*entities.ImportJob, error) {
+	ret := _m.Called(ctx, id, outcome)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetImportJob")
+	}
+
+	var r0 *entities.ImportJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, enums.ImportOutcome) (*entities.ImportJob, error)); ok {
+		return rf(ctx, id, outcome)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, enums.ImportOutcome) *entities.ImportJob); ok {
+		r0 = rf(ctx, id, outcome)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.ImportJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, enums.ImportOutcome) error); ok {
+		r1 = rf(ctx, id, outcome)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetItem provides a mock function with given fields: ctx, bookID, id
+func (_m *Service) GetItem(ctx context.Context, bookID uuid.UUID, id uuid.UUID) (*entities.Item, error) {
+	ret := _m.Called(ctx, bookID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetItem")
+	}
+
+	var r0 *entities.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*entities.Item, error)); ok {
+		return rf(ctx, bookID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *entities.Item); ok {
+		r0 = rf(ctx, bookID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, bookID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLoan provides a mock function with given fields: ctx, id
+func (_m *Service) GetLoan(ctx context.Context, id uuid.UUID) (*entities.LoanResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoan")
+	}
+
+	var r0 *entities.LoanResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.LoanResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.LoanResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.LoanResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMemberBalance provides a mock function with given fields: ctx, memberID
+func (_m *Service) GetMemberBalance(ctx context.Context, memberID uuid.UUID) (*entities.MemberBalance, error) {
+	ret := _m.Called(ctx, memberID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMemberBalance")
+	}
+
+	var r0 *entities.MemberBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.MemberBalance, error)); ok {
+		return rf(ctx, memberID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.MemberBalance); ok {
+		r0 = rf(ctx, memberID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.MemberBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, memberID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublisher provides a mock function with given fields: ctx, id
+func (_m *Service) GetPublisher(ctx context.Context, id uuid.UUID) (*entities.PublisherResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPublisher")
+	}
+
+	var r0 *entities.PublisherResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.PublisherResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.PublisherResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.PublisherResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetReservation provides a mock function with given fields: ctx, id
+func (_m *Service) GetReservation(ctx context.Context, id uuid.UUID) (*entities.ReservationResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReservation")
+	}
+
+	var r0 *entities.ReservationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.ReservationResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.ReservationResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.ReservationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeatAvailability provides a mock function with given fields: ctx, date
+func (_m *Service) GetSeatAvailability(ctx context.Context, date time.Time) ([]*entities.SlotAvailability, error) {
+	ret := _m.Called(ctx, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSeatAvailability")
+	}
+
+	var r0 []*entities.SlotAvailability
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]*entities.SlotAvailability, error)); ok {
+		return rf(ctx, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []*entities.SlotAvailability); ok {
+		r0 = rf(ctx, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.SlotAvailability)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeries provides a mock function with given fields: ctx, id
+func (_m *Service) GetSeries(ctx context.Context, id uuid.UUID) (*entities.SeriesResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSeries")
+	}
+
+	var r0 *entities.SeriesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.SeriesResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.SeriesResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.SeriesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeriesPosition provides a mock function with given fields: ctx, bookID
+func (_m *Service) GetSeriesPosition(ctx context.Context, bookID uuid.UUID) (*entities.SeriesPosition, error) {
+	ret := _m.Called(ctx, bookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSeriesPosition")
+	}
+
+	var r0 *entities.SeriesPosition
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.SeriesPosition, error)); ok {
+		return rf(ctx, bookID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.SeriesPosition); ok {
+		r0 = rf(ctx, bookID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.SeriesPosition)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, bookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubject provides a mock function with given fields: ctx, id
+func (_m *Service) GetSubject(ctx context.Context, id uuid.UUID) (*entities.SubjectTree, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubject")
+	}
+
+	var r0 *entities.SubjectTree
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.SubjectTree, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.SubjectTree); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.SubjectTree)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserRoles provides a mock function with given fields: ctx, userID
+func (_m *Service) GetUserRoles(ctx context.Context, userID uuid.UUID) (*entities.UserResponse, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserRoles")
+	}
+
+	var r0 *entities.UserResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.UserResponse, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.UserResponse); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.UserResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWork provides a mock function with given fields: ctx, id
+func (_m *Service) GetWork(ctx context.Context, id uuid.UUID) (*entities.WorkResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWork")
+	}
+
+	var r0 *entities.WorkResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.WorkResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.WorkResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.WorkResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantRole provides a mock function with given fields: ctx, userID, role
+func (_m *Service) GrantRole(ctx context.Context, userID uuid.UUID, role enums.Role) error {
+	ret := _m.Called(ctx, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, enums.Role) error); ok {
+		r0 = rf(ctx, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ImportBooks provides a mock function with given fields: ctx, req
+func (_m *Service) ImportBooks(ctx context.Context, req *entities.ImportRequest) (*entities.ImportJob, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportBooks")
+	}
+
+	var r0 *entities.ImportJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.ImportRequest) (*entities.ImportJob, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.ImportRequest) *entities.ImportJob); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.ImportJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.ImportRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsInGoodStanding provides a mock function with given fields: ctx, memberID
+func (_m *Service) IsInGoodStanding(ctx context.Context, memberID uuid.UUID) (bool, error) {
+	ret := _m.Called(ctx, memberID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsInGoodStanding")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (bool, error)); ok {
+		return rf(ctx, memberID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) bool); ok {
+		r0 = rf(ctx, memberID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, memberID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAuthorBooks provides a mock function with given fields: ctx, id, role
+func (_m *Service) ListAuthorBooks(ctx context.Context, id uuid.UUID, role enums.ContributorRole) ([]*entities.BookResponse, error) {
+	ret := _m.Called(ctx, id, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAuthorBooks")
+	}
+
+	var r0 []*entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, enums.ContributorRole) ([]*entities.BookResponse, error)); ok {
+		return rf(ctx, id, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, enums.ContributorRole) []*entities.BookResponse); ok {
+		r0 = rf(ctx, id, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, enums.ContributorRole) error); ok {
+		r1 = rf(ctx, id, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAuthors provides a mock function with given fields: ctx, query
+func (_m *Service) ListAuthors(ctx context.Context, query *entities.AuthorQuery) (*entities.AuthorPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAuthors")
+	}
+
+	var r0 *entities.AuthorPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.AuthorQuery) (*entities.AuthorPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.AuthorQuery) *entities.AuthorPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.AuthorPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.AuthorQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListBookHistory provides a mock function with given fields: ctx, id
+func (_m *Service) ListBookHistory(ctx context.Context, id uuid.UUID) ([]entities.BookRevision, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBookHistory")
+	}
+
+	var r0 []entities.BookRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]entities.BookRevision, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []entities.BookRevision); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.BookRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListEditions provides a mock function with given fields: ctx, id
+func (_m *Service) ListEditions(ctx context.Context, id uuid.UUID) ([]*entities.BookResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEditions")
+	}
+
+	var r0 []*entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]*entities.BookResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*entities.BookResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListHolds provides a mock function with given fields: ctx, query
+func (_m *Service) ListHolds(ctx context.Context, query *entities.HoldQuery) ([]*entities.HoldResponse, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListHolds")
+	}
+
+	var r0 []*entities.HoldResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.HoldQuery) ([]*entities.HoldResponse, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.HoldQuery) []*entities.HoldResponse); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.HoldResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.HoldQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListItems provides a mock function with given fields: ctx, bookID
+func (_m *Service) ListItems(ctx context.Context, bookID uuid.UUID) ([]entities.Item, error) {
+	ret := _m.Called(ctx, bookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListItems")
+	}
+
+	var r0 []entities.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]entities.Item, error)); ok {
+		return rf(ctx, bookID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []entities.Item); ok {
+		r0 = rf(ctx, bookID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, bookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListLoans provides a mock function with given fields: ctx, query
+func (_m *Service) ListLoans(ctx context.Context, query *entities.LoanQuery) ([]*entities.LoanResponse, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLoans")
+	}
+
+	var r0 []*entities.LoanResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.LoanQuery) ([]*entities.LoanResponse, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.LoanQuery) []*entities.LoanResponse); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.LoanResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.LoanQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMemberTransactions provides a mock function with given fields: ctx, memberID
+func (_m *Service) ListMemberTransactions(ctx context.Context, memberID uuid.UUID) ([]entities.LedgerTransaction, error) {
+	ret := _m.Called(ctx, memberID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMemberTransactions")
+	}
+
+	var r0 []entities.LedgerTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]entities.LedgerTransaction, error)); ok {
+		return rf(ctx, memberID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []entities.LedgerTransaction); ok {
+		r0 = rf(ctx, memberID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.LedgerTransaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, memberID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPublisherBooks provides a mock function with given fields: ctx, id, imprints
+func (_m *Service) ListPublisherBooks(ctx context.Context, id uuid.UUID, imprints bool) ([]*entities.BookResponse, error) {
+	ret := _m.Called(ctx, id, imprints)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPublisherBooks")
+	}
+
+	var r0 []*entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool) ([]*entities.BookResponse, error)); ok {
+		return rf(ctx, id, imprints)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool) []*entities.BookResponse); ok {
+		r0 = rf(ctx, id, imprints)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, bool) error); ok {
+		r1 = rf(ctx, id, imprints)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPublishers provides a mock function with given fields: ctx, query
+func (_m *Service) ListPublishers(ctx context.Context, query *entities.PublisherQuery) (*entities.PublisherPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPublishers")
+	}
+
+	var r0 *entities.PublisherPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.PublisherQuery) (*entities.PublisherPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.PublisherQuery) *entities.PublisherPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.PublisherPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.PublisherQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListReservations provides a mock function with given fields: ctx, query
+func (_m *Service) ListReservations(ctx context.Context, query *entities.ReservationQuery) ([]*entities.ReservationResponse, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListReservations")
+	}
+
+	var r0 []*entities.ReservationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.ReservationQuery) ([]*entities.ReservationResponse, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.ReservationQuery) []*entities.ReservationResponse); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.ReservationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.ReservationQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListRoleAudit provides a mock function with given fields: ctx, userID
+func (_m *Service) ListRoleAudit(ctx context.Context, userID *uuid.UUID) ([]entities.RoleAudit, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRoleAudit")
+	}
+
+	var r0 []entities.RoleAudit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *uuid.UUID) ([]entities.RoleAudit, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *uuid.UUID) []entities.RoleAudit); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.RoleAudit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSeries provides a mock function with given fields: ctx, name
+func (_m *Service) ListSeries(ctx context.Context, name string) ([]*entities.SeriesResponse, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSeries")
+	}
+
+	var r0 []*entities.SeriesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*entities.SeriesResponse, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*entities.SeriesResponse); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.SeriesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTrash provides a mock function with given fields: ctx
+func (_m *Service) ListTrash(ctx context.Context) ([]*entities.BookResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTrash")
+	}
+
+	var r0 []*entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*entities.BookResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*entities.BookResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWorks provides a mock function with given fields: ctx, query
+func (_m *Service) ListWorks(ctx context.Context, query *entities.WorkQuery) (*entities.WorkPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWorks")
+	}
+
+	var r0 *entities.WorkPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.WorkQuery) (*entities.WorkPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.WorkQuery) *entities.WorkPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.WorkPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.WorkQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: ctx, req
+func (_m *Service) Login(ctx context.Context, req *entities.LoginRequest) (*entities.TokenResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *entities.TokenResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.LoginRequest) (*entities.TokenResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.LoginRequest) *entities.TokenResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.TokenResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.LoginRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MergePublishers provides a mock function with given fields: ctx, id, req
+func (_m *Service) MergePublishers(ctx context.Context, id uuid.UUID, req *entities.PublisherMergeRequest) (*entities.PublisherResponse, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergePublishers")
+	}
+
+	var r0 *entities.PublisherResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.PublisherMergeRequest) (*entities.PublisherResponse, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.PublisherMergeRequest) *entities.PublisherResponse); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.PublisherResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.PublisherMergeRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchBook provides a mock function with given fields: ctx, id, version, patch
+func (_m *Service) PatchBook(ctx context.Context, id uuid.UUID, version int64, patch *entities.BookPatch) (*entities.BookResponse, error) {
+	ret := _m.Called(ctx, id, version, patch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchBook")
+	}
+
+	var r0 *entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64, *entities.BookPatch) (*entities.BookResponse, error)); ok {
+		return rf(ctx, id, version, patch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64, *entities.BookPatch) *entities.BookResponse); ok {
+		r0 = rf(ctx, id, version, patch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int64, *entities.BookPatch) error); ok {
+		r1 = rf(ctx, id, version, patch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PlaceHold provides a mock function with given fields: ctx, bookID, req
+func (_m *Service) PlaceHold(ctx context.Context, bookID uuid.UUID, req *entities.HoldRequest) (*entities.HoldResponse, error) {
+	ret := _m.Called(ctx, bookID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlaceHold")
+	}
+
+	var r0 *entities.HoldResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.HoldRequest) (*entities.HoldResponse, error)); ok {
+		return rf(ctx, bookID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.HoldRequest) *entities.HoldResponse); ok {
+		r0 = rf(ctx, bookID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.HoldResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.HoldRequest) error); ok {
+		r1 = rf(ctx, bookID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeBook provides a mock function with given fields: ctx, id
+func (_m *Service) PurgeBook(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeBook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeTrash provides a mock function with given fields: ctx
+func (_m *Service) PurgeTrash(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeTrash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordPayment provides a mock function with given fields: ctx, memberID, req
+func (_m *Service) RecordPayment(ctx context.Context, memberID uuid.UUID, req *entities.PaymentRequest) (*entities.LedgerTransaction, error) {
+	ret := _m.Called(ctx, memberID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordPayment")
+	}
+
+	var r0 *entities.LedgerTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.PaymentRequest) (*entities.LedgerTransaction, error)); ok {
+		return rf(ctx, memberID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.PaymentRequest) *entities.LedgerTransaction); ok {
+		r0 = rf(ctx, memberID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.LedgerTransaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.PaymentRequest) error); ok {
+		r1 = rf(ctx, memberID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshToken provides a mock function with given fields: ctx, req
+func (_m *Service) RefreshToken(ctx context.Context, req *entities.RefreshRequest) (*entities.TokenResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshToken")
+	}
+
+	var r0 *entities.TokenResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.RefreshRequest) (*entities.TokenResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.RefreshRequest) *entities.TokenResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.TokenResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.RefreshRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Register provides a mock function with given fields: ctx, req
+func (_m *Service) Register(ctx context.Context, req *entities.RegisterRequest) (*entities.UserResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Register")
+	}
+
+	var r0 *entities.UserResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.RegisterRequest) (*entities.UserResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.RegisterRequest) *entities.UserResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.UserResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.RegisterRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReserveSeat provides a mock function with given fields: ctx, req
+func (_m *Service) ReserveSeat(ctx context.Context, req *entities.ReservationRequest) (*entities.ReservationResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveSeat")
+	}
+
+	var r0 *entities.ReservationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.ReservationRequest) (*entities.ReservationResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.ReservationRequest) *entities.ReservationResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.ReservationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.ReservationRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreBook provides a mock function with given fields: ctx, id
+func (_m *Service) RestoreBook(ctx context.Context, id uuid.UUID) (*entities.BookResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreBook")
+	}
+
+	var r0 *entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.BookResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.BookResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReturnLoan provides a mock function with given fields: ctx, id
+func (_m *Service) ReturnLoan(ctx context.Context, id uuid.UUID) (*entities.LoanResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReturnLoan")
+	}
+
+	var r0 *entities.LoanResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*entities.LoanResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *entities.LoanResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.LoanResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevertBook provides a mock function with given fields: ctx, id, version, revision
+func (_m *Service) RevertBook(ctx context.Context, id uuid.UUID, version int64, revision int64) (*entities.BookResponse, error) {
+	ret := _m.Called(ctx, id, version, revision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevertBook")
+	}
+
+	var r0 *entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64, int64) (*entities.BookResponse, error)); ok {
+		return rf(ctx, id, version, revision)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64, int64) *entities.BookResponse); ok {
+		r0 = rf(ctx, id, version, revision)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int64, int64) error); ok {
+		r1 = rf(ctx, id, version, revision)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeRole provides a mock function with given fields: ctx, userID, role
+func (_m *Service) RevokeRole(ctx context.Context, userID uuid.UUID, role enums.Role) error {
+	ret := _m.Called(ctx, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, enums.Role) error); ok {
+		r0 = rf(ctx, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchBooks provides a mock function with given fields: ctx, query
+func (_m *Service) SearchBooks(ctx context.Context, query *entities.BookSearchQuery) (*entities.BookSearchPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchBooks")
+	}
+
+	var r0 *entities.BookSearchPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookSearchQuery) (*entities.BookSearchPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *entities.BookSearchQuery) *entities.BookSearchPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookSearchPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *entities.BookSearchQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetCover provides a mock function with given fields: ctx, id, version, r
+func (_m *Service) SetCover(ctx context.Context, id uuid.UUID, version int64, r io.Reader) (*entities.BookResponse, error) {
+	ret := _m.Called(ctx, id, version, r)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCover")
+	}
+
+	var r0 *entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64, io.Reader) (*entities.BookResponse, error)); ok {
+		return rf(ctx, id, version, r)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64, io.Reader) *entities.BookResponse); ok {
+		r0 = rf(ctx, id, version, r)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int64, io.Reader) error); ok {
+		r1 = rf(ctx, id, version, r)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetHoldPriority provides a mock function with given fields: ctx, id, req
+func (_m *Service) SetHoldPriority(ctx context.Context, id uuid.UUID, req *entities.HoldPriorityRequest) (*entities.HoldResponse, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetHoldPriority")
+	}
+
+	var r0 *entities.HoldResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.HoldPriorityRequest) (*entities.HoldResponse, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.HoldPriorityRequest) *entities.HoldResponse); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.HoldResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.HoldPriorityRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateAuthor provides a mock function with given fields: ctx, id, req
+func (_m *Service) UpdateAuthor(ctx context.Context, id uuid.UUID, req *entities.AuthorRequest) (*entities.AuthorResponse, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAuthor")
+	}
+
+	var r0 *entities.AuthorResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.AuthorRequest) (*entities.AuthorResponse, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.AuthorRequest) *entities.AuthorResponse); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.AuthorResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.AuthorRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateBook provides a mock function with given fields: ctx, id, version, req
+func (_m *Service) UpdateBook(ctx context.Context, id uuid.UUID, version int64, req *entities.BookRequest) (*entities.BookResponse, error) {
+	ret := _m.Called(ctx, id, version, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBook")
+	}
+
+	var r0 *entities.BookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64, *entities.BookRequest) (*entities.BookResponse, error)); ok {
+		return rf(ctx, id, version, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64, *entities.BookRequest) *entities.BookResponse); ok {
+		r0 = rf(ctx, id, version, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.BookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, int64, *entities.BookRequest) error); ok {
+		r1 = rf(ctx, id, version, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateItem provides a mock function with given fields: ctx, bookID, id, req
+func (_m *Service) UpdateItem(ctx context.Context, bookID uuid.UUID, id uuid.UUID, req *entities.ItemRequest) (*entities.Item, error) {
+	ret := _m.Called(ctx, bookID, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateItem")
+	}
+
+	var r0 *entities.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, *entities.ItemRequest) (*entities.Item, error)); ok {
+		return rf(ctx, bookID, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, *entities.ItemRequest) *entities.Item); ok {
+		r0 = rf(ctx, bookID, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, *entities.ItemRequest) error); ok {
+		r1 = rf(ctx, bookID, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePublisher provides a mock function with given fields: ctx, id, req
+func (_m *Service) UpdatePublisher(ctx context.Context, id uuid.UUID, req *entities.PublisherRequest) (*entities.PublisherResponse, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePublisher")
+	}
+
+	var r0 *entities.PublisherResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.PublisherRequest) (*entities.PublisherResponse, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.PublisherRequest) *entities.PublisherResponse); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.PublisherResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.PublisherRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSeries provides a mock function with given fields: ctx, id, req
+func (_m *Service) UpdateSeries(ctx context.Context, id uuid.UUID, req *entities.SeriesRequest) (*entities.SeriesResponse, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSeries")
+	}
+
+	var r0 *entities.SeriesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.SeriesRequest) (*entities.SeriesResponse, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.SeriesRequest) *entities.SeriesResponse); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.SeriesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.SeriesRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSubject provides a mock function with given fields: ctx, id, req
+func (_m *Service) UpdateSubject(ctx context.Context, id uuid.UUID, req *entities.SubjectRequest) (*entities.SubjectNode, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSubject")
+	}
+
+	var r0 *entities.SubjectNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.SubjectRequest) (*entities.SubjectNode, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.SubjectRequest) *entities.SubjectNode); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.SubjectNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.SubjectRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWork provides a mock function with given fields: ctx, id, req
+func (_m *Service) UpdateWork(ctx context.Context, id uuid.UUID, req *entities.WorkRequest) (*entities.WorkResponse, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWork")
+	}
+
+	var r0 *entities.WorkResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.WorkRequest) (*entities.WorkResponse, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.WorkRequest) *entities.WorkResponse); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.WorkResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.WorkRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WaiveFine provides a mock function with given fields: ctx, memberID, req
+func (_m *Service) WaiveFine(ctx context.Context, memberID uuid.UUID, req *entities.WaiverRequest) (*entities.LedgerTransaction, error) {
+	ret := _m.Called(ctx, memberID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WaiveFine")
+	}
+
+	var r0 *entities.LedgerTransaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.WaiverRequest) (*entities.LedgerTransaction, error)); ok {
+		return rf(ctx, memberID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *entities.WaiverRequest) *entities.LedgerTransaction); ok {
+		r0 = rf(ctx, memberID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.LedgerTransaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *entities.WaiverRequest) error); ok {
+		r1 = rf(ctx, memberID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // NewService creates a new instance of Service. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
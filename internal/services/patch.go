@@ -0,0 +1,139 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"library-system/internal/entities"
+	"library-system/internal/jsonpatch"
+
+	"github.com/gofrs/uuid"
+)
+
+// PatchBook applies a JSON Merge Patch or a JSON Patch to a book, taken as
+// the request a PUT would send, and saves what changed. Only the patched
+// book is validated. The version works as for UpdateBook.
+func (s *service) PatchBook(ctx context.Context, id uuid.UUID, version int64, patch *entities.BookPatch) (*entities.BookResponse, error) {
+	book, err := s.bookAt(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.bookRequest(ctx, book)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := patchDocument(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch patch.ContentType {
+	case jsonpatch.MergePatchType:
+		patched, err = jsonpatch.MergePatch(doc, patch.Body)
+	case jsonpatch.JSONPatchType:
+		patched, err = jsonpatch.Apply(doc, patch.Body)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", entities.ErrInvalidPatch, patch.ContentType)
+	}
+	switch {
+	case errors.Is(err, jsonpatch.ErrInvalid):
+		return nil, fmt.Errorf("%w: %v", entities.ErrInvalidPatch, err)
+	case errors.Is(err, jsonpatch.ErrConflict):
+		return nil, fmt.Errorf("%w: %v", entities.ErrPatchConflict, err)
+	case err != nil:
+		return nil, err
+	}
+
+	req := &entities.BookRequest{}
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(req); err != nil {
+		return nil, fmt.Errorf("%w: %v", entities.ErrInvalidBook, err)
+	}
+
+	// Copies count the copies on the shelf, so a book that is all out on
+	// loan has none and they are checked apart from the required fields
+	if err := s.validate.StructExcept(req, "Copies"); err != nil {
+		return nil, fmt.Errorf("%w: %w", entities.ErrInvalidBook, err)
+	}
+	if req.Copies < 0 {
+		return nil, fmt.Errorf("%w: copies cannot be negative", entities.ErrInvalidBook)
+	}
+
+	return s.saveBook(ctx, book, req, current)
+}
+
+// bookRequest describes a stored book as the request that would save it
+// unchanged
+func (s *service) bookRequest(ctx context.Context, book *entities.Book) (*entities.BookRequest, error) {
+	contributors, err := s.model.Author.ForBook(ctx, book.ID)
+	if err != nil {
+		return nil, err
+	}
+	subjects, err := s.model.Subject.ForBook(ctx, book.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &entities.BookRequest{
+		Title:       book.Title,
+		Author:      book.Author,
+		ISBN:        book.ISBN,
+		Publisher:   book.Publisher,
+		PublisherID: book.PublisherID,
+		PublishDate: book.PublishDate,
+		Description: book.Description,
+		Copies:      book.Copies,
+		ItemType:    book.ItemType,
+		Dewey:       book.Dewey,
+		LCC:         book.LCC,
+		WorkID:      book.WorkID,
+	}
+	for _, c := range contributors {
+		req.Contributors = append(req.Contributors, entities.ContributorRequest{AuthorID: &c.AuthorID, Role: c.Role})
+	}
+	for _, subject := range subjects {
+		req.SubjectIDs = append(req.SubjectIDs, subject.ID)
+	}
+
+	return req, nil
+}
+
+// patchDocument renders the request a patch applies to, with every member
+// present, so that JSON Patch operations can replace empty ones
+func patchDocument(req *entities.BookRequest) ([]byte, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := map[string]interface{}{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	for name, empty := range map[string]interface{}{
+		"publisher_id": nil,
+		"dewey":        "",
+		"lcc":          "",
+		"contributors": []interface{}{},
+		"subject_ids":  []interface{}{},
+		"work_id":      nil,
+	} {
+		if _, ok := doc[name]; !ok {
+			doc[name] = empty
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+// sameContributor reports whether a patched contributor still credits the
+// same author in the same role
+func sameContributor(a, b entities.ContributorRequest) bool {
+	return sameID(a.AuthorID, b.AuthorID) && a.Name == b.Name && a.Role == b.Role
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/jsonpatch"
+	"library-system/internal/models"
+	authorMock "library-system/internal/models/author/mocks"
+	bookMock "library-system/internal/models/book/mocks"
+	subjectMock "library-system/internal/models/subject/mocks"
+	"library-system/internal/validation"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_PatchBook(t *testing.T) {
+	bookID := uuid.Must(uuid.NewV4())
+	authorID := uuid.Must(uuid.NewV4())
+	publisherID := uuid.Must(uuid.NewV4())
+	filed := uuid.Must(uuid.NewV4())
+	added := uuid.Must(uuid.NewV4())
+
+	// Every copy is out on loan, which a patch must not trip over
+	stored := func() *entities.Book {
+		return &entities.Book{
+			ID: bookID, Title: "Nineteen Eighty-Four", Author: "George Orwell", ISBN: "9780451524935",
+			Publisher: "Signet", PublisherID: &publisherID, PublishDate: time.Date(1950, 7, 1, 0, 0, 0, 0, time.UTC),
+			ItemType: enums.ItemBook, Version: 6,
+		}
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		columns     []string
+		subjects    []uuid.UUID
+		wantErr     error
+	}{
+		{
+			name:        "merge patch",
+			contentType: jsonpatch.MergePatchType,
+			body:        `{"description": "A dystopia", "lcc": "PR6029.R8 N49"}`,
+			columns:     []string{"description", "lcc"},
+		},
+		{
+			name:        "json patch",
+			contentType: jsonpatch.JSONPatchType,
+			body:        `[{"op": "test", "path": "/title", "value": "Nineteen Eighty-Four"}, {"op": "add", "path": "/subject_ids/-", "value": "` + added.String() + `"}]`,
+			subjects:    []uuid.UUID{filed, added},
+		},
+		{
+			name:        "failed test",
+			contentType: jsonpatch.JSONPatchType,
+			body:        `[{"op": "test", "path": "/title", "value": "Animal Farm"}, {"op": "replace", "path": "/title", "value": "x"}]`,
+			wantErr:     entities.ErrPatchConflict,
+		},
+		{
+			name:        "malformed patch",
+			contentType: jsonpatch.JSONPatchType,
+			body:        `{"op": "remove"}`,
+			wantErr:     entities.ErrInvalidPatch,
+		},
+		{
+			name:        "required field removed",
+			contentType: jsonpatch.MergePatchType,
+			body:        `{"title": null}`,
+			wantErr:     entities.ErrInvalidBook,
+		},
+		{
+			name:        "unknown field",
+			contentType: jsonpatch.MergePatchType,
+			body:        `{"colour": "red"}`,
+			wantErr:     entities.ErrInvalidBook,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			book := stored()
+			books := bookMock.Book{}
+			books.On("GetByID", mock.Anything, bookID).Return(book, nil)
+			if tt.columns != nil || tt.subjects != nil {
+				books.On("Update", mock.Anything, mock.MatchedBy(func(c *entities.BookChange) bool {
+					return c.Book == book && slices.Equal(c.Columns, tt.columns) &&
+						c.Contributors == nil && slices.Equal(c.SubjectIDs, tt.subjects) && c.Copies == nil
+				})).Return(nil)
+			}
+
+			authors := authorMock.Author{}
+			authors.On("ForBook", mock.Anything, bookID).Return([]entities.BookContributor{
+				{BookID: bookID, AuthorID: authorID, Role: enums.ContributorAuthor},
+			}, nil)
+
+			subjects := subjectMock.Subject{}
+			subjects.On("ForBook", mock.Anything, bookID).Return([]entities.Subject{{ID: filed}}, nil)
+
+			s := &service{
+				model:    models.Model{Book: &books, Author: &authors, Subject: &subjects},
+				validate: validation.New(),
+			}
+
+			got, err := s.PatchBook(context.Background(), bookID, 6, &entities.BookPatch{ContentType: tt.contentType, Body: []byte(tt.body)})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PatchBook() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.ID != bookID {
+				t.Errorf("PatchBook() = %+v", got)
+			}
+
+			// Only what the patch changed is written, links included
+			books.AssertExpectations(t)
+		})
+	}
+
+	books := bookMock.Book{}
+	books.On("GetByID", mock.Anything, bookID).Return(stored(), nil)
+	s := &service{model: models.Model{Book: &books}}
+	if _, err := s.PatchBook(context.Background(), bookID, 5, &entities.BookPatch{}); !errors.Is(err, entities.ErrBookModified) {
+		t.Errorf("PatchBook() of an older version error = %v, want %v", err, entities.ErrBookModified)
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"library-system/internal/entities"
+	"library-system/internal/names"
+
+	"github.com/gofrs/uuid"
+)
+
+// CreatePublisher adds a publisher to the registry
+func (s *service) CreatePublisher(ctx context.Context, req *entities.PublisherRequest) (*entities.PublisherResponse, error) {
+	publisher := &entities.Publisher{}
+	if err := applyPublisherRequest(publisher, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.model.Publisher.Create(ctx, publisher); err != nil {
+		return nil, err
+	}
+
+	return s.GetPublisher(ctx, publisher.ID)
+}
+
+// GetPublisher retrieves a publisher with its aliases, parent and imprints
+func (s *service) GetPublisher(ctx context.Context, id uuid.UUID) (*entities.PublisherResponse, error) {
+	publisher, err := s.model.Publisher.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toPublisherResponse(publisher), nil
+}
+
+// ListPublishers retrieves one page of publishers, optionally matching a
+// name
+func (s *service) ListPublishers(ctx context.Context, query *entities.PublisherQuery) (*entities.PublisherPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = entities.DefaultPageSize
+	}
+	if query.Limit > entities.MaxPageSize {
+		query.Limit = entities.MaxPageSize
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	query.Name = strings.TrimSpace(query.Name)
+
+	publishers, total, err := s.model.Publisher.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entities.PublisherResponse, len(publishers))
+	for i := range publishers {
+		resp[i] = toPublisherResponse(&publishers[i])
+	}
+
+	return &entities.PublisherPage{
+		Data:  resp,
+		Total: total,
+		Page:  query.Page,
+		Limit: query.Limit,
+	}, nil
+}
+
+// UpdatePublisher changes a publisher's name and parent. The aliases given
+// replace the publisher's aliases.
+func (s *service) UpdatePublisher(ctx context.Context, id uuid.UUID, req *entities.PublisherRequest) (*entities.PublisherResponse, error) {
+	publisher, err := s.model.Publisher.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyPublisherRequest(publisher, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.model.Publisher.Update(ctx, publisher); err != nil {
+		return nil, err
+	}
+
+	return s.GetPublisher(ctx, id)
+}
+
+// DeletePublisher removes a publisher without books or imprints
+func (s *service) DeletePublisher(ctx context.Context, id uuid.UUID) error {
+	return s.model.Publisher.Delete(ctx, id)
+}
+
+// ListPublisherBooks lists the books linked to a publisher, and with
+// imprints to any of its imprints
+func (s *service) ListPublisherBooks(ctx context.Context, id uuid.UUID, imprints bool) ([]*entities.BookResponse, error) {
+	if _, err := s.model.Publisher.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	books, err := s.model.Publisher.Books(ctx, id, imprints)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entities.BookResponse, len(books))
+	for i := range books {
+		resp[i] = s.toBookResponse(&books[i])
+	}
+
+	return resp, nil
+}
+
+// MergePublishers folds duplicate publishers into the one with the given ID
+func (s *service) MergePublishers(ctx context.Context, id uuid.UUID, req *entities.PublisherMergeRequest) (*entities.PublisherResponse, error) {
+	if err := s.model.Publisher.Merge(ctx, id, req.From); err != nil {
+		return nil, err
+	}
+
+	return s.GetPublisher(ctx, id)
+}
+
+// bookPublisher finds the registry entry for a book request: the publisher
+// given by ID, or else the one behind the publisher credit
+func (s *service) bookPublisher(ctx context.Context, req *entities.BookRequest) (*uuid.UUID, error) {
+	if req.PublisherID != nil {
+		if _, err := s.model.Publisher.GetByID(ctx, *req.PublisherID); err != nil {
+			if errors.Is(err, entities.ErrPublisherNotFound) {
+				return nil, fmt.Errorf("%w: %v", entities.ErrInvalidPublisher, err)
+			}
+			return nil, err
+		}
+		return req.PublisherID, nil
+	}
+
+	ids, err := s.model.Publisher.Resolve(ctx, []string{req.Publisher})
+	if err != nil {
+		return nil, err
+	}
+	return ids[0], nil
+}
+
+// applyPublisherRequest copies the request onto the publisher, filling in
+// the matching keys
+func applyPublisherRequest(publisher *entities.Publisher, req *entities.PublisherRequest) error {
+	publisher.Name = strings.Join(strings.Fields(req.Name), " ")
+	publisher.NameKey = names.OrganizationKey(publisher.Name)
+	if publisher.NameKey == "" {
+		return fmt.Errorf("%w: name %q", entities.ErrInvalidPublisher, req.Name)
+	}
+	if req.ParentID != nil && *req.ParentID == publisher.ID {
+		return fmt.Errorf("%w: a publisher cannot be its own imprint", entities.ErrInvalidPublisher)
+	}
+	publisher.ParentID = req.ParentID
+	publisher.Parent = nil
+
+	// Aliases that match the name or each other would never be looked up
+	publisher.Aliases = nil
+	seen := map[string]bool{publisher.NameKey: true}
+	for _, alias := range req.Aliases {
+		alias = strings.Join(strings.Fields(alias), " ")
+		key := names.OrganizationKey(alias)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		publisher.Aliases = append(publisher.Aliases, entities.PublisherAlias{PublisherID: publisher.ID, Name: alias, NameKey: key})
+	}
+
+	return nil
+}
+
+// toPublisherResponse maps a stored publisher onto its API representation
+func toPublisherResponse(publisher *entities.Publisher) *entities.PublisherResponse {
+	aliases := make([]string, len(publisher.Aliases))
+	for i, alias := range publisher.Aliases {
+		aliases[i] = alias.Name
+	}
+
+	resp := &entities.PublisherResponse{
+		ID:        publisher.ID,
+		Name:      publisher.Name,
+		Aliases:   aliases,
+		BookCount: publisher.BookCount,
+		CreatedAt: publisher.CreatedAt,
+		UpdatedAt: publisher.UpdatedAt,
+	}
+	if publisher.Parent != nil {
+		resp.Parent = &entities.PublisherSummary{ID: publisher.Parent.ID, Name: publisher.Parent.Name}
+	}
+	for _, imprint := range publisher.Imprints {
+		resp.Imprints = append(resp.Imprints, entities.PublisherSummary{ID: imprint.ID, Name: imprint.Name})
+	}
+
+	return resp
+}
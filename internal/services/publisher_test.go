@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"library-system/internal/entities"
+	"library-system/internal/models"
+	publisherMock "library-system/internal/models/publisher/mocks"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_UpdatePublisher(t *testing.T) {
+	id, _ := uuid.NewV4()
+	parentID, _ := uuid.NewV4()
+
+	publishers := publisherMock.Publisher{}
+	publishers.On("GetByID", mock.Anything, id).Return(&entities.Publisher{ID: id, Name: "Penguin"}, nil)
+	publishers.On("Update", mock.Anything, mock.MatchedBy(func(p *entities.Publisher) bool {
+		return p.Name == "Penguin Classics" && p.NameKey == "penguinclassics" && *p.ParentID == parentID &&
+			len(p.Aliases) == 1 && p.Aliases[0].Name == "Penguin Classic" && p.Aliases[0].NameKey == "penguinclassic"
+	})).Return(nil)
+
+	s := &service{model: models.Model{Publisher: &publishers}}
+
+	_, err := s.UpdatePublisher(context.Background(), id, &entities.PublisherRequest{
+		Name:     " Penguin  Classics ",
+		ParentID: &parentID,
+		Aliases:  []string{"Penguin Classic", "Penguin Classics Ltd", "penguin classic"},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePublisher() error = %v", err)
+	}
+	publishers.AssertExpectations(t)
+
+	_, err = s.UpdatePublisher(context.Background(), id, &entities.PublisherRequest{Name: "Penguin", ParentID: &id})
+	if !errors.Is(err, entities.ErrInvalidPublisher) {
+		t.Errorf("UpdatePublisher() as its own imprint error = %v, want %v", err, entities.ErrInvalidPublisher)
+	}
+}
+
+func Test_service_bookPublisher(t *testing.T) {
+	known, _ := uuid.NewV4()
+	unknown, _ := uuid.NewV4()
+
+	publishers := publisherMock.Publisher{}
+	publishers.On("GetByID", mock.Anything, known).Return(&entities.Publisher{ID: known}, nil)
+	publishers.On("GetByID", mock.Anything, unknown).Return(nil, entities.ErrPublisherNotFound)
+	publishers.On("Resolve", mock.Anything, []string{"Ace Books"}).Return([]*uuid.UUID{&known}, nil)
+
+	s := &service{model: models.Model{Publisher: &publishers}}
+
+	tests := []struct {
+		name    string
+		req     *entities.BookRequest
+		want    *uuid.UUID
+		wantErr error
+	}{
+		{name: "picked by ID", req: &entities.BookRequest{Publisher: "Ace", PublisherID: &known}, want: &known},
+		{name: "unknown ID", req: &entities.BookRequest{PublisherID: &unknown}, wantErr: entities.ErrInvalidPublisher},
+		{name: "from the credit", req: &entities.BookRequest{Publisher: "Ace Books"}, want: &known},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.bookPublisher(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("bookPublisher() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want != nil && (got == nil || *got != *tt.want) {
+				t.Errorf("bookPublisher() = %v, want %v", got, *tt.want)
+			}
+		})
+	}
+}
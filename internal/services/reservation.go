@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"library-system/internal/auth"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// ReserveSeat books a reading-room seat for the slot containing
+// req.StartsAt. Members book for themselves; staff may name another member.
+func (s *service) ReserveSeat(ctx context.Context, req *entities.ReservationRequest) (*entities.ReservationResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, entities.ErrUnauthenticated
+	}
+
+	member := principal.UserID
+	if req.UserID != nil && *req.UserID != principal.UserID {
+		if !auth.Can(principal, auth.PermReservationsManage) {
+			return nil, entities.ErrForbidden
+		}
+		member = *req.UserID
+	}
+
+	if !req.Location.IsValid() {
+		return nil, entities.ErrInvalidReservation
+	}
+
+	startsAt := req.StartsAt.In(time.Local)
+	slot := enums.GetSlot(startsAt)
+	day := reservationDay(startsAt)
+
+	// A slot that is under way can still be booked, one that is over can't
+	if !slotStart(day, slot).Add(enums.SlotDuration).After(time.Now()) {
+		return nil, entities.ErrInvalidReservation
+	}
+
+	reservation := &entities.Reservation{
+		UserID:   member,
+		Date:     day,
+		Slot:     slot,
+		Location: req.Location,
+	}
+	limits := entities.ReservationLimits{
+		Capacity:   s.seats(req.Location),
+		DailyLimit: s.config.ReservationsPerDay,
+	}
+
+	if err := s.model.Reservation.Book(ctx, reservation, limits); err != nil {
+		return nil, err
+	}
+
+	return toReservationResponse(reservation), nil
+}
+
+// CancelReservation releases a seat before its slot starts
+func (s *service) CancelReservation(ctx context.Context, id uuid.UUID) (*entities.ReservationResponse, error) {
+	reservation, err := s.authorizeReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if reservation.Status == enums.Cancelled {
+		return nil, entities.ErrReservationCancelled
+	}
+
+	now := time.Now()
+	if !slotStart(reservation.Date, reservation.Slot).After(now) {
+		return nil, entities.ErrReservationStarted
+	}
+
+	reservation, err = s.model.Reservation.Cancel(ctx, id, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return toReservationResponse(reservation), nil
+}
+
+// GetReservation retrieves a reservation of the caller, or of anyone for staff
+func (s *service) GetReservation(ctx context.Context, id uuid.UUID) (*entities.ReservationResponse, error) {
+	reservation, err := s.authorizeReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toReservationResponse(reservation), nil
+}
+
+// ListReservations lists the caller's reservations. Staff may list any
+// member's, or everyone's when no member is given.
+func (s *service) ListReservations(ctx context.Context, query *entities.ReservationQuery) ([]*entities.ReservationResponse, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, entities.ErrUnauthenticated
+	}
+
+	if !auth.Can(principal, auth.PermReservationsManage) {
+		if query.UserID != nil && *query.UserID != principal.UserID {
+			return nil, entities.ErrForbidden
+		}
+		query.UserID = &principal.UserID
+	}
+
+	if query.Date != nil {
+		day := reservationDay(*query.Date)
+		query.Date = &day
+	}
+
+	reservations, err := s.model.Reservation.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entities.ReservationResponse, len(reservations))
+	for i := range reservations {
+		resp[i] = toReservationResponse(&reservations[i])
+	}
+
+	return resp, nil
+}
+
+// GetSeatAvailability reports booked and free seats for every slot and
+// location of a day
+func (s *service) GetSeatAvailability(ctx context.Context, date time.Time) ([]*entities.SlotAvailability, error) {
+	day := reservationDay(date)
+
+	occupancy, err := s.model.Reservation.Occupancy(ctx, day)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		slot     enums.RevervationSlot
+		location enums.Location
+	}
+	booked := make(map[key]int, len(occupancy))
+	for _, o := range occupancy {
+		booked[key{o.Slot, o.Location}] = o.Booked
+	}
+
+	var resp []*entities.SlotAvailability
+	for slot := enums.Slot1; slot <= enums.Slot12; slot++ {
+		start := slotStart(day, slot)
+		for _, location := range []enums.Location{enums.Inside, enums.Outside} {
+			capacity := s.seats(location)
+			n := booked[key{slot, location}]
+			resp = append(resp, &entities.SlotAvailability{
+				Slot:      slot,
+				Location:  location,
+				StartsAt:  start,
+				EndsAt:    start.Add(enums.SlotDuration),
+				Capacity:  capacity,
+				Booked:    n,
+				Available: max(capacity-n, 0),
+			})
+		}
+	}
+
+	return resp, nil
+}
+
+// authorizeReservation loads a reservation the caller is allowed to act on:
+// their own, or any reservation for staff
+func (s *service) authorizeReservation(ctx context.Context, id uuid.UUID) (*entities.Reservation, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, entities.ErrUnauthenticated
+	}
+
+	reservation, err := s.model.Reservation.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if reservation.UserID != principal.UserID && !auth.Can(principal, auth.PermReservationsManage) {
+		// Don't reveal other members' reservations
+		return nil, entities.ErrReservationNotFound
+	}
+
+	return reservation, nil
+}
+
+func (s *service) seats(location enums.Location) int {
+	if location == enums.Outside {
+		return s.config.SeatsOutside
+	}
+	return s.config.SeatsInside
+}
+
+// reservationDay is the calendar day of t in the server's time zone, as
+// stored in the date column
+func reservationDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// slotStart is when the slot begins on the given day, in the server's time zone
+func slotStart(day time.Time, slot enums.RevervationSlot) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), slot.StartHour(), 0, 0, 0, time.Local)
+}
+
+func toReservationResponse(reservation *entities.Reservation) *entities.ReservationResponse {
+	start := slotStart(reservation.Date, reservation.Slot)
+	return &entities.ReservationResponse{
+		ID:          reservation.ID,
+		UserID:      reservation.UserID,
+		Date:        reservation.Date.Format("2006-01-02"),
+		Slot:        reservation.Slot,
+		Location:    reservation.Location,
+		Status:      reservation.Status,
+		StartsAt:    start,
+		EndsAt:      start.Add(enums.SlotDuration),
+		CreatedAt:   reservation.CreatedAt,
+		CancelledAt: reservation.CancelledAt,
+	}
+}
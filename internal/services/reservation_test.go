@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"library-system/internal/auth"
+	"library-system/internal/config"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models"
+	reservationMock "library-system/internal/models/reservation/mocks"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_ReserveSeat(t *testing.T) {
+	memberID, _ := uuid.NewV4()
+	otherID, _ := uuid.NewV4()
+	memberCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: memberID, Roles: []enums.Role{enums.RoleMember}})
+	cfg := &config.Config{SeatsInside: 30, SeatsOutside: 10, ReservationsPerDay: 2}
+
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	startsAt := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 15, 30, 0, 0, time.Local)
+	wantDay := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+
+	bookMock := reservationMock.Reservation{}
+	bookMock.On("Book", memberCtx, mock.MatchedBy(func(r *entities.Reservation) bool {
+		return r.UserID == memberID && r.Slot == enums.Slot8 && r.Date.Equal(wantDay) && r.Location == enums.Outside
+	}), entities.ReservationLimits{Capacity: 10, DailyLimit: 2}).Return(nil)
+
+	fullMock := reservationMock.Reservation{}
+	fullMock.On("Book", memberCtx, mock.Anything, mock.Anything).Return(entities.ErrSlotFull)
+
+	tests := []struct {
+		name    string
+		s       *service
+		req     *entities.ReservationRequest
+		wantErr error
+	}{
+		{
+			name: "future slot",
+			s:    &service{model: models.Model{Reservation: &bookMock}, config: cfg},
+			req:  &entities.ReservationRequest{StartsAt: startsAt, Location: enums.Outside},
+		},
+		{
+			name:    "slot full",
+			s:       &service{model: models.Model{Reservation: &fullMock}, config: cfg},
+			req:     &entities.ReservationRequest{StartsAt: startsAt, Location: enums.Inside},
+			wantErr: entities.ErrSlotFull,
+		},
+		{
+			name:    "slot already over",
+			s:       &service{model: models.Model{Reservation: &reservationMock.Reservation{}}, config: cfg},
+			req:     &entities.ReservationRequest{StartsAt: time.Now().AddDate(0, 0, -1), Location: enums.Inside},
+			wantErr: entities.ErrInvalidReservation,
+		},
+		{
+			name:    "unknown location",
+			s:       &service{model: models.Model{Reservation: &reservationMock.Reservation{}}, config: cfg},
+			req:     &entities.ReservationRequest{StartsAt: startsAt, Location: "rooftop"},
+			wantErr: entities.ErrInvalidReservation,
+		},
+		{
+			name:    "member cannot book for someone else",
+			s:       &service{model: models.Model{Reservation: &reservationMock.Reservation{}}, config: cfg},
+			req:     &entities.ReservationRequest{StartsAt: startsAt, Location: enums.Inside, UserID: &otherID},
+			wantErr: entities.ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.ReserveSeat(memberCtx, tt.req)
+			if err != tt.wantErr {
+				t.Errorf("ReserveSeat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.StartsAt.Hour() != 14 || got.EndsAt.Hour() != 16) {
+				t.Errorf("ReserveSeat() = %+v", got)
+			}
+			tt.s.model.Reservation.(*reservationMock.Reservation).AssertExpectations(t)
+		})
+	}
+}
+
+func Test_service_CancelReservation(t *testing.T) {
+	id, _ := uuid.NewV4()
+	memberID, _ := uuid.NewV4()
+	memberCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: memberID, Roles: []enums.Role{enums.RoleMember}})
+
+	started := &entities.Reservation{ID: id, UserID: memberID, Date: reservationDay(time.Now().AddDate(0, 0, -1)), Slot: enums.Slot1, Status: enums.Booked}
+	upcoming := &entities.Reservation{ID: id, UserID: memberID, Date: reservationDay(time.Now().AddDate(0, 0, 2)), Slot: enums.Slot5, Status: enums.Booked}
+	cancelled := *upcoming
+	cancelled.Status = enums.Cancelled
+
+	upcomingMock := reservationMock.Reservation{}
+	upcomingMock.On("GetByID", memberCtx, id).Return(upcoming, nil)
+	upcomingMock.On("Cancel", memberCtx, id, mock.Anything).Return(&cancelled, nil)
+
+	startedMock := reservationMock.Reservation{}
+	startedMock.On("GetByID", memberCtx, id).Return(started, nil)
+
+	tests := []struct {
+		name    string
+		s       *service
+		wantErr error
+	}{
+		{name: "upcoming", s: &service{model: models.Model{Reservation: &upcomingMock}}},
+		{name: "already started", s: &service{model: models.Model{Reservation: &startedMock}}, wantErr: entities.ErrReservationStarted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.CancelReservation(memberCtx, id)
+			if err != tt.wantErr {
+				t.Errorf("CancelReservation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Status != enums.Cancelled {
+				t.Errorf("CancelReservation() = %+v", got)
+			}
+			tt.s.model.Reservation.(*reservationMock.Reservation).AssertExpectations(t)
+		})
+	}
+}
+
+func Test_service_GetSeatAvailability(t *testing.T) {
+	date := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.Local)
+	day := reservationDay(date)
+
+	occupancyMock := reservationMock.Reservation{}
+	occupancyMock.On("Occupancy", context.Background(), day).Return([]entities.SlotOccupancy{
+		{Slot: enums.Slot5, Location: enums.Inside, Booked: 3},
+		{Slot: enums.Slot5, Location: enums.Outside, Booked: 12},
+	}, nil)
+
+	s := &service{model: models.Model{Reservation: &occupancyMock}, config: &config.Config{SeatsInside: 30, SeatsOutside: 10}}
+	got, err := s.GetSeatAvailability(context.Background(), date)
+	if err != nil {
+		t.Fatalf("GetSeatAvailability() error = %v", err)
+	}
+
+	if len(got) != 24 {
+		t.Fatalf("GetSeatAvailability() returned %d slots, want 24", len(got))
+	}
+	// Slot5 is the fifth slot, inside listed before outside
+	inside, outside := got[8], got[9]
+	if inside.Slot != enums.Slot5 || inside.Available != 27 || inside.StartsAt.Hour() != 8 {
+		t.Errorf("inside = %+v", inside)
+	}
+	if outside.Location != enums.Outside || outside.Available != 0 {
+		t.Errorf("outside = %+v", outside)
+	}
+	if got[0].Available != 30 {
+		t.Errorf("empty slot = %+v", got[0])
+	}
+}
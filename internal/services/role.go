@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+
+	"library-system/internal/auth"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// GetUserRoles returns a user together with the roles they hold
+func (s *service) GetUserRoles(ctx context.Context, userID uuid.UUID) (*entities.UserResponse, error) {
+	user, err := s.model.User.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserResponse(user), nil
+}
+
+// CreateAdmin registers an account that also holds the admin role. It
+// bootstraps administration from the command line, where there is no
+// authenticated caller, so the grant is audited as made by the new admin.
+// The account, its roles and the audit are created together, so a failure
+// never leaves an account without the role.
+func (s *service) CreateAdmin(ctx context.Context, req *entities.RegisterRequest) (*entities.UserResponse, error) {
+	return s.createAccount(ctx, req, enums.RoleAdmin)
+}
+
+// GrantRole gives a user a role on behalf of the authenticated admin
+func (s *service) GrantRole(ctx context.Context, userID uuid.UUID, role enums.Role) error {
+	actor, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return entities.ErrUnauthenticated
+	}
+	if !role.IsValid() {
+		return entities.ErrInvalidRole
+	}
+
+	return s.model.User.GrantRole(ctx, userID, role, actor.UserID)
+}
+
+// RevokeRole takes a role from a user on behalf of the authenticated admin.
+// The last remaining admin cannot be demoted, so the system never locks
+// itself out of role management.
+func (s *service) RevokeRole(ctx context.Context, userID uuid.UUID, role enums.Role) error {
+	actor, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return entities.ErrUnauthenticated
+	}
+	if !role.IsValid() {
+		return entities.ErrInvalidRole
+	}
+
+	return s.model.User.RevokeRole(ctx, userID, role, actor.UserID)
+}
+
+// ListRoleAudit returns the role change history, optionally for one user
+func (s *service) ListRoleAudit(ctx context.Context, userID *uuid.UUID) ([]entities.RoleAudit, error) {
+	return s.model.User.ListRoleAudit(ctx, userID)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"library-system/internal/auth"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models"
+	userMock "library-system/internal/models/user/mocks"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_GrantRole(t *testing.T) {
+	userID, _ := uuid.NewV4()
+	adminID, _ := uuid.NewV4()
+	adminCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: adminID, Roles: []enums.Role{enums.RoleAdmin}})
+
+	successMock := userMock.User{}
+	successMock.On("GrantRole", adminCtx, userID, enums.RoleLibrarian, adminID).Return(nil)
+
+	tests := []struct {
+		name    string
+		s       *service
+		ctx     context.Context
+		role    enums.Role
+		wantErr error
+	}{
+		{
+			name: "granted by admin",
+			s:    &service{model: models.Model{User: &successMock}},
+			ctx:  adminCtx,
+			role: enums.RoleLibrarian,
+		},
+		{
+			name:    "unknown role",
+			s:       &service{model: models.Model{User: &userMock.User{}}},
+			ctx:     adminCtx,
+			role:    "superuser",
+			wantErr: entities.ErrInvalidRole,
+		},
+		{
+			name:    "no actor",
+			s:       &service{model: models.Model{User: &userMock.User{}}},
+			ctx:     context.Background(),
+			role:    enums.RoleLibrarian,
+			wantErr: entities.ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.s.GrantRole(tt.ctx, userID, tt.role); err != tt.wantErr {
+				t.Errorf("GrantRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			tt.s.model.User.(*userMock.User).AssertExpectations(t)
+		})
+	}
+}
+
+func Test_service_RevokeRole(t *testing.T) {
+	userID, _ := uuid.NewV4()
+	adminID, _ := uuid.NewV4()
+	adminCtx := auth.WithPrincipal(context.Background(), &entities.Principal{UserID: adminID, Roles: []enums.Role{enums.RoleAdmin}})
+
+	librarianMock := userMock.User{}
+	librarianMock.On("RevokeRole", adminCtx, userID, enums.RoleLibrarian, adminID).Return(nil)
+
+	lastAdminMock := userMock.User{}
+	lastAdminMock.On("RevokeRole", adminCtx, userID, enums.RoleAdmin, adminID).Return(entities.ErrLastAdmin)
+
+	tests := []struct {
+		name    string
+		s       *service
+		role    enums.Role
+		wantErr error
+	}{
+		{
+			name: "revoke librarian",
+			s:    &service{model: models.Model{User: &librarianMock}},
+			role: enums.RoleLibrarian,
+		},
+		{
+			name:    "revoke last admin",
+			s:       &service{model: models.Model{User: &lastAdminMock}},
+			role:    enums.RoleAdmin,
+			wantErr: entities.ErrLastAdmin,
+		},
+		{
+			name:    "unknown role",
+			s:       &service{model: models.Model{User: &userMock.User{}}},
+			role:    enums.Role("owner"),
+			wantErr: entities.ErrInvalidRole,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.s.RevokeRole(adminCtx, userID, tt.role); err != tt.wantErr {
+				t.Errorf("RevokeRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			tt.s.model.User.(*userMock.User).AssertExpectations(t)
+		})
+	}
+}
+
+func Test_service_CreateAdmin(t *testing.T) {
+	ctx := context.Background()
+	req := &entities.RegisterRequest{Name: "Ada", Email: " Ada@Example.com ", Password: "correct horse"}
+
+	successMock := userMock.User{}
+	successMock.On("Create", ctx, mock.MatchedBy(func(u *entities.User) bool {
+		return u.Email == "ada@example.com" && len(u.Roles) == 2 &&
+			u.Roles[0].Role == enums.RoleMember && u.Roles[1].Role == enums.RoleAdmin
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entities.User).ID = uuid.Must(uuid.FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8"))
+	}).Return(nil).Once()
+
+	takenMock := userMock.User{}
+	takenMock.On("Create", ctx, mock.Anything).Return(entities.ErrEmailTaken)
+
+	tests := []struct {
+		name    string
+		s       *service
+		wantErr error
+	}{
+		{
+			name: "new admin",
+			s:    &service{model: models.Model{User: &successMock}},
+		},
+		{
+			name:    "email taken",
+			s:       &service{model: models.Model{User: &takenMock}},
+			wantErr: entities.ErrEmailTaken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.CreateAdmin(ctx, req)
+			if err != tt.wantErr {
+				t.Errorf("CreateAdmin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (len(got.Roles) != 2 || got.Roles[1] != enums.RoleAdmin) {
+				t.Errorf("CreateAdmin() roles = %v", got.Roles)
+			}
+			tt.s.model.User.(*userMock.User).AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"library-system/internal/entities"
+
+	"github.com/gofrs/uuid"
+)
+
+// CreateSeries adds a series
+func (s *service) CreateSeries(ctx context.Context, req *entities.SeriesRequest) (*entities.SeriesResponse, error) {
+	series := &entities.Series{}
+	if err := applySeriesRequest(series, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.model.Series.Create(ctx, series); err != nil {
+		return nil, err
+	}
+
+	return toSeriesResponse(series), nil
+}
+
+// GetSeries retrieves a series with its works in volume order
+func (s *service) GetSeries(ctx context.Context, id uuid.UUID) (*entities.SeriesResponse, error) {
+	series, err := s.model.Series.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	works, err := s.model.Work.InSeries(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := toSeriesResponse(series)
+	for i := range works {
+		resp.Works = append(resp.Works, toWorkSummary(&works[i]))
+	}
+	return resp, nil
+}
+
+// ListSeries lists the series, optionally matching a name
+func (s *service) ListSeries(ctx context.Context, name string) ([]*entities.SeriesResponse, error) {
+	series, err := s.model.Series.List(ctx, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entities.SeriesResponse, len(series))
+	for i := range series {
+		resp[i] = toSeriesResponse(&series[i])
+	}
+
+	return resp, nil
+}
+
+// UpdateSeries renames a series or changes its description
+func (s *service) UpdateSeries(ctx context.Context, id uuid.UUID, req *entities.SeriesRequest) (*entities.SeriesResponse, error) {
+	series, err := s.model.Series.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applySeriesRequest(series, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.model.Series.Update(ctx, series); err != nil {
+		return nil, err
+	}
+
+	return s.GetSeries(ctx, id)
+}
+
+// DeleteSeries removes a series, leaving its works outside any series
+func (s *service) DeleteSeries(ctx context.Context, id uuid.UUID) error {
+	return s.model.Series.Delete(ctx, id)
+}
+
+// applySeriesRequest copies the request onto the series
+func applySeriesRequest(series *entities.Series, req *entities.SeriesRequest) error {
+	series.Name = strings.Join(strings.Fields(req.Name), " ")
+	if series.Name == "" {
+		return fmt.Errorf("%w: name is empty", entities.ErrInvalidSeries)
+	}
+	series.Description = strings.TrimSpace(req.Description)
+
+	return nil
+}
+
+func toSeriesResponse(series *entities.Series) *entities.SeriesResponse {
+	return &entities.SeriesResponse{
+		ID:          series.ID,
+		Name:        series.Name,
+		Description: series.Description,
+		CreatedAt:   series.CreatedAt,
+		UpdatedAt:   series.UpdatedAt,
+	}
+}
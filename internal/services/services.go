@@ -2,31 +2,156 @@ package services
 
 import (
 	"context"
+	"io"
+	"time"
 
+	"library-system/internal/auth"
+	"library-system/internal/config"
 	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
 	"library-system/internal/models"
+	"library-system/internal/storage"
+	"library-system/internal/validation"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/gofrs/uuid"
 )
 
 // Service represents the service layer having
 // all the services from all service packages
 type service struct {
-	model models.Model
+	model    models.Model
+	config   *config.Config
+	tokens   *auth.TokenManager
+	validate *validator.Validate
+	// store keeps cover images
+	store storage.Store
 }
 
 // New creates a new instance of Service
-func New(model *models.Model) Service {
-	m := &service{model: *model}
+func New(model *models.Model, cfg *config.Config, store storage.Store) Service {
+	m := &service{
+		model:    *model,
+		config:   cfg,
+		tokens:   auth.NewTokenManager(cfg.JWTSecret, cfg.AccessTokenTTL, cfg.RefreshTokenTTL),
+		validate: validation.New(),
+		store:    store,
+	}
 	return m
 }
 
 type Service interface {
 
 	// Book services
-	CreateBook(ctx context.Context, req *entities.BookRequest) error
+	CreateBook(ctx context.Context, req *entities.BookRequest) (*entities.BookResponse, error)
 	GetBookByID(ctx context.Context, id uuid.UUID) (*entities.BookResponse, error)
-	GetAllBooks(ctx context.Context) (resp []*entities.BookResponse, err error)
-	UpdateBook(ctx context.Context, id uuid.UUID, req *entities.BookRequest) error
-	DeleteBook(ctx context.Context, id uuid.UUID) error
+	GetBookByISBN(ctx context.Context, isbn string) (*entities.BookResponse, error)
+	GetAllBooks(ctx context.Context, query *entities.BookQuery) (*entities.BookPage, error)
+	SearchBooks(ctx context.Context, query *entities.BookSearchQuery) (*entities.BookSearchPage, error)
+	UpdateBook(ctx context.Context, id uuid.UUID, version int64, req *entities.BookRequest) (*entities.BookResponse, error)
+	PatchBook(ctx context.Context, id uuid.UUID, version int64, patch *entities.BookPatch) (*entities.BookResponse, error)
+	DeleteBook(ctx context.Context, id uuid.UUID, version int64) error
+	ListTrash(ctx context.Context) ([]*entities.BookResponse, error)
+	RestoreBook(ctx context.Context, id uuid.UUID) (*entities.BookResponse, error)
+	PurgeBook(ctx context.Context, id uuid.UUID) error
+	PurgeTrash(ctx context.Context) error
+	ImportBooks(ctx context.Context, req *entities.ImportRequest) (*entities.ImportJob, error)
+	GetImportJob(ctx context.Context, id uuid.UUID, outcome enums.ImportOutcome) (*entities.ImportJob, error)
+	FailStaleImports(ctx context.Context) error
+	ExportBooks(ctx context.Context, format string, query *entities.BookQuery, w io.Writer) (int, error)
+	SetCover(ctx context.Context, id uuid.UUID, version int64, r io.Reader) (*entities.BookResponse, error)
+	DeleteCover(ctx context.Context, id uuid.UUID, version int64) error
+	ListBookHistory(ctx context.Context, id uuid.UUID) ([]entities.BookRevision, error)
+	GetBookAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*entities.BookResponse, error)
+	RevertBook(ctx context.Context, id uuid.UUID, version, revision int64) (*entities.BookResponse, error)
+
+	// Author services
+	CreateAuthor(ctx context.Context, req *entities.AuthorRequest) (*entities.AuthorResponse, error)
+	GetAuthor(ctx context.Context, id uuid.UUID) (*entities.AuthorResponse, error)
+	ListAuthors(ctx context.Context, query *entities.AuthorQuery) (*entities.AuthorPage, error)
+	UpdateAuthor(ctx context.Context, id uuid.UUID, req *entities.AuthorRequest) (*entities.AuthorResponse, error)
+	DeleteAuthor(ctx context.Context, id uuid.UUID) error
+	ListAuthorBooks(ctx context.Context, id uuid.UUID, role enums.ContributorRole) ([]*entities.BookResponse, error)
+
+	// Publisher services
+	CreatePublisher(ctx context.Context, req *entities.PublisherRequest) (*entities.PublisherResponse, error)
+	GetPublisher(ctx context.Context, id uuid.UUID) (*entities.PublisherResponse, error)
+	ListPublishers(ctx context.Context, query *entities.PublisherQuery) (*entities.PublisherPage, error)
+	UpdatePublisher(ctx context.Context, id uuid.UUID, req *entities.PublisherRequest) (*entities.PublisherResponse, error)
+	DeletePublisher(ctx context.Context, id uuid.UUID) error
+	ListPublisherBooks(ctx context.Context, id uuid.UUID, imprints bool) ([]*entities.BookResponse, error)
+	MergePublishers(ctx context.Context, id uuid.UUID, req *entities.PublisherMergeRequest) (*entities.PublisherResponse, error)
+
+	// Subject and classification services
+	CreateSubject(ctx context.Context, req *entities.SubjectRequest) (*entities.SubjectNode, error)
+	BrowseSubjects(ctx context.Context, kind enums.SubjectKind) (*entities.SubjectTree, error)
+	GetSubject(ctx context.Context, id uuid.UUID) (*entities.SubjectTree, error)
+	UpdateSubject(ctx context.Context, id uuid.UUID, req *entities.SubjectRequest) (*entities.SubjectNode, error)
+	DeleteSubject(ctx context.Context, id uuid.UUID) error
+	BrowseClasses(ctx context.Context, scheme enums.ClassScheme) ([]*entities.ClassNode, error)
+
+	// Work and series services
+	CreateWork(ctx context.Context, req *entities.WorkRequest) (*entities.WorkResponse, error)
+	GetWork(ctx context.Context, id uuid.UUID) (*entities.WorkResponse, error)
+	ListWorks(ctx context.Context, query *entities.WorkQuery) (*entities.WorkPage, error)
+	UpdateWork(ctx context.Context, id uuid.UUID, req *entities.WorkRequest) (*entities.WorkResponse, error)
+	DeleteWork(ctx context.Context, id uuid.UUID) error
+	ListEditions(ctx context.Context, id uuid.UUID) ([]*entities.BookResponse, error)
+	GetSeriesPosition(ctx context.Context, bookID uuid.UUID) (*entities.SeriesPosition, error)
+	CreateSeries(ctx context.Context, req *entities.SeriesRequest) (*entities.SeriesResponse, error)
+	GetSeries(ctx context.Context, id uuid.UUID) (*entities.SeriesResponse, error)
+	ListSeries(ctx context.Context, name string) ([]*entities.SeriesResponse, error)
+	UpdateSeries(ctx context.Context, id uuid.UUID, req *entities.SeriesRequest) (*entities.SeriesResponse, error)
+	DeleteSeries(ctx context.Context, id uuid.UUID) error
+
+	// Item services
+	ListItems(ctx context.Context, bookID uuid.UUID) ([]entities.Item, error)
+	GetItem(ctx context.Context, bookID, id uuid.UUID) (*entities.Item, error)
+	CreateItem(ctx context.Context, bookID uuid.UUID, req *entities.ItemRequest) (*entities.Item, error)
+	UpdateItem(ctx context.Context, bookID, id uuid.UUID, req *entities.ItemRequest) (*entities.Item, error)
+	DeleteItem(ctx context.Context, bookID, id uuid.UUID) error
+
+	// Auth services
+	Register(ctx context.Context, req *entities.RegisterRequest) (*entities.UserResponse, error)
+	Login(ctx context.Context, req *entities.LoginRequest) (*entities.TokenResponse, error)
+	RefreshToken(ctx context.Context, req *entities.RefreshRequest) (*entities.TokenResponse, error)
+	Authenticate(ctx context.Context, token string) (*entities.Principal, error)
+	GetCurrentUser(ctx context.Context) (*entities.UserResponse, error)
+
+	// Loan services
+	CheckoutBook(ctx context.Context, bookID uuid.UUID, req *entities.CheckoutRequest) (*entities.LoanResponse, error)
+	ReturnLoan(ctx context.Context, id uuid.UUID) (*entities.LoanResponse, error)
+	GetLoan(ctx context.Context, id uuid.UUID) (*entities.LoanResponse, error)
+	ListLoans(ctx context.Context, query *entities.LoanQuery) ([]*entities.LoanResponse, error)
+
+	// Fine services
+	GetMemberBalance(ctx context.Context, memberID uuid.UUID) (*entities.MemberBalance, error)
+	ListMemberTransactions(ctx context.Context, memberID uuid.UUID) ([]entities.LedgerTransaction, error)
+	IsInGoodStanding(ctx context.Context, memberID uuid.UUID) (bool, error)
+	RecordPayment(ctx context.Context, memberID uuid.UUID, req *entities.PaymentRequest) (*entities.LedgerTransaction, error)
+	WaiveFine(ctx context.Context, memberID uuid.UUID, req *entities.WaiverRequest) (*entities.LedgerTransaction, error)
+	ChargeMember(ctx context.Context, memberID uuid.UUID, req *entities.ChargeRequest) (*entities.LedgerTransaction, error)
+
+	// Hold services
+	PlaceHold(ctx context.Context, bookID uuid.UUID, req *entities.HoldRequest) (*entities.HoldResponse, error)
+	CancelHold(ctx context.Context, id uuid.UUID) (*entities.HoldResponse, error)
+	GetHold(ctx context.Context, id uuid.UUID) (*entities.HoldResponse, error)
+	ListHolds(ctx context.Context, query *entities.HoldQuery) ([]*entities.HoldResponse, error)
+	GetHoldQueue(ctx context.Context, bookID uuid.UUID) ([]*entities.HoldResponse, error)
+	SetHoldPriority(ctx context.Context, id uuid.UUID, req *entities.HoldPriorityRequest) (*entities.HoldResponse, error)
+	ExpireHolds(ctx context.Context) error
+
+	// Reservation services
+	ReserveSeat(ctx context.Context, req *entities.ReservationRequest) (*entities.ReservationResponse, error)
+	CancelReservation(ctx context.Context, id uuid.UUID) (*entities.ReservationResponse, error)
+	GetReservation(ctx context.Context, id uuid.UUID) (*entities.ReservationResponse, error)
+	ListReservations(ctx context.Context, query *entities.ReservationQuery) ([]*entities.ReservationResponse, error)
+	GetSeatAvailability(ctx context.Context, date time.Time) ([]*entities.SlotAvailability, error)
+
+	// Role services
+	GetUserRoles(ctx context.Context, userID uuid.UUID) (*entities.UserResponse, error)
+	CreateAdmin(ctx context.Context, req *entities.RegisterRequest) (*entities.UserResponse, error)
+	GrantRole(ctx context.Context, userID uuid.UUID, role enums.Role) error
+	RevokeRole(ctx context.Context, userID uuid.UUID, role enums.Role) error
+	ListRoleAudit(ctx context.Context, userID *uuid.UUID) ([]entities.RoleAudit, error)
 }
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"library-system/internal/classification"
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+
+	"github.com/gofrs/uuid"
+)
+
+// CreateSubject adds a subject, under its parent if it has one
+func (s *service) CreateSubject(ctx context.Context, req *entities.SubjectRequest) (*entities.SubjectNode, error) {
+	subject := &entities.Subject{}
+	if err := applySubjectRequest(subject, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.model.Subject.Create(ctx, subject); err != nil {
+		return nil, err
+	}
+
+	return toSubjectNode(subject), nil
+}
+
+// BrowseSubjects returns the subject tree with book counts, optionally of
+// one kind only. A subject whose parent is of another kind is listed at
+// the top.
+func (s *service) BrowseSubjects(ctx context.Context, kind enums.SubjectKind) (*entities.SubjectTree, error) {
+	if kind != "" && !kind.IsValid() {
+		return nil, fmt.Errorf("%w: unknown kind %q", entities.ErrInvalidSubject, kind)
+	}
+
+	subjects, err := s.model.Subject.Tree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind != "" {
+		var kept []entities.Subject
+		for _, subject := range subjects {
+			if subject.Kind == kind {
+				kept = append(kept, subject)
+			}
+		}
+		subjects = kept
+	}
+
+	return &entities.SubjectTree{Subjects: subjectTree(subjects, nil)}, nil
+}
+
+// GetSubject returns a subject with the tree below it and the path of
+// subjects above it
+func (s *service) GetSubject(ctx context.Context, id uuid.UUID) (*entities.SubjectTree, error) {
+	subjects, err := s.model.Subject.Tree(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*entities.Subject, len(subjects))
+	for i := range subjects {
+		byID[subjects[i].ID] = &subjects[i]
+	}
+	subject, ok := byID[id]
+	if !ok {
+		return nil, entities.ErrSubjectNotFound
+	}
+
+	tree := &entities.SubjectTree{Subjects: []*entities.SubjectNode{toSubjectNode(subject)}}
+	tree.Subjects[0].Children = subjectTree(subjects, &id)
+	for p := subject.ParentID; p != nil && byID[*p] != nil; p = byID[*p].ParentID {
+		tree.Path = append([]entities.SubjectSummary{toSubjectSummary(byID[*p])}, tree.Path...)
+	}
+
+	return tree, nil
+}
+
+// UpdateSubject renames a subject or moves it under another parent
+func (s *service) UpdateSubject(ctx context.Context, id uuid.UUID, req *entities.SubjectRequest) (*entities.SubjectNode, error) {
+	subject, err := s.model.Subject.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applySubjectRequest(subject, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.model.Subject.Update(ctx, subject); err != nil {
+		return nil, err
+	}
+
+	return toSubjectNode(subject), nil
+}
+
+// DeleteSubject removes a subject no book is filed under and with no
+// narrower subjects
+func (s *service) DeleteSubject(ctx context.Context, id uuid.UUID) error {
+	return s.model.Subject.Delete(ctx, id)
+}
+
+// BrowseClasses returns the outline of a classification scheme with the
+// number of books filed under each class, leaving out empty classes
+func (s *service) BrowseClasses(ctx context.Context, scheme enums.ClassScheme) ([]*entities.ClassNode, error) {
+	if !scheme.IsValid() {
+		return nil, fmt.Errorf("%w: unknown classification scheme %q", entities.ErrInvalidBookQuery, scheme)
+	}
+
+	counts, err := s.model.Book.Classes(ctx, scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []*entities.ClassNode
+	nodes := make(map[string]*entities.ClassNode)
+	for _, count := range counts {
+		siblings := &roots
+		for _, class := range classification.Path(scheme, count.Class) {
+			node, ok := nodes[class]
+			if !ok {
+				node = &entities.ClassNode{Class: class, Label: classification.Label(scheme, class)}
+				nodes[class] = node
+				*siblings = append(*siblings, node)
+			}
+			node.BookCount += count.Count
+			siblings = &node.Children
+		}
+	}
+
+	return roots, nil
+}
+
+// requestedSubjects files a book under the subjects of a request, which
+// must exist
+func (s *service) requestedSubjects(ctx context.Context, ids []uuid.UUID) ([]entities.BookSubject, error) {
+	var filed []entities.BookSubject
+	for _, id := range ids {
+		if _, err := s.model.Subject.GetByID(ctx, id); err != nil {
+			if errors.Is(err, entities.ErrSubjectNotFound) {
+				return nil, fmt.Errorf("%w: %v", entities.ErrInvalidSubject, err)
+			}
+			return nil, err
+		}
+		filed = append(filed, entities.BookSubject{SubjectID: id})
+	}
+	return filed, nil
+}
+
+// applySubjectRequest copies the request onto the subject
+func applySubjectRequest(subject *entities.Subject, req *entities.SubjectRequest) error {
+	subject.Name = strings.Join(strings.Fields(req.Name), " ")
+	if subject.Name == "" {
+		return fmt.Errorf("%w: name is empty", entities.ErrInvalidSubject)
+	}
+	if req.ParentID != nil && *req.ParentID == subject.ID {
+		return fmt.Errorf("%w: a subject cannot be filed under itself", entities.ErrInvalidSubject)
+	}
+
+	subject.Kind = req.Kind
+	if subject.Kind == "" {
+		subject.Kind = enums.SubjectTopic
+	}
+	subject.ParentID = req.ParentID
+	subject.Parent = nil
+
+	return nil
+}
+
+// subjectTree builds the nodes below a parent, or the top of the tree for
+// a nil parent. Subjects whose parent is not in the list count as top
+// level. The order of the list is kept among siblings.
+func subjectTree(subjects []entities.Subject, parent *uuid.UUID) []*entities.SubjectNode {
+	present := make(map[uuid.UUID]bool, len(subjects))
+	for _, subject := range subjects {
+		present[subject.ID] = true
+	}
+
+	children := make(map[uuid.UUID][]*entities.SubjectNode)
+	var top []*entities.SubjectNode
+	nodes := make([]*entities.SubjectNode, len(subjects))
+	for i := range subjects {
+		nodes[i] = toSubjectNode(&subjects[i])
+		if p := subjects[i].ParentID; p != nil && present[*p] {
+			children[*p] = append(children[*p], nodes[i])
+		} else {
+			top = append(top, nodes[i])
+		}
+	}
+	for _, node := range nodes {
+		node.Children = children[node.ID]
+	}
+
+	if parent != nil {
+		return children[*parent]
+	}
+	return top
+}
+
+func toSubjectNode(subject *entities.Subject) *entities.SubjectNode {
+	return &entities.SubjectNode{
+		ID:         subject.ID,
+		Name:       subject.Name,
+		Kind:       subject.Kind,
+		ParentID:   subject.ParentID,
+		BookCount:  subject.BookCount,
+		TotalCount: subject.TotalCount,
+	}
+}
+
+func toSubjectSummary(subject *entities.Subject) entities.SubjectSummary {
+	return entities.SubjectSummary{ID: subject.ID, Name: subject.Name, Kind: subject.Kind}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/models"
+	bookMock "library-system/internal/models/book/mocks"
+	subjectMock "library-system/internal/models/subject/mocks"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_GetSubject(t *testing.T) {
+	fiction, _ := uuid.NewV4()
+	sf, _ := uuid.NewV4()
+	dystopias, _ := uuid.NewV4()
+	history, _ := uuid.NewV4()
+
+	subjects := subjectMock.Subject{}
+	subjects.On("Tree", mock.Anything).Return([]entities.Subject{
+		{ID: dystopias, Name: "Dystopias", Kind: enums.SubjectGenre, ParentID: &sf, BookCount: 2, TotalCount: 2},
+		{ID: fiction, Name: "Fiction", Kind: enums.SubjectGenre, BookCount: 1, TotalCount: 4},
+		{ID: history, Name: "History", Kind: enums.SubjectTopic},
+		{ID: sf, Name: "Science fiction", Kind: enums.SubjectGenre, ParentID: &fiction, BookCount: 1, TotalCount: 3},
+	}, nil)
+
+	s := &service{model: models.Model{Subject: &subjects}}
+
+	got, err := s.GetSubject(context.Background(), sf)
+	if err != nil {
+		t.Fatalf("GetSubject() error = %v", err)
+	}
+	if len(got.Path) != 1 || got.Path[0].ID != fiction {
+		t.Errorf("GetSubject() path = %+v", got.Path)
+	}
+	if len(got.Subjects) != 1 || got.Subjects[0].TotalCount != 3 ||
+		len(got.Subjects[0].Children) != 1 || got.Subjects[0].Children[0].ID != dystopias {
+		t.Errorf("GetSubject() subjects = %+v", got.Subjects)
+	}
+
+	if _, err := s.GetSubject(context.Background(), uuid.Must(uuid.NewV4())); !errors.Is(err, entities.ErrSubjectNotFound) {
+		t.Errorf("GetSubject() of unknown subject error = %v, want %v", err, entities.ErrSubjectNotFound)
+	}
+
+	tree, err := s.BrowseSubjects(context.Background(), enums.SubjectTopic)
+	if err != nil {
+		t.Fatalf("BrowseSubjects() error = %v", err)
+	}
+	if len(tree.Subjects) != 1 || tree.Subjects[0].ID != history {
+		t.Errorf("BrowseSubjects() = %+v", tree.Subjects)
+	}
+}
+
+func Test_service_BrowseClasses(t *testing.T) {
+	books := bookMock.Book{}
+	books.On("Classes", mock.Anything, enums.SchemeDewey).Return([]entities.ClassCount{
+		{Class: "811", Count: 1},
+		{Class: "813", Count: 4},
+		{Class: "823", Count: 2},
+		{Class: "973", Count: 1},
+	}, nil)
+
+	s := &service{model: models.Model{Book: &books}}
+
+	got, err := s.BrowseClasses(context.Background(), enums.SchemeDewey)
+	if err != nil {
+		t.Fatalf("BrowseClasses() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Class != "800" || got[0].Label != "Literature" || got[0].BookCount != 7 {
+		t.Fatalf("BrowseClasses() = %+v", got)
+	}
+	if american := got[0].Children[0]; american.Class != "810" || american.BookCount != 5 || len(american.Children) != 2 {
+		t.Errorf("BrowseClasses() 810 = %+v", american)
+	}
+
+	if _, err := s.BrowseClasses(context.Background(), "udc"); !errors.Is(err, entities.ErrInvalidBookQuery) {
+		t.Errorf("BrowseClasses() with unknown scheme error = %v, want %v", err, entities.ErrInvalidBookQuery)
+	}
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"library-system/internal/entities"
+	"library-system/internal/entities/enums"
+	"library-system/internal/names"
+
+	"github.com/gofrs/uuid"
+)
+
+// CreateWork adds a work, grouping the books of the request under it
+func (s *service) CreateWork(ctx context.Context, req *entities.WorkRequest) (*entities.WorkResponse, error) {
+	work := &entities.Work{}
+	if err := applyWorkRequest(work, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.model.Work.Create(ctx, work, req.BookIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetWork(ctx, work.ID)
+}
+
+// GetWork retrieves a work with its series and number of editions
+func (s *service) GetWork(ctx context.Context, id uuid.UUID) (*entities.WorkResponse, error) {
+	work, err := s.model.Work.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toWorkResponse(work), nil
+}
+
+// ListWorks retrieves one page of works, optionally matching a title and
+// an author
+func (s *service) ListWorks(ctx context.Context, query *entities.WorkQuery) (*entities.WorkPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = entities.DefaultPageSize
+	}
+	if query.Limit > entities.MaxPageSize {
+		query.Limit = entities.MaxPageSize
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	query.Title = strings.TrimSpace(query.Title)
+	query.Author = strings.TrimSpace(query.Author)
+
+	works, total, err := s.model.Work.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entities.WorkResponse, len(works))
+	for i := range works {
+		resp[i] = toWorkResponse(&works[i])
+	}
+
+	return &entities.WorkPage{
+		Data:  resp,
+		Total: total,
+		Page:  query.Page,
+		Limit: query.Limit,
+	}, nil
+}
+
+// UpdateWork changes a work's title, author and place in a series. The
+// books of the request join the editions it has.
+func (s *service) UpdateWork(ctx context.Context, id uuid.UUID, req *entities.WorkRequest) (*entities.WorkResponse, error) {
+	work, err := s.model.Work.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyWorkRequest(work, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.model.Work.Update(ctx, work, req.BookIDs); err != nil {
+		return nil, err
+	}
+
+	return s.GetWork(ctx, id)
+}
+
+// DeleteWork removes a work, leaving its editions ungrouped
+func (s *service) DeleteWork(ctx context.Context, id uuid.UUID) error {
+	return s.model.Work.Delete(ctx, id)
+}
+
+// ListEditions lists the books of a work, oldest first
+func (s *service) ListEditions(ctx context.Context, id uuid.UUID) ([]*entities.BookResponse, error) {
+	if _, err := s.model.Work.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	books, err := s.model.Work.Editions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]*entities.BookResponse, len(books))
+	for i := range books {
+		resp[i] = s.toBookResponse(&books[i])
+	}
+
+	return resp, nil
+}
+
+// GetSeriesPosition finds the works before and after a book's work in its
+// series
+func (s *service) GetSeriesPosition(ctx context.Context, bookID uuid.UUID) (*entities.SeriesPosition, error) {
+	book, err := s.model.Book.GetByID(ctx, bookID)
+	if err != nil {
+		return nil, err
+	}
+	if book.WorkID == nil {
+		return nil, entities.ErrNotInSeries
+	}
+
+	work, err := s.model.Work.GetByID(ctx, *book.WorkID)
+	if err != nil {
+		return nil, err
+	}
+	if work.SeriesID == nil || work.Series == nil {
+		return nil, entities.ErrNotInSeries
+	}
+
+	works, err := s.model.Work.InSeries(ctx, *work.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	position := &entities.SeriesPosition{
+		Series: entities.SeriesSummary{ID: work.Series.ID, Name: work.Series.Name},
+		Work:   toWorkSummary(work),
+	}
+	i := slices.IndexFunc(works, func(w entities.Work) bool { return w.ID == work.ID })
+	if i > 0 {
+		previous := toWorkSummary(&works[i-1])
+		position.Previous = &previous
+	}
+	if i >= 0 && i < len(works)-1 {
+		next := toWorkSummary(&works[i+1])
+		position.Next = &next
+	}
+
+	return position, nil
+}
+
+// bookWork checks the work a book request groups the book under
+func (s *service) bookWork(ctx context.Context, id uuid.UUID) (*uuid.UUID, error) {
+	if _, err := s.model.Work.GetByID(ctx, id); err != nil {
+		if errors.Is(err, entities.ErrWorkNotFound) {
+			return nil, fmt.Errorf("%w: %v", entities.ErrInvalidWork, err)
+		}
+		return nil, err
+	}
+	return &id, nil
+}
+
+// workSuggestions looks for editions of a new book: works with the same
+// title and first author, and books of its authors with the same title
+// that are not grouped yet. Lookups that fail are logged and skipped, as
+// the book is stored by then.
+func (s *service) workSuggestions(ctx context.Context, book *entities.Book) []entities.WorkSuggestion {
+	titleKey := names.TitleKey(book.Title)
+	if titleKey == "" {
+		return nil
+	}
+
+	var suggestions []entities.WorkSuggestion
+	works, err := s.model.Work.Matching(ctx, titleKey, authorKey(book.Author))
+	if err != nil {
+		log.Printf("suggest works for book %s: %v", book.ID, err)
+	}
+	for i := range works {
+		summary := toWorkSummary(&works[i])
+		suggestions = append(suggestions, entities.WorkSuggestion{Work: &summary})
+	}
+
+	grouped := []uuid.UUID{book.ID}
+	for _, c := range book.Contributors {
+		if c.Role != enums.ContributorAuthor {
+			continue
+		}
+		books, err := s.model.Author.Books(ctx, c.AuthorID, enums.ContributorAuthor)
+		if err != nil {
+			log.Printf("suggest works for book %s: %v", book.ID, err)
+			continue
+		}
+		for _, b := range books {
+			if b.WorkID == nil && !slices.Contains(grouped, b.ID) && names.TitleKey(b.Title) == titleKey {
+				grouped = append(grouped, b.ID)
+			}
+		}
+	}
+	if len(grouped) > 1 {
+		suggestions = append(suggestions, entities.WorkSuggestion{BookIDs: grouped})
+	}
+
+	return suggestions
+}
+
+// applyWorkRequest copies the request onto the work, filling in the
+// matching keys
+func applyWorkRequest(work *entities.Work, req *entities.WorkRequest) error {
+	work.Title = strings.Join(strings.Fields(req.Title), " ")
+	work.Author = strings.Join(strings.Fields(req.Author), " ")
+	work.TitleKey = names.TitleKey(work.Title)
+	work.AuthorKey = authorKey(work.Author)
+	if work.TitleKey == "" {
+		return fmt.Errorf("%w: title %q", entities.ErrInvalidWork, req.Title)
+	}
+	if req.SeriesVolume != nil && req.SeriesID == nil {
+		return fmt.Errorf("%w: a volume number needs a series", entities.ErrInvalidWork)
+	}
+
+	work.SeriesID = req.SeriesID
+	work.SeriesVolume = req.SeriesVolume
+	work.Series = nil
+
+	return nil
+}
+
+// authorKey is the key of the first name in an author credit, which works
+// are matched by
+func authorKey(credit string) string {
+	credited := names.Split(credit)
+	if len(credited) == 0 {
+		return ""
+	}
+	return names.Key(credited[0])
+}
+
+func toWorkResponse(work *entities.Work) *entities.WorkResponse {
+	resp := &entities.WorkResponse{
+		ID:           work.ID,
+		Title:        work.Title,
+		Author:       work.Author,
+		SeriesVolume: work.SeriesVolume,
+		EditionCount: work.EditionCount,
+		CreatedAt:    work.CreatedAt,
+		UpdatedAt:    work.UpdatedAt,
+	}
+	if work.Series != nil {
+		resp.Series = &entities.SeriesSummary{ID: work.Series.ID, Name: work.Series.Name}
+	}
+	return resp
+}
+
+func toWorkSummary(work *entities.Work) entities.WorkSummary {
+	return entities.WorkSummary{
+		ID:           work.ID,
+		Title:        work.Title,
+		Author:       work.Author,
+		SeriesVolume: work.SeriesVolume,
+	}
+}
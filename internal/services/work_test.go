@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"library-system/internal/entities"
+	"library-system/internal/models"
+	bookMock "library-system/internal/models/book/mocks"
+	workMock "library-system/internal/models/work/mocks"
+
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_service_GetSeriesPosition(t *testing.T) {
+	seriesID, _ := uuid.NewV4()
+	series := &entities.Series{ID: seriesID, Name: "The Lord of the Rings"}
+	one, two, three := 1.0, 2.0, 3.0
+	fellowship := entities.Work{ID: uuid.Must(uuid.NewV4()), Title: "The Fellowship of the Ring", SeriesID: &seriesID, SeriesVolume: &one}
+	towers := entities.Work{ID: uuid.Must(uuid.NewV4()), Title: "The Two Towers", SeriesID: &seriesID, SeriesVolume: &two, Series: series}
+	king := entities.Work{ID: uuid.Must(uuid.NewV4()), Title: "The Return of the King", SeriesID: &seriesID, SeriesVolume: &three}
+	hobbit := entities.Work{ID: uuid.Must(uuid.NewV4()), Title: "The Hobbit"}
+
+	bookID, _ := uuid.NewV4()
+	standaloneID, _ := uuid.NewV4()
+	ungroupedID, _ := uuid.NewV4()
+	books := bookMock.Book{}
+	books.On("GetByID", mock.Anything, bookID).Return(&entities.Book{ID: bookID, WorkID: &towers.ID}, nil)
+	books.On("GetByID", mock.Anything, standaloneID).Return(&entities.Book{ID: standaloneID, WorkID: &hobbit.ID}, nil)
+	books.On("GetByID", mock.Anything, ungroupedID).Return(&entities.Book{ID: ungroupedID}, nil)
+
+	works := workMock.Work{}
+	works.On("GetByID", mock.Anything, towers.ID).Return(&towers, nil)
+	works.On("GetByID", mock.Anything, hobbit.ID).Return(&hobbit, nil)
+	works.On("InSeries", mock.Anything, seriesID).Return([]entities.Work{fellowship, towers, king}, nil)
+
+	s := &service{model: models.Model{Book: &books, Work: &works}}
+
+	got, err := s.GetSeriesPosition(context.Background(), bookID)
+	if err != nil {
+		t.Fatalf("GetSeriesPosition() error = %v", err)
+	}
+	if got.Series.Name != series.Name || got.Work.ID != towers.ID ||
+		got.Previous == nil || got.Previous.ID != fellowship.ID ||
+		got.Next == nil || got.Next.ID != king.ID || *got.Next.SeriesVolume != three {
+		t.Errorf("GetSeriesPosition() = %+v", got)
+	}
+
+	for _, id := range []uuid.UUID{standaloneID, ungroupedID} {
+		if _, err := s.GetSeriesPosition(context.Background(), id); !errors.Is(err, entities.ErrNotInSeries) {
+			t.Errorf("GetSeriesPosition() error = %v, want %v", err, entities.ErrNotInSeries)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Package storage keeps blobs such as cover images under slash-separated
+// keys, on the local filesystem or in an S3-compatible bucket.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+)
+
+// ErrNotFound is returned for a key that holds no blob
+var ErrNotFound = errors.New("blob not found")
+
+// Store keeps blobs under keys such as "covers/<book id>/<hash>.jpg".
+// Putting a key that exists replaces the blob and deleting a missing key
+// succeeds.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// validKey rejects keys that are empty, absolute or climb out of the store
+// with ".." elements
+func validKey(key string) error {
+	if !fs.ValidPath(key) || key == "." || strings.Contains(key, `\`) {
+		return errors.New("invalid blob key " + key)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Package validation builds the request validator shared by the handlers,
+// the services and the command line, and turns its errors into messages
+// for API clients.
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"library-system/internal/classification"
+	"library-system/internal/entities"
+	"library-system/internal/isbn"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// New returns a validator with the custom rules registered. Fields are
+// named by their JSON tag in errors.
+func New() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	if err := v.RegisterValidation("isbn", isbn.ValidateField); err != nil {
+		panic("registering isbn validator: " + err.Error())
+	}
+	if err := v.RegisterValidation("dewey", classification.ValidateDewey); err != nil {
+		panic("registering dewey validator: " + err.Error())
+	}
+	if err := v.RegisterValidation("lcc", classification.ValidateLCC); err != nil {
+		panic("registering lcc validator: " + err.Error())
+	}
+	return v
+}
+
+// Reason describes a validation error in one line, such as
+// "title is required; copies must be at least 0"
+func Reason(err error) string {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err.Error()
+	}
+
+	reasons := make([]string, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		reasons[i] = fe.Field() + " " + describe(fe)
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Fields lists what is wrong with each field of a validation error, with
+// fields named by their JSON path, or nothing for other errors
+func Fields(err error) []entities.FieldError {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return nil
+	}
+
+	fields := make([]entities.FieldError, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		// The namespace starts with the struct's Go name
+		_, path, _ := strings.Cut(fe.Namespace(), ".")
+		fields[i] = entities.FieldError{Field: path, Code: fe.Tag(), Detail: describe(fe)}
+	}
+	return fields
+}
+
+func describe(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "email":
+		return "is not a valid email address"
+	case "isbn":
+		return "is not a valid ISBN-10 or ISBN-13"
+	case "dewey":
+		return "is not a Dewey Decimal call number"
+	case "lcc":
+		return "is not a Library of Congress call number"
+	}
+	return fmt.Sprintf("failed the %s rule", fe.Tag())
+}
@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"library-system/internal/entities"
+)
+
+func TestReason(t *testing.T) {
+	v := New()
+
+	tests := []struct {
+		name string
+		req  entities.BookRequest
+		want string
+	}{
+		{
+			name: "missing fields",
+			req:  entities.BookRequest{Author: "A", ISBN: "9780441013593", Publisher: "P", Copies: 1, PublishDate: someDate},
+			want: "title is required",
+		},
+		{
+			name: "bad isbn and item type",
+			req:  entities.BookRequest{Title: "T", Author: "A", ISBN: "123", Publisher: "P", Copies: 1, PublishDate: someDate, ItemType: "scroll"},
+			want: "isbn is not a valid ISBN-10 or ISBN-13; item_type must be one of book, periodical, media, reference",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Struct(tt.req)
+			if err == nil {
+				t.Fatal("Struct() error = nil")
+			}
+			if got := Reason(err); got != tt.want {
+				t.Errorf("Reason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	req := entities.BookRequest{
+		Title: "T", Author: "A", ISBN: "9780441013593", Publisher: "P", Copies: -1, PublishDate: someDate,
+		Contributors: []entities.ContributorRequest{{Role: "author"}, {Name: "B", Role: "muse"}},
+	}
+
+	got := Fields(New().Struct(req))
+	want := map[string]string{
+		"copies":               "min",
+		"contributors[1].role": "oneof",
+	}
+	for field, code := range want {
+		found := false
+		for _, fe := range got {
+			found = found || (fe.Field == field && fe.Code == code && fe.Detail != "")
+		}
+		if !found {
+			t.Errorf("Fields() = %+v, want %s failing %s", got, field, code)
+		}
+	}
+
+	if got := Fields(entities.ErrInvalidBook); got != nil {
+		t.Errorf("Fields() of a plain error = %+v", got)
+	}
+}
+
+var someDate = time.Date(2001, time.February, 3, 0, 0, 0, 0, time.UTC)
@@ -1,6 +1,9 @@
 package rest
 
 import (
+	"net/http"
+
+	"library-system/internal/auth"
 	"library-system/internal/handlers"
 
 	"github.com/gorilla/mux"
@@ -9,13 +12,118 @@ import (
 // NewRouter returns a new router instance with configured routes
 func NewRouter(h *handlers.Handler) *mux.Router {
 	router := mux.NewRouter()
+	router.Use(h.V1.Authenticate)
+
+	// protect wraps a handler with the permission the route requires
+	protect := func(perm auth.Permission, f http.HandlerFunc) http.Handler {
+		return h.V1.Authorize(perm)(f)
+	}
+
+	// Auth endpoints
+	router.HandleFunc("/api/auth/register", h.V1.Register).Methods("POST")
+	router.HandleFunc("/api/auth/login", h.V1.Login).Methods("POST")
+	router.HandleFunc("/api/auth/refresh", h.V1.RefreshToken).Methods("POST")
+	router.HandleFunc("/api/auth/me", h.V1.GetCurrentUser).Methods("GET")
 
 	// Book endpoints
 	router.HandleFunc("/api/books", h.V1.GetAllBooks).Methods("GET")
-	router.HandleFunc("/api/books", h.V1.CreateBook).Methods("POST")
+	router.Handle("/api/books", protect(auth.PermBooksCreate, h.V1.CreateBook)).Methods("POST")
+	router.HandleFunc("/api/books/search", h.V1.SearchBooks).Methods("GET")
+	router.HandleFunc("/api/books/isbn/{isbn}", h.V1.GetBookByISBN).Methods("GET")
+	router.HandleFunc("/api/books/export", h.V1.ExportBooks).Methods("GET")
+	router.Handle("/api/books/import", protect(auth.PermBooksImport, h.V1.ImportBooks)).Methods("POST")
+	router.Handle("/api/books/import/{id}", protect(auth.PermBooksImport, h.V1.GetImportJob)).Methods("GET")
+	router.Handle("/api/books/trash", protect(auth.PermBooksDelete, h.V1.ListTrash)).Methods("GET")
+	router.Handle("/api/books/trash/{id}", protect(auth.PermBooksPurge, h.V1.PurgeBook)).Methods("DELETE")
 	router.HandleFunc("/api/books/{id}", h.V1.GetBookByID).Methods("GET")
-	router.HandleFunc("/api/books/{id}", h.V1.UpdateBook).Methods("PUT")
-	router.HandleFunc("/api/books/{id}", h.V1.DeleteBook).Methods("DELETE")
+	router.Handle("/api/books/{id}", protect(auth.PermBooksUpdate, h.V1.UpdateBook)).Methods("PUT")
+	router.Handle("/api/books/{id}", protect(auth.PermBooksUpdate, h.V1.PatchBook)).Methods("PATCH")
+	router.Handle("/api/books/{id}", protect(auth.PermBooksDelete, h.V1.DeleteBook)).Methods("DELETE")
+	router.Handle("/api/books/{id}/restore", protect(auth.PermBooksDelete, h.V1.RestoreBook)).Methods("POST")
+	router.Handle("/api/books/{id}/cover", protect(auth.PermBooksUpdate, h.V1.SetCover)).Methods("PUT")
+	router.Handle("/api/books/{id}/cover", protect(auth.PermBooksUpdate, h.V1.DeleteCover)).Methods("DELETE")
+	router.Handle("/api/books/{id}/history", protect(auth.PermBooksUpdate, h.V1.ListBookHistory)).Methods("GET")
+	router.Handle("/api/books/{id}/history/{revision}/revert", protect(auth.PermBooksUpdate, h.V1.RevertBook)).Methods("POST")
+
+	// Author endpoints
+	router.HandleFunc("/api/authors", h.V1.ListAuthors).Methods("GET")
+	router.Handle("/api/authors", protect(auth.PermAuthorsManage, h.V1.CreateAuthor)).Methods("POST")
+	router.HandleFunc("/api/authors/{id}", h.V1.GetAuthor).Methods("GET")
+	router.Handle("/api/authors/{id}", protect(auth.PermAuthorsManage, h.V1.UpdateAuthor)).Methods("PUT")
+	router.Handle("/api/authors/{id}", protect(auth.PermAuthorsManage, h.V1.DeleteAuthor)).Methods("DELETE")
+	router.HandleFunc("/api/authors/{id}/books", h.V1.ListAuthorBooks).Methods("GET")
+
+	// Publisher endpoints
+	router.HandleFunc("/api/publishers", h.V1.ListPublishers).Methods("GET")
+	router.Handle("/api/publishers", protect(auth.PermPublishersManage, h.V1.CreatePublisher)).Methods("POST")
+	router.HandleFunc("/api/publishers/{id}", h.V1.GetPublisher).Methods("GET")
+	router.Handle("/api/publishers/{id}", protect(auth.PermPublishersManage, h.V1.UpdatePublisher)).Methods("PUT")
+	router.Handle("/api/publishers/{id}", protect(auth.PermPublishersManage, h.V1.DeletePublisher)).Methods("DELETE")
+	router.HandleFunc("/api/publishers/{id}/books", h.V1.ListPublisherBooks).Methods("GET")
+	router.Handle("/api/publishers/{id}/merge", protect(auth.PermPublishersManage, h.V1.MergePublishers)).Methods("POST")
+
+	// Subject and classification browse endpoints
+	router.HandleFunc("/api/subjects", h.V1.BrowseSubjects).Methods("GET")
+	router.Handle("/api/subjects", protect(auth.PermSubjectsManage, h.V1.CreateSubject)).Methods("POST")
+	router.HandleFunc("/api/subjects/{id}", h.V1.GetSubject).Methods("GET")
+	router.Handle("/api/subjects/{id}", protect(auth.PermSubjectsManage, h.V1.UpdateSubject)).Methods("PUT")
+	router.Handle("/api/subjects/{id}", protect(auth.PermSubjectsManage, h.V1.DeleteSubject)).Methods("DELETE")
+	router.HandleFunc("/api/classification/{scheme}", h.V1.BrowseClasses).Methods("GET")
+
+	// Work and series endpoints
+	router.HandleFunc("/api/works", h.V1.ListWorks).Methods("GET")
+	router.Handle("/api/works", protect(auth.PermWorksManage, h.V1.CreateWork)).Methods("POST")
+	router.HandleFunc("/api/works/{id}", h.V1.GetWork).Methods("GET")
+	router.Handle("/api/works/{id}", protect(auth.PermWorksManage, h.V1.UpdateWork)).Methods("PUT")
+	router.Handle("/api/works/{id}", protect(auth.PermWorksManage, h.V1.DeleteWork)).Methods("DELETE")
+	router.HandleFunc("/api/works/{id}/editions", h.V1.ListEditions).Methods("GET")
+	router.HandleFunc("/api/books/{id}/series", h.V1.GetSeriesPosition).Methods("GET")
+	router.HandleFunc("/api/series", h.V1.ListSeries).Methods("GET")
+	router.Handle("/api/series", protect(auth.PermWorksManage, h.V1.CreateSeries)).Methods("POST")
+	router.HandleFunc("/api/series/{id}", h.V1.GetSeries).Methods("GET")
+	router.Handle("/api/series/{id}", protect(auth.PermWorksManage, h.V1.UpdateSeries)).Methods("PUT")
+	router.Handle("/api/series/{id}", protect(auth.PermWorksManage, h.V1.DeleteSeries)).Methods("DELETE")
+
+	// Item endpoints
+	router.HandleFunc("/api/books/{id}/items", h.V1.ListItems).Methods("GET")
+	router.Handle("/api/books/{id}/items", protect(auth.PermItemsManage, h.V1.CreateItem)).Methods("POST")
+	router.HandleFunc("/api/books/{id}/items/{itemId}", h.V1.GetItem).Methods("GET")
+	router.Handle("/api/books/{id}/items/{itemId}", protect(auth.PermItemsManage, h.V1.UpdateItem)).Methods("PUT")
+	router.Handle("/api/books/{id}/items/{itemId}", protect(auth.PermItemsManage, h.V1.DeleteItem)).Methods("DELETE")
+
+	// Loan endpoints
+	router.Handle("/api/books/{id}/checkout", protect(auth.PermLoansBorrow, h.V1.CheckoutBook)).Methods("POST")
+	router.Handle("/api/loans", protect(auth.PermLoansBorrow, h.V1.ListLoans)).Methods("GET")
+	router.Handle("/api/loans/{id}", protect(auth.PermLoansBorrow, h.V1.GetLoan)).Methods("GET")
+	router.Handle("/api/loans/{id}/return", protect(auth.PermLoansBorrow, h.V1.ReturnLoan)).Methods("POST")
+
+	// Fine and payment endpoints
+	router.Handle("/api/members/{id}/balance", protect(auth.PermFinesView, h.V1.GetMemberBalance)).Methods("GET")
+	router.Handle("/api/members/{id}/transactions", protect(auth.PermFinesView, h.V1.ListMemberTransactions)).Methods("GET")
+	router.Handle("/api/members/{id}/payments", protect(auth.PermFinesManage, h.V1.RecordPayment)).Methods("POST")
+	router.Handle("/api/members/{id}/waivers", protect(auth.PermFinesManage, h.V1.WaiveFine)).Methods("POST")
+	router.Handle("/api/members/{id}/charges", protect(auth.PermFinesManage, h.V1.ChargeMember)).Methods("POST")
+
+	// Hold endpoints
+	router.Handle("/api/books/{id}/holds", protect(auth.PermHoldsPlace, h.V1.PlaceHold)).Methods("POST")
+	router.Handle("/api/books/{id}/holds", protect(auth.PermHoldsManage, h.V1.GetHoldQueue)).Methods("GET")
+	router.Handle("/api/holds", protect(auth.PermHoldsPlace, h.V1.ListHolds)).Methods("GET")
+	router.Handle("/api/holds/{id}", protect(auth.PermHoldsPlace, h.V1.GetHold)).Methods("GET")
+	router.Handle("/api/holds/{id}/cancel", protect(auth.PermHoldsPlace, h.V1.CancelHold)).Methods("POST")
+	router.Handle("/api/holds/{id}/priority", protect(auth.PermHoldsManage, h.V1.SetHoldPriority)).Methods("PUT")
+
+	// Reading-room reservation endpoints
+	router.HandleFunc("/api/reservations/availability", h.V1.GetSeatAvailability).Methods("GET")
+	router.Handle("/api/reservations", protect(auth.PermReservationsBook, h.V1.ReserveSeat)).Methods("POST")
+	router.Handle("/api/reservations", protect(auth.PermReservationsBook, h.V1.ListReservations)).Methods("GET")
+	router.Handle("/api/reservations/{id}", protect(auth.PermReservationsBook, h.V1.GetReservation)).Methods("GET")
+	router.Handle("/api/reservations/{id}/cancel", protect(auth.PermReservationsBook, h.V1.CancelReservation)).Methods("POST")
+
+	// Admin endpoints
+	router.Handle("/api/admin/users/{id}/roles", protect(auth.PermRolesManage, h.V1.GetUserRoles)).Methods("GET")
+	router.Handle("/api/admin/users/{id}/roles", protect(auth.PermRolesManage, h.V1.GrantRole)).Methods("POST")
+	router.Handle("/api/admin/users/{id}/roles/{role}", protect(auth.PermRolesManage, h.V1.RevokeRole)).Methods("DELETE")
+	router.Handle("/api/admin/role-audit", protect(auth.PermRolesManage, h.V1.ListRoleAudit)).Methods("GET")
 
 	return router
 }